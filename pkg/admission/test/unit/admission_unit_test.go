@@ -452,6 +452,32 @@ var _ = Describe("BackingStore admission unit tests", func() {
 					Ω(err).ShouldNot(HaveOccurred())
 				})
 			})
+			Context("Decommission list", func() {
+				It("Should Deny a pod of another backingstore", func() {
+					bs.Spec = nbv1.BackingStoreSpec{
+						Type: nbv1.StoreTypePVPool,
+						PVPool: &nbv1.PVPoolSpec{
+							Decommission: []string{"other-noobaa-pod-a1b2"},
+						},
+					}
+
+					err = validations.ValidatePvpoolDecommission(*bs)
+					Ω(err).Should(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("expected a pod of BackingStore"))
+				})
+
+				It("Should Allow", func() {
+					bs.Spec = nbv1.BackingStoreSpec{
+						Type: nbv1.StoreTypePVPool,
+						PVPool: &nbv1.PVPoolSpec{
+							Decommission: []string{bs.Name + "-noobaa-pod-a1b2"},
+						},
+					}
+
+					err = validations.ValidatePvpoolDecommission(*bs)
+					Ω(err).ShouldNot(HaveOccurred())
+				})
+			})
 		})
 		Describe("S3 Compatible backingstore", func() {
 			Context("Invalid signature version", func() {
@@ -521,7 +547,27 @@ var _ = Describe("BackingStore admission unit tests", func() {
 
 					err = validations.ValidatePvpoolScaleDown(*bs, *updatedBS)
 					Ω(err).Should(HaveOccurred())
-					Expect(err.Error()).To(Equal("Scaling down the number of nodes is not currently supported"))
+					Expect(err.Error()).To(Equal("Scaling down the number of nodes from 15 to 10 requires decommissioning 5 pods in spec.pvPool.decommission"))
+				})
+
+				It("Should Allow when the removed pods are decommissioned", func() {
+					bs.Spec = nbv1.BackingStoreSpec{
+						Type: nbv1.StoreTypePVPool,
+						PVPool: &nbv1.PVPoolSpec{
+							NumVolumes:   2,
+							Decommission: []string{"bs-noobaa-pod-a1b2"},
+						},
+					}
+
+					updatedBS.Spec = nbv1.BackingStoreSpec{
+						Type: nbv1.StoreTypePVPool,
+						PVPool: &nbv1.PVPoolSpec{
+							NumVolumes: 3,
+						},
+					}
+
+					err = validations.ValidatePvpoolScaleDown(*bs, *updatedBS)
+					Ω(err).ShouldNot(HaveOccurred())
 				})
 
 				It("Should Allow", func() {
@@ -73,6 +73,21 @@ func (nav *ResourceValidator) ValidateCreateNA() {
 		nav.SetValidationResult(false, err.Error())
 		return
 	}
+
+	if err := validations.ValidateAccountPermissions(*na); err != nil && util.IsValidationError(err) {
+		nav.SetValidationResult(false, err.Error())
+		return
+	}
+
+	if err := validations.ValidateAccountCredentialRotation(*na); err != nil && util.IsValidationError(err) {
+		nav.SetValidationResult(false, err.Error())
+		return
+	}
+
+	if err := validations.ValidateAccountSTSRole(*na); err != nil && util.IsValidationError(err) {
+		nav.SetValidationResult(false, err.Error())
+		return
+	}
 }
 
 // ValidateUpdateNA runs all the validations tests for UPDATE operations
@@ -95,4 +110,25 @@ func (nav *ResourceValidator) ValidateUpdateNA() {
 			return
 		}
 	}
+
+	if !reflect.DeepEqual(oldNA.Spec.Permissions, na.Spec.Permissions) {
+		if err := validations.ValidateAccountPermissions(*na); err != nil && util.IsValidationError(err) {
+			nav.SetValidationResult(false, err.Error())
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldNA.Spec.CredentialRotation, na.Spec.CredentialRotation) {
+		if err := validations.ValidateAccountCredentialRotation(*na); err != nil && util.IsValidationError(err) {
+			nav.SetValidationResult(false, err.Error())
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldNA.Spec.STSRole, na.Spec.STSRole) {
+		if err := validations.ValidateAccountSTSRole(*na); err != nil && util.IsValidationError(err) {
+			nav.SetValidationResult(false, err.Error())
+			return
+		}
+	}
 }
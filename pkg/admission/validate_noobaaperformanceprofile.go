@@ -0,0 +1,69 @@
+package admission
+
+import (
+	"encoding/json"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/validations"
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewPerformanceProfileValidator initializes a PerformanceProfileValidator to be used for loading and validating a noobaaperformanceprofile
+func NewPerformanceProfileValidator(arRequest admissionv1.AdmissionReview) *ResourceValidator {
+	ppv := &ResourceValidator{
+		Logger:    logrus.WithField("admission noobaaperformanceprofile validation", arRequest.Request.Name),
+		arRequest: &arRequest,
+		arResponse: &admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "AdmissionReview",
+				APIVersion: "admission.k8s.io/v1",
+			},
+			Response: &admissionv1.AdmissionResponse{
+				UID:     arRequest.Request.UID,
+				Allowed: true,
+				Result: &metav1.Status{
+					Message: "allowed",
+				},
+			},
+		},
+	}
+	return ppv
+}
+
+// ValidatePerformanceProfile call appropriate validations based on the operation
+func (ppv *ResourceValidator) ValidatePerformanceProfile() admissionv1.AdmissionReview {
+	switch ppv.arRequest.Request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		ppv.ValidatePerformanceProfileSpec()
+	default:
+		ppv.Logger.Errorf("No action registered for the operation type: %v", ppv.arRequest.Request.Operation)
+	}
+	return *ppv.arResponse
+}
+
+// DeserializePerformanceProfile extract the noobaaperformanceprofile object from the request
+func (ppv *ResourceValidator) DeserializePerformanceProfile(rawProfile []byte) *nbv1.NooBaaPerformanceProfile {
+	profile := nbv1.NooBaaPerformanceProfile{}
+	if err := json.Unmarshal(rawProfile, &profile); err != nil {
+		ppv.Logger.Error("error deserializing noobaaperformanceprofile")
+		return nil
+	}
+	return &profile
+}
+
+// ValidatePerformanceProfileSpec runs all the validations tests for CREATE and UPDATE operations
+func (ppv *ResourceValidator) ValidatePerformanceProfileSpec() {
+	profile := ppv.DeserializePerformanceProfile(ppv.arRequest.Request.Object.Raw)
+	if profile == nil {
+		ppv.SetValidationResult(false, "failed deserializing noobaaperformanceprofile")
+		return
+	}
+
+	if err := validations.ValidatePerformanceProfile(profile); err != nil && util.IsValidationError(err) {
+		ppv.SetValidationResult(false, err.Error())
+		return
+	}
+}
@@ -63,6 +63,8 @@ func (gs *ServerHandler) serve(w http.ResponseWriter, r *http.Request) {
 		arResponse = NewNoobaaAccountValidator(arRequest).ValidateNoobaAaccount()
 	case "noobaas":
 		arResponse = NewNoobaaValidator(arRequest).ValidateNoobaa()
+	case "noobaaperformanceprofiles":
+		arResponse = NewPerformanceProfileValidator(arRequest).ValidatePerformanceProfile()
 	default:
 		log.Error("failed to identify resource type")
 		http.Error(w, "incorrect resource", http.StatusBadRequest)
@@ -105,8 +105,52 @@ type BackingStoreStatus struct {
 	// Mode specifies the updating mode of a BackingStore
 	// +optional
 	Mode BackingStoreMode `json:"mode,omitempty"`
+
+	// PVPoolDecommission is the progress of the pv-pool pods that are decommissioned
+	// +optional
+	PVPoolDecommission []PVPoolDecommissionStatus `json:"pvPoolDecommission,omitempty"`
 }
 
+// PVPoolDecommissionStatus is the progress of decommissioning a pv-pool pod and its PVC
+type PVPoolDecommissionStatus struct {
+	// PodName is the name of the decommissioned pod
+	PodName string `json:"podName"`
+
+	// PVCName is the name of the PVC of the decommissioned pod
+	// +optional
+	PVCName string `json:"pvcName,omitempty"`
+
+	// Phase is the step of the decommission
+	Phase PVPoolDecommissionPhase `json:"phase"`
+
+	// Progress is the percentage of the data that was evacuated from the pod
+	// +optional
+	Progress int `json:"progress,omitempty"`
+
+	// StartTime is the time the decommission started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the pod and its PVC were deleted
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// PVPoolDecommissionPhase is a string enum type for the steps of decommissioning a pv-pool pod
+type PVPoolDecommissionPhase string
+
+// These are the valid decommission phases:
+const (
+	// PVPoolDecommissionPhaseDecommissioning means the data of the pod is evacuated to the other pods
+	PVPoolDecommissionPhaseDecommissioning PVPoolDecommissionPhase = "Decommissioning"
+
+	// PVPoolDecommissionPhaseDeleting means the data was evacuated and the pod and its PVC are deleted
+	PVPoolDecommissionPhaseDeleting PVPoolDecommissionPhase = "Deleting"
+
+	// PVPoolDecommissionPhaseCompleted means the pod and its PVC were deleted
+	PVPoolDecommissionPhaseCompleted PVPoolDecommissionPhase = "Completed"
+)
+
 // BackingStoreMode defines the updated Mode of BackingStore
 type BackingStoreMode struct {
 	// ModeCode specifies the updated mode of backingstore
@@ -260,6 +304,13 @@ type PVPoolSpec struct {
 	// The secret should define AGENT_CONFIG containing agent_configuration from noobaa-core.
 	// +optional
 	Secret corev1.SecretReference `json:"secret"`
+
+	// Decommission (optional) is a list of pv-pool pods to retire. The data of each pod is evacuated
+	// to the other pods before the pod and its PVC are deleted. A pod can also be retired by setting
+	// the annotation noobaa.io/decommission=true on it. When NumVolumes is not decreased accordingly,
+	// a new pod and PVC replace the retired ones.
+	// +optional
+	Decommission []string `json:"decommission,omitempty"`
 }
 
 // S3SignatureVersion specifies the client signature version to use when signing requests.
@@ -84,6 +84,11 @@ type BucketClassSpec struct {
 	// Requires PlacementPolicy to also be set.
 	// +optional
 	ArchivePolicy *ArchivePolicy `json:"archivePolicy,omitempty"`
+
+	// LifecyclePolicy specifies the lifecycle rules that are applied to every bucket of the bucket class.
+	// Setting an empty list of rules removes the lifecycle configuration from the buckets.
+	// +optional
+	LifecyclePolicy *LifecyclePolicy `json:"lifecyclePolicy,omitempty"`
 }
 
 // BucketClassStatus defines the observed state of BucketClass
@@ -105,6 +110,10 @@ type BucketClassStatus struct {
 	// Mode is a simple, high-level summary of where the System is in its lifecycle
 	// +optional
 	Mode string `json:"mode,omitempty"`
+
+	// LifecyclePolicy reports the result of applying the lifecycle policy to the buckets of the bucket class
+	// +optional
+	LifecyclePolicy *LifecyclePolicyStatus `json:"lifecyclePolicy,omitempty"`
 }
 
 // PlacementPolicy specifies the placement policy for the bucket class
@@ -256,6 +265,66 @@ type ArchivePolicy struct {
 	DeepArchiveResource string `json:"deepArchiveResource,omitempty"`
 }
 
+// LifecyclePolicy specifies the lifecycle policy for a bucket class
+type LifecyclePolicy struct {
+
+	// Rules is the list of lifecycle rules to apply on every bucket of the bucket class
+	// +optional
+	Rules []LifecycleRule `json:"rules,omitempty"`
+}
+
+// LifecycleRule specifies a single lifecycle rule of a bucket class
+type LifecycleRule struct {
+
+	// ID is the unique identifier of the rule
+	ID string `json:"id"`
+
+	// Disabled keeps the rule in the policy without enforcing it
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Prefix limits the rule to objects whose key starts with the prefix.
+	// When empty the rule applies to all the objects of the bucket.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// ExpirationDays is the age in days after which current objects expire
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ExpirationDays int `json:"expirationDays,omitempty"`
+
+	// NoncurrentVersionExpirationDays is the number of days after which noncurrent object versions expire
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	NoncurrentVersionExpirationDays int `json:"noncurrentVersionExpirationDays,omitempty"`
+
+	// NewerNoncurrentVersions is the number of newest noncurrent versions to retain
+	// regardless of NoncurrentVersionExpirationDays
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	NewerNoncurrentVersions int `json:"newerNoncurrentVersions,omitempty"`
+
+	// AbortIncompleteMultipartUploadDays is the number of days after which incomplete multipart uploads are aborted
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	AbortIncompleteMultipartUploadDays int `json:"abortIncompleteMultipartUploadDays,omitempty"`
+}
+
+// LifecyclePolicyStatus reports the result of applying the lifecycle policy of a bucket class
+type LifecyclePolicyStatus struct {
+
+	// ObservedGeneration is the bucket class generation the lifecycle policy was last applied from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AppliedBuckets is the number of buckets of the bucket class that have the current lifecycle policy
+	AppliedBuckets int `json:"appliedBuckets"`
+
+	// FailedBuckets is the list of buckets that failed to get the lifecycle policy
+	// +optional
+	FailedBuckets []string `json:"failedBuckets,omitempty"`
+}
+
 // VectorPolicy specifies the vector policy for the bucket class
 type VectorPolicy struct {
 
@@ -0,0 +1,178 @@
+package v1alpha1
+
+import (
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Note 1: Run "make gen-api" to regenerate code after modifying this file
+// Note 2: Add custom validation using kubebuilder tags: https://book.kubebuilder.io/reference/generating-crd.html
+
+func init() {
+	SchemeBuilder.Register(&BucketReplication{}, &BucketReplicationList{})
+}
+
+// BucketReplication is the Schema for the bucketreplications API
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".status.sourceBucket",description="Source"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase"
+// +kubebuilder:printcolumn:name="Last-Sync",type="date",JSONPath=".status.lastSyncTime",description="Last-Sync"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type BucketReplication struct {
+
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the noobaa BucketReplication.
+	// +optional
+	Spec BucketReplicationSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the noobaa BucketReplication.
+	// +optional
+	Status BucketReplicationStatus `json:"status,omitempty"`
+}
+
+// BucketReplicationList contains a list of BucketReplication
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BucketReplicationList struct {
+
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of BucketReplications.
+	Items []BucketReplication `json:"items"`
+}
+
+// BucketReplicationSpec defines the desired state of BucketReplication
+// +k8s:openapi-gen=true
+type BucketReplicationSpec struct {
+
+	// Source specifies the bucket to replicate from
+	Source BucketReplicationSource `json:"source"`
+
+	// Rules is the list of replication rules of the source bucket
+	// +kubebuilder:validation:MinItems=1
+	Rules []BucketReplicationRule `json:"rules"`
+
+	// LogReplicationSource specifies where the bucket access logs used for
+	// log based replication are read from
+	// +optional
+	LogReplicationSource *LogReplicationSource `json:"logReplicationSource,omitempty"`
+}
+
+// BucketReplicationSource specifies the source bucket of a replication.
+// Exactly one of BucketName or ObjectBucketClaim should be set.
+type BucketReplicationSource struct {
+
+	// BucketName is the name of the noobaa bucket to replicate from
+	// +optional
+	BucketName string `json:"bucketName,omitempty"`
+
+	// ObjectBucketClaim is the name of an ObjectBucketClaim in the namespace of the
+	// BucketReplication whose bucket is replicated
+	// +optional
+	ObjectBucketClaim string `json:"objectBucketClaim,omitempty"`
+}
+
+// BucketReplicationRule specifies a single replication rule
+type BucketReplicationRule struct {
+
+	// RuleID is the unique identifier of the rule
+	RuleID string `json:"ruleId"`
+
+	// DestinationBucket is the name of the noobaa bucket to replicate to
+	DestinationBucket string `json:"destinationBucket"`
+
+	// Prefix limits the rule to objects whose key starts with the prefix
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// SyncDeletions specifies if deletions on the source bucket are replicated as well
+	// +optional
+	SyncDeletions bool `json:"syncDeletions,omitempty"`
+
+	// SyncVersions specifies if all the object versions are replicated and not only the latest
+	// +optional
+	SyncVersions bool `json:"syncVersions,omitempty"`
+}
+
+// LogReplicationSource specifies the location of the bucket logs used for log based replication
+type LogReplicationSource struct {
+
+	// EndpointType is the type of the cloud endpoint that writes the bucket logs
+	// +kubebuilder:validation:Enum=AWS;AZURE
+	// +optional
+	EndpointType string `json:"endpointType,omitempty"`
+
+	// LogsBucket is the name of the bucket that holds the logs
+	// +optional
+	LogsBucket string `json:"logsBucket,omitempty"`
+
+	// Prefix is the key prefix of the logs inside LogsBucket
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// BucketReplicationStatus defines the observed state of BucketReplication
+// +k8s:openapi-gen=true
+type BucketReplicationStatus struct {
+
+	// Phase is a simple, high-level summary of where the BucketReplication is in its lifecycle
+	// +optional
+	Phase BucketReplicationPhase `json:"phase,omitempty"`
+
+	// Conditions is a list of conditions related to operator reconciliation
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +optional
+	Conditions []conditionsv1.Condition `json:"conditions,omitempty"  patchStrategy:"merge" patchMergeKey:"type"`
+
+	// SourceBucket is the resolved name of the noobaa bucket the policy is applied to
+	// +optional
+	SourceBucket string `json:"sourceBucket,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last applied to the source bucket
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the last time the replication policy was read from noobaa and found applied to the source bucket
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastError is the error of the last failed attempt to apply the replication policy
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// BucketReplicationPhase is a string enum type for bucket replication reconcile phases
+type BucketReplicationPhase string
+
+// These are the valid phases:
+const (
+
+	// BucketReplicationPhaseRejected means the spec has been rejected by the operator,
+	// this is most likely due to an incompatible configuration.
+	// Use describe to see events.
+	BucketReplicationPhaseRejected BucketReplicationPhase = "Rejected"
+
+	// BucketReplicationPhaseVerifying means the operator is verifying the spec
+	BucketReplicationPhaseVerifying BucketReplicationPhase = "Verifying"
+
+	// BucketReplicationPhaseConfiguring means the operator is applying the replication policy to the source bucket
+	BucketReplicationPhaseConfiguring BucketReplicationPhase = "Configuring"
+
+	// BucketReplicationPhaseReady means the replication policy is applied to the source bucket
+	BucketReplicationPhaseReady BucketReplicationPhase = "Ready"
+
+	// BucketReplicationPhaseDeleting means the operator is removing the replication policy from the source bucket
+	BucketReplicationPhaseDeleting BucketReplicationPhase = "Deleting"
+)
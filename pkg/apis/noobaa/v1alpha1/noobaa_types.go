@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
@@ -267,9 +268,17 @@ type NooBaaSpec struct {
 	// PerformanceProfile (optional) selects a bundle of resource and count
 	// settings for the core, db and endpoint components.
 	// Explicit per-component resource/count fields take precedence over the profile.
+	// Either one of the built-in profiles: default, mixed-workload, small-objects, dev-env, mini-env,
+	// or the name of a NooBaaPerformanceProfile.
 	// +optional
-	// +kubebuilder:validation:Enum=default;mixed-workload;small-objects;dev-env;mini-env
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
 	PerformanceProfile PerformanceProfileType `json:"performanceProfile,omitempty"`
+
+	// NetworkPolicy (optional) customizes the network policies of the NooBaa pods,
+	// with additional ingress rules and a default-deny egress with an allow-list
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
 }
 
 // Affinity is a group of affinity scheduling rules.
@@ -300,8 +309,100 @@ type AutoscalerSpec struct {
 	// Prometheus namespace that scrap metrics from noobaa
 	// +optional
 	PrometheusNamespace string `json:"prometheusNamespace,omitempty"`
+
+	// Metrics (optional) the metrics to scale the endpoints on.
+	// The number of endpoints is the highest number calculated for any of the metrics.
+	// Defaults to a CPU utilization of 80%
+	// +optional
+	Metrics []AutoscalerMetric `json:"metrics,omitempty"`
+
+	// ScaleDownStabilizationWindowSeconds (optional) the number of seconds the autoscaler looks back
+	// for a higher recommendation before removing endpoints, to avoid flapping. Defaults to 600
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	ScaleDownStabilizationWindowSeconds *int32 `json:"scaleDownStabilizationWindowSeconds,omitempty"`
+}
+
+// AutoscalerMetric is a metric to scale the endpoints on and its target value
+type AutoscalerMetric struct {
+	// Type of the metric:
+	// cpu - CPU utilization of the endpoint pods
+	// requestsPerSecond - S3 requests per second per endpoint pod
+	// latencyP95 - 95th percentile of the S3 request latency
+	// inflightOps - S3 operations in flight per endpoint pod
+	// +kubebuilder:validation:Enum=cpu;requestsPerSecond;latencyP95;inflightOps
+	Type AutoscalerMetricType `json:"type"`
+
+	// Target value of the metric to keep, endpoints are added when the metric is above the target:
+	// cpu - percent of the CPU request, e.g. "80"
+	// requestsPerSecond, inflightOps - an average value per endpoint pod, e.g. "500"
+	// latencyP95 - a duration, e.g. "250ms"
+	Target string `json:"target"`
 }
 
+// NetworkPolicySpec customizes the network policies reconciled for the NooBaa pods
+type NetworkPolicySpec struct {
+	// AdditionalIngress (optional) ingress rules added to the network policies of the NooBaa pods
+	// +optional
+	AdditionalIngress []NetworkPolicyIngressRule `json:"additionalIngress,omitempty"`
+
+	// Egress (optional) restricts the egress traffic of the NooBaa pods
+	// +optional
+	Egress *NetworkPolicyEgressSpec `json:"egress,omitempty"`
+}
+
+// NetworkPolicyIngressRule allows traffic from additional namespaces to additional ports of a NooBaa component
+type NetworkPolicyIngressRule struct {
+	// Component is the NooBaa component the rule applies to
+	// +kubebuilder:validation:Enum=core;endpoint;db
+	Component NetworkPolicyComponent `json:"component"`
+
+	// Namespaces (optional) the names of the namespaces the traffic is allowed from.
+	// When empty the traffic is allowed from all the namespaces
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Ports the ports of the component pods the traffic is allowed to
+	// +kubebuilder:validation:MinItems=1
+	Ports []networkingv1.NetworkPolicyPort `json:"ports"`
+}
+
+// NetworkPolicyEgressSpec defines the egress isolation of the NooBaa pods
+type NetworkPolicyEgressSpec struct {
+	// DenyByDefault (optional) when true the NooBaa, operator and CNPG controller pods
+	// are only allowed egress traffic to DNS, the kubernetes API server, the pods of the namespace,
+	// the endpoints of the BackingStores, NamespaceStores, KMS and external Postgres,
+	// and the allowed CIDRs and hosts
+	// +optional
+	DenyByDefault bool `json:"denyByDefault,omitempty"`
+
+	// AllowedCIDRs (optional) additional IP ranges the NooBaa pods are allowed to connect to
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// AllowedHosts (optional) additional hostnames the NooBaa pods are allowed to connect to.
+	// Network policies only match IP addresses, so the operator resolves the hostnames to addresses
+	// every 2 minutes. Connections to an address that rotated in between are blocked until the next resolve,
+	// so prefer AllowedCIDRs for endpoints with rotating addresses
+	// +optional
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+}
+
+// NetworkPolicyComponent is a NooBaa component that has a network policy
+type NetworkPolicyComponent string
+
+const (
+	// NetworkPolicyComponentCore is the noobaa-core pod
+	NetworkPolicyComponentCore NetworkPolicyComponent = "core"
+
+	// NetworkPolicyComponentEndpoint is the noobaa-endpoint pods
+	NetworkPolicyComponentEndpoint NetworkPolicyComponent = "endpoint"
+
+	// NetworkPolicyComponentDB is the CNPG database pods
+	NetworkPolicyComponentDB NetworkPolicyComponent = "db"
+)
+
 // BucketLoggingSpec defines the bucket logging configuration
 type BucketLoggingSpec struct {
 	// LoggingType specifies the type of logging for the bucket
@@ -406,8 +507,119 @@ type SecuritySpec struct {
 	// platform TLS profile here and NooBaa applies it to endpoint HTTPS servers.
 	// +optional
 	APIServerSecurity *TLSSecuritySpec `json:"apiServerSecurity,omitempty"`
+
+	// OIDC (optional) the OIDC identity providers trusted by the NooBaa STS service,
+	// allowing workloads to assume NooBaa roles with web identity tokens
+	// +optional
+	OIDC *OIDCSpec `json:"oidc,omitempty"`
 }
 
+// OIDCSpec configures the OIDC identity providers of the NooBaa STS service
+type OIDCSpec struct {
+	// Enabled renders the providers to the <name>-oidc-config secret and mounts it to the endpoint pods
+	// at /etc/noobaa-server/oidc/config. Enable it only with a core image that reads this configuration,
+	// the providers are not used by the endpoints while it is false.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Providers is the list of trusted OIDC identity providers
+	// +optional
+	Providers []OIDCProviderSpec `json:"providers,omitempty"`
+}
+
+// OIDCProviderSpec is an OIDC identity provider trusted by the NooBaa STS service
+type OIDCProviderSpec struct {
+	// Name is a unique name of the provider
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Type of the provider:
+	// keycloak - a Keycloak realm
+	// oidc - any provider that serves an OIDC discovery document
+	// kubernetes - the service account issuer of this cluster, for projected service account tokens
+	// dex - a Dex server
+	// +kubebuilder:validation:Enum=keycloak;oidc;kubernetes;dex
+	Type OIDCProviderType `json:"type"`
+
+	// Issuer (optional for kubernetes) the issuer URL, the discovery document is read from
+	// <issuer>/.well-known/openid-configuration. Defaults to the service account issuer for kubernetes
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// Audiences (optional) the accepted values of the aud claim of the tokens.
+	// Defaults to "noobaa" for kubernetes, and to any audience for the other types
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+
+	// Validation (optional) how the tokens are validated:
+	// jwks - the token signature is verified with the public keys of the provider (default)
+	// introspection - the token is sent to the introspection endpoint of the provider, only for keycloak and oidc
+	// +kubebuilder:validation:Enum=jwks;introspection
+	// +optional
+	Validation OIDCTokenValidation `json:"validation,omitempty"`
+
+	// JWKSURI (optional) overrides the jwks_uri of the discovery document
+	// +optional
+	JWKSURI string `json:"jwksURI,omitempty"`
+
+	// IntrospectionEndpoint (optional) overrides the introspection_endpoint of the discovery document
+	// +optional
+	IntrospectionEndpoint string `json:"introspectionEndpoint,omitempty"`
+
+	// ClientSecret (optional) refers to a secret with the client_id and client_secret keys
+	// of the client that calls the introspection endpoint, required for introspection
+	// +optional
+	ClientSecret *corev1.SecretReference `json:"clientSecret,omitempty"`
+
+	// ClaimMappings are the rules that map the claims of a token to a NooBaa account.
+	// The rules are evaluated in order and the first matching rule is used, a token that matches no rule is rejected
+	// +kubebuilder:validation:MinItems=1
+	ClaimMappings []OIDCClaimMapping `json:"claimMappings"`
+}
+
+// OIDCClaimMapping maps the tokens with a matching claim to a NooBaa account
+type OIDCClaimMapping struct {
+	// Claim (optional) the name of the claim to match, defaults to sub
+	// +optional
+	Claim string `json:"claim,omitempty"`
+
+	// Value is the value the claim must match, a "*" matches any sequence of characters,
+	// e.g. system:serviceaccount:apps:* for all the service accounts of the apps namespace
+	Value string `json:"value"`
+
+	// Account is the name of the NooBaa account the matching tokens are mapped to
+	Account string `json:"account"`
+}
+
+// OIDCProviderType is the type of an OIDC identity provider
+type OIDCProviderType string
+
+const (
+	// OIDCProviderKeycloak is a Keycloak realm
+	OIDCProviderKeycloak OIDCProviderType = "keycloak"
+
+	// OIDCProviderGeneric is a provider that serves an OIDC discovery document
+	OIDCProviderGeneric OIDCProviderType = "oidc"
+
+	// OIDCProviderKubernetes is the service account issuer of the cluster
+	OIDCProviderKubernetes OIDCProviderType = "kubernetes"
+
+	// OIDCProviderDex is a Dex server
+	OIDCProviderDex OIDCProviderType = "dex"
+)
+
+// OIDCTokenValidation is the method used to validate the tokens of an OIDC provider
+type OIDCTokenValidation string
+
+const (
+	// OIDCTokenValidationJWKS verifies the token signature with the public keys of the provider
+	OIDCTokenValidationJWKS OIDCTokenValidation = "jwks"
+
+	// OIDCTokenValidationIntrospection sends the token to the introspection endpoint of the provider
+	OIDCTokenValidationIntrospection OIDCTokenValidation = "introspection"
+)
+
 // KeyManagementServiceSpec represent various details of the KMS server
 type KeyManagementServiceSpec struct {
 	// +optional
@@ -451,16 +663,42 @@ type NooBaaDBSpec struct {
 	// +optional
 	DBConf map[string]string `json:"dbConf,omitempty"`
 
-	// DBBackup (optional) configure automatic scheduled backups of the database volume.
-	// Currently, only volume snapshots are supported.
+	// DBAutoGrow (optional) automatically expand the database volume when its usage crosses a threshold.
+	// The storage class of the database volume must allow volume expansion.
+	// +optional
+	DBAutoGrow *DBAutoGrowSpec `json:"dbAutoGrow,omitempty"`
+
+	// DBBackup (optional) configure automatic scheduled backups of the database,
+	// either as volume snapshots or as base backups and WAL archives in an S3 object store.
 	// +optional
 	DBBackup *DBBackupSpec `json:"dbBackup,omitempty"`
 
-	// DBRecovery (optional) configure database recovery from snapshot
+	// DBRecovery (optional) configure database recovery from a volume snapshot or from the backup object store,
+	// optionally to a point in time
 	// +optional
 	DBRecovery *DBRecoverySpec `json:"dbRecovery,omitempty"`
 }
 
+// DBAutoGrowSpec defines the policy for automatic expansion of the database volume
+type DBAutoGrowSpec struct {
+	// ThresholdPercent (optional) the used percentage of the database volume that triggers an expansion. Defaults to 80.
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=95
+	// +optional
+	ThresholdPercent int `json:"thresholdPercent,omitempty"`
+
+	// GrowthStep (optional) the size added to the database volume on each expansion,
+	// either a quantity such as 10Gi or a percentage of the current size such as 25%. Defaults to 20%.
+	// +kubebuilder:validation:Pattern=`^([1-9][0-9]*%|[0-9]+(\.[0-9]+)?(Ki|Mi|Gi|Ti|Pi|Ei|k|M|G|T|P|E)?)$`
+	// +optional
+	GrowthStep string `json:"growthStep,omitempty"`
+
+	// MaxSize the maximum size to expand the database volume to
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	MaxSize string `json:"maxSize"`
+}
+
 // DBBackupSpec defines the desired parameters for the database automatic backup
 type DBBackupSpec struct {
 	// Schedule the schedule for the database backup in cron format.
@@ -469,9 +707,16 @@ type DBBackupSpec struct {
 	Schedule string `json:"schedule"`
 
 	// VolumeSnapshot the volume snapshot backup configuration.
-	// Currently this is the only supported backup method and hence it is required.
-	// +kubebuilder:validation:Required
+	// Exactly one of VolumeSnapshot or ObjectStore should be set.
+	// +optional
 	VolumeSnapshot *VolumeSnapshotBackupSpec `json:"volumeSnapshot,omitempty"`
+
+	// ObjectStore the object store backup configuration.
+	// Base backups are taken on schedule and WAL files are archived continuously to an S3 bucket,
+	// which allows recovering the database off-cluster and to a point in time.
+	// Exactly one of VolumeSnapshot or ObjectStore should be set.
+	// +optional
+	ObjectStore *ObjectStoreBackupSpec `json:"objectStore,omitempty"`
 }
 
 type VolumeSnapshotBackupSpec struct {
@@ -486,12 +731,82 @@ type VolumeSnapshotBackupSpec struct {
 	MaxSnapshots int `json:"maxSnapshots"`
 }
 
-// DBRecoverySpec defines the desired parameters for database recovery from snapshot
-type DBRecoverySpec struct {
-	// VolumeSnapshotName specifies the name of the volume snapshot to recover from
+// ObjectStoreBackupSpec defines the S3 object store to back up the database to
+type ObjectStoreBackupSpec struct {
+	// DestinationPath the S3 path to store the base backups and WAL files in: s3://bucket/path
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^s3://.+`
+	DestinationPath string `json:"destinationPath"`
+
+	// Endpoint (optional) the endpoint of an S3 compatible object store: https://host:port
+	// For example the S3 endpoint of a NooBaa system in another cluster. Defaults to AWS S3.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// EndpointCASecret (optional) the name of a secret in the noobaa namespace
+	// holding the CA bundle that signed the endpoint certificate in the key ca.crt
+	// +optional
+	EndpointCASecret string `json:"endpointCASecret,omitempty"`
+
+	// CredentialsSecret the name of a secret in the noobaa namespace with the S3 credentials
+	// The secret should define AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, and optionally AWS_REGION
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
-	VolumeSnapshotName string `json:"volumeSnapshotName"`
+	CredentialsSecret string `json:"credentialsSecret"`
+
+	// RetentionPolicy (optional) the recovery window to keep base backups and WAL files for,
+	// a number followed by d, w or m for days, weeks or months, for example 30d.
+	// When not set, backups are kept in the object store until deleted manually.
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*[dwm]$`
+	// +optional
+	RetentionPolicy string `json:"retentionPolicy,omitempty"`
+}
+
+// DBRecoverySpec defines the desired parameters for database recovery from snapshot
+type DBRecoverySpec struct {
+	// VolumeSnapshotName (optional) specifies the name of the volume snapshot to recover from.
+	// When not set, the database is recovered from the base backups in the backup object store (dbBackup.objectStore).
+	// +optional
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// RecoveryTarget (optional) the point to stop the recovery at, by replaying the WAL files archived
+	// in the backup object store (dbBackup.objectStore).
+	// When not set, the database is recovered to the end of the volume snapshot,
+	// or to the last archived WAL file when recovering from the object store.
+	// +optional
+	RecoveryTarget *DBRecoveryTargetSpec `json:"recoveryTarget,omitempty"`
+
+	// ServerName (optional) the server name of the backups in the object store to recover from.
+	// Defaults to the server name the database is currently archived under (status.dbStatus.backupStatus.serverName).
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// DBRecoveryTargetSpec defines the point to stop the database recovery at.
+// Exactly one of TargetTime, TargetLSN or TargetName should be set.
+type DBRecoveryTargetSpec struct {
+	// TargetTime (optional) the time stamp to recover to in RFC 3339 format, for example 2026-01-13T08:50:00Z
+	// +optional
+	TargetTime string `json:"targetTime,omitempty"`
+
+	// TargetLSN (optional) the write-ahead log location to recover to, for example 0/3000060
+	// +kubebuilder:validation:Pattern=`^[0-9A-Fa-f]+/[0-9A-Fa-f]+$`
+	// +optional
+	TargetLSN string `json:"targetLSN,omitempty"`
+
+	// TargetName (optional) the named restore point to recover to, created with pg_create_restore_point()
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+
+	// BackupID (optional) the ID of the base backup in the object store to start the recovery from,
+	// as listed in status.dbStatus.backupStatus.backups. By default, the latest base backup before TargetTime is used,
+	// or the latest base backup when recovering to a TargetLSN or a TargetName.
+	// +optional
+	BackupID string `json:"backupID,omitempty"`
+
+	// Exclusive (optional) stop the recovery just before the recovery target instead of just after it
+	// +optional
+	Exclusive *bool `json:"exclusive,omitempty"`
 }
 
 // EndpointsSpec defines the desired state of noobaa endpoint deployment
@@ -621,6 +936,15 @@ type NooBaaDBStatus struct {
 	// ActualVolumeSize is the actual size of the postgres cluster volume. This can be different than the requested size
 	ActualVolumeSize string `json:"actualVolumeSize,omitempty"`
 
+	// VolumeUsedPercent is the used percentage of the postgres cluster volume at the last auto grow check
+	VolumeUsedPercent int `json:"volumeUsedPercent,omitempty"`
+
+	// VolumeUsageCheckTime is the time of the last auto grow check of the postgres cluster volume usage
+	VolumeUsageCheckTime *metav1.Time `json:"volumeUsageCheckTime,omitempty"`
+
+	// LastAutoGrowTime is the time the postgres cluster volume was last expanded automatically
+	LastAutoGrowTime *metav1.Time `json:"lastAutoGrowTime,omitempty"`
+
 	// BackupStatus reports the status of database backups
 	// +optional
 	BackupStatus *DBBackupStatus `json:"backupStatus,omitempty"`
@@ -646,6 +970,7 @@ const (
 	DBClusterStatusImporting DBClusterStatus = "Importing"
 
 	// DBClusterStatusRecovering means a new DB cluster is being created and data is being recovered from a volume snapshot
+	// or from the backup object store
 	DBClusterStatusRecovering DBClusterStatus = "Recovering"
 
 	// DBClusterStatusReady means the DB cluster is ready
@@ -668,6 +993,44 @@ type DBBackupStatus struct {
 
 	// AvailableSnapshots list of available snapshot names
 	AvailableSnapshots []string `json:"availableSnapshots,omitempty"`
+
+	// Backups list of the object store base backups, newest first
+	Backups []DBObjectStoreBackup `json:"backups,omitempty"`
+
+	// FirstRecoverabilityPoint the earliest point in time the database can be recovered to from the object store
+	FirstRecoverabilityPoint *metav1.Time `json:"firstRecoverabilityPoint,omitempty"`
+
+	// ContinuousArchiving true when WAL files are archived to the object store successfully
+	ContinuousArchiving bool `json:"continuousArchiving,omitempty"`
+
+	// ContinuousArchivingMessage the reason WAL archiving to the object store is failing
+	ContinuousArchivingMessage string `json:"continuousArchivingMessage,omitempty"`
+
+	// ServerName the server name the database is archived under in the object store.
+	// A database recovered from the object store is archived under a new server name to keep the archive it was recovered from intact.
+	// Empty means the name of the database cluster.
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// DBObjectStoreBackup reports a base backup of the database in the object store
+type DBObjectStoreBackup struct {
+	// Name the name of the backup resource
+	Name string `json:"name"`
+
+	// BackupID the ID of the backup in the object store
+	BackupID string `json:"backupId,omitempty"`
+
+	// Phase the phase of the backup
+	Phase string `json:"phase,omitempty"`
+
+	// StartedAt the time the backup started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// StoppedAt the time the backup completed or failed
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// Error the error of a failed backup
+	Error string `json:"error,omitempty"`
 }
 
 // DBRecoveryStatus reports the status of database recovery
@@ -678,10 +1041,39 @@ type DBRecoveryStatus struct {
 	// SnapshotName name of the snapshot being recovered from
 	SnapshotName string `json:"snapshotName,omitempty"`
 
+	// Source the source of the recovery, VolumeSnapshot or ObjectStore
+	Source DBRecoverySource `json:"source,omitempty"`
+
+	// ServerName the server name of the backups in the object store being recovered from
+	ServerName string `json:"serverName,omitempty"`
+
+	// RecoveryTarget the point the recovery stops at
+	RecoveryTarget *DBRecoveryTargetSpec `json:"recoveryTarget,omitempty"`
+
 	// RecoveryTime timestamp when recovery was initiated
 	RecoveryTime *metav1.Time `json:"recoveryTime,omitempty"`
+
+	// CompletionTime timestamp when recovery completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ClusterPhase the phase of the database cluster while recovering
+	ClusterPhase string `json:"clusterPhase,omitempty"`
+
+	// Message details on the recovery progress
+	Message string `json:"message,omitempty"`
 }
 
+// DBRecoverySource represents the source of a database recovery
+type DBRecoverySource string
+
+const (
+	// DBRecoverySourceVolumeSnapshot means the database is recovered from a volume snapshot
+	DBRecoverySourceVolumeSnapshot DBRecoverySource = "VolumeSnapshot"
+
+	// DBRecoverySourceObjectStore means the database is recovered from a base backup in the backup object store
+	DBRecoverySourceObjectStore DBRecoverySource = "ObjectStore"
+)
+
 // DBRecoveryStatusType represents the status of database recovery
 type DBRecoveryStatusType string
 
@@ -706,6 +1098,9 @@ const (
 const (
 	ConditionTypeKMSStatus conditionsv1.ConditionType = "KMS-Status"
 	ConditionTypeKMSType   conditionsv1.ConditionType = "KMS-Type"
+
+	// ConditionTypeDBVolumeAutoGrow reports the state of the automatic expansion of the database volume
+	ConditionTypeDBVolumeAutoGrow conditionsv1.ConditionType = "DB-Volume-AutoGrow"
 )
 
 // These are NooBaa condition statuses
@@ -881,6 +1276,21 @@ const (
 	AutoscalerTypeHPAV2 AutoscalerTypes = "hpav2"
 )
 
+// AutoscalerMetricType is a string enum type for the metrics the endpoints can be scaled on
+type AutoscalerMetricType string
+
+// These are the valid AutoscalerMetricType types:
+const (
+	// AutoscalerMetricCPU is the CPU utilization of the endpoint pods
+	AutoscalerMetricCPU AutoscalerMetricType = "cpu"
+	// AutoscalerMetricRequestsPerSecond is the S3 requests per second per endpoint pod
+	AutoscalerMetricRequestsPerSecond AutoscalerMetricType = "requestsPerSecond"
+	// AutoscalerMetricLatencyP95 is the 95th percentile of the S3 request latency
+	AutoscalerMetricLatencyP95 AutoscalerMetricType = "latencyP95"
+	// AutoscalerMetricInflightOps is the S3 operations in flight per endpoint pod
+	AutoscalerMetricInflightOps AutoscalerMetricType = "inflightOps"
+)
+
 // BucketLoggingTypes is a string enum type for specifying the types of bucketlogging supported.
 type BucketLoggingTypes string
 
@@ -70,8 +70,85 @@ type NooBaaAccountSpec struct {
 	// ForceMd5Etag specifies whether MD5 Etags should be calculated for the account or not
 	// +optional
 	ForceMd5Etag *bool `json:"force_md5_etag,omitempty"`
+
+	// Permissions specifies the access of the account to buckets it does not own.
+	// The operator applies it as statements in the bucket policies of these buckets.
+	// +optional
+	Permissions *AccountPermissions `json:"permissions,omitempty"`
+
+	// CredentialRotation specifies a schedule to rotate the S3 access keys of the account
+	// +optional
+	CredentialRotation *CredentialRotationSpec `json:"credential_rotation,omitempty"`
+
+	// STSRole specifies the role that other accounts can assume for this account through the NooBaa STS.
+	// The operator applies it as the role config of the account, and removes it when the field is unset.
+	// +optional
+	STSRole *AccountSTSRole `json:"sts_role,omitempty"`
+}
+
+// AccountSTSRole is the declarative role config of an account
+type AccountSTSRole struct {
+	// RoleName is the name of the role, defaults to the account name
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	RoleName string `json:"role_name,omitempty"`
+
+	// TrustedPrincipals is the list of accounts that are allowed to assume the role
+	// +kubebuilder:validation:MinItems=1
+	TrustedPrincipals []string `json:"trusted_principals"`
+
+	// AllowedActions is the list of STS actions the trusted principals are allowed, defaults to sts:AssumeRole
+	// +optional
+	AllowedActions []string `json:"allowed_actions,omitempty"`
+}
+
+// CredentialRotationSpec specifies the schedule of the S3 access keys rotation
+type CredentialRotationSpec struct {
+	// Schedule is the rotation schedule in cron format, for example "0 0 1 */3 *" to rotate every 3 months.
+	// When the schedule is due, new keys are added to the account and published in the credentials Secret as
+	// NEXT_AWS_ACCESS_KEY_ID and NEXT_AWS_SECRET_ACCESS_KEY. Both keys are valid during the grace period,
+	// and the new keys replace the current keys when it ends.
+	Schedule string `json:"schedule"`
+
+	// GracePeriod is the time both the current and the new keys are valid, defaults to 24h
+	// +optional
+	GracePeriod *metav1.Duration `json:"grace_period,omitempty"`
 }
 
+// AccountPermissions is the declarative access of an account to a list of buckets
+type AccountPermissions struct {
+	// Buckets is the list of buckets the account is allowed to access
+	// +optional
+	Buckets []AccountBucketPermission `json:"buckets,omitempty"`
+
+	// AllowedSourceCIDRs limits the granted access to requests coming from these CIDRs
+	// +optional
+	AllowedSourceCIDRs []string `json:"allowed_source_cidrs,omitempty"`
+}
+
+// AccountBucketPermission grants the account access to a single bucket
+type AccountBucketPermission struct {
+	// BucketName is the name of the noobaa bucket
+	BucketName string `json:"bucket_name"`
+
+	// Access is the access mode granted on the bucket, defaults to read-only
+	// +kubebuilder:validation:Enum=read-only;read-write
+	// +optional
+	Access BucketAccessMode `json:"access,omitempty"`
+}
+
+// BucketAccessMode is a string enum type for the access mode of an account to a bucket
+type BucketAccessMode string
+
+// These are the valid bucket access modes:
+const (
+	// BucketAccessReadOnly allows listing the bucket and reading its objects
+	BucketAccessReadOnly BucketAccessMode = "read-only"
+
+	// BucketAccessReadWrite allows reading, writing and deleting the bucket objects
+	BucketAccessReadWrite BucketAccessMode = "read-write"
+)
+
 // AccountNsfsConfig is the configuration of NSFS of CreateAccountParams
 type AccountNsfsConfig struct {
 	UID               *int   `json:"uid,omitempty"`
@@ -98,6 +175,52 @@ type NooBaaAccountStatus struct {
 	// RelatedObjects is a list of objects related to this operator.
 	// +optional
 	RelatedObjects []corev1.ObjectReference `json:"relatedObjects,omitempty"`
+
+	// PermittedBuckets is the list of buckets whose bucket policy holds the permissions of this account
+	// +optional
+	PermittedBuckets []string `json:"permittedBuckets,omitempty"`
+
+	// CredentialRotation is the state of the scheduled S3 access keys rotation
+	// +optional
+	CredentialRotation *CredentialRotationStatus `json:"credentialRotation,omitempty"`
+
+	// STSRole is the state of the role config that was applied to the account
+	// +optional
+	STSRole *AccountSTSRoleStatus `json:"stsRole,omitempty"`
+}
+
+// AccountSTSRoleStatus is the state of the role config that was applied to the account
+type AccountSTSRoleStatus struct {
+	// RoleName is the name of the applied role
+	RoleName string `json:"roleName"`
+
+	// TrustedPrincipals is the list of accounts that are allowed to assume the applied role
+	// +optional
+	TrustedPrincipals []string `json:"trustedPrincipals,omitempty"`
+
+	// AllowedActions is the list of STS actions of the applied role, empty for the default sts:AssumeRole
+	// +optional
+	AllowedActions []string `json:"allowedActions,omitempty"`
+
+	// LastAppliedTime is the last time the role config was applied to the account
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// CredentialRotationStatus is the state of the scheduled S3 access keys rotation
+type CredentialRotationStatus struct {
+	// LastRotationTime is the last time the account keys were replaced
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// PendingSince is the time the new keys were published in the credentials Secret,
+	// it is set only during the grace period of a rotation
+	// +optional
+	PendingSince *metav1.Time `json:"pendingSince,omitempty"`
+
+	// NextRotationTime is the time the next rotation step is due, publishing new keys or replacing the current keys
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
 }
 
 // NooBaaAccountPhase is a string enum type for backing store reconcile phases
@@ -0,0 +1,97 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Note 1: Run "make gen-api" to regenerate code after modifying this file
+// Note 2: Add custom validation using kubebuilder tags: https://book.kubebuilder.io/reference/generating-crd.html
+
+func init() {
+	SchemeBuilder.Register(&NooBaaPerformanceProfile{}, &NooBaaPerformanceProfileList{})
+}
+
+// NooBaaPerformanceProfile is the Schema for the noobaaperformanceprofiles API.
+// It defines a cluster wide bundle of resource and count settings that can be selected
+// by name in NooBaaSpec.PerformanceProfile, in addition to the built-in profiles.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Endpoint-Min",type="integer",JSONPath=".spec.endpointMinCount",description="Endpoint-Min"
+// +kubebuilder:printcolumn:name="Endpoint-Max",type="integer",JSONPath=".spec.endpointMaxCount",description="Endpoint-Max"
+// +kubebuilder:printcolumn:name="DB-Instances",type="integer",JSONPath=".spec.dbInstances",description="DB-Instances"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type NooBaaPerformanceProfile struct {
+
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the resource and count settings of the profile.
+	// +optional
+	Spec NooBaaPerformanceProfileSpec `json:"spec,omitempty"`
+}
+
+// NooBaaPerformanceProfileList contains a list of NooBaaPerformanceProfile
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NooBaaPerformanceProfileList struct {
+
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of NooBaaPerformanceProfiles.
+	Items []NooBaaPerformanceProfile `json:"items"`
+}
+
+// NooBaaPerformanceProfileSpec defines the settings of a performance profile.
+// Fields that are not set are taken from the built-in "default" profile.
+// +k8s:openapi-gen=true
+type NooBaaPerformanceProfileSpec struct {
+
+	// CoreResources (optional) the resource requirements of the core container
+	// +optional
+	CoreResources *corev1.ResourceRequirements `json:"coreResources,omitempty"`
+
+	// LogResources (optional) the resource requirements of the log sidecar container
+	// +optional
+	LogResources *corev1.ResourceRequirements `json:"logResources,omitempty"`
+
+	// DBResources (optional) the resource requirements of the db instances
+	// +optional
+	DBResources *corev1.ResourceRequirements `json:"dbResources,omitempty"`
+
+	// EndpointResources (optional) the resource requirements of the endpoint containers
+	// +optional
+	EndpointResources *corev1.ResourceRequirements `json:"endpointResources,omitempty"`
+
+	// PVPoolResources (optional) the resource requirements of the pv-pool backingstore pods
+	// +optional
+	PVPoolResources *corev1.ResourceRequirements `json:"pvPoolResources,omitempty"`
+
+	// EndpointMinCount (optional) the minimal number of endpoint pods
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	EndpointMinCount int32 `json:"endpointMinCount,omitempty"`
+
+	// EndpointMaxCount (optional) the maximal number of endpoint pods
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	EndpointMaxCount int32 `json:"endpointMaxCount,omitempty"`
+
+	// DBInstances (optional) the number of db instances
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DBInstances int `json:"dbInstances,omitempty"`
+
+	// PVPoolNumVolumes (optional) the number of volumes of the default pv-pool backingstore
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PVPoolNumVolumes int `json:"pvPoolNumVolumes,omitempty"`
+}
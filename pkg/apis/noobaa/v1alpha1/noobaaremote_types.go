@@ -0,0 +1,185 @@
+package v1alpha1
+
+import (
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Note 1: Run "make gen-api" to regenerate code after modifying this file
+// Note 2: Add custom validation using kubebuilder tags: https://book.kubebuilder.io/reference/generating-crd.html
+
+func init() {
+	SchemeBuilder.Register(&NooBaaRemote{}, &NooBaaRemoteList{})
+}
+
+// NooBaaRemote is the Schema for the noobaaremotes API.
+// It federates a remote NooBaa system into the local system by exposing remote buckets as namespace stores.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="S3-Endpoint",type="string",JSONPath=".spec.s3Endpoint",description="S3-Endpoint"
+// +kubebuilder:printcolumn:name="Reachable",type="boolean",JSONPath=".status.reachability.s3Reachable",description="Reachable"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type NooBaaRemote struct {
+
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the noobaa NooBaaRemote.
+	// +optional
+	Spec NooBaaRemoteSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the noobaa NooBaaRemote.
+	// +optional
+	Status NooBaaRemoteStatus `json:"status,omitempty"`
+}
+
+// NooBaaRemoteList contains a list of NooBaaRemote
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NooBaaRemoteList struct {
+
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of NooBaaRemotes.
+	Items []NooBaaRemote `json:"items"`
+}
+
+// NooBaaRemoteSpec defines the desired state of NooBaaRemote
+// +k8s:openapi-gen=true
+type NooBaaRemoteSpec struct {
+
+	// MgmtEndpoint is the management endpoint of the remote noobaa system: https://host:port
+	MgmtEndpoint string `json:"mgmtEndpoint"`
+
+	// S3Endpoint is the S3 endpoint of the remote noobaa system: https://host:port
+	S3Endpoint string `json:"s3Endpoint"`
+
+	// Secret refers to a secret that provides the S3 credentials of an account on the remote system
+	// The secret should define AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	Secret corev1.SecretReference `json:"secret"`
+
+	// SignatureVersion specifies the client signature version to use when signing requests.
+	// +optional
+	SignatureVersion S3SignatureVersion `json:"signatureVersion,omitempty"`
+
+	// Buckets is the list of remote buckets exposed on the local system as namespace stores
+	// +optional
+	Buckets []NooBaaRemoteBucket `json:"buckets,omitempty"`
+}
+
+// NooBaaRemoteBucket specifies a remote bucket exposed on the local system
+type NooBaaRemoteBucket struct {
+
+	// Name is the name of the bucket on the remote system
+	Name string `json:"name"`
+
+	// NamespaceStore is the name of the local namespace store created for the bucket.
+	// Defaults to <remote-name>-<bucket-name>
+	// +optional
+	NamespaceStore string `json:"namespaceStore,omitempty"`
+
+	// AccessMode is the access mode of the namespace store
+	// +kubebuilder:validation:Enum=ReadWrite;ReadOnly
+	// +optional
+	AccessMode AccessModeType `json:"accessMode,omitempty"`
+}
+
+// NooBaaRemoteStatus defines the observed state of NooBaaRemote
+// +k8s:openapi-gen=true
+type NooBaaRemoteStatus struct {
+
+	// Phase is a simple, high-level summary of where the NooBaaRemote is in its lifecycle
+	// +optional
+	Phase NooBaaRemotePhase `json:"phase,omitempty"`
+
+	// Conditions is a list of conditions related to operator reconciliation
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +optional
+	Conditions []conditionsv1.Condition `json:"conditions,omitempty"  patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Reachability is the result of the last health check of the remote system
+	// +optional
+	Reachability NooBaaRemoteReachability `json:"reachability,omitempty"`
+
+	// NamespaceStores is the list of namespace stores created for the remote buckets
+	// +optional
+	NamespaceStores []NooBaaRemoteNamespaceStore `json:"namespaceStores,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// NooBaaRemoteReachability reports the reachability of the remote system endpoints
+type NooBaaRemoteReachability struct {
+
+	// MgmtReachable is true when the management endpoint responded to the operator
+	// +optional
+	MgmtReachable bool `json:"mgmtReachable"`
+
+	// S3Reachable is true when the local noobaa system connected to the S3 endpoint with the credentials
+	// +optional
+	S3Reachable bool `json:"s3Reachable"`
+
+	// S3Status is the external connection status returned by the local noobaa system for the S3 endpoint
+	// +optional
+	S3Status string `json:"s3Status,omitempty"`
+
+	// Message describes the failure of the last check
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastCheckTime is the last time the remote system was checked
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// NooBaaRemoteNamespaceStore reports a namespace store created for a remote bucket
+type NooBaaRemoteNamespaceStore struct {
+
+	// Name is the name of the namespace store
+	Name string `json:"name"`
+
+	// Bucket is the name of the bucket on the remote system
+	Bucket string `json:"bucket"`
+
+	// Phase is the phase of the namespace store
+	// +optional
+	Phase NamespaceStorePhase `json:"phase,omitempty"`
+}
+
+// NooBaaRemotePhase is a string enum type for noobaa remote reconcile phases
+type NooBaaRemotePhase string
+
+// These are the valid phases:
+const (
+
+	// NooBaaRemotePhaseRejected means the spec has been rejected by the operator,
+	// this is most likely due to an incompatible configuration.
+	// Use describe to see events.
+	NooBaaRemotePhaseRejected NooBaaRemotePhase = "Rejected"
+
+	// NooBaaRemotePhaseVerifying means the operator is verifying the spec and checking the remote system
+	NooBaaRemotePhaseVerifying NooBaaRemotePhase = "Verifying"
+
+	// NooBaaRemotePhaseConfiguring means the operator is creating the namespace stores of the remote buckets
+	NooBaaRemotePhaseConfiguring NooBaaRemotePhase = "Configuring"
+
+	// NooBaaRemotePhaseReady means the remote system is reachable and its namespace stores are configured
+	NooBaaRemotePhaseReady NooBaaRemotePhase = "Ready"
+
+	// NooBaaRemotePhaseUnreachable means the remote system failed the last health check,
+	// the namespace stores are kept and the check is retried periodically
+	NooBaaRemotePhaseUnreachable NooBaaRemotePhase = "Unreachable"
+)
@@ -12,6 +12,8 @@ package v1alpha1
 import (
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -37,6 +39,22 @@ func (in *AWSS3Spec) DeepCopy() *AWSS3Spec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountBucketPermission) DeepCopyInto(out *AccountBucketPermission) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountBucketPermission.
+func (in *AccountBucketPermission) DeepCopy() *AccountBucketPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountBucketPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountNsfsConfig) DeepCopyInto(out *AccountNsfsConfig) {
 	*out = *in
@@ -63,6 +81,88 @@ func (in *AccountNsfsConfig) DeepCopy() *AccountNsfsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountPermissions) DeepCopyInto(out *AccountPermissions) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]AccountBucketPermission, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedSourceCIDRs != nil {
+		in, out := &in.AllowedSourceCIDRs, &out.AllowedSourceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountPermissions.
+func (in *AccountPermissions) DeepCopy() *AccountPermissions {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountPermissions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountSTSRole) DeepCopyInto(out *AccountSTSRole) {
+	*out = *in
+	if in.TrustedPrincipals != nil {
+		in, out := &in.TrustedPrincipals, &out.TrustedPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedActions != nil {
+		in, out := &in.AllowedActions, &out.AllowedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountSTSRole.
+func (in *AccountSTSRole) DeepCopy() *AccountSTSRole {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountSTSRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountSTSRoleStatus) DeepCopyInto(out *AccountSTSRoleStatus) {
+	*out = *in
+	if in.TrustedPrincipals != nil {
+		in, out := &in.TrustedPrincipals, &out.TrustedPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedActions != nil {
+		in, out := &in.AllowedActions, &out.AllowedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountSTSRoleStatus.
+func (in *AccountSTSRoleStatus) DeepCopy() *AccountSTSRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountSTSRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountsStatus) DeepCopyInto(out *AccountsStatus) {
 	*out = *in
@@ -181,9 +281,35 @@ func (in *ArchivePolicy) DeepCopy() *ArchivePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalerMetric) DeepCopyInto(out *AutoscalerMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerMetric.
+func (in *AutoscalerMetric) DeepCopy() *AutoscalerMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalerMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoscalerSpec) DeepCopyInto(out *AutoscalerSpec) {
 	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AutoscalerMetric, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScaleDownStabilizationWindowSeconds != nil {
+		in, out := &in.ScaleDownStabilizationWindowSeconds, &out.ScaleDownStabilizationWindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -373,6 +499,13 @@ func (in *BackingStoreStatus) DeepCopyInto(out *BackingStoreStatus) {
 		copy(*out, *in)
 	}
 	out.Mode = in.Mode
+	if in.PVPoolDecommission != nil {
+		in, out := &in.PVPoolDecommission, &out.PVPoolDecommission
+		*out = make([]PVPoolDecommissionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -475,6 +608,11 @@ func (in *BucketClassSpec) DeepCopyInto(out *BucketClassSpec) {
 		*out = new(ArchivePolicy)
 		**out = **in
 	}
+	if in.LifecyclePolicy != nil {
+		in, out := &in.LifecyclePolicy, &out.LifecyclePolicy
+		*out = new(LifecyclePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -503,6 +641,11 @@ func (in *BucketClassStatus) DeepCopyInto(out *BucketClassStatus) {
 		*out = make([]v1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.LifecyclePolicy != nil {
+		in, out := &in.LifecyclePolicy, &out.LifecyclePolicy
+		*out = new(LifecyclePolicyStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -564,532 +707,1134 @@ func (in *BucketNotificationsSpec) DeepCopy() *BucketNotificationsSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CacheNamespacePolicy) DeepCopyInto(out *CacheNamespacePolicy) {
+func (in *BucketReplication) DeepCopyInto(out *BucketReplication) {
 	*out = *in
-	if in.Caching != nil {
-		in, out := &in.Caching, &out.Caching
-		*out = new(CacheSpec)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheNamespacePolicy.
-func (in *CacheNamespacePolicy) DeepCopy() *CacheNamespacePolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketReplication.
+func (in *BucketReplication) DeepCopy() *BucketReplication {
 	if in == nil {
 		return nil
 	}
-	out := new(CacheNamespacePolicy)
+	out := new(BucketReplication)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BucketReplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CacheSpec) DeepCopyInto(out *CacheSpec) {
+func (in *BucketReplicationList) DeepCopyInto(out *BucketReplicationList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BucketReplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheSpec.
-func (in *CacheSpec) DeepCopy() *CacheSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketReplicationList.
+func (in *BucketReplicationList) DeepCopy() *BucketReplicationList {
 	if in == nil {
 		return nil
 	}
-	out := new(CacheSpec)
+	out := new(BucketReplicationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BucketReplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
+func (in *BucketReplicationRule) DeepCopyInto(out *BucketReplicationRule) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicySpec.
-func (in *CleanupPolicySpec) DeepCopy() *CleanupPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketReplicationRule.
+func (in *BucketReplicationRule) DeepCopy() *BucketReplicationRule {
 	if in == nil {
 		return nil
 	}
-	out := new(CleanupPolicySpec)
+	out := new(BucketReplicationRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DBBackupSpec) DeepCopyInto(out *DBBackupSpec) {
+func (in *BucketReplicationSource) DeepCopyInto(out *BucketReplicationSource) {
 	*out = *in
-	if in.VolumeSnapshot != nil {
-		in, out := &in.VolumeSnapshot, &out.VolumeSnapshot
-		*out = new(VolumeSnapshotBackupSpec)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBBackupSpec.
-func (in *DBBackupSpec) DeepCopy() *DBBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketReplicationSource.
+func (in *BucketReplicationSource) DeepCopy() *BucketReplicationSource {
 	if in == nil {
 		return nil
 	}
-	out := new(DBBackupSpec)
+	out := new(BucketReplicationSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DBBackupStatus) DeepCopyInto(out *DBBackupStatus) {
+func (in *BucketReplicationSpec) DeepCopyInto(out *BucketReplicationSpec) {
 	*out = *in
-	if in.LastBackupTime != nil {
-		in, out := &in.LastBackupTime, &out.LastBackupTime
-		*out = (*in).DeepCopy()
-	}
-	if in.NextBackupTime != nil {
-		in, out := &in.NextBackupTime, &out.NextBackupTime
-		*out = (*in).DeepCopy()
-	}
-	if in.AvailableSnapshots != nil {
-		in, out := &in.AvailableSnapshots, &out.AvailableSnapshots
-		*out = make([]string, len(*in))
+	out.Source = in.Source
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]BucketReplicationRule, len(*in))
 		copy(*out, *in)
 	}
+	if in.LogReplicationSource != nil {
+		in, out := &in.LogReplicationSource, &out.LogReplicationSource
+		*out = new(LogReplicationSource)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBBackupStatus.
-func (in *DBBackupStatus) DeepCopy() *DBBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketReplicationSpec.
+func (in *BucketReplicationSpec) DeepCopy() *BucketReplicationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DBBackupStatus)
+	out := new(BucketReplicationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DBRecoverySpec) DeepCopyInto(out *DBRecoverySpec) {
+func (in *BucketReplicationStatus) DeepCopyInto(out *BucketReplicationStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]conditionsv1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBRecoverySpec.
-func (in *DBRecoverySpec) DeepCopy() *DBRecoverySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketReplicationStatus.
+func (in *BucketReplicationStatus) DeepCopy() *BucketReplicationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DBRecoverySpec)
+	out := new(BucketReplicationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DBRecoveryStatus) DeepCopyInto(out *DBRecoveryStatus) {
+func (in *CacheNamespacePolicy) DeepCopyInto(out *CacheNamespacePolicy) {
 	*out = *in
-	if in.RecoveryTime != nil {
-		in, out := &in.RecoveryTime, &out.RecoveryTime
-		*out = (*in).DeepCopy()
+	if in.Caching != nil {
+		in, out := &in.Caching, &out.Caching
+		*out = new(CacheSpec)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBRecoveryStatus.
-func (in *DBRecoveryStatus) DeepCopy() *DBRecoveryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheNamespacePolicy.
+func (in *CacheNamespacePolicy) DeepCopy() *CacheNamespacePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(DBRecoveryStatus)
+	out := new(CacheNamespacePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EndpointsSpec) DeepCopyInto(out *EndpointsSpec) {
+func (in *CacheSpec) DeepCopyInto(out *CacheSpec) {
 	*out = *in
-	if in.AdditionalVirtualHosts != nil {
-		in, out := &in.AdditionalVirtualHosts, &out.AdditionalVirtualHosts
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointsSpec.
-func (in *EndpointsSpec) DeepCopy() *EndpointsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheSpec.
+func (in *CacheSpec) DeepCopy() *CacheSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointsSpec)
+	out := new(CacheSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EndpointsStatus) DeepCopyInto(out *EndpointsStatus) {
+func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
 	*out = *in
-	if in.VirtualHosts != nil {
-		in, out := &in.VirtualHosts, &out.VirtualHosts
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointsStatus.
-func (in *EndpointsStatus) DeepCopy() *EndpointsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicySpec.
+func (in *CleanupPolicySpec) DeepCopy() *CleanupPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointsStatus)
+	out := new(CleanupPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleCloudStorageSpec) DeepCopyInto(out *GoogleCloudStorageSpec) {
+func (in *CredentialRotationSpec) DeepCopyInto(out *CredentialRotationSpec) {
 	*out = *in
-	out.Secret = in.Secret
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCloudStorageSpec.
-func (in *GoogleCloudStorageSpec) DeepCopy() *GoogleCloudStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationSpec.
+func (in *CredentialRotationSpec) DeepCopy() *CredentialRotationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleCloudStorageSpec)
+	out := new(CredentialRotationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IBMCosSpec) DeepCopyInto(out *IBMCosSpec) {
+func (in *CredentialRotationStatus) DeepCopyInto(out *CredentialRotationStatus) {
 	*out = *in
-	out.Secret = in.Secret
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingSince != nil {
+		in, out := &in.PendingSince, &out.PendingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRotationTime != nil {
+		in, out := &in.NextRotationTime, &out.NextRotationTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMCosSpec.
-func (in *IBMCosSpec) DeepCopy() *IBMCosSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationStatus.
+func (in *CredentialRotationStatus) DeepCopy() *CredentialRotationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IBMCosSpec)
+	out := new(CredentialRotationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyManagementServiceSpec) DeepCopyInto(out *KeyManagementServiceSpec) {
+func (in *DBAutoGrowSpec) DeepCopyInto(out *DBAutoGrowSpec) {
 	*out = *in
-	if in.ConnectionDetails != nil {
-		in, out := &in.ConnectionDetails, &out.ConnectionDetails
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyManagementServiceSpec.
-func (in *KeyManagementServiceSpec) DeepCopy() *KeyManagementServiceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBAutoGrowSpec.
+func (in *DBAutoGrowSpec) DeepCopy() *DBAutoGrowSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeyManagementServiceSpec)
+	out := new(DBAutoGrowSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Labels) DeepCopyInto(out *Labels) {
-	{
-		in := &in
-		*out = make(Labels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-		return
+func (in *DBBackupSpec) DeepCopyInto(out *DBBackupSpec) {
+	*out = *in
+	if in.VolumeSnapshot != nil {
+		in, out := &in.VolumeSnapshot, &out.VolumeSnapshot
+		*out = new(VolumeSnapshotBackupSpec)
+		**out = **in
+	}
+	if in.ObjectStore != nil {
+		in, out := &in.ObjectStore, &out.ObjectStore
+		*out = new(ObjectStoreBackupSpec)
+		**out = **in
 	}
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Labels.
-func (in Labels) DeepCopy() Labels {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBBackupSpec.
+func (in *DBBackupSpec) DeepCopy() *DBBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Labels)
+	out := new(DBBackupSpec)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in LabelsSpec) DeepCopyInto(out *LabelsSpec) {
-	{
-		in := &in
-		*out = make(LabelsSpec, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(Labels, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
+func (in *DBBackupStatus) DeepCopyInto(out *DBBackupStatus) {
+	*out = *in
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextBackupTime != nil {
+		in, out := &in.NextBackupTime, &out.NextBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AvailableSnapshots != nil {
+		in, out := &in.AvailableSnapshots, &out.AvailableSnapshots
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]DBObjectStoreBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
-		return
 	}
+	if in.FirstRecoverabilityPoint != nil {
+		in, out := &in.FirstRecoverabilityPoint, &out.FirstRecoverabilityPoint
+		*out = (*in).DeepCopy()
+	}
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelsSpec.
-func (in LabelsSpec) DeepCopy() LabelsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBBackupStatus.
+func (in *DBBackupStatus) DeepCopy() *DBBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LabelsSpec)
+	out := new(DBBackupStatus)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadBalancerSourceSubnetSpec) DeepCopyInto(out *LoadBalancerSourceSubnetSpec) {
+func (in *DBObjectStoreBackup) DeepCopyInto(out *DBObjectStoreBackup) {
 	*out = *in
-	if in.S3 != nil {
-		in, out := &in.S3, &out.S3
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.STS != nil {
-		in, out := &in.STS, &out.STS
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.IAM != nil {
-		in, out := &in.IAM, &out.IAM
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.Vectors != nil {
-		in, out := &in.Vectors, &out.Vectors
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerSourceSubnetSpec.
-func (in *LoadBalancerSourceSubnetSpec) DeepCopy() *LoadBalancerSourceSubnetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBObjectStoreBackup.
+func (in *DBObjectStoreBackup) DeepCopy() *DBObjectStoreBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadBalancerSourceSubnetSpec)
+	out := new(DBObjectStoreBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultiNamespacePolicy) DeepCopyInto(out *MultiNamespacePolicy) {
+func (in *DBRecoverySpec) DeepCopyInto(out *DBRecoverySpec) {
 	*out = *in
-	if in.ReadResources != nil {
-		in, out := &in.ReadResources, &out.ReadResources
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.RecoveryTarget != nil {
+		in, out := &in.RecoveryTarget, &out.RecoveryTarget
+		*out = new(DBRecoveryTargetSpec)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiNamespacePolicy.
-func (in *MultiNamespacePolicy) DeepCopy() *MultiNamespacePolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBRecoverySpec.
+func (in *DBRecoverySpec) DeepCopy() *DBRecoverySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MultiNamespacePolicy)
+	out := new(DBRecoverySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSFSSpec) DeepCopyInto(out *NSFSSpec) {
+func (in *DBRecoveryStatus) DeepCopyInto(out *DBRecoveryStatus) {
 	*out = *in
+	if in.RecoveryTarget != nil {
+		in, out := &in.RecoveryTarget, &out.RecoveryTarget
+		*out = new(DBRecoveryTargetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecoveryTime != nil {
+		in, out := &in.RecoveryTime, &out.RecoveryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSFSSpec.
-func (in *NSFSSpec) DeepCopy() *NSFSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBRecoveryStatus.
+func (in *DBRecoveryStatus) DeepCopy() *DBRecoveryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NSFSSpec)
+	out := new(DBRecoveryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespacePolicy) DeepCopyInto(out *NamespacePolicy) {
+func (in *DBRecoveryTargetSpec) DeepCopyInto(out *DBRecoveryTargetSpec) {
 	*out = *in
-	if in.Single != nil {
-		in, out := &in.Single, &out.Single
-		*out = new(SingleNamespacePolicy)
+	if in.Exclusive != nil {
+		in, out := &in.Exclusive, &out.Exclusive
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Multi != nil {
-		in, out := &in.Multi, &out.Multi
-		*out = new(MultiNamespacePolicy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Cache != nil {
-		in, out := &in.Cache, &out.Cache
-		*out = new(CacheNamespacePolicy)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePolicy.
-func (in *NamespacePolicy) DeepCopy() *NamespacePolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DBRecoveryTargetSpec.
+func (in *DBRecoveryTargetSpec) DeepCopy() *DBRecoveryTargetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespacePolicy)
+	out := new(DBRecoveryTargetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceStore) DeepCopyInto(out *NamespaceStore) {
+func (in *EndpointsSpec) DeepCopyInto(out *EndpointsSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.AdditionalVirtualHosts != nil {
+		in, out := &in.AdditionalVirtualHosts, &out.AdditionalVirtualHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStore.
-func (in *NamespaceStore) DeepCopy() *NamespaceStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointsSpec.
+func (in *EndpointsSpec) DeepCopy() *EndpointsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceStore)
+	out := new(EndpointsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NamespaceStore) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceStoreList) DeepCopyInto(out *NamespaceStoreList) {
+func (in *EndpointsStatus) DeepCopyInto(out *EndpointsStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]NamespaceStore, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.VirtualHosts != nil {
+		in, out := &in.VirtualHosts, &out.VirtualHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreList.
-func (in *NamespaceStoreList) DeepCopy() *NamespaceStoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointsStatus.
+func (in *EndpointsStatus) DeepCopy() *EndpointsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceStoreList)
+	out := new(EndpointsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NamespaceStoreList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleCloudStorageSpec) DeepCopyInto(out *GoogleCloudStorageSpec) {
+	*out = *in
+	out.Secret = in.Secret
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCloudStorageSpec.
+func (in *GoogleCloudStorageSpec) DeepCopy() *GoogleCloudStorageSpec {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(GoogleCloudStorageSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceStoreMode) DeepCopyInto(out *NamespaceStoreMode) {
+func (in *IBMCosSpec) DeepCopyInto(out *IBMCosSpec) {
 	*out = *in
+	out.Secret = in.Secret
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreMode.
-func (in *NamespaceStoreMode) DeepCopy() *NamespaceStoreMode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IBMCosSpec.
+func (in *IBMCosSpec) DeepCopy() *IBMCosSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceStoreMode)
+	out := new(IBMCosSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceStoreSpec) DeepCopyInto(out *NamespaceStoreSpec) {
+func (in *KeyManagementServiceSpec) DeepCopyInto(out *KeyManagementServiceSpec) {
 	*out = *in
-	if in.AWSS3 != nil {
-		in, out := &in.AWSS3, &out.AWSS3
-		*out = new(AWSS3Spec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.S3Compatible != nil {
-		in, out := &in.S3Compatible, &out.S3Compatible
-		*out = new(S3CompatibleSpec)
-		**out = **in
-	}
-	if in.IBMCos != nil {
-		in, out := &in.IBMCos, &out.IBMCos
-		*out = new(IBMCosSpec)
-		**out = **in
-	}
-	if in.AzureBlob != nil {
-		in, out := &in.AzureBlob, &out.AzureBlob
-		*out = new(AzureBlobSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.GoogleCloudStorage != nil {
-		in, out := &in.GoogleCloudStorage, &out.GoogleCloudStorage
-		*out = new(GoogleCloudStorageSpec)
-		**out = **in
-	}
-	if in.NSFS != nil {
-		in, out := &in.NSFS, &out.NSFS
-		*out = new(NSFSSpec)
-		**out = **in
+	if in.ConnectionDetails != nil {
+		in, out := &in.ConnectionDetails, &out.ConnectionDetails
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreSpec.
-func (in *NamespaceStoreSpec) DeepCopy() *NamespaceStoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyManagementServiceSpec.
+func (in *KeyManagementServiceSpec) DeepCopy() *KeyManagementServiceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceStoreSpec)
+	out := new(KeyManagementServiceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceStoreStatus) DeepCopyInto(out *NamespaceStoreStatus) {
-	*out = *in
+func (in Labels) DeepCopyInto(out *Labels) {
+	{
+		in := &in
+		*out = make(Labels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Labels.
+func (in Labels) DeepCopy() Labels {
+	if in == nil {
+		return nil
+	}
+	out := new(Labels)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in LabelsSpec) DeepCopyInto(out *LabelsSpec) {
+	{
+		in := &in
+		*out = make(LabelsSpec, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(Labels, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelsSpec.
+func (in LabelsSpec) DeepCopy() LabelsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelsSpec)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecyclePolicy) DeepCopyInto(out *LifecyclePolicy) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]LifecycleRule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecyclePolicy.
+func (in *LifecyclePolicy) DeepCopy() *LifecyclePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecyclePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecyclePolicyStatus) DeepCopyInto(out *LifecyclePolicyStatus) {
+	*out = *in
+	if in.FailedBuckets != nil {
+		in, out := &in.FailedBuckets, &out.FailedBuckets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecyclePolicyStatus.
+func (in *LifecyclePolicyStatus) DeepCopy() *LifecyclePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecyclePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleRule) DeepCopyInto(out *LifecycleRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleRule.
+func (in *LifecycleRule) DeepCopy() *LifecycleRule {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerSourceSubnetSpec) DeepCopyInto(out *LoadBalancerSourceSubnetSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.STS != nil {
+		in, out := &in.STS, &out.STS
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IAM != nil {
+		in, out := &in.IAM, &out.IAM
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Vectors != nil {
+		in, out := &in.Vectors, &out.Vectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerSourceSubnetSpec.
+func (in *LoadBalancerSourceSubnetSpec) DeepCopy() *LoadBalancerSourceSubnetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerSourceSubnetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogReplicationSource) DeepCopyInto(out *LogReplicationSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogReplicationSource.
+func (in *LogReplicationSource) DeepCopy() *LogReplicationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(LogReplicationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiNamespacePolicy) DeepCopyInto(out *MultiNamespacePolicy) {
+	*out = *in
+	if in.ReadResources != nil {
+		in, out := &in.ReadResources, &out.ReadResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiNamespacePolicy.
+func (in *MultiNamespacePolicy) DeepCopy() *MultiNamespacePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiNamespacePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSFSSpec) DeepCopyInto(out *NSFSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSFSSpec.
+func (in *NSFSSpec) DeepCopy() *NSFSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NSFSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePolicy) DeepCopyInto(out *NamespacePolicy) {
+	*out = *in
+	if in.Single != nil {
+		in, out := &in.Single, &out.Single
+		*out = new(SingleNamespacePolicy)
+		**out = **in
+	}
+	if in.Multi != nil {
+		in, out := &in.Multi, &out.Multi
+		*out = new(MultiNamespacePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(CacheNamespacePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePolicy.
+func (in *NamespacePolicy) DeepCopy() *NamespacePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceStore) DeepCopyInto(out *NamespaceStore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStore.
+func (in *NamespaceStore) DeepCopy() *NamespaceStore {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceStore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceStoreList) DeepCopyInto(out *NamespaceStoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreList.
+func (in *NamespaceStoreList) DeepCopy() *NamespaceStoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceStoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceStoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceStoreMode) DeepCopyInto(out *NamespaceStoreMode) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreMode.
+func (in *NamespaceStoreMode) DeepCopy() *NamespaceStoreMode {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceStoreMode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceStoreSpec) DeepCopyInto(out *NamespaceStoreSpec) {
+	*out = *in
+	if in.AWSS3 != nil {
+		in, out := &in.AWSS3, &out.AWSS3
+		*out = new(AWSS3Spec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3Compatible != nil {
+		in, out := &in.S3Compatible, &out.S3Compatible
+		*out = new(S3CompatibleSpec)
+		**out = **in
+	}
+	if in.IBMCos != nil {
+		in, out := &in.IBMCos, &out.IBMCos
+		*out = new(IBMCosSpec)
+		**out = **in
+	}
+	if in.AzureBlob != nil {
+		in, out := &in.AzureBlob, &out.AzureBlob
+		*out = new(AzureBlobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoogleCloudStorage != nil {
+		in, out := &in.GoogleCloudStorage, &out.GoogleCloudStorage
+		*out = new(GoogleCloudStorageSpec)
+		**out = **in
+	}
+	if in.NSFS != nil {
+		in, out := &in.NSFS, &out.NSFS
+		*out = new(NSFSSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreSpec.
+func (in *NamespaceStoreSpec) DeepCopy() *NamespaceStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceStoreStatus) DeepCopyInto(out *NamespaceStoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]conditionsv1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RelatedObjects != nil {
+		in, out := &in.RelatedObjects, &out.RelatedObjects
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	out.Mode = in.Mode
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreStatus.
+func (in *NamespaceStoreStatus) DeepCopy() *NamespaceStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyEgressSpec) DeepCopyInto(out *NetworkPolicyEgressSpec) {
+	*out = *in
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedHosts != nil {
+		in, out := &in.AllowedHosts, &out.AllowedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyEgressSpec.
+func (in *NetworkPolicyEgressSpec) DeepCopy() *NetworkPolicyEgressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyEgressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyIngressRule) DeepCopyInto(out *NetworkPolicyIngressRule) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]networkingv1.NetworkPolicyPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyIngressRule.
+func (in *NetworkPolicyIngressRule) DeepCopy() *NetworkPolicyIngressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyIngressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+	if in.AdditionalIngress != nil {
+		in, out := &in.AdditionalIngress, &out.AdditionalIngress
+		*out = make([]NetworkPolicyIngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(NetworkPolicyEgressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaa) DeepCopyInto(out *NooBaa) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaa.
+func (in *NooBaa) DeepCopy() *NooBaa {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaa)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NooBaa) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaAccount) DeepCopyInto(out *NooBaaAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccount.
+func (in *NooBaaAccount) DeepCopy() *NooBaaAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NooBaaAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaAccountList) DeepCopyInto(out *NooBaaAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NooBaaAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccountList.
+func (in *NooBaaAccountList) DeepCopy() *NooBaaAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NooBaaAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaAccountSpec) DeepCopyInto(out *NooBaaAccountSpec) {
+	*out = *in
+	if in.NsfsAccountConfig != nil {
+		in, out := &in.NsfsAccountConfig, &out.NsfsAccountConfig
+		*out = new(AccountNsfsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ForceMd5Etag != nil {
+		in, out := &in.ForceMd5Etag, &out.ForceMd5Etag
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = new(AccountPermissions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialRotation != nil {
+		in, out := &in.CredentialRotation, &out.CredentialRotation
+		*out = new(CredentialRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.STSRole != nil {
+		in, out := &in.STSRole, &out.STSRole
+		*out = new(AccountSTSRole)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccountSpec.
+func (in *NooBaaAccountSpec) DeepCopy() *NooBaaAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaAccountStatus) DeepCopyInto(out *NooBaaAccountStatus) {
+	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]conditionsv1.Condition, len(*in))
@@ -1097,27 +1842,272 @@ func (in *NamespaceStoreStatus) DeepCopyInto(out *NamespaceStoreStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.RelatedObjects != nil {
-		in, out := &in.RelatedObjects, &out.RelatedObjects
-		*out = make([]v1.ObjectReference, len(*in))
-		copy(*out, *in)
+	if in.RelatedObjects != nil {
+		in, out := &in.RelatedObjects, &out.RelatedObjects
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PermittedBuckets != nil {
+		in, out := &in.PermittedBuckets, &out.PermittedBuckets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialRotation != nil {
+		in, out := &in.CredentialRotation, &out.CredentialRotation
+		*out = new(CredentialRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.STSRole != nil {
+		in, out := &in.STSRole, &out.STSRole
+		*out = new(AccountSTSRoleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccountStatus.
+func (in *NooBaaAccountStatus) DeepCopy() *NooBaaAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaDBSpec) DeepCopyInto(out *NooBaaDBSpec) {
+	*out = *in
+	if in.DBImage != nil {
+		in, out := &in.DBImage, &out.DBImage
+		*out = new(string)
+		**out = **in
+	}
+	if in.PostgresMajorVersion != nil {
+		in, out := &in.PostgresMajorVersion, &out.PostgresMajorVersion
+		*out = new(int)
+		**out = **in
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(int)
+		**out = **in
+	}
+	if in.DBResources != nil {
+		in, out := &in.DBResources, &out.DBResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DBStorageClass != nil {
+		in, out := &in.DBStorageClass, &out.DBStorageClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.DBConf != nil {
+		in, out := &in.DBConf, &out.DBConf
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DBAutoGrow != nil {
+		in, out := &in.DBAutoGrow, &out.DBAutoGrow
+		*out = new(DBAutoGrowSpec)
+		**out = **in
+	}
+	if in.DBBackup != nil {
+		in, out := &in.DBBackup, &out.DBBackup
+		*out = new(DBBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DBRecovery != nil {
+		in, out := &in.DBRecovery, &out.DBRecovery
+		*out = new(DBRecoverySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaDBSpec.
+func (in *NooBaaDBSpec) DeepCopy() *NooBaaDBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaDBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaDBStatus) DeepCopyInto(out *NooBaaDBStatus) {
+	*out = *in
+	if in.VolumeUsageCheckTime != nil {
+		in, out := &in.VolumeUsageCheckTime, &out.VolumeUsageCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAutoGrowTime != nil {
+		in, out := &in.LastAutoGrowTime, &out.LastAutoGrowTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BackupStatus != nil {
+		in, out := &in.BackupStatus, &out.BackupStatus
+		*out = new(DBBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecoveryStatus != nil {
+		in, out := &in.RecoveryStatus, &out.RecoveryStatus
+		*out = new(DBRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaDBStatus.
+func (in *NooBaaDBStatus) DeepCopy() *NooBaaDBStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaDBStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaList) DeepCopyInto(out *NooBaaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NooBaa, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaList.
+func (in *NooBaaList) DeepCopy() *NooBaaList {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NooBaaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaPerformanceProfile) DeepCopyInto(out *NooBaaPerformanceProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaPerformanceProfile.
+func (in *NooBaaPerformanceProfile) DeepCopy() *NooBaaPerformanceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaPerformanceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NooBaaPerformanceProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaPerformanceProfileList) DeepCopyInto(out *NooBaaPerformanceProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NooBaaPerformanceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaPerformanceProfileList.
+func (in *NooBaaPerformanceProfileList) DeepCopy() *NooBaaPerformanceProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(NooBaaPerformanceProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NooBaaPerformanceProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NooBaaPerformanceProfileSpec) DeepCopyInto(out *NooBaaPerformanceProfileSpec) {
+	*out = *in
+	if in.CoreResources != nil {
+		in, out := &in.CoreResources, &out.CoreResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogResources != nil {
+		in, out := &in.LogResources, &out.LogResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DBResources != nil {
+		in, out := &in.DBResources, &out.DBResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EndpointResources != nil {
+		in, out := &in.EndpointResources, &out.EndpointResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PVPoolResources != nil {
+		in, out := &in.PVPoolResources, &out.PVPoolResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
-	out.Mode = in.Mode
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceStoreStatus.
-func (in *NamespaceStoreStatus) DeepCopy() *NamespaceStoreStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaPerformanceProfileSpec.
+func (in *NooBaaPerformanceProfileSpec) DeepCopy() *NooBaaPerformanceProfileSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceStoreStatus)
+	out := new(NooBaaPerformanceProfileSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaa) DeepCopyInto(out *NooBaa) {
+func (in *NooBaaRemote) DeepCopyInto(out *NooBaaRemote) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1126,18 +2116,18 @@ func (in *NooBaa) DeepCopyInto(out *NooBaa) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaa.
-func (in *NooBaa) DeepCopy() *NooBaa {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaRemote.
+func (in *NooBaaRemote) DeepCopy() *NooBaaRemote {
 	if in == nil {
 		return nil
 	}
-	out := new(NooBaa)
+	out := new(NooBaaRemote)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NooBaa) DeepCopyObject() runtime.Object {
+func (in *NooBaaRemote) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1145,41 +2135,29 @@ func (in *NooBaa) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaaAccount) DeepCopyInto(out *NooBaaAccount) {
+func (in *NooBaaRemoteBucket) DeepCopyInto(out *NooBaaRemoteBucket) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccount.
-func (in *NooBaaAccount) DeepCopy() *NooBaaAccount {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaRemoteBucket.
+func (in *NooBaaRemoteBucket) DeepCopy() *NooBaaRemoteBucket {
 	if in == nil {
 		return nil
 	}
-	out := new(NooBaaAccount)
+	out := new(NooBaaRemoteBucket)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NooBaaAccount) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaaAccountList) DeepCopyInto(out *NooBaaAccountList) {
+func (in *NooBaaRemoteList) DeepCopyInto(out *NooBaaRemoteList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NooBaaAccount, len(*in))
+		*out = make([]NooBaaRemote, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1187,18 +2165,18 @@ func (in *NooBaaAccountList) DeepCopyInto(out *NooBaaAccountList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccountList.
-func (in *NooBaaAccountList) DeepCopy() *NooBaaAccountList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaRemoteList.
+func (in *NooBaaRemoteList) DeepCopy() *NooBaaRemoteList {
 	if in == nil {
 		return nil
 	}
-	out := new(NooBaaAccountList)
+	out := new(NooBaaRemoteList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NooBaaAccountList) DeepCopyObject() runtime.Object {
+func (in *NooBaaRemoteList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1206,176 +2184,92 @@ func (in *NooBaaAccountList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaaAccountSpec) DeepCopyInto(out *NooBaaAccountSpec) {
-	*out = *in
-	if in.NsfsAccountConfig != nil {
-		in, out := &in.NsfsAccountConfig, &out.NsfsAccountConfig
-		*out = new(AccountNsfsConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ForceMd5Etag != nil {
-		in, out := &in.ForceMd5Etag, &out.ForceMd5Etag
-		*out = new(bool)
-		**out = **in
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccountSpec.
-func (in *NooBaaAccountSpec) DeepCopy() *NooBaaAccountSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(NooBaaAccountSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaaAccountStatus) DeepCopyInto(out *NooBaaAccountStatus) {
+func (in *NooBaaRemoteNamespaceStore) DeepCopyInto(out *NooBaaRemoteNamespaceStore) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]conditionsv1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.RelatedObjects != nil {
-		in, out := &in.RelatedObjects, &out.RelatedObjects
-		*out = make([]v1.ObjectReference, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaAccountStatus.
-func (in *NooBaaAccountStatus) DeepCopy() *NooBaaAccountStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaRemoteNamespaceStore.
+func (in *NooBaaRemoteNamespaceStore) DeepCopy() *NooBaaRemoteNamespaceStore {
 	if in == nil {
 		return nil
 	}
-	out := new(NooBaaAccountStatus)
+	out := new(NooBaaRemoteNamespaceStore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaaDBSpec) DeepCopyInto(out *NooBaaDBSpec) {
+func (in *NooBaaRemoteReachability) DeepCopyInto(out *NooBaaRemoteReachability) {
 	*out = *in
-	if in.DBImage != nil {
-		in, out := &in.DBImage, &out.DBImage
-		*out = new(string)
-		**out = **in
-	}
-	if in.PostgresMajorVersion != nil {
-		in, out := &in.PostgresMajorVersion, &out.PostgresMajorVersion
-		*out = new(int)
-		**out = **in
-	}
-	if in.Instances != nil {
-		in, out := &in.Instances, &out.Instances
-		*out = new(int)
-		**out = **in
-	}
-	if in.DBResources != nil {
-		in, out := &in.DBResources, &out.DBResources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DBStorageClass != nil {
-		in, out := &in.DBStorageClass, &out.DBStorageClass
-		*out = new(string)
-		**out = **in
-	}
-	if in.DBConf != nil {
-		in, out := &in.DBConf, &out.DBConf
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.DBBackup != nil {
-		in, out := &in.DBBackup, &out.DBBackup
-		*out = new(DBBackupSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DBRecovery != nil {
-		in, out := &in.DBRecovery, &out.DBRecovery
-		*out = new(DBRecoverySpec)
-		**out = **in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaDBSpec.
-func (in *NooBaaDBSpec) DeepCopy() *NooBaaDBSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaRemoteReachability.
+func (in *NooBaaRemoteReachability) DeepCopy() *NooBaaRemoteReachability {
 	if in == nil {
 		return nil
 	}
-	out := new(NooBaaDBSpec)
+	out := new(NooBaaRemoteReachability)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaaDBStatus) DeepCopyInto(out *NooBaaDBStatus) {
+func (in *NooBaaRemoteSpec) DeepCopyInto(out *NooBaaRemoteSpec) {
 	*out = *in
-	if in.BackupStatus != nil {
-		in, out := &in.BackupStatus, &out.BackupStatus
-		*out = new(DBBackupStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RecoveryStatus != nil {
-		in, out := &in.RecoveryStatus, &out.RecoveryStatus
-		*out = new(DBRecoveryStatus)
-		(*in).DeepCopyInto(*out)
+	out.Secret = in.Secret
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]NooBaaRemoteBucket, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaDBStatus.
-func (in *NooBaaDBStatus) DeepCopy() *NooBaaDBStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaRemoteSpec.
+func (in *NooBaaRemoteSpec) DeepCopy() *NooBaaRemoteSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NooBaaDBStatus)
+	out := new(NooBaaRemoteSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NooBaaList) DeepCopyInto(out *NooBaaList) {
+func (in *NooBaaRemoteStatus) DeepCopyInto(out *NooBaaRemoteStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]NooBaa, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]conditionsv1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Reachability.DeepCopyInto(&out.Reachability)
+	if in.NamespaceStores != nil {
+		in, out := &in.NamespaceStores, &out.NamespaceStores
+		*out = make([]NooBaaRemoteNamespaceStore, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaList.
-func (in *NooBaaList) DeepCopy() *NooBaaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NooBaaRemoteStatus.
+func (in *NooBaaRemoteStatus) DeepCopy() *NooBaaRemoteStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NooBaaList)
+	out := new(NooBaaRemoteStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NooBaaList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NooBaaSpec) DeepCopyInto(out *NooBaaSpec) {
 	*out = *in
@@ -1513,9 +2407,14 @@ func (in *NooBaaSpec) DeepCopyInto(out *NooBaaSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.LoadBalancerSourceSubnets.DeepCopyInto(&out.LoadBalancerSourceSubnets)
-	out.Autoscaler = in.Autoscaler
+	in.Autoscaler.DeepCopyInto(&out.Autoscaler)
 	in.BucketLogging.DeepCopyInto(&out.BucketLogging)
 	in.BucketNotifications.DeepCopyInto(&out.BucketNotifications)
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1583,6 +2482,116 @@ func (in *NooBaaStatus) DeepCopy() *NooBaaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCClaimMapping) DeepCopyInto(out *OIDCClaimMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCClaimMapping.
+func (in *OIDCClaimMapping) DeepCopy() *OIDCClaimMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCClaimMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCProviderSpec) DeepCopyInto(out *OIDCProviderSpec) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientSecret != nil {
+		in, out := &in.ClientSecret, &out.ClientSecret
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.ClaimMappings != nil {
+		in, out := &in.ClaimMappings, &out.ClaimMappings
+		*out = make([]OIDCClaimMapping, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCProviderSpec.
+func (in *OIDCProviderSpec) DeepCopy() *OIDCProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCSpec) DeepCopyInto(out *OIDCSpec) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]OIDCProviderSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCSpec.
+func (in *OIDCSpec) DeepCopy() *OIDCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreBackupSpec) DeepCopyInto(out *ObjectStoreBackupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreBackupSpec.
+func (in *ObjectStoreBackupSpec) DeepCopy() *ObjectStoreBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVPoolDecommissionStatus) DeepCopyInto(out *PVPoolDecommissionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVPoolDecommissionStatus.
+func (in *PVPoolDecommissionStatus) DeepCopy() *PVPoolDecommissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PVPoolDecommissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PVPoolSpec) DeepCopyInto(out *PVPoolSpec) {
 	*out = *in
@@ -1592,6 +2601,11 @@ func (in *PVPoolSpec) DeepCopyInto(out *PVPoolSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.Secret = in.Secret
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1670,6 +2684,11 @@ func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
 		*out = new(TLSSecuritySpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
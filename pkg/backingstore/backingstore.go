@@ -433,7 +433,11 @@ func createCommon(cmd *cobra.Command, args []string, storeType nbv1.StoreType, p
 	populate(backStore, secret)
 
 	if storeType == nbv1.StoreTypePVPool && backStore.Spec.PVPool != nil {
-		profileDefaults := system.GetPVPoolResources(sys)
+		customProfile, err := system.LoadPerformanceProfile(util.Context(), util.KubeClient(), sys)
+		if err != nil {
+			log.Fatalf(`❌ Could not load the performance profile of NooBaa system %q: %v`, sys.Name, err)
+		}
+		profileDefaults := system.GetPVPoolResources(sys, customProfile)
 		effective := corev1.ResourceRequirements{
 			Requests: profileDefaults.Requests.DeepCopy(),
 			Limits:   profileDefaults.Limits.DeepCopy(),
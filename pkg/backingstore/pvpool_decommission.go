@@ -0,0 +1,279 @@
+package backingstore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DecommissionAnnotation marks a pv-pool pod to be decommissioned when set to "true"
+	DecommissionAnnotation = "noobaa.io/decommission"
+
+	// pvPoolDecommissionRequeue is the interval of checking the evacuation progress of decommissioned pods
+	pvPoolDecommissionRequeue = 30 * time.Second
+
+	// pvPoolDecommissionHistory is how long completed decommissions of pods that are not in the spec list are reported
+	pvPoolDecommissionHistory = 24 * time.Hour
+
+	hostModeDecommissioning = "DECOMMISSIONING"
+	hostModeDecommissioned  = "DECOMMISSIONED"
+)
+
+type decommissionAction int
+
+const (
+	decommissionStart decommissionAction = iota
+	decommissionWait
+	decommissionDelete
+)
+
+// pvPoolRetirement holds the names of the pods and PVCs that are decommissioned
+type pvPoolRetirement struct {
+	pods map[string]bool
+	pvcs map[string]bool
+}
+
+// isPodDecommissioned returns true when the pod is listed in the decommission list or has the decommission annotation
+func isPodDecommissioned(bs *nbv1.BackingStore, pod *corev1.Pod) bool {
+	if pod.Annotations[DecommissionAnnotation] == "true" {
+		return true
+	}
+	return bs.Spec.PVPool != nil && util.Contains(bs.Spec.PVPool.Decommission, pod.Name)
+}
+
+// getPodClaimName returns the name of the PVC that holds the data of a pv-pool pod
+func getPodClaimName(pod *corev1.Pod) string {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil {
+			return vol.PersistentVolumeClaim.ClaimName
+		}
+	}
+	return ""
+}
+
+// findPodHost returns the noobaa host of a pv-pool pod, or nil when the pod did not attach to noobaa
+func findPodHost(hosts []nb.HostInfo, podName string) *nb.HostInfo {
+	for i := range hosts {
+		if strings.HasPrefix(hosts[i].Name, podName) {
+			return &hosts[i]
+		}
+	}
+	return nil
+}
+
+// nextDecommissionAction returns the next step of decommissioning the host of a pod.
+// A pod without a host is deleted only when its decommissioned host was already deleted by an earlier
+// reconcile, or when its PVC holds no data. Otherwise the host may not be reported yet, so we wait for it.
+func nextDecommissionAction(host *nb.HostInfo, hostDeleted bool, pvcEmpty bool) decommissionAction {
+	if host == nil {
+		if hostDeleted || pvcEmpty {
+			return decommissionDelete
+		}
+		return decommissionWait
+	}
+	switch host.Mode {
+	case hostModeDecommissioned:
+		return decommissionDelete
+	case hostModeDecommissioning:
+		return decommissionWait
+	default:
+		return decommissionStart
+	}
+}
+
+// decommissionProgress returns the percentage of the data that was evacuated from a decommissioning host
+func decommissionProgress(host *nb.HostInfo) int {
+	if host == nil || host.DataActivity == nil || host.DataActivity.Reason != hostModeDecommissioning {
+		return 0
+	}
+	if host.DataActivity.Completed {
+		return 100
+	}
+	return min(max(int(host.DataActivity.Progress*100), 0), 100)
+}
+
+// countRetiredHosts returns the number of hosts of the pool that are decommissioned or belong to decommissioned pods
+func (r *Reconciler) countRetiredHosts(hosts []nb.HostInfo) int {
+	podsList := &corev1.PodList{}
+	util.KubeList(podsList, client.InNamespace(options.Namespace), client.MatchingLabels{"pool": r.BackingStore.Name})
+	count := 0
+	for i := range hosts {
+		host := &hosts[i]
+		if host.Mode == hostModeDecommissioning || host.Mode == hostModeDecommissioned {
+			count++
+			continue
+		}
+		for j := range podsList.Items {
+			pod := &podsList.Items[j]
+			if isPodDecommissioned(r.BackingStore, pod) && strings.HasPrefix(host.Name, pod.Name) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// reconcilePvPoolDecommission evacuates the data of the decommissioned pv-pool pods through the noobaa hosts
+// decommission, and deletes the pods and their PVCs once the data was rebuilt on the other pods.
+// The progress is reported in the backing store status.
+func (r *Reconciler) reconcilePvPoolDecommission(
+	podsList *corev1.PodList,
+	pvcsList *corev1.PersistentVolumeClaimList,
+) (*pvPoolRetirement, error) {
+
+	log := r.Logger
+	retired := &pvPoolRetirement{pods: map[string]bool{}, pvcs: map[string]bool{}}
+	now := metav1.Now()
+
+	prevStatus := map[string]*nbv1.PVPoolDecommissionStatus{}
+	for i := range r.BackingStore.Status.PVPoolDecommission {
+		s := &r.BackingStore.Status.PVPoolDecommission[i]
+		prevStatus[s.PodName] = s
+	}
+
+	statuses := []nbv1.PVPoolDecommissionStatus{}
+	for i := range podsList.Items {
+		pod := &podsList.Items[i]
+		if !isPodDecommissioned(r.BackingStore, pod) {
+			continue
+		}
+		pvcName := getPodClaimName(pod)
+		retired.pods[pod.Name] = true
+		if pvcName != "" {
+			retired.pvcs[pvcName] = true
+		}
+
+		status := nbv1.PVPoolDecommissionStatus{PodName: pod.Name, PVCName: pvcName, StartTime: &now}
+		prev := prevStatus[pod.Name]
+		if prev != nil && prev.Phase != nbv1.PVPoolDecommissionPhaseCompleted {
+			status.StartTime = prev.StartTime
+		}
+
+		host := findPodHost(*r.HostsInfo, pod.Name)
+		hostDeleted := prev != nil && prev.Phase == nbv1.PVPoolDecommissionPhaseDeleting
+		switch nextDecommissionAction(host, hostDeleted, isPVCEmpty(pvcsList, pvcName)) {
+		case decommissionStart:
+			storage := false
+			err := r.NBClient.UpdateHostServicesAPI(nb.UpdateHostServicesParams{
+				Name:     host.Name,
+				Services: nb.HostServicesSettings{Storage: &storage},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to decommission host %q of pod %q: %v", host.Name, pod.Name, err)
+			}
+			status.Phase = nbv1.PVPoolDecommissionPhaseDecommissioning
+			log.Infof("Started decommissioning pod %q, evacuating the data of host %q", pod.Name, host.Name)
+			r.recordEvent(corev1.EventTypeNormal, "PVPoolDecommissionStarted",
+				fmt.Sprintf("Evacuating the data of pod %q before deleting it and its PVC %q", pod.Name, pvcName))
+
+		case decommissionWait:
+			status.Phase = nbv1.PVPoolDecommissionPhaseDecommissioning
+			if host == nil {
+				log.Infof("Pod %q is decommissioning, waiting for its host to be reported", pod.Name)
+				break
+			}
+			status.Progress = decommissionProgress(host)
+			log.Infof("Pod %q is decommissioning, %d%% of its data was evacuated", pod.Name, status.Progress)
+
+		case decommissionDelete:
+			if host != nil {
+				err := r.NBClient.DeleteHostAPI(nb.DeleteHostParams{Name: host.Name})
+				if err != nil {
+					return nil, fmt.Errorf("failed to delete the decommissioned host %q of pod %q: %v", host.Name, pod.Name, err)
+				}
+			}
+			util.KubeDeleteNoPolling(pod)
+			if pvcName != "" {
+				util.KubeDeleteNoPolling(&corev1.PersistentVolumeClaim{
+					TypeMeta:   metav1.TypeMeta{Kind: "PersistentVolumeClaim"},
+					ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: pod.Namespace},
+				})
+			}
+			status.Phase = nbv1.PVPoolDecommissionPhaseDeleting
+			status.Progress = 100
+			log.Infof("✅ Pod %q was decommissioned, deleting it and its PVC %q", pod.Name, pvcName)
+			r.recordEvent(corev1.EventTypeNormal, "PVPoolDecommissioned",
+				fmt.Sprintf("The data of pod %q was evacuated, the pod and its PVC %q are deleted", pod.Name, pvcName))
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	// report the decommissions of pods that are gone
+	for _, prev := range r.BackingStore.Status.PVPoolDecommission {
+		if retired.pods[prev.PodName] {
+			continue
+		}
+		if prev.Phase != nbv1.PVPoolDecommissionPhaseCompleted && pvcExists(pvcsList, prev.PVCName) {
+			// the pod was deleted during the decommission and is recreated for its PVC
+			statuses = append(statuses, prev)
+			continue
+		}
+		if prev.Phase != nbv1.PVPoolDecommissionPhaseCompleted {
+			prev.Phase = nbv1.PVPoolDecommissionPhaseCompleted
+			prev.Progress = 100
+			prev.CompletionTime = &now
+		}
+		listed := r.BackingStore.Spec.PVPool != nil && util.Contains(r.BackingStore.Spec.PVPool.Decommission, prev.PodName)
+		if listed || (prev.CompletionTime != nil && now.Sub(prev.CompletionTime.Time) < pvPoolDecommissionHistory) {
+			statuses = append(statuses, prev)
+		}
+	}
+
+	for _, s := range statuses {
+		if s.Phase != nbv1.PVPoolDecommissionPhaseCompleted {
+			r.PVPoolDecommissionInProgress = true
+			break
+		}
+	}
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	r.BackingStore.Status.PVPoolDecommission = statuses
+	return retired, nil
+}
+
+// pvcExists returns true when the PVC is in the list and is not being deleted
+func pvcExists(pvcsList *corev1.PersistentVolumeClaimList, name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := range pvcsList.Items {
+		pvc := &pvcsList.Items[i]
+		if pvc.Name == name && pvc.DeletionTimestamp == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isPVCEmpty returns true when the pod has no PVC or its PVC was never bound, so it holds no data
+func isPVCEmpty(pvcsList *corev1.PersistentVolumeClaimList, name string) bool {
+	if name == "" {
+		return true
+	}
+	for i := range pvcsList.Items {
+		pvc := &pvcsList.Items[i]
+		if pvc.Name == name {
+			return pvc.Status.Phase == corev1.ClaimPending
+		}
+	}
+	return false
+}
+
+func (r *Reconciler) recordEvent(eventType string, reason string, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(r.BackingStore, nil, eventType, reason, reason, "%s", message)
+	}
+}
@@ -0,0 +1,110 @@
+package backingstore
+
+import (
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsPodDecommissioned(t *testing.T) {
+	bs := &nbv1.BackingStore{Spec: nbv1.BackingStoreSpec{PVPool: &nbv1.PVPoolSpec{
+		Decommission: []string{"bs-noobaa-pod-aaaa"},
+	}}}
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want bool
+	}{
+		{
+			name: "listed pod",
+			pod:  corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bs-noobaa-pod-aaaa"}},
+			want: true,
+		},
+		{
+			name: "annotated pod",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:        "bs-noobaa-pod-bbbb",
+				Annotations: map[string]string{DecommissionAnnotation: "true"},
+			}},
+			want: true,
+		},
+		{
+			name: "other pod",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:        "bs-noobaa-pod-cccc",
+				Annotations: map[string]string{DecommissionAnnotation: "false"},
+			}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPodDecommissioned(bs, &tt.pod); got != tt.want {
+				t.Errorf("isPodDecommissioned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextDecommissionAction(t *testing.T) {
+	hosts := []nb.HostInfo{
+		{Name: "bs-noobaa-pod-aaaa-noobaa_storage-1", Mode: "OPTIMAL"},
+		{Name: "bs-noobaa-pod-bbbb-noobaa_storage-1", Mode: hostModeDecommissioning,
+			DataActivity: &nb.HostDataActivity{Reason: hostModeDecommissioning, Progress: 0.42}},
+		{Name: "bs-noobaa-pod-cccc-noobaa_storage-1", Mode: hostModeDecommissioned},
+	}
+	tests := []struct {
+		name         string
+		pod          string
+		hostDeleted  bool
+		pvcEmpty     bool
+		want         decommissionAction
+		wantProgress int
+	}{
+		{name: "optimal host", pod: "bs-noobaa-pod-aaaa", want: decommissionStart},
+		{name: "decommissioning host", pod: "bs-noobaa-pod-bbbb", want: decommissionWait, wantProgress: 42},
+		{name: "decommissioned host", pod: "bs-noobaa-pod-cccc", want: decommissionDelete},
+		{name: "decommissioned host with empty pvc", pod: "bs-noobaa-pod-cccc", pvcEmpty: true, want: decommissionDelete},
+		{name: "optimal host with empty pvc", pod: "bs-noobaa-pod-aaaa", pvcEmpty: true, want: decommissionStart},
+		{name: "missing host", pod: "bs-noobaa-pod-dddd", want: decommissionWait},
+		{name: "missing host that was deleted", pod: "bs-noobaa-pod-dddd", hostDeleted: true, want: decommissionDelete},
+		{name: "missing host with empty pvc", pod: "bs-noobaa-pod-dddd", pvcEmpty: true, want: decommissionDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := findPodHost(hosts, tt.pod)
+			if got := nextDecommissionAction(host, tt.hostDeleted, tt.pvcEmpty); got != tt.want {
+				t.Errorf("nextDecommissionAction() = %v, want %v", got, tt.want)
+			}
+			if got := decommissionProgress(host); got != tt.wantProgress {
+				t.Errorf("decommissionProgress() = %v, want %v", got, tt.wantProgress)
+			}
+		})
+	}
+}
+
+func TestIsPVCEmpty(t *testing.T) {
+	pvcsList := &corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: "bound"}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pending"}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+	}}
+	tests := []struct {
+		pvc  string
+		want bool
+	}{
+		{pvc: "", want: true},
+		{pvc: "pending", want: true},
+		{pvc: "bound", want: false},
+		{pvc: "missing", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pvc, func(t *testing.T) {
+			if got := isPVCEmpty(pvcsList, tt.pvc); got != tt.want {
+				t.Errorf("isPVCEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
 	"github.com/noobaa/noobaa-operator/v5/pkg/constants"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/system"
@@ -93,6 +94,8 @@ type Reconciler struct {
 	CreateHostsPoolParams          *nb.CreateHostsPoolParams
 	UpdateHostsPoolParams          *nb.UpdateHostsPoolParams
 	UpdateExternalConnectionParams *nb.UpdateExternalConnectionParams
+
+	PVPoolDecommissionInProgress bool
 }
 
 // Own sets the object owner references to the backingstore
@@ -155,6 +158,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 
 	if !util.KubeCheck(r.BackingStore) {
 		log.Infof("❌ BackingStore %q not found.", r.BackingStore.Name)
+		metrics.DeletePhase("BackingStore", r.BackingStore.Namespace, r.BackingStore.Name)
 		return reconcile.Result{}, nil // final state
 	}
 
@@ -195,6 +199,7 @@ func (r *Reconciler) completeReconcile(err error) (reconcile.Result, error) {
 	res := reconcile.Result{}
 
 	if err != nil {
+		metrics.ReconcileError(metrics.ControllerBackingStore, err)
 		if perr, isPERR := err.(*util.PersistentError); isPERR {
 			r.SetPhase(nbv1.BackingStorePhaseRejected, perr.Reason, perr.Message)
 			log.Errorf("❌ Persistent Error: %s", err)
@@ -230,6 +235,9 @@ func (r *Reconciler) completeReconcile(err error) (reconcile.Result, error) {
 			)
 			log.Infof("✅ Done")
 		}
+		if r.PVPoolDecommissionInProgress {
+			res.RequeueAfter = pvPoolDecommissionRequeue
+		}
 	}
 
 	err = r.UpdateStatus()
@@ -345,6 +353,7 @@ func (r *Reconciler) SetPhase(phase nbv1.BackingStorePhase, reason string, messa
 
 	r.Logger.Infof("SetPhase: %s", phase)
 	r.BackingStore.Status.Phase = phase
+	metrics.SetPhase("BackingStore", r.BackingStore.Namespace, r.BackingStore.Name, string(phase))
 	switch phase {
 	case nbv1.BackingStorePhaseReady:
 		util.SetAvailableCondition(c, reason, message)
@@ -547,7 +556,7 @@ func (r *Reconciler) ReadSystemInfo() error {
 	if err != nil {
 		return err
 	}
-	r.NBClient = sysClient.NBClient
+	r.NBClient = sysClient.NBClient.WithContext(r.Ctx)
 
 	systemInfo, err := r.NBClient.ReadSystemAPI()
 	if err != nil {
@@ -600,9 +609,11 @@ func (r *Reconciler) ReadSystemInfo() error {
 				return err
 			}
 
-			if len(hostsInfo.Hosts) > pvPool.NumVolumes { // scaling down - not supported
-				return util.NewPersistentError("InvalidBackingStore",
-					"Scaling down the number of nodes is not currently supported")
+			// scaling down is done by decommissioning pods
+			if activeHosts := len(hostsInfo.Hosts) - r.countRetiredHosts(hostsInfo.Hosts); activeHosts > pvPool.NumVolumes {
+				return util.NewPersistentError("InvalidBackingStore", fmt.Sprintf(
+					"Scaling down the number of nodes from %d to %d requires decommissioning %d pods in spec.pvPool.decommission",
+					activeHosts, pvPool.NumVolumes, activeHosts-pvPool.NumVolumes))
 			}
 			if pvPool.NumVolumes != int(pool.Hosts.ConfiguredCount) {
 				r.UpdateHostsPoolParams = &nb.UpdateHostsPoolParams{ // update core
@@ -1086,8 +1097,20 @@ func (r *Reconciler) reconcilePvPool() error {
 	pvcsList := &corev1.PersistentVolumeClaimList{}
 	util.KubeList(podsList, client.InNamespace(options.Namespace), client.MatchingLabels{"pool": r.BackingStore.Name})
 	util.KubeList(pvcsList, client.InNamespace(options.Namespace), client.MatchingLabels{"pool": r.BackingStore.Name})
-	if len(pvcsList.Items) < r.BackingStore.Spec.PVPool.NumVolumes {
-		err := r.reconcileMissingPvcs(pvcsList)
+	retired, err := r.reconcilePvPoolDecommission(podsList, pvcsList)
+	if err != nil {
+		return err
+	}
+	// decommissioned and deleted PVCs are replaced unless the number of volumes was decreased
+	activePvcs := 0
+	for i := range pvcsList.Items {
+		pvc := &pvcsList.Items[i]
+		if pvc.DeletionTimestamp == nil && !retired.pvcs[pvc.Name] {
+			activePvcs++
+		}
+	}
+	if activePvcs < r.BackingStore.Spec.PVPool.NumVolumes {
+		err := r.reconcileMissingPvcs(activePvcs)
 		if err != nil {
 			return err
 		}
@@ -1099,7 +1122,7 @@ func (r *Reconciler) reconcilePvPool() error {
 			return err
 		}
 	}
-	return r.reconcileExistingPods(podsList)
+	return r.reconcileExistingPods(podsList, retired)
 }
 
 func (r *Reconciler) reconcileMissingPods(podsList *corev1.PodList, pvcsList *corev1.PersistentVolumeClaimList) error {
@@ -1111,7 +1134,7 @@ func (r *Reconciler) reconcileMissingPods(podsList *corev1.PodList, pvcsList *co
 		return err
 	}
 	for _, pvc := range pvcsList.Items {
-		if !util.Contains(claimNames, pvc.Name) {
+		if pvc.DeletionTimestamp == nil && !util.Contains(claimNames, pvc.Name) {
 			i := strings.LastIndex(pvc.Name, "-")
 			postfix := pvc.Name[i+1:]
 			newPod := r.PodAgentTemplate.DeepCopy()
@@ -1125,10 +1148,15 @@ func (r *Reconciler) reconcileMissingPods(podsList *corev1.PodList, pvcsList *co
 	return nil
 }
 
-func (r *Reconciler) reconcileExistingPods(podsList *corev1.PodList) error {
+func (r *Reconciler) reconcileExistingPods(podsList *corev1.PodList, retired *pvPoolRetirement) error {
+	activePods := 0
 	noneAttachingAgents := 0
 	failedAttachingAgents := 0
 	for _, pod := range podsList.Items {
+		if pod.DeletionTimestamp != nil || retired.pods[pod.Name] {
+			continue
+		}
+		activePods++
 		// check if pod need to be updated and deleted
 		if r.needUpdate(&pod) {
 			util.KubeDelete(&pod)
@@ -1142,11 +1170,11 @@ func (r *Reconciler) reconcileExistingPods(podsList *corev1.PodList) error {
 			}
 		}
 	}
-	if len(podsList.Items) < r.BackingStore.Spec.PVPool.NumVolumes {
+	if activePods < r.BackingStore.Spec.PVPool.NumVolumes {
 		return fmt.Errorf("BackingStore Still didn't start all the pods. %d from %d has started",
-			len(podsList.Items), r.BackingStore.Spec.PVPool.NumVolumes)
+			activePods, r.BackingStore.Spec.PVPool.NumVolumes)
 	}
-	attachedAgents := len(podsList.Items) - noneAttachingAgents
+	attachedAgents := activePods - noneAttachingAgents
 	if attachedAgents < r.BackingStore.Spec.PVPool.NumVolumes {
 		if failedAttachingAgents == noneAttachingAgents {
 			return util.NewPersistentError("Failed connecting all pods in backingstore for more than 10 minutes",
@@ -1291,9 +1319,9 @@ func (r *Reconciler) needUpdate(pod *corev1.Pod) bool {
 	return false
 }
 
-func (r *Reconciler) reconcileMissingPvcs(pvcsList *corev1.PersistentVolumeClaimList) error {
+func (r *Reconciler) reconcileMissingPvcs(activePvcs int) error {
 	r.updatePvcTemplate()
-	for i := len(pvcsList.Items); i < r.BackingStore.Spec.PVPool.NumVolumes; i++ {
+	for i := activePvcs; i < r.BackingStore.Spec.PVPool.NumVolumes; i++ {
 		postfix := util.RandomHex(4)
 		pvcName := fmt.Sprintf("%s-%s-pvc-%s", r.BackingStore.Name, options.SystemName, postfix)
 		newPvc := r.PvcAgentTemplate.DeepCopy()
@@ -1392,7 +1420,11 @@ func (r *Reconciler) updatePodTemplate() error {
 }
 
 func (r *Reconciler) updatePodResourcesTemplate(c *corev1.Container) error {
-	profileDefaults := system.GetPVPoolResources(r.NooBaa)
+	customProfile, err := system.LoadPerformanceProfile(r.Ctx, r.Client, r.NooBaa)
+	if err != nil {
+		return fmt.Errorf("failed to load the performance profile of NooBaa %q: %w", r.NooBaa.Name, err)
+	}
+	profileDefaults := system.GetPVPoolResources(r.NooBaa, customProfile)
 
 	c.Resources.Requests = profileDefaults.Requests.DeepCopy()
 	c.Resources.Limits = profileDefaults.Limits.DeepCopy()
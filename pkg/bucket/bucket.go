@@ -299,18 +299,17 @@ func RunStatus(cmd *cobra.Command, args []string) {
 func RunList(cmd *cobra.Command, args []string) {
 	log := util.Logger()
 	nbClient := system.GetNBClient()
-	list, err := nbClient.ListBucketsAPI(nb.ListBucketsParams{})
+	names, err := nb.ListAllBuckets(nbClient, nb.DefaultListPageSize)
 	if err != nil {
 		log.Fatal(err)
 	}
-	if len(list.Buckets) == 0 {
+	if len(names) == 0 {
 		fmt.Printf("No buckets found.\n")
 		return
 	}
 	table := (&util.PrintTable{}).AddRow("BUCKET-NAME")
-	for i := range list.Buckets {
-		b := &list.Buckets[i]
-		table.AddRow(b.Name)
+	for _, name := range names {
+		table.AddRow(name)
 	}
 	fmt.Printf("\n")
 	fmt.Print(table.String())
@@ -866,6 +866,51 @@ func CreateNamespaceBucketInfoStructure(namespacePolicy nbv1.NamespacePolicy, pa
 	return namespaceBucketInfo
 }
 
+// CreateLifecycleRulesStructure converts a bucket class lifecycle policy to the lifecycle rules of a bucket
+func CreateLifecycleRulesStructure(lifecyclePolicy nbv1.LifecyclePolicy) []nb.LifecycleRuleConfig {
+	rules := []nb.LifecycleRuleConfig{}
+	for i := range lifecyclePolicy.Rules {
+		rule := &lifecyclePolicy.Rules[i]
+		status := "Enabled"
+		if rule.Disabled {
+			status = "Disabled"
+		}
+		ruleConfig := nb.LifecycleRuleConfig{
+			ID:     rule.ID,
+			Status: status,
+			Filter: nb.LifecycleRuleFilter{Prefix: rule.Prefix},
+		}
+		if rule.ExpirationDays > 0 {
+			ruleConfig.Expiration = &nb.LifecycleExpiration{Days: rule.ExpirationDays}
+		}
+		if rule.NoncurrentVersionExpirationDays > 0 {
+			ruleConfig.NoncurrentVersionExpiration = &nb.LifecycleNoncurrentVersionExpiration{
+				NoncurrentDays:          rule.NoncurrentVersionExpirationDays,
+				NewerNoncurrentVersions: rule.NewerNoncurrentVersions,
+			}
+		}
+		if rule.AbortIncompleteMultipartUploadDays > 0 {
+			ruleConfig.AbortIncompleteMultipartUpload = &nb.LifecycleAbortIncompleteMultipart{
+				DaysAfterInitiation: rule.AbortIncompleteMultipartUploadDays,
+			}
+		}
+		rules = append(rules, ruleConfig)
+	}
+	return rules
+}
+
+// ApplyLifecyclePolicy sets the bucket class lifecycle policy on a bucket,
+// or removes the bucket lifecycle configuration when the policy has no rules
+func ApplyLifecyclePolicy(lifecyclePolicy nbv1.LifecyclePolicy, bucketName string, nbClient nb.Client) error {
+	if len(lifecyclePolicy.Rules) == 0 {
+		return nbClient.DeleteBucketLifecycleAPI(nb.DeleteBucketLifecycleParams{Name: bucketName})
+	}
+	return nbClient.SetBucketLifecycleConfigurationRulesAPI(nb.BucketLifecycleParams{
+		Name:  bucketName,
+		Rules: CreateLifecycleRulesStructure(lifecyclePolicy),
+	})
+}
+
 // GetDefaultBucketClass will get the default bucket class
 func GetDefaultBucketClass(Namespace string) (*nbv1.BucketClass, error) {
 	bucketClassName := options.SystemName + "-default-bucket-class"
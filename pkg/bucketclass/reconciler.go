@@ -8,6 +8,7 @@ import (
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/system"
@@ -84,6 +85,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 
 	if !util.KubeCheck(r.BucketClass) {
 		log.Infof("❌ BucketClass %q not found or deleted.", r.BucketClass.Name)
+		metrics.DeletePhase("BucketClass", r.BucketClass.Namespace, r.BucketClass.Name)
 		return res, err
 	}
 
@@ -108,6 +110,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 
 	err = r.ReconcilePhases()
 	if err != nil {
+		metrics.ReconcileError(metrics.ControllerBucketClass, err)
 		if perr, isPERR := err.(*util.PersistentError); isPERR {
 			r.SetPhase(nbv1.BucketClassPhaseRejected, perr.Reason, perr.Message)
 			log.Errorf("❌ Persistent Error: %s", err)
@@ -170,6 +173,7 @@ func (r *Reconciler) SetPhase(phase nbv1.BucketClassPhase, reason string, messag
 
 	r.Logger.Infof("SetPhase: %s", phase)
 	r.BucketClass.Status.Phase = phase
+	metrics.SetPhase("BucketClass", r.BucketClass.Namespace, r.BucketClass.Name, string(phase))
 	switch phase {
 	case nbv1.BucketClassPhaseReady:
 		util.SetAvailableCondition(c, reason, message)
@@ -242,6 +246,8 @@ func (r *Reconciler) ReconcilePhaseConfiguring() error {
 	util.KubeList(objectBuckets, &client.ListOptions{LabelSelector: obcSelector})
 
 	var bucketNames []string
+	// buckets provisioned from the current generation already got its policies
+	provisionedBuckets := 0
 	for i := range objectBuckets.Items {
 		ob := &objectBuckets.Items[i]
 		bucketClass := ob.Spec.AdditionalState["bucketclass"]
@@ -251,22 +257,32 @@ func (r *Reconciler) ReconcilePhaseConfiguring() error {
 			continue
 		}
 		if bucketClassGeneration == fmt.Sprintf("%d", r.BucketClass.Generation) {
+			if ob.DeletionTimestamp == nil {
+				provisionedBuckets++
+			}
 			continue
 		}
 		bucketNames = append(bucketNames, bucketName)
 	}
 
-	if len(bucketNames) == 0 {
-		return nil
-	}
+	if len(bucketNames) > 0 {
+		sysClient, err := system.Connect(false)
+		if err != nil {
+			return err
+		}
+		r.NBClient = sysClient.NBClient.WithContext(r.Ctx)
 
-	sysClient, err := system.Connect(false)
-	if err != nil {
-		return err
+		bucketNames, err = r.getExistingBuckets(bucketNames)
+		if err != nil {
+			return err
+		}
+		if err := r.UpdateBucketClass(bucketNames); err != nil {
+			return err
+		}
 	}
-	r.NBClient = sysClient.NBClient
 
-	if err := r.UpdateBucketClass(bucketNames); err != nil {
+	// the lifecycle status is reported even when no bucket needs an update
+	if err := r.updateLifecyclePolicyForBuckets(bucketNames, provisionedBuckets); err != nil {
 		return err
 	}
 
@@ -327,6 +343,29 @@ func (r *Reconciler) updateNamespaceBucketClass(bucketNames []string) error {
 	return nil
 }
 
+// getExistingBuckets returns the buckets out of bucketNames that exist in noobaa core, so the buckets
+// of object buckets that were already deleted are skipped. The buckets are listed a page at a time,
+// since a system can have many buckets.
+func (r *Reconciler) getExistingBuckets(bucketNames []string) ([]string, error) {
+	allNames, err := nb.ListAllBuckets(r.NBClient, nb.DefaultListPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the buckets: %w", err)
+	}
+	all := map[string]bool{}
+	for _, name := range allNames {
+		all[name] = true
+	}
+	existing := []string{}
+	for _, name := range bucketNames {
+		if !all[name] {
+			r.Logger.Warnf("Bucket %q of bucket class %q was not found, skipping it", name, r.BucketClass.Name)
+			continue
+		}
+		existing = append(existing, name)
+	}
+	return existing, nil
+}
+
 // UpdateBucketClass updates all buckets that are assigned to a BucketClass
 func (r *Reconciler) UpdateBucketClass(bucketNames []string) error {
 	log := r.Logger
@@ -445,3 +484,47 @@ func (r *Reconciler) updateArchivePolicyForExistingBuckets(bucketNames []string)
 	}
 	return errors.Join(errs...)
 }
+
+// updateLifecyclePolicyForBuckets pushes the BucketClass lifecycle policy to the given buckets
+// and reports in the status how many buckets of the class have the policy and which ones failed.
+// provisionedBuckets is the number of other buckets of the class that got the policy when they were provisioned.
+// Unlike the archive policy, the lifecycle policy is owned by the bucket class so it
+// overrides any lifecycle rules that were set on the buckets directly, and removing the policy
+// from the bucket class removes the lifecycle configuration from the buckets.
+// Failed buckets are returned as an error, so the reconcile is requeued and they are retried.
+func (r *Reconciler) updateLifecyclePolicyForBuckets(bucketNames []string, provisionedBuckets int) error {
+	lifecyclePolicy := r.BucketClass.Spec.LifecyclePolicy
+	if lifecyclePolicy == nil {
+		// the status is kept until the policy the bucket class had is removed from all its buckets
+		if r.BucketClass.Status.LifecyclePolicy == nil {
+			return nil
+		}
+		lifecyclePolicy = &nbv1.LifecyclePolicy{}
+	}
+
+	log := r.Logger
+	lifecycleStatus := &nbv1.LifecyclePolicyStatus{ObservedGeneration: r.BucketClass.Generation}
+	if r.BucketClass.Spec.LifecyclePolicy != nil {
+		lifecycleStatus.AppliedBuckets = provisionedBuckets
+	}
+	r.BucketClass.Status.LifecyclePolicy = lifecycleStatus
+
+	var errs []error
+	for _, bucketName := range bucketNames {
+		err := ApplyLifecyclePolicy(*lifecyclePolicy, bucketName, r.NBClient)
+		if err != nil {
+			lifecycleStatus.FailedBuckets = append(lifecycleStatus.FailedBuckets, bucketName)
+			errs = append(errs, fmt.Errorf("failed to apply lifecycle policy to bucket %q: %w", bucketName, err))
+			continue
+		}
+		lifecycleStatus.AppliedBuckets++
+		log.Infof("✅ Applied lifecycle policy to bucket %q", bucketName)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if r.BucketClass.Spec.LifecyclePolicy == nil {
+		r.BucketClass.Status.LifecyclePolicy = nil
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package bucketclass
+
+import (
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb/fake"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFakeReconciler(t *testing.T, server *fake.Server, lifecyclePolicy *nbv1.LifecyclePolicy) *Reconciler {
+	return &Reconciler{
+		Logger:   logrus.WithField("test", t.Name()),
+		NBClient: server.Client(),
+		BucketClass: &nbv1.BucketClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "class", Generation: 2},
+			Spec:       nbv1.BucketClassSpec{LifecyclePolicy: lifecyclePolicy},
+		},
+	}
+}
+
+func TestUpdateLifecyclePolicyForBuckets(t *testing.T) {
+	server := fake.NewServer()
+	for _, name := range []string{"first.bucket", "second.bucket"} {
+		if err := server.Client().CreateBucketAPI(nb.CreateBucketParams{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	policy := &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{{ID: "expire", ExpirationDays: 30}}}
+
+	// a bucket that fails is reported in the status and returned to requeue the reconcile
+	r := newFakeReconciler(t, server, policy)
+	err := r.updateLifecyclePolicyForBuckets([]string{"first.bucket", "missing.bucket", "second.bucket"}, 0)
+	status := r.BucketClass.Status.LifecyclePolicy
+	if err == nil || status == nil || status.AppliedBuckets != 2 || len(status.FailedBuckets) != 1 ||
+		status.FailedBuckets[0] != "missing.bucket" || status.ObservedGeneration != 2 {
+		t.Fatalf("expected one failed bucket, got %+v %v", status, err)
+	}
+	if len(server.BucketLifecycles["first.bucket"]) != 1 || len(server.BucketLifecycles["second.bucket"]) != 1 {
+		t.Fatalf("expected the rules on both buckets, got %+v", server.BucketLifecycles)
+	}
+
+	// the status is reported when no bucket needs an update, and counts the buckets provisioned with the policy
+	r = newFakeReconciler(t, server, policy)
+	if err := r.updateLifecyclePolicyForBuckets(nil, 3); err != nil {
+		t.Fatal(err)
+	}
+	if status := r.BucketClass.Status.LifecyclePolicy; status == nil || status.ObservedGeneration != 2 || status.AppliedBuckets != 3 {
+		t.Fatalf("expected the status of the current generation, got %+v", status)
+	}
+
+	// the buckets updated in this reconcile are added to the buckets provisioned with the policy
+	r = newFakeReconciler(t, server, policy)
+	if err := r.updateLifecyclePolicyForBuckets([]string{"first.bucket"}, 3); err != nil {
+		t.Fatal(err)
+	}
+	if status := r.BucketClass.Status.LifecyclePolicy; status == nil || status.AppliedBuckets != 4 {
+		t.Fatalf("expected all the buckets of the class to be counted, got %+v", status)
+	}
+
+	// removing the policy deletes the lifecycle of the buckets, then clears the status
+	r = newFakeReconciler(t, server, nil)
+	r.BucketClass.Status.LifecyclePolicy = &nbv1.LifecyclePolicyStatus{ObservedGeneration: 1, AppliedBuckets: 2}
+	server.InjectError("bucket_api", "delete_bucket_lifecycle", &nb.RPCError{RPCCode: "INTERNAL"}, 1)
+	if err := r.updateLifecyclePolicyForBuckets([]string{"first.bucket", "second.bucket"}, 0); err == nil {
+		t.Fatalf("expected the failed removal to be returned")
+	}
+	if status := r.BucketClass.Status.LifecyclePolicy; status == nil || len(status.FailedBuckets) != 1 {
+		t.Fatalf("expected the status to be kept until the policy is removed from all the buckets, got %+v", status)
+	}
+	if err := r.updateLifecyclePolicyForBuckets([]string{"first.bucket", "second.bucket"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.BucketLifecycles) != 0 || r.BucketClass.Status.LifecyclePolicy != nil {
+		t.Fatalf("expected the lifecycle of the buckets and the status to be removed, got %+v %+v",
+			server.BucketLifecycles, r.BucketClass.Status.LifecyclePolicy)
+	}
+
+	// a bucket class that never had a policy does not touch the buckets
+	server.ResetCalls()
+	r = newFakeReconciler(t, server, nil)
+	if err := r.updateLifecyclePolicyForBuckets([]string{"first.bucket"}, 0); err != nil || len(server.Calls()) != 0 {
+		t.Fatalf("expected no calls, got %+v %v", server.Calls(), err)
+	}
+}
+
+func TestGetExistingBuckets(t *testing.T) {
+	server := fake.NewServer()
+	for _, name := range []string{"first.bucket", "second.bucket"} {
+		if err := server.Client().CreateBucketAPI(nb.CreateBucketParams{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := newFakeReconciler(t, server, nil)
+	existing, err := r.getExistingBuckets([]string{"first.bucket", "deleted.bucket", "second.bucket"})
+	if err != nil || len(existing) != 2 || existing[0] != "first.bucket" || existing[1] != "second.bucket" {
+		t.Fatalf("expected the deleted bucket to be skipped, got %v %v", existing, err)
+	}
+
+	server.InjectError("bucket_api", "list_buckets", &nb.RPCError{RPCCode: "INTERNAL"}, 1)
+	if _, err := r.getExistingBuckets([]string{"first.bucket"}); err == nil {
+		t.Fatalf("expected the list error to be returned")
+	}
+}
@@ -0,0 +1,432 @@
+package bucketreplication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/validations"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	sigyaml "sigs.k8s.io/yaml"
+)
+
+var ctx = context.TODO()
+
+// Cmd returns a CLI command
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replication",
+		Short: "Manage noobaa bucket replications",
+	}
+	cmd.AddCommand(
+		CmdCreate(),
+		CmdDelete(),
+		CmdStatus(),
+		CmdList(),
+		CmdReconcile(),
+	)
+	return cmd
+}
+
+// CmdCreate returns a CLI command
+func CmdCreate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <bucket-replication-name>",
+		Short: "Create bucket replication",
+		Run:   RunCreate,
+	}
+	cmd.Flags().String("source-bucket", "", "The name of the noobaa bucket to replicate from")
+	cmd.Flags().String("source-obc", "", "The name of the ObjectBucketClaim whose bucket is replicated (instead of --source-bucket)")
+	cmd.Flags().StringSlice("destination-bucket", nil,
+		"The name of the bucket to replicate to, repeat the flag to add a rule per destination")
+	cmd.Flags().String("prefix", "", "Replicate only objects whose key starts with this prefix")
+	cmd.Flags().Bool("sync-deletions", false, "Replicate deletions of objects on the source bucket")
+	cmd.Flags().Bool("sync-versions", false, "Replicate all the object versions and not only the latest")
+	cmd.Flags().String("log-endpoint-type", "", "Enable log based replication, the endpoint type that writes the logs (AWS, AZURE)")
+	cmd.Flags().String("logs-bucket", "", "The bucket holding the logs for log based replication")
+	cmd.Flags().String("logs-prefix", "", "The prefix of the logs inside the logs bucket")
+	return cmd
+}
+
+// CmdDelete returns a CLI command
+func CmdDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <bucket-replication-name>",
+		Short: "Delete bucket replication",
+		Run:   RunDelete,
+	}
+	return cmd
+}
+
+// CmdStatus returns a CLI command
+func CmdStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status <bucket-replication-name>",
+		Short: "Status bucket replication",
+		Run:   RunStatus,
+	}
+	return cmd
+}
+
+// CmdList returns a CLI command
+func CmdList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List bucket replications",
+		Run:   RunList,
+	}
+	return cmd
+}
+
+// CmdReconcile returns a CLI command
+func CmdReconcile() *cobra.Command {
+	cmd := &cobra.Command{
+		Hidden: true,
+		Use:    "reconcile",
+		Short:  "Runs a reconcile attempt like noobaa-operator",
+		Run:    RunReconcile,
+	}
+	return cmd
+}
+
+// RunCreate runs a CLI command
+func RunCreate(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`❌ Missing expected arguments: <bucket-replication-name> %s`, cmd.UsageString())
+	}
+	name := args[0]
+
+	sourceBucket, _ := cmd.Flags().GetString("source-bucket")
+	sourceOBC, _ := cmd.Flags().GetString("source-obc")
+	destinationBuckets, _ := cmd.Flags().GetStringSlice("destination-bucket")
+	prefix, _ := cmd.Flags().GetString("prefix")
+	syncDeletions, _ := cmd.Flags().GetBool("sync-deletions")
+	syncVersions, _ := cmd.Flags().GetBool("sync-versions")
+	logEndpointType, _ := cmd.Flags().GetString("log-endpoint-type")
+	logsBucket, _ := cmd.Flags().GetString("logs-bucket")
+	logsPrefix, _ := cmd.Flags().GetString("logs-prefix")
+
+	if len(destinationBuckets) == 0 {
+		log.Fatalf(`❌ Missing expected flag: --destination-bucket %s`, cmd.UsageString())
+	}
+
+	// Check and get system
+	o := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaa_cr_yaml)
+	sys := o.(*nbv1.NooBaa)
+	sys.Name = options.SystemName
+	sys.Namespace = options.Namespace
+
+	o = util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_bucketreplication_cr_yaml)
+	bucketReplication := o.(*nbv1.BucketReplication)
+	bucketReplication.Name = name
+	bucketReplication.Namespace = options.Namespace
+	bucketReplication.Spec.Source = nbv1.BucketReplicationSource{
+		BucketName:        sourceBucket,
+		ObjectBucketClaim: sourceOBC,
+	}
+	for i, destination := range destinationBuckets {
+		bucketReplication.Spec.Rules = append(bucketReplication.Spec.Rules, nbv1.BucketReplicationRule{
+			RuleID:            fmt.Sprintf("%s-rule-%d", name, i+1),
+			DestinationBucket: destination,
+			Prefix:            prefix,
+			SyncDeletions:     syncDeletions,
+			SyncVersions:      syncVersions,
+		})
+	}
+	if logEndpointType != "" || logsBucket != "" {
+		bucketReplication.Spec.LogReplicationSource = &nbv1.LogReplicationSource{
+			EndpointType: logEndpointType,
+			LogsBucket:   logsBucket,
+			Prefix:       logsPrefix,
+		}
+	}
+
+	if err := validations.ValidateBucketReplication(bucketReplication); err != nil {
+		log.Fatalf(`❌ %s`, err.Error())
+	}
+
+	if !util.KubeCheck(sys) {
+		log.Fatalf(`❌ Could not find NooBaa system %q in namespace %q`, sys.Name, sys.Namespace)
+	}
+
+	err := util.KubeClient().Get(util.Context(), util.ObjectKey(bucketReplication), bucketReplication)
+	if err == nil {
+		log.Fatalf(`❌ BucketReplication %q already exists in namespace %q`, bucketReplication.Name, bucketReplication.Namespace)
+	}
+
+	// Create bucket replication CR
+	util.Panic(controllerutil.SetControllerReference(sys, bucketReplication, scheme.Scheme))
+	if !util.KubeCreateSkipExisting(bucketReplication) {
+		log.Fatalf(`❌ Could not create BucketReplication %q in Namespace %q (conflict)`, bucketReplication.Name, bucketReplication.Namespace)
+	}
+
+	log.Printf("")
+	util.PrintThisNoteWhenFinishedApplyingAndStartWaitLoop()
+	log.Printf("")
+	log.Printf("BucketReplication Wait Ready:")
+	if WaitReady(bucketReplication) {
+		log.Printf("")
+		log.Printf("")
+		RunStatus(cmd, args)
+	}
+}
+
+// RunDelete runs a CLI command
+func RunDelete(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`❌ Missing expected arguments: <bucket-replication-name> %s`, cmd.UsageString())
+	}
+
+	o := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_bucketreplication_cr_yaml)
+	bucketReplication := o.(*nbv1.BucketReplication)
+	bucketReplication.Name = args[0]
+	bucketReplication.Namespace = options.Namespace
+
+	if !util.KubeDelete(bucketReplication) {
+		log.Fatalf(`❌ Could not delete BucketReplication %q in namespace %q`,
+			bucketReplication.Name, bucketReplication.Namespace)
+	}
+}
+
+// RunStatus runs a CLI command
+func RunStatus(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`❌ Missing expected arguments: <bucket-replication-name> %s`, cmd.UsageString())
+	}
+
+	o := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_bucketreplication_cr_yaml)
+	bucketReplication := o.(*nbv1.BucketReplication)
+	bucketReplication.Name = args[0]
+	bucketReplication.Namespace = options.Namespace
+
+	if !util.KubeCheck(bucketReplication) {
+		log.Fatalf(`❌ Could not get BucketReplication %q in namespace %q`,
+			bucketReplication.Name, bucketReplication.Namespace)
+	}
+
+	CheckPhase(bucketReplication)
+
+	fmt.Println()
+	fmt.Println("# BucketReplication spec:")
+	output, err := sigyaml.Marshal(bucketReplication.Spec)
+	util.Panic(err)
+	fmt.Print(string(output))
+	fmt.Println()
+
+	status := bucketReplication.Status
+	fmt.Println("# BucketReplication status:")
+	fmt.Printf("  %-20s : %s\n", "Source Bucket", status.SourceBucket)
+	lastSync := "never"
+	if status.LastSyncTime != nil {
+		lastSync = status.LastSyncTime.Time.Format(time.RFC3339)
+	}
+	fmt.Printf("  %-20s : %s\n", "Last Sync", lastSync)
+	if status.LastError != "" {
+		fmt.Printf("  %-20s : %s\n", "Last Error", status.LastError)
+	}
+	fmt.Println()
+}
+
+// WaitReady waits until the bucket replication phase changes to ready by the operator
+func WaitReady(bucketReplication *nbv1.BucketReplication) bool {
+	log := util.Logger()
+	klient := util.KubeClient()
+
+	interval := time.Duration(3)
+
+	err := wait.PollUntilContextCancel(ctx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		err := klient.Get(util.Context(), util.ObjectKey(bucketReplication), bucketReplication)
+		if err != nil {
+			log.Printf("⏳ Failed to get BucketReplication: %s", err)
+			return false, nil
+		}
+		CheckPhase(bucketReplication)
+		if bucketReplication.Status.Phase == nbv1.BucketReplicationPhaseRejected {
+			return false, fmt.Errorf("BucketReplicationPhaseRejected")
+		}
+		if bucketReplication.Status.Phase != nbv1.BucketReplicationPhaseReady {
+			return false, nil
+		}
+		return true, nil
+	})
+	return err == nil
+}
+
+// CheckPhase prints the phase and reason for it
+func CheckPhase(bucketReplication *nbv1.BucketReplication) {
+	log := util.Logger()
+
+	reason := "waiting..."
+	for _, c := range bucketReplication.Status.Conditions {
+		if c.Type == "Available" {
+			reason = fmt.Sprintf("%s %s", c.Reason, c.Message)
+		}
+	}
+
+	switch bucketReplication.Status.Phase {
+
+	case nbv1.BucketReplicationPhaseReady:
+		log.Printf("✅ BucketReplication %q Phase is Ready", bucketReplication.Name)
+
+	case nbv1.BucketReplicationPhaseRejected:
+		log.Errorf("❌ BucketReplication %q Phase is %q: %s", bucketReplication.Name, bucketReplication.Status.Phase, reason)
+
+	case nbv1.BucketReplicationPhaseVerifying:
+		fallthrough
+	case nbv1.BucketReplicationPhaseConfiguring:
+		fallthrough
+	case nbv1.BucketReplicationPhaseDeleting:
+		fallthrough
+	default:
+		log.Printf("⏳ BucketReplication %q Phase is %q: %s", bucketReplication.Name, bucketReplication.Status.Phase, reason)
+	}
+}
+
+// RunList runs a CLI command
+func RunList(cmd *cobra.Command, args []string) {
+	list := &nbv1.BucketReplicationList{
+		TypeMeta: metav1.TypeMeta{Kind: "BucketReplicationList"},
+	}
+	if !util.KubeList(list, &client.ListOptions{Namespace: options.Namespace}) {
+		return
+	}
+	if len(list.Items) == 0 {
+		fmt.Printf("No bucket replications found.\n")
+		return
+	}
+	table := (&util.PrintTable{}).AddRow(
+		"NAME",
+		"SOURCE",
+		"RULES",
+		"PHASE",
+		"LAST-SYNC",
+		"AGE",
+	)
+	for i := range list.Items {
+		br := &list.Items[i]
+		lastSync := "never"
+		if br.Status.LastSyncTime != nil {
+			lastSync = time.Since(br.Status.LastSyncTime.Time).Round(time.Second).String()
+		}
+		table.AddRow(
+			br.Name,
+			br.Status.SourceBucket,
+			fmt.Sprint(len(br.Spec.Rules)),
+			string(br.Status.Phase),
+			lastSync,
+			time.Since(br.CreationTimestamp.Time).Round(time.Second).String(),
+		)
+	}
+	fmt.Print(table.String())
+}
+
+// RunReconcile runs a CLI command
+func RunReconcile(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`Missing expected arguments: <bucket-replication-name> %s`, cmd.UsageString())
+	}
+	bucketReplicationName := args[0]
+	klient := util.KubeClient()
+	interval := time.Duration(3)
+	util.Panic(wait.PollUntilContextCancel(ctx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: options.Namespace,
+				Name:      bucketReplicationName,
+			},
+		}
+		res, err := NewReconciler(req.NamespacedName, klient, scheme.Scheme, nil).Reconcile()
+		if err != nil {
+			return false, err
+		}
+		if res.RequeueAfter != 0 {
+			log.Printf("\nRetrying in %d seconds\n", interval)
+			return false, nil
+		}
+		return true, nil
+	}))
+}
+
+// CreateReplicationPolicyStructure converts the BucketReplication spec to the
+// replication policy structure expected by bucket_api.put_bucket_replication()
+func CreateReplicationPolicyStructure(spec *nbv1.BucketReplicationSpec) nb.ReplicationPolicy {
+	policy := nb.ReplicationPolicy{}
+	for _, rule := range spec.Rules {
+		replicationRule := nb.ReplicationRule{
+			RuleID:            rule.RuleID,
+			DestinationBucket: rule.DestinationBucket,
+			SyncDeletions:     rule.SyncDeletions,
+			SyncVersions:      rule.SyncVersions,
+		}
+		if rule.Prefix != "" {
+			replicationRule.Filter = &nb.ReplicationRuleFilter{Prefix: rule.Prefix}
+		}
+		policy.Rules = append(policy.Rules, replicationRule)
+	}
+	if logSource := spec.LogReplicationSource; logSource != nil {
+		logInfo := nb.LogReplicationInfo{EndpointType: logSource.EndpointType}
+		if logSource.LogsBucket != "" {
+			logInfo.LogsLocation = &nb.LogsLocation{
+				LogsBucket: logSource.LogsBucket,
+				Prefix:     logSource.Prefix,
+			}
+		}
+		policy.LogReplicationInfo = logInfo
+	}
+	return policy
+}
+
+// replicationPolicyFields are the fields of a replication policy that the operator sets
+type replicationPolicyFields struct {
+	Rules              []nb.ReplicationRule   `json:"rules,omitempty"`
+	LogReplicationInfo *nb.LogReplicationInfo `json:"log_replication_info,omitempty"`
+}
+
+// IsReplicationPolicyApplied returns true if the replication policy read from noobaa has the rules
+// and the log replication info of the desired policy. Fields that the operator does not set are ignored.
+func IsReplicationPolicyApplied(applied nb.ReplicationPolicy, desired nb.ReplicationPolicy) bool {
+	appliedFields, err := getReplicationPolicyFields(applied)
+	if err != nil {
+		return false
+	}
+	desiredFields, err := getReplicationPolicyFields(desired)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(appliedFields, desiredFields)
+}
+
+// getReplicationPolicyFields decodes the policy, whose rules are either typed or decoded from json, to the operator fields
+func getReplicationPolicyFields(policy nb.ReplicationPolicy) (*replicationPolicyFields, error) {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	fields := &replicationPolicyFields{}
+	if err := json.Unmarshal(data, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
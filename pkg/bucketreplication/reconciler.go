@@ -0,0 +1,487 @@
+package bucketreplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	obv1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/system"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/validations"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// resyncInterval is the interval in which the replication policy is verified on the source bucket,
+// and in which rejected replications are checked again since their conflicts are resolved in other resources
+const resyncInterval = 5 * time.Minute
+
+// Reconciler is the context for loading or reconciling a bucket replication
+type Reconciler struct {
+	Request  types.NamespacedName
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Ctx      context.Context
+	Logger   *logrus.Entry
+	Recorder events.EventRecorder
+
+	NBClient nb.Client
+
+	BucketReplication *nbv1.BucketReplication
+	NooBaa            *nbv1.NooBaa
+}
+
+// NewReconciler initializes a reconciler to be used for loading or reconciling a bucket replication
+func NewReconciler(
+	req types.NamespacedName,
+	client client.Client,
+	scheme *runtime.Scheme,
+	recorder events.EventRecorder,
+) *Reconciler {
+
+	r := &Reconciler{
+		Request:           req,
+		Client:            client,
+		Scheme:            scheme,
+		Recorder:          recorder,
+		Ctx:               context.TODO(),
+		Logger:            logrus.WithField("bucketreplication", req.Namespace+"/"+req.Name),
+		BucketReplication: util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_bucketreplication_cr_yaml).(*nbv1.BucketReplication),
+		NooBaa:            util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaa_cr_yaml).(*nbv1.NooBaa),
+	}
+
+	// Set Namespace
+	r.BucketReplication.Namespace = r.Request.Namespace
+	r.NooBaa.Namespace = options.Namespace
+
+	// Set Names
+	r.BucketReplication.Name = r.Request.Name
+	r.NooBaa.Name = options.SystemName
+
+	return r
+}
+
+// Reconcile reads that state of the cluster for a BucketReplication object,
+// and makes changes based on the state read and what is in the BucketReplication.Spec.
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *Reconciler) Reconcile() (reconcile.Result, error) {
+	var err error
+	res := reconcile.Result{}
+	log := r.Logger
+	log.Infof("Start BucketReplication Reconcile...")
+
+	systemFound := system.CheckSystem(r.NooBaa)
+
+	if !util.KubeCheck(r.BucketReplication) {
+		log.Infof("❌ BucketReplication %q not found or deleted.", r.BucketReplication.Name)
+		return res, nil
+	}
+
+	if r.BucketReplication.DeletionTimestamp != nil {
+		err = r.ReconcileDeletion(systemFound)
+		if err != nil {
+			res.RequeueAfter = 3 * time.Second
+			log.Warnf("⏳ Temporary Error: %s", err)
+		}
+		return res, nil
+	}
+
+	if !systemFound {
+		log.Infof("NooBaa not found or already deleted. Skip reconcile.")
+		return res, nil
+	}
+
+	if util.EnsureCommonMetaFields(r.BucketReplication, nbv1.Finalizer) {
+		if !util.KubeUpdate(r.BucketReplication) {
+			log.Errorf("❌ BucketReplication %q failed to add mandatory meta fields", r.BucketReplication.Name)
+
+			res.RequeueAfter = 3 * time.Second
+			return res, nil
+		}
+	}
+
+	err = r.ReconcilePhases()
+	if err != nil {
+		r.BucketReplication.Status.LastError = err.Error()
+		if perr, isPERR := err.(*util.PersistentError); isPERR {
+			r.SetPhase(nbv1.BucketReplicationPhaseRejected, perr.Reason, perr.Message)
+			log.Errorf("❌ Persistent Error: %s", err)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(r.BucketReplication, nil, corev1.EventTypeWarning, perr.Reason, perr.Reason, perr.Message)
+			}
+			// only a spec change fixes an invalid spec, other rejections depend on OBCs, BucketClasses and buckets
+			if perr.Reason != "ValidationError" {
+				res.RequeueAfter = resyncInterval
+			}
+		} else {
+			res.RequeueAfter = 3 * time.Second
+			// leave current phase as is
+			r.SetPhase("", "TemporaryError", err.Error())
+			log.Warnf("⏳ Temporary Error: %s", err)
+		}
+	} else {
+		r.SetPhase(
+			nbv1.BucketReplicationPhaseReady,
+			"BucketReplicationPhaseReady",
+			"noobaa operator completed reconcile - bucket replication is ready",
+		)
+		res.RequeueAfter = resyncInterval
+		log.Infof("✅ Done")
+	}
+
+	err = r.UpdateStatus()
+	// if updateStatus will fail to update the CR for any reason we will continue to requeue the reconcile
+	// until the spec status will reflect the actual status of the bucket replication
+	if err != nil {
+		res.RequeueAfter = 3 * time.Second
+		log.Warnf("⏳ Temporary Error: %s", err)
+	}
+	return res, nil
+}
+
+// ReconcilePhases runs the reconcile flow and populates BucketReplication.Status.
+func (r *Reconciler) ReconcilePhases() error {
+
+	if err := r.ReconcilePhaseVerifying(); err != nil {
+		return err
+	}
+	if err := r.ReconcilePhaseConfiguring(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetPhase updates the status phase and conditions
+func (r *Reconciler) SetPhase(phase nbv1.BucketReplicationPhase, reason string, message string) {
+
+	c := &r.BucketReplication.Status.Conditions
+
+	if phase == "" {
+		r.Logger.Infof("SetPhase: temporary error during phase %q", r.BucketReplication.Status.Phase)
+		util.SetProgressingCondition(c, reason, message)
+		return
+	}
+
+	r.Logger.Infof("SetPhase: %s", phase)
+	r.BucketReplication.Status.Phase = phase
+	switch phase {
+	case nbv1.BucketReplicationPhaseReady:
+		util.SetAvailableCondition(c, reason, message)
+	case nbv1.BucketReplicationPhaseRejected:
+		util.SetErrorCondition(c, reason, message)
+	default:
+		util.SetProgressingCondition(c, reason, message)
+	}
+}
+
+// UpdateStatus updates the bucket replication status in kubernetes from the memory
+func (r *Reconciler) UpdateStatus() error {
+	err := r.Client.Status().Update(r.Ctx, r.BucketReplication)
+	if err != nil {
+		r.Logger.Errorf("UpdateStatus: %s", err)
+		return err
+	}
+	r.Logger.Infof("UpdateStatus: Done")
+	return nil
+}
+
+// ReconcilePhaseVerifying validates the spec and resolves the source bucket
+func (r *Reconciler) ReconcilePhaseVerifying() error {
+
+	r.SetPhase(
+		nbv1.BucketReplicationPhaseVerifying,
+		"BucketReplicationPhaseVerifying",
+		"noobaa operator started phase 1/2 - \"Verifying\"",
+	)
+
+	if err := validations.ValidateBucketReplication(r.BucketReplication); err != nil {
+		return util.NewPersistentError("ValidationError", err.Error())
+	}
+
+	if r.NooBaa.UID == "" {
+		return util.NewPersistentError("MissingSystem",
+			fmt.Sprintf("NooBaa system %q not found or deleted", r.NooBaa.Name))
+	}
+
+	sourceBucket, err := r.resolveSourceBucket()
+	if err != nil {
+		return err
+	}
+
+	// the policy of a previous source would keep replicating after the source changed
+	previousSource := r.BucketReplication.Status.SourceBucket
+	if previousSource != "" && previousSource != sourceBucket {
+		if r.NBClient == nil {
+			sysClient, err := system.Connect(false)
+			if err != nil {
+				return err
+			}
+			r.NBClient = sysClient.NBClient
+		}
+		if err := r.removeReplicationPolicy(previousSource); err != nil {
+			return err
+		}
+	}
+	r.BucketReplication.Status.SourceBucket = sourceBucket
+
+	return nil
+}
+
+// ReconcilePhaseConfiguring validates the replication policy with noobaa and applies it to the source bucket
+func (r *Reconciler) ReconcilePhaseConfiguring() error {
+
+	r.SetPhase(
+		nbv1.BucketReplicationPhaseConfiguring,
+		"BucketReplicationPhaseConfiguring",
+		"noobaa operator started phase 2/2 - \"Configuring\"",
+	)
+
+	sysClient, err := system.Connect(false)
+	if err != nil {
+		return err
+	}
+	r.NBClient = sysClient.NBClient
+
+	replicationParams := nb.BucketReplicationParams{
+		Name:              r.BucketReplication.Status.SourceBucket,
+		ReplicationPolicy: CreateReplicationPolicyStructure(&r.BucketReplication.Spec),
+	}
+
+	// a policy that is already in place is only verified, a changed or missing policy is applied again
+	// a missing bucket is rejected by the validation of the policy
+	appliedPolicy, err := r.NBClient.GetBucketReplicationAPI(nb.ReadBucketParams{Name: replicationParams.Name})
+	if nbErr, ok := err.(*nb.RPCError); err != nil && (!ok || nbErr.RPCCode != "NO_SUCH_BUCKET") {
+		return fmt.Errorf("failed to read replication of bucket %q: %w", replicationParams.Name, err)
+	}
+	if err != nil || !IsReplicationPolicyApplied(appliedPolicy, replicationParams.ReplicationPolicy) {
+		if err := r.applyReplicationPolicy(replicationParams); err != nil {
+			return err
+		}
+	}
+
+	now := metav1.Now()
+	r.BucketReplication.Status.LastSyncTime = &now
+	r.BucketReplication.Status.ObservedGeneration = r.BucketReplication.Generation
+	r.BucketReplication.Status.LastError = ""
+
+	return nil
+}
+
+// applyReplicationPolicy validates the replication policy with noobaa, puts it on the source bucket
+// and reads it back to verify it was applied
+func (r *Reconciler) applyReplicationPolicy(replicationParams nb.BucketReplicationParams) error {
+	if err := r.NBClient.ValidateReplicationAPI(replicationParams); err != nil {
+		if rpcErr, isRPCErr := err.(*nb.RPCError); isRPCErr {
+			switch rpcErr.RPCCode {
+			case "INVALID_REPLICATION_POLICY", "INVALID_LOG_REPLICATION_INFO", "NO_SUCH_BUCKET":
+				return util.NewPersistentError("InvalidReplicationPolicy",
+					fmt.Sprintf("BucketReplication %q was rejected by noobaa: %s", r.BucketReplication.Name, rpcErr.Message))
+			}
+		}
+		return fmt.Errorf("failed to validate replication of bucket %q: %w", replicationParams.Name, err)
+	}
+
+	if err := r.NBClient.PutBucketReplicationAPI(replicationParams); err != nil {
+		return fmt.Errorf("failed to put replication on bucket %q: %w", replicationParams.Name, err)
+	}
+
+	appliedPolicy, err := r.NBClient.GetBucketReplicationAPI(nb.ReadBucketParams{Name: replicationParams.Name})
+	if err != nil {
+		return fmt.Errorf("failed to read replication of bucket %q: %w", replicationParams.Name, err)
+	}
+	if !IsReplicationPolicyApplied(appliedPolicy, replicationParams.ReplicationPolicy) {
+		return fmt.Errorf("replication policy of bucket %q does not match the spec after it was applied", replicationParams.Name)
+	}
+	r.Logger.Infof("✅ Applied replication policy on bucket %q", replicationParams.Name)
+
+	return nil
+}
+
+// ReconcileDeletion removes the replication policy from the source bucket and releases the finalizer
+func (r *Reconciler) ReconcileDeletion(systemFound bool) error {
+
+	// Set the phase to let users know the operator has noticed the deletion request
+	if r.BucketReplication.Status.Phase != nbv1.BucketReplicationPhaseDeleting {
+		r.SetPhase(
+			nbv1.BucketReplicationPhaseDeleting,
+			"BucketReplicationPhaseDeleting",
+			"noobaa operator started deletion",
+		)
+		err := r.UpdateStatus()
+		if err != nil {
+			return err
+		}
+	}
+
+	sourceBucket := r.BucketReplication.Status.SourceBucket
+	if !systemFound || sourceBucket == "" {
+		r.Logger.Infof("BucketReplication %q remove finalizer, no replication to remove", r.BucketReplication.Name)
+		return r.FinalizeDeletion()
+	}
+
+	sysClient, err := system.Connect(false)
+	if err != nil {
+		return err
+	}
+	r.NBClient = sysClient.NBClient
+
+	if err := r.removeReplicationPolicy(sourceBucket); err != nil {
+		return err
+	}
+
+	return r.FinalizeDeletion()
+}
+
+// removeReplicationPolicy deletes the replication policy of the bucket, a missing bucket has nothing to remove
+func (r *Reconciler) removeReplicationPolicy(bucketName string) error {
+	err := r.NBClient.DeleteBucketReplicationAPI(nb.DeleteBucketReplicationParams{Name: bucketName})
+	if err != nil {
+		if nbErr, ok := err.(*nb.RPCError); ok && nbErr.RPCCode == "NO_SUCH_BUCKET" {
+			r.Logger.Warnf("Source bucket %q of replication was not found", bucketName)
+			return nil
+		}
+		return fmt.Errorf("failed to delete replication of bucket %q: %w", bucketName, err)
+	}
+	r.Logger.Infof("✅ Removed replication policy from bucket %q", bucketName)
+	return nil
+}
+
+// FinalizeDeletion removed the finalizer and updates in order to let the bucket replication get reclaimed by kubernetes
+func (r *Reconciler) FinalizeDeletion() error {
+	util.RemoveFinalizer(r.BucketReplication, nbv1.Finalizer)
+	if !util.KubeUpdate(r.BucketReplication) {
+		return fmt.Errorf("BucketReplication %q failed to remove finalizer %q", r.BucketReplication.Name, nbv1.Finalizer)
+	}
+	return nil
+}
+
+// resolveSourceBucket returns the noobaa bucket name of the replication source.
+// When the source is an OBC it must be bound. The source bucket must not be replicated by an OBC
+// with its own replicationPolicy or by an older BucketReplication, otherwise they would keep overriding each other.
+func (r *Reconciler) resolveSourceBucket() (string, error) {
+	source := r.BucketReplication.Spec.Source
+	sourceBucket := source.BucketName
+
+	if source.ObjectBucketClaim != "" {
+		obc := &nbv1.ObjectBucketClaim{
+			TypeMeta: metav1.TypeMeta{Kind: "ObjectBucketClaim"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      source.ObjectBucketClaim,
+				Namespace: r.BucketReplication.Namespace,
+			},
+		}
+		if !util.KubeCheck(obc) {
+			return "", fmt.Errorf("ObjectBucketClaim %q not found in namespace %q", obc.Name, obc.Namespace)
+		}
+		if err := checkOBCReplicationPolicy(obc, r.getOBCBucketClass(obc), r.BucketReplication.Name); err != nil {
+			return "", err
+		}
+		if obc.Status.Phase != obv1.ObjectBucketClaimStatusPhaseBound || obc.Spec.BucketName == "" {
+			return "", fmt.Errorf("ObjectBucketClaim %q is not bound yet (phase %q)", obc.Name, obc.Status.Phase)
+		}
+		sourceBucket = obc.Spec.BucketName
+	}
+
+	obcs := &nbv1.ObjectBucketClaimList{TypeMeta: metav1.TypeMeta{Kind: "ObjectBucketClaim"}}
+	replications := &nbv1.BucketReplicationList{TypeMeta: metav1.TypeMeta{Kind: "BucketReplication"}}
+	if !util.KubeList(obcs) || !util.KubeList(replications, client.InNamespace(r.BucketReplication.Namespace)) {
+		return "", fmt.Errorf("failed to list the replications of bucket %q", sourceBucket)
+	}
+	if err := checkConflictingReplication(r.BucketReplication, sourceBucket, obcs.Items, replications.Items, r.getOBCBucketClass); err != nil {
+		return "", err
+	}
+	return sourceBucket, nil
+}
+
+// checkConflictingReplication returns a persistent error when the source bucket of the replication is also
+// replicated by the replicationPolicy of an OBC or of its BucketClass, or by a BucketReplication that was created before this one
+func checkConflictingReplication(
+	replication *nbv1.BucketReplication,
+	sourceBucket string,
+	obcs []nbv1.ObjectBucketClaim,
+	replications []nbv1.BucketReplication,
+	getBucketClass func(*nbv1.ObjectBucketClaim) *nbv1.BucketClass,
+) error {
+	for i := range obcs {
+		obc := &obcs[i]
+		if obc.Spec.BucketName != sourceBucket {
+			continue
+		}
+		if err := checkOBCReplicationPolicy(obc, getBucketClass(obc), replication.Name); err != nil {
+			return err
+		}
+	}
+	for i := range replications {
+		other := &replications[i]
+		if other.UID == replication.UID || other.DeletionTimestamp != nil || other.Status.SourceBucket != sourceBucket {
+			continue
+		}
+		if other.CreationTimestamp.Before(&replication.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&replication.CreationTimestamp) && other.Name < replication.Name) {
+			return util.NewPersistentError("ConflictingReplicationPolicy",
+				fmt.Sprintf("bucket %q is already replicated by BucketReplication %s/%s", sourceBucket, other.Namespace, other.Name))
+		}
+	}
+	return nil
+}
+
+// checkOBCReplicationPolicy returns a persistent error when the OBC or its BucketClass sets a replicationPolicy,
+// which the provisioner applies to the bucket of the OBC
+func checkOBCReplicationPolicy(obc *nbv1.ObjectBucketClaim, bucketClass *nbv1.BucketClass, replicationName string) error {
+	if obc.Spec.AdditionalConfig["replicationPolicy"] != "" {
+		return util.NewPersistentError("ConflictingReplicationPolicy",
+			fmt.Sprintf("ObjectBucketClaim %s/%s already sets additionalConfig.replicationPolicy, remove it to manage replication with BucketReplication %q",
+				obc.Namespace, obc.Name, replicationName))
+	}
+	if bucketClass != nil && bucketClass.Spec.ReplicationPolicy != "" {
+		return util.NewPersistentError("ConflictingReplicationPolicy",
+			fmt.Sprintf("BucketClass %s/%s of ObjectBucketClaim %s/%s already sets replicationPolicy, use a BucketClass without it to manage replication with BucketReplication %q",
+				bucketClass.Namespace, bucketClass.Name, obc.Namespace, obc.Name, replicationName))
+	}
+	return nil
+}
+
+// getOBCBucketClass returns the BucketClass the OBC was provisioned with, or nil when it is not found.
+// The BucketClass is looked up in the namespace of the OBC first, then in the noobaa system namespace, like the provisioner does.
+func (r *Reconciler) getOBCBucketClass(obc *nbv1.ObjectBucketClaim) *nbv1.BucketClass {
+	bucketClassName := ""
+	if obc.Spec.ObjectBucketName != "" {
+		ob := &nbv1.ObjectBucket{
+			TypeMeta:   metav1.TypeMeta{Kind: "ObjectBucket"},
+			ObjectMeta: metav1.ObjectMeta{Name: obc.Spec.ObjectBucketName},
+		}
+		if util.KubeCheckQuiet(ob) && ob.Spec.Connection != nil {
+			bucketClassName = ob.Spec.AdditionalState["bucketclass"]
+		}
+	}
+	if bucketClassName == "" {
+		bucketClassName = obc.Spec.AdditionalConfig["bucketclass"]
+	}
+	if bucketClassName == "" {
+		return nil
+	}
+	for _, namespace := range []string{obc.Namespace, r.NooBaa.Namespace} {
+		bucketClass := &nbv1.BucketClass{
+			TypeMeta:   metav1.TypeMeta{Kind: "BucketClass"},
+			ObjectMeta: metav1.ObjectMeta{Name: bucketClassName, Namespace: namespace},
+		}
+		if util.KubeCheckQuiet(bucketClass) {
+			return bucketClass
+		}
+	}
+	return nil
+}
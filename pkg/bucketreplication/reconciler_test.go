@@ -0,0 +1,109 @@
+package bucketreplication
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb/fake"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCheckConflictingReplication(t *testing.T) {
+	created := metav1.NewTime(time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC))
+	earlier := metav1.NewTime(created.Add(-time.Hour))
+	newReplication := func(name string, uid types.UID, creation metav1.Time, sourceBucket string) nbv1.BucketReplication {
+		br := nbv1.BucketReplication{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "noobaa", UID: uid, CreationTimestamp: creation}}
+		br.Status.SourceBucket = sourceBucket
+		return br
+	}
+	replication := newReplication("repl", "1", created, "")
+	obcWithPolicy := nbv1.ObjectBucketClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "app"}}
+	obcWithPolicy.Spec.BucketName = "bucket-a"
+	obcWithPolicy.Spec.AdditionalConfig = map[string]string{"replicationPolicy": `{"rules":[]}`}
+	obcOfClassWithPolicy := nbv1.ObjectBucketClaim{ObjectMeta: metav1.ObjectMeta{Name: "class-claim", Namespace: "app"}}
+	obcOfClassWithPolicy.Spec.BucketName = "bucket-a"
+	obcOfClassWithPolicy.Spec.AdditionalConfig = map[string]string{"bucketclass": "replicated-class"}
+	obcOfClassWithPolicy.Spec.ObjectBucketName = "obc-app-class-claim"
+	getBucketClass := func(obc *nbv1.ObjectBucketClaim) *nbv1.BucketClass {
+		if obc.Spec.AdditionalConfig["bucketclass"] != "replicated-class" {
+			return nil
+		}
+		bucketClass := &nbv1.BucketClass{ObjectMeta: metav1.ObjectMeta{Name: "replicated-class", Namespace: "noobaa"}}
+		bucketClass.Spec.ReplicationPolicy = `{"rules":[]}`
+		return bucketClass
+	}
+
+	tests := []struct {
+		name         string
+		obcs         []nbv1.ObjectBucketClaim
+		replications []nbv1.BucketReplication
+		conflict     bool
+	}{
+		{"no other replication", nil, []nbv1.BucketReplication{replication}, false},
+		{"bucket of an obc with a replication policy", []nbv1.ObjectBucketClaim{obcWithPolicy}, nil, true},
+		{"bucket of an obc whose bucketclass has a replication policy", []nbv1.ObjectBucketClaim{obcOfClassWithPolicy}, nil, true},
+		{"older replication of the bucket", nil, []nbv1.BucketReplication{newReplication("older", "2", earlier, "bucket-a")}, true},
+		{"newer replication of the bucket", nil, []nbv1.BucketReplication{newReplication("newer", "2", metav1.NewTime(created.Add(time.Hour)), "bucket-a")}, false},
+		{"older replication of another bucket", nil, []nbv1.BucketReplication{newReplication("older", "2", earlier, "bucket-b")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkConflictingReplication(&replication, "bucket-a", tt.obcs, tt.replications, getBucketClass)
+			if tt.conflict {
+				if perr, ok := err.(*util.PersistentError); !ok || perr.Reason != "ConflictingReplicationPolicy" {
+					t.Fatalf("expected a ConflictingReplicationPolicy error, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestRemoveReplicationPolicy(t *testing.T) {
+	server := fake.NewServer()
+	r := &Reconciler{NBClient: server.Client(), Logger: logrus.WithField("test", t.Name())}
+	if err := r.NBClient.CreateBucketAPI(nb.CreateBucketParams{Name: "old-source"}); err != nil {
+		t.Fatal(err)
+	}
+	server.BucketReplications["old-source"] = &nb.ReplicationPolicy{}
+
+	if err := r.removeReplicationPolicy("old-source"); err != nil {
+		t.Fatal(err)
+	}
+	if server.BucketReplications["old-source"] != nil {
+		t.Fatalf("expected the replication policy of the previous source to be removed")
+	}
+	if err := r.removeReplicationPolicy("missing"); err != nil {
+		t.Fatalf("expected a missing bucket to be ignored, got %v", err)
+	}
+}
+
+func TestIsReplicationPolicyApplied(t *testing.T) {
+	spec := &nbv1.BucketReplicationSpec{
+		Rules: []nbv1.BucketReplicationRule{{RuleID: "rule-1", DestinationBucket: "target", Prefix: "logs/"}},
+	}
+	desired := CreateReplicationPolicyStructure(spec)
+
+	// the policy read from noobaa is decoded from json and may hold fields the operator does not set
+	var applied nb.ReplicationPolicy
+	if err := json.Unmarshal([]byte(`{"rules":[{"rule_id":"rule-1","destination_bucket":"target","filter":{"prefix":"logs/"},"rule_status":{}}]}`), &applied); err != nil {
+		t.Fatal(err)
+	}
+	if !IsReplicationPolicyApplied(applied, desired) {
+		t.Fatalf("expected the policy read from noobaa to match the spec")
+	}
+	if IsReplicationPolicyApplied(nb.ReplicationPolicy{}, desired) {
+		t.Fatalf("expected a missing policy not to match the spec")
+	}
+	spec.Rules[0].SyncDeletions = true
+	if IsReplicationPolicyApplied(applied, CreateReplicationPolicyStructure(spec)) {
+		t.Fatalf("expected a changed rule not to match the spec")
+	}
+}
@@ -2,7 +2,7 @@ package bundle
 
 const Version = "5.23.0"
 
-const Sha256_deploy_cluster_role_yaml = "9c0ac29295c67349acc345029b8b2e3de01a512d5dc92e31b17938b52f41c60d"
+const Sha256_deploy_cluster_role_yaml = "141e4c65e507f622012017ca49678be131311630e7b22e8d546fa95fc1d9823c"
 
 const File_deploy_cluster_role_yaml = `apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRole
@@ -71,6 +71,18 @@ rules:
       - get
       - list
       - watch
+  - nonResourceURLs: # the service account issuer of the kubernetes OIDC provider
+      - /.well-known/openid-configuration
+      - /openid/v1/jwks
+    verbs:
+      - get
+  - apiGroups: # the API server addresses for the egress network policies
+      - discovery.k8s.io
+    resources:
+      - endpointslices
+    verbs:
+      - get
+      - list
   - apiGroups: # from system:auth-delegator
       - authentication.k8s.io
     resources:
@@ -305,7 +317,7 @@ spec:
 
 `
 
-const Sha256_deploy_crds_noobaa_io_backingstores_yaml = "86a2cc60a53782e3eaab5ecc236f4219c9f32d36c713949c53b43fd6c932833b"
+const Sha256_deploy_crds_noobaa_io_backingstores_yaml = "755adfc0727c60093d0545bee38559be8ccb8436f8bec3a28146d29a4aa6fe31"
 
 const File_deploy_crds_noobaa_io_backingstores_yaml = `---
 apiVersion: apiextensions.k8s.io/v1
@@ -497,6 +509,15 @@ spec:
               pvPool:
                 description: PVPool specifies a backing store of type pv-pool
                 properties:
+                  decommission:
+                    description: |-
+                      Decommission (optional) is a list of pv-pool pods to retire. The data of each pod is evacuated
+                      to the other pods before the pod and its PVC are deleted. A pod can also be retired by setting
+                      the annotation noobaa.io/decommission=true on it. When NumVolumes is not decreased accordingly,
+                      a new pod and PVC replace the retired ones.
+                    items:
+                      type: string
+                    type: array
                   numVolumes:
                     description: NumVolumes is the number of volumes to allocate
                     type: integer
@@ -641,6 +662,41 @@ spec:
                 description: Phase is a simple, high-level summary of where the backing
                   store is in its lifecycle
                 type: string
+              pvPoolDecommission:
+                description: PVPoolDecommission is the progress of the pv-pool pods
+                  that are decommissioned
+                items:
+                  description: PVPoolDecommissionStatus is the progress of decommissioning
+                    a pv-pool pod and its PVC
+                  properties:
+                    completionTime:
+                      description: CompletionTime is the time the pod and its PVC
+                        were deleted
+                      format: date-time
+                      type: string
+                    phase:
+                      description: Phase is the step of the decommission
+                      type: string
+                    podName:
+                      description: PodName is the name of the decommissioned pod
+                      type: string
+                    progress:
+                      description: Progress is the percentage of the data that was
+                        evacuated from the pod
+                      type: integer
+                    pvcName:
+                      description: PVCName is the name of the PVC of the decommissioned
+                        pod
+                      type: string
+                    startTime:
+                      description: StartTime is the time the decommission started
+                      format: date-time
+                      type: string
+                  required:
+                  - phase
+                  - podName
+                  type: object
+                type: array
               relatedObjects:
                 description: RelatedObjects is a list of objects related to this operator.
                 items:
@@ -696,7 +752,7 @@ spec:
       status: {}
 `
 
-const Sha256_deploy_crds_noobaa_io_bucketclasses_yaml = "d31ffd534863d5fec56bbac39cf4f956fb613f5547b4402c36f19e9bc344df49"
+const Sha256_deploy_crds_noobaa_io_bucketclasses_yaml = "11944dd5773eff116bf2c881471e646d533b88fba52e7084b629fe9bbf5ed985"
 
 const File_deploy_crds_noobaa_io_bucketclasses_yaml = `---
 apiVersion: apiextensions.k8s.io/v1
@@ -775,6 +831,56 @@ spec:
                       currently only supports IBM Deep Archive as the archive target
                     type: string
                 type: object
+              lifecyclePolicy:
+                description: |-
+                  LifecyclePolicy specifies the lifecycle rules that are applied to every bucket of the bucket class.
+                  Setting an empty list of rules removes the lifecycle configuration from the buckets.
+                properties:
+                  rules:
+                    description: Rules is the list of lifecycle rules to apply on
+                      every bucket of the bucket class
+                    items:
+                      description: LifecycleRule specifies a single lifecycle rule
+                        of a bucket class
+                      properties:
+                        abortIncompleteMultipartUploadDays:
+                          description: AbortIncompleteMultipartUploadDays is the number
+                            of days after which incomplete multipart uploads are aborted
+                          minimum: 1
+                          type: integer
+                        disabled:
+                          description: Disabled keeps the rule in the policy without
+                            enforcing it
+                          type: boolean
+                        expirationDays:
+                          description: ExpirationDays is the age in days after which
+                            current objects expire
+                          minimum: 1
+                          type: integer
+                        id:
+                          description: ID is the unique identifier of the rule
+                          type: string
+                        newerNoncurrentVersions:
+                          description: |-
+                            NewerNoncurrentVersions is the number of newest noncurrent versions to retain
+                            regardless of NoncurrentVersionExpirationDays
+                          minimum: 0
+                          type: integer
+                        noncurrentVersionExpirationDays:
+                          description: NoncurrentVersionExpirationDays is the number
+                            of days after which noncurrent object versions expire
+                          minimum: 1
+                          type: integer
+                        prefix:
+                          description: |-
+                            Prefix limits the rule to objects whose key starts with the prefix.
+                            When empty the rule applies to all the objects of the bucket.
+                          type: string
+                      required:
+                      - id
+                      type: object
+                    type: array
+                type: object
               namespacePolicy:
                 description: NamespacePolicy specifies the namespace policy for the
                   bucket class
@@ -920,6 +1026,28 @@ spec:
                   - type
                   type: object
                 type: array
+              lifecyclePolicy:
+                description: LifecyclePolicy reports the result of applying the
+                  lifecycle policy to the buckets of the bucket class
+                properties:
+                  appliedBuckets:
+                    description: AppliedBuckets is the number of buckets of the
+                      bucket class that have the current lifecycle policy
+                    type: integer
+                  failedBuckets:
+                    description: FailedBuckets is the list of buckets that failed
+                      to get the lifecycle policy
+                    items:
+                      type: string
+                    type: array
+                  observedGeneration:
+                    description: ObservedGeneration is the bucket class generation
+                      the lifecycle policy was last applied from
+                    format: int64
+                    type: integer
+                required:
+                - appliedBuckets
+                type: object
               mode:
                 description: Mode is a simple, high-level summary of where the System
                   is in its lifecycle
@@ -983,6 +1111,196 @@ spec:
       status: {}
 `
 
+const Sha256_deploy_crds_noobaa_io_bucketreplications_yaml = "d680da948e3b3e3d278cc2bb3c73587263ed33f29d2895a3e0a9ecd6b200382c"
+
+const File_deploy_crds_noobaa_io_bucketreplications_yaml = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.18.0
+  name: bucketreplications.noobaa.io
+spec:
+  group: noobaa.io
+  names:
+    kind: BucketReplication
+    listKind: BucketReplicationList
+    plural: bucketreplications
+    singular: bucketreplication
+  scope: Namespaced
+  versions:
+  - additionalPrinterColumns:
+    - description: Source
+      jsonPath: .status.sourceBucket
+      name: Source
+      type: string
+    - description: Phase
+      jsonPath: .status.phase
+      name: Phase
+      type: string
+    - description: Last-Sync
+      jsonPath: .status.lastSyncTime
+      name: Last-Sync
+      type: date
+    - jsonPath: .metadata.creationTimestamp
+      name: Age
+      type: date
+    name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        description: BucketReplication is the Schema for the bucketreplications API
+        properties:
+          apiVersion:
+            description: |-
+              APIVersion defines the versioned schema of this representation of an object.
+              Servers should convert recognized schemas to the latest internal value, and
+              may reject unrecognized values.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+            type: string
+          kind:
+            description: |-
+              Kind is a string value representing the REST resource this object represents.
+              Servers may infer this from the endpoint the client submits requests to.
+              Cannot be updated.
+              In CamelCase.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+            type: string
+          metadata:
+            type: object
+          spec:
+            description: Specification of the desired behavior of the noobaa BucketReplication.
+            properties:
+              logReplicationSource:
+                description: |-
+                  LogReplicationSource specifies where the bucket access logs used for
+                  log based replication are read from
+                properties:
+                  endpointType:
+                    description: EndpointType is the type of the cloud endpoint that
+                      writes the bucket logs
+                    enum:
+                    - AWS
+                    - AZURE
+                    type: string
+                  logsBucket:
+                    description: LogsBucket is the name of the bucket that holds the
+                      logs
+                    type: string
+                  prefix:
+                    description: Prefix is the key prefix of the logs inside LogsBucket
+                    type: string
+                type: object
+              rules:
+                description: Rules is the list of replication rules of the source
+                  bucket
+                items:
+                  description: BucketReplicationRule specifies a single replication
+                    rule
+                  properties:
+                    destinationBucket:
+                      description: DestinationBucket is the name of the noobaa bucket
+                        to replicate to
+                      type: string
+                    prefix:
+                      description: Prefix limits the rule to objects whose key starts
+                        with the prefix
+                      type: string
+                    ruleId:
+                      description: RuleID is the unique identifier of the rule
+                      type: string
+                    syncDeletions:
+                      description: SyncDeletions specifies if deletions on the source
+                        bucket are replicated as well
+                      type: boolean
+                    syncVersions:
+                      description: SyncVersions specifies if all the object versions
+                        are replicated and not only the latest
+                      type: boolean
+                  required:
+                  - destinationBucket
+                  - ruleId
+                  type: object
+                minItems: 1
+                type: array
+              source:
+                description: Source specifies the bucket to replicate from
+                properties:
+                  bucketName:
+                    description: BucketName is the name of the noobaa bucket to replicate
+                      from
+                    type: string
+                  objectBucketClaim:
+                    description: |-
+                      ObjectBucketClaim is the name of an ObjectBucketClaim in the namespace of the
+                      BucketReplication whose bucket is replicated
+                    type: string
+                type: object
+            required:
+            - rules
+            - source
+            type: object
+          status:
+            description: Most recently observed status of the noobaa BucketReplication.
+            properties:
+              conditions:
+                description: Conditions is a list of conditions related to operator
+                  reconciliation
+                items:
+                  description: |-
+                    Condition represents the state of the operator's
+                    reconciliation functionality.
+                  properties:
+                    lastHeartbeatTime:
+                      format: date-time
+                      type: string
+                    lastTransitionTime:
+                      format: date-time
+                      type: string
+                    message:
+                      type: string
+                    reason:
+                      type: string
+                    status:
+                      type: string
+                    type:
+                      description: ConditionType is the state of the operator's reconciliation
+                        functionality.
+                      type: string
+                  required:
+                  - status
+                  - type
+                  type: object
+                type: array
+              lastError:
+                description: LastError is the error of the last failed attempt to
+                  apply the replication policy
+                type: string
+              lastSyncTime:
+                description: LastSyncTime is the last time the replication policy
+                  was read from noobaa and found applied to the source bucket
+                format: date-time
+                type: string
+              observedGeneration:
+                description: ObservedGeneration is the generation of the spec that
+                  was last applied to the source bucket
+                format: int64
+                type: integer
+              phase:
+                description: Phase is a simple, high-level summary of where the BucketReplication
+                  is in its lifecycle
+                type: string
+              sourceBucket:
+                description: SourceBucket is the resolved name of the noobaa bucket
+                  the policy is applied to
+                type: string
+            type: object
+        type: object
+    served: true
+    storage: true
+    subresources:
+      status: {}
+`
+
 const Sha256_deploy_crds_noobaa_io_namespacestores_yaml = "25ae97238bab92188947130a2d883af7ea6fba1b0e209c8c2b6105b0d39f5152"
 
 const File_deploy_crds_noobaa_io_namespacestores_yaml = `---
@@ -1342,7 +1660,7 @@ spec:
       status: {}
 `
 
-const Sha256_deploy_crds_noobaa_io_noobaaaccounts_yaml = "8bc46314e327636719cd220e5d8c3745762f3008b8b5c58a62722ef9003688e6"
+const Sha256_deploy_crds_noobaa_io_noobaaaccounts_yaml = "0f2d83ac5ad9c84da7e6ae48dea0b132c8f9e77782d72643ed9c742f4cc6815a"
 
 const File_deploy_crds_noobaa_io_noobaaaccounts_yaml = `---
 apiVersion: apiextensions.k8s.io/v1
@@ -1397,6 +1715,24 @@ spec:
                 description: AllowBucketCreate specifies if new buckets can be created
                   by this account
                 type: boolean
+              credential_rotation:
+                description: CredentialRotation specifies a schedule to rotate the
+                  S3 access keys of the account
+                properties:
+                  grace_period:
+                    description: GracePeriod is the time both the current and the
+                      new keys are valid, defaults to 24h
+                    type: string
+                  schedule:
+                    description: |-
+                      Schedule is the rotation schedule in cron format, for example "0 0 1 */3 *" to rotate every 3 months.
+                      When the schedule is due, new keys are added to the account and published in the credentials Secret as
+                      NEXT_AWS_ACCESS_KEY_ID and NEXT_AWS_SECRET_ACCESS_KEY. Both keys are valid during the grace period,
+                      and the new keys replace the current keys when it ends.
+                    type: string
+                required:
+                - schedule
+                type: object
               default_resource:
                 description: DefaultResource specifies which backingstore this account
                   will use to create new buckets
@@ -1424,6 +1760,65 @@ spec:
                 - new_buckets_path
                 - nsfs_only
                 type: object
+              permissions:
+                description: |-
+                  Permissions specifies the access of the account to buckets it does not own.
+                  The operator applies it as statements in the bucket policies of these buckets.
+                properties:
+                  allowed_source_cidrs:
+                    description: AllowedSourceCIDRs limits the granted access to requests
+                      coming from these CIDRs
+                    items:
+                      type: string
+                    type: array
+                  buckets:
+                    description: Buckets is the list of buckets the account is allowed
+                      to access
+                    items:
+                      description: AccountBucketPermission grants the account access
+                        to a single bucket
+                      properties:
+                        access:
+                          description: Access is the access mode granted on the bucket,
+                            defaults to read-only
+                          enum:
+                          - read-only
+                          - read-write
+                          type: string
+                        bucket_name:
+                          description: BucketName is the name of the noobaa bucket
+                          type: string
+                      required:
+                      - bucket_name
+                      type: object
+                    type: array
+                type: object
+              sts_role:
+                description: |-
+                  STSRole specifies the role that other accounts can assume for this account through the NooBaa STS.
+                  The operator applies it as the role config of the account, and removes it when the field is unset.
+                properties:
+                  allowed_actions:
+                    description: AllowedActions is the list of STS actions the trusted
+                      principals are allowed, defaults to sts:AssumeRole
+                    items:
+                      type: string
+                    type: array
+                  role_name:
+                    description: RoleName is the name of the role, defaults to the
+                      account name
+                    maxLength: 64
+                    type: string
+                  trusted_principals:
+                    description: TrustedPrincipals is the list of accounts that are
+                      allowed to assume the role
+                    items:
+                      type: string
+                    minItems: 1
+                    type: array
+                required:
+                - trusted_principals
+                type: object
             required:
             - allow_bucket_creation
             type: object
@@ -1459,10 +1854,37 @@ spec:
                   - type
                   type: object
                 type: array
+              credentialRotation:
+                description: CredentialRotation is the state of the scheduled S3 access
+                  keys rotation
+                properties:
+                  lastRotationTime:
+                    description: LastRotationTime is the last time the account keys
+                      were replaced
+                    format: date-time
+                    type: string
+                  nextRotationTime:
+                    description: NextRotationTime is the time the next rotation step
+                      is due, publishing new keys or replacing the current keys
+                    format: date-time
+                    type: string
+                  pendingSince:
+                    description: |-
+                      PendingSince is the time the new keys were published in the credentials Secret,
+                      it is set only during the grace period of a rotation
+                    format: date-time
+                    type: string
+                type: object
               phase:
                 description: Phase is a simple, high-level summary of where the noobaa
                   user is in its lifecycle
                 type: string
+              permittedBuckets:
+                description: PermittedBuckets is the list of buckets whose bucket policy
+                  holds the permissions of this account
+                items:
+                  type: string
+                type: array
               relatedObjects:
                 description: RelatedObjects is a list of objects related to this operator.
                 items:
@@ -1510,6 +1932,33 @@ spec:
                   type: object
                   x-kubernetes-map-type: atomic
                 type: array
+              stsRole:
+                description: STSRole is the state of the role config that was applied
+                  to the account
+                properties:
+                  allowedActions:
+                    description: AllowedActions is the list of STS actions of the
+                      applied role, empty for the default sts:AssumeRole
+                    items:
+                      type: string
+                    type: array
+                  lastAppliedTime:
+                    description: LastAppliedTime is the last time the role config
+                      was applied to the account
+                    format: date-time
+                    type: string
+                  roleName:
+                    description: RoleName is the name of the applied role
+                    type: string
+                  trustedPrincipals:
+                    description: TrustedPrincipals is the list of accounts that are
+                      allowed to assume the applied role
+                    items:
+                      type: string
+                    type: array
+                required:
+                - roleName
+                type: object
             type: object
         type: object
     served: true
@@ -1518,62 +1967,47 @@ spec:
       status: {}
 `
 
-const Sha256_deploy_crds_noobaa_io_noobaas_yaml = "cf8b0283a5d8660badba7edc2cb196fb43815f7a837eab7cb86f5fbeb204e2cf"
+const Sha256_deploy_crds_noobaa_io_noobaaperformanceprofiles_yaml = "a64feb896d8fb019e12079d14f945d2164c557d3a24c23a1438be288679cd71a"
 
-const File_deploy_crds_noobaa_io_noobaas_yaml = `---
+const File_deploy_crds_noobaa_io_noobaaperformanceprofiles_yaml = `---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
 metadata:
   annotations:
     controller-gen.kubebuilder.io/version: v0.18.0
-  name: noobaas.noobaa.io
+  name: noobaaperformanceprofiles.noobaa.io
 spec:
   group: noobaa.io
   names:
-    kind: NooBaa
-    listKind: NooBaaList
-    plural: noobaas
-    shortNames:
-    - nb
-    singular: noobaa
-  scope: Namespaced
+    kind: NooBaaPerformanceProfile
+    listKind: NooBaaPerformanceProfileList
+    plural: noobaaperformanceprofiles
+    singular: noobaaperformanceprofile
+  scope: Cluster
   versions:
   - additionalPrinterColumns:
-    - description: S3 Endpoints
-      jsonPath: .status.services.serviceS3.nodePorts
-      name: S3-Endpoints
-      type: string
-    - description: STS Endpoints
-      jsonPath: .status.services.serviceSts.nodePorts
-      name: Sts-Endpoints
-      type: string
-    - description: IAM Endpoints
-      jsonPath: .status.services.serviceIam.nodePorts
-      name: Iam-Endpoints
-      type: string
-    - description: Vectors Endpoints
-      jsonPath: .status.services.serviceVectors.nodePorts
-      name: Vectors-Endpoints
-      type: string
-    - description: Syslog Endpoints
-      jsonPath: .status.services.serviceSyslog.nodePorts
-      name: Syslog-Endpoints
-      type: string
-    - description: Actual Image
-      jsonPath: .status.actualImage
-      name: Image
-      type: string
-    - description: Phase
-      jsonPath: .status.phase
-      name: Phase
-      type: string
+    - description: Endpoint-Min
+      jsonPath: .spec.endpointMinCount
+      name: Endpoint-Min
+      type: integer
+    - description: Endpoint-Max
+      jsonPath: .spec.endpointMaxCount
+      name: Endpoint-Max
+      type: integer
+    - description: DB-Instances
+      jsonPath: .spec.dbInstances
+      name: DB-Instances
+      type: integer
     - jsonPath: .metadata.creationTimestamp
       name: Age
       type: date
     name: v1alpha1
     schema:
       openAPIV3Schema:
-        description: NooBaa is the Schema for the NooBaas API
+        description: |-
+          NooBaaPerformanceProfile is the Schema for the noobaaperformanceprofiles API.
+          It defines a cluster wide bundle of resource and count settings that can be selected
+          by name in NooBaaSpec.PerformanceProfile, in addition to the built-in profiles.
         properties:
           apiVersion:
             description: |-
@@ -1593,14 +2027,639 @@ spec:
           metadata:
             type: object
           spec:
-            description: Specification of the desired behavior of the noobaa system.
+            description: Specification of the resource and count settings of the
+              profile.
             properties:
-              affinity:
-                description: Affinity (optional) passed through to noobaa's pods
+              coreResources:
+                description: CoreResources (optional) the resource requirements
+                  of the core container
                 properties:
-                  nodeAffinity:
-                    description: Describes node affinity scheduling rules for the
-                      pod.
+                  claims:
+                    description: |-
+                      Claims lists the names of resources, defined in spec.resourceClaims,
+                      that are used by this container.
+
+                      This field depends on the
+                      DynamicResourceAllocation feature gate.
+
+                      This field is immutable. It can only be set for containers.
+                    items:
+                      description: ResourceClaim references one entry in
+                        PodSpec.ResourceClaims.
+                      properties:
+                        name:
+                          description: |-
+                            Name must match the name of one entry in pod.spec.resourceClaims of
+                            the Pod where this field is used. It makes that resource available
+                            inside a container.
+                          type: string
+                        request:
+                          description: |-
+                            Request is the name chosen for a request in the referenced claim.
+                            If empty, everything from the claim is made available, otherwise
+                            only the result of this request.
+                          type: string
+                      required:
+                      - name
+                      type: object
+                    type: array
+                    x-kubernetes-list-map-keys:
+                    - name
+                    x-kubernetes-list-type: map
+                  limits:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Limits describes the maximum amount of compute resources allowed.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                  requests:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Requests describes the minimum amount of compute resources required.
+                      If Requests is omitted for a container, it defaults to Limits if that is explicitly specified,
+                      otherwise to an implementation-defined value. Requests cannot exceed Limits.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                type: object
+              dbInstances:
+                description: DBInstances (optional) the number of db instances
+                minimum: 1
+                type: integer
+              dbResources:
+                description: DBResources (optional) the resource requirements of
+                  the db instances
+                properties:
+                  claims:
+                    description: |-
+                      Claims lists the names of resources, defined in spec.resourceClaims,
+                      that are used by this container.
+
+                      This field depends on the
+                      DynamicResourceAllocation feature gate.
+
+                      This field is immutable. It can only be set for containers.
+                    items:
+                      description: ResourceClaim references one entry in
+                        PodSpec.ResourceClaims.
+                      properties:
+                        name:
+                          description: |-
+                            Name must match the name of one entry in pod.spec.resourceClaims of
+                            the Pod where this field is used. It makes that resource available
+                            inside a container.
+                          type: string
+                        request:
+                          description: |-
+                            Request is the name chosen for a request in the referenced claim.
+                            If empty, everything from the claim is made available, otherwise
+                            only the result of this request.
+                          type: string
+                      required:
+                      - name
+                      type: object
+                    type: array
+                    x-kubernetes-list-map-keys:
+                    - name
+                    x-kubernetes-list-type: map
+                  limits:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Limits describes the maximum amount of compute resources allowed.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                  requests:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Requests describes the minimum amount of compute resources required.
+                      If Requests is omitted for a container, it defaults to Limits if that is explicitly specified,
+                      otherwise to an implementation-defined value. Requests cannot exceed Limits.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                type: object
+              endpointMaxCount:
+                description: EndpointMaxCount (optional) the maximal number of
+                  endpoint pods
+                format: int32
+                minimum: 1
+                type: integer
+              endpointMinCount:
+                description: EndpointMinCount (optional) the minimal number of
+                  endpoint pods
+                format: int32
+                minimum: 1
+                type: integer
+              endpointResources:
+                description: EndpointResources (optional) the resource
+                  requirements of the endpoint containers
+                properties:
+                  claims:
+                    description: |-
+                      Claims lists the names of resources, defined in spec.resourceClaims,
+                      that are used by this container.
+
+                      This field depends on the
+                      DynamicResourceAllocation feature gate.
+
+                      This field is immutable. It can only be set for containers.
+                    items:
+                      description: ResourceClaim references one entry in
+                        PodSpec.ResourceClaims.
+                      properties:
+                        name:
+                          description: |-
+                            Name must match the name of one entry in pod.spec.resourceClaims of
+                            the Pod where this field is used. It makes that resource available
+                            inside a container.
+                          type: string
+                        request:
+                          description: |-
+                            Request is the name chosen for a request in the referenced claim.
+                            If empty, everything from the claim is made available, otherwise
+                            only the result of this request.
+                          type: string
+                      required:
+                      - name
+                      type: object
+                    type: array
+                    x-kubernetes-list-map-keys:
+                    - name
+                    x-kubernetes-list-type: map
+                  limits:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Limits describes the maximum amount of compute resources allowed.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                  requests:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Requests describes the minimum amount of compute resources required.
+                      If Requests is omitted for a container, it defaults to Limits if that is explicitly specified,
+                      otherwise to an implementation-defined value. Requests cannot exceed Limits.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                type: object
+              logResources:
+                description: LogResources (optional) the resource requirements
+                  of the log sidecar container
+                properties:
+                  claims:
+                    description: |-
+                      Claims lists the names of resources, defined in spec.resourceClaims,
+                      that are used by this container.
+
+                      This field depends on the
+                      DynamicResourceAllocation feature gate.
+
+                      This field is immutable. It can only be set for containers.
+                    items:
+                      description: ResourceClaim references one entry in
+                        PodSpec.ResourceClaims.
+                      properties:
+                        name:
+                          description: |-
+                            Name must match the name of one entry in pod.spec.resourceClaims of
+                            the Pod where this field is used. It makes that resource available
+                            inside a container.
+                          type: string
+                        request:
+                          description: |-
+                            Request is the name chosen for a request in the referenced claim.
+                            If empty, everything from the claim is made available, otherwise
+                            only the result of this request.
+                          type: string
+                      required:
+                      - name
+                      type: object
+                    type: array
+                    x-kubernetes-list-map-keys:
+                    - name
+                    x-kubernetes-list-type: map
+                  limits:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Limits describes the maximum amount of compute resources allowed.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                  requests:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Requests describes the minimum amount of compute resources required.
+                      If Requests is omitted for a container, it defaults to Limits if that is explicitly specified,
+                      otherwise to an implementation-defined value. Requests cannot exceed Limits.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                type: object
+              pvPoolNumVolumes:
+                description: PVPoolNumVolumes (optional) the number of volumes
+                  of the default pv-pool backingstore
+                minimum: 1
+                type: integer
+              pvPoolResources:
+                description: PVPoolResources (optional) the resource
+                  requirements of the pv-pool backingstore pods
+                properties:
+                  claims:
+                    description: |-
+                      Claims lists the names of resources, defined in spec.resourceClaims,
+                      that are used by this container.
+
+                      This field depends on the
+                      DynamicResourceAllocation feature gate.
+
+                      This field is immutable. It can only be set for containers.
+                    items:
+                      description: ResourceClaim references one entry in
+                        PodSpec.ResourceClaims.
+                      properties:
+                        name:
+                          description: |-
+                            Name must match the name of one entry in pod.spec.resourceClaims of
+                            the Pod where this field is used. It makes that resource available
+                            inside a container.
+                          type: string
+                        request:
+                          description: |-
+                            Request is the name chosen for a request in the referenced claim.
+                            If empty, everything from the claim is made available, otherwise
+                            only the result of this request.
+                          type: string
+                      required:
+                      - name
+                      type: object
+                    type: array
+                    x-kubernetes-list-map-keys:
+                    - name
+                    x-kubernetes-list-type: map
+                  limits:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Limits describes the maximum amount of compute resources allowed.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                  requests:
+                    additionalProperties:
+                      anyOf:
+                      - type: integer
+                      - type: string
+                      pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                      x-kubernetes-int-or-string: true
+                    description: |-
+                      Requests describes the minimum amount of compute resources required.
+                      If Requests is omitted for a container, it defaults to Limits if that is explicitly specified,
+                      otherwise to an implementation-defined value. Requests cannot exceed Limits.
+                      More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+                    type: object
+                type: object
+            type: object
+        type: object
+    served: true
+    storage: true
+`
+
+const Sha256_deploy_crds_noobaa_io_noobaaremotes_yaml = "c159288a550d2a92051678aa549c74ae06ad0c2fe1a9028593056868c2ff1ba4"
+
+const File_deploy_crds_noobaa_io_noobaaremotes_yaml = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.18.0
+  name: noobaaremotes.noobaa.io
+spec:
+  group: noobaa.io
+  names:
+    kind: NooBaaRemote
+    listKind: NooBaaRemoteList
+    plural: noobaaremotes
+    singular: noobaaremote
+  scope: Namespaced
+  versions:
+  - additionalPrinterColumns:
+    - description: S3-Endpoint
+      jsonPath: .spec.s3Endpoint
+      name: S3-Endpoint
+      type: string
+    - description: Reachable
+      jsonPath: .status.reachability.s3Reachable
+      name: Reachable
+      type: boolean
+    - description: Phase
+      jsonPath: .status.phase
+      name: Phase
+      type: string
+    - jsonPath: .metadata.creationTimestamp
+      name: Age
+      type: date
+    name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        description: |-
+          NooBaaRemote is the Schema for the noobaaremotes API.
+          It federates a remote NooBaa system into the local system by exposing remote buckets as namespace stores.
+        properties:
+          apiVersion:
+            description: |-
+              APIVersion defines the versioned schema of this representation of an object.
+              Servers should convert recognized schemas to the latest internal value, and
+              may reject unrecognized values.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+            type: string
+          kind:
+            description: |-
+              Kind is a string value representing the REST resource this object represents.
+              Servers may infer this from the endpoint the client submits requests to.
+              Cannot be updated.
+              In CamelCase.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+            type: string
+          metadata:
+            type: object
+          spec:
+            description: Specification of the desired behavior of the noobaa NooBaaRemote.
+            properties:
+              buckets:
+                description: Buckets is the list of remote buckets exposed on the
+                  local system as namespace stores
+                items:
+                  description: NooBaaRemoteBucket specifies a remote bucket exposed
+                    on the local system
+                  properties:
+                    accessMode:
+                      description: AccessMode is the access mode of the namespace
+                        store
+                      enum:
+                      - ReadWrite
+                      - ReadOnly
+                      type: string
+                    name:
+                      description: Name is the name of the bucket on the remote system
+                      type: string
+                    namespaceStore:
+                      description: |-
+                        NamespaceStore is the name of the local namespace store created for the bucket.
+                        Defaults to <remote-name>-<bucket-name>
+                      type: string
+                  required:
+                  - name
+                  type: object
+                type: array
+              mgmtEndpoint:
+                description: 'MgmtEndpoint is the management endpoint of the remote
+                  noobaa system: https://host:port'
+                type: string
+              s3Endpoint:
+                description: 'S3Endpoint is the S3 endpoint of the remote noobaa
+                  system: https://host:port'
+                type: string
+              secret:
+                description: |-
+                  Secret refers to a secret that provides the S3 credentials of an account on the remote system
+                  The secret should define AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+                properties:
+                  name:
+                    description: name is unique within a namespace to reference
+                      a secret resource.
+                    type: string
+                  namespace:
+                    description: namespace defines the space within which the secret
+                      name must be unique.
+                    type: string
+                type: object
+                x-kubernetes-map-type: atomic
+              signatureVersion:
+                description: SignatureVersion specifies the client signature version
+                  to use when signing requests.
+                type: string
+            required:
+            - mgmtEndpoint
+            - s3Endpoint
+            - secret
+            type: object
+          status:
+            description: Most recently observed status of the noobaa NooBaaRemote.
+            properties:
+              conditions:
+                description: Conditions is a list of conditions related to operator
+                  reconciliation
+                items:
+                  description: |-
+                    Condition represents the state of the operator's
+                    reconciliation functionality.
+                  properties:
+                    lastHeartbeatTime:
+                      format: date-time
+                      type: string
+                    lastTransitionTime:
+                      format: date-time
+                      type: string
+                    message:
+                      type: string
+                    reason:
+                      type: string
+                    status:
+                      type: string
+                    type:
+                      description: ConditionType is the state of the operator's reconciliation
+                        functionality.
+                      type: string
+                  required:
+                  - status
+                  - type
+                  type: object
+                type: array
+              namespaceStores:
+                description: NamespaceStores is the list of namespace stores created
+                  for the remote buckets
+                items:
+                  description: NooBaaRemoteNamespaceStore reports a namespace store
+                    created for a remote bucket
+                  properties:
+                    bucket:
+                      description: Bucket is the name of the bucket on the remote
+                        system
+                      type: string
+                    name:
+                      description: Name is the name of the namespace store
+                      type: string
+                    phase:
+                      description: Phase is the phase of the namespace store
+                      type: string
+                  required:
+                  - bucket
+                  - name
+                  type: object
+                type: array
+              observedGeneration:
+                description: ObservedGeneration is the generation of the spec that
+                  was last reconciled
+                format: int64
+                type: integer
+              phase:
+                description: Phase is a simple, high-level summary of where the NooBaaRemote
+                  is in its lifecycle
+                type: string
+              reachability:
+                description: Reachability is the result of the last health check
+                  of the remote system
+                properties:
+                  lastCheckTime:
+                    description: LastCheckTime is the last time the remote system
+                      was checked
+                    format: date-time
+                    type: string
+                  message:
+                    description: Message describes the failure of the last check
+                    type: string
+                  mgmtReachable:
+                    description: MgmtReachable is true when the management endpoint
+                      responded to the operator
+                    type: boolean
+                  s3Reachable:
+                    description: S3Reachable is true when the local noobaa system
+                      connected to the S3 endpoint with the credentials
+                    type: boolean
+                  s3Status:
+                    description: S3Status is the external connection status returned
+                      by the local noobaa system for the S3 endpoint
+                    type: string
+                type: object
+            type: object
+        type: object
+    served: true
+    storage: true
+    subresources:
+      status: {}
+`
+
+const Sha256_deploy_crds_noobaa_io_noobaas_yaml = "4a64fc00845ccf3b70dc0d83417aaacd90c664ed35a710e0814478746e89c8b9"
+
+const File_deploy_crds_noobaa_io_noobaas_yaml = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    controller-gen.kubebuilder.io/version: v0.18.0
+  name: noobaas.noobaa.io
+spec:
+  group: noobaa.io
+  names:
+    kind: NooBaa
+    listKind: NooBaaList
+    plural: noobaas
+    shortNames:
+    - nb
+    singular: noobaa
+  scope: Namespaced
+  versions:
+  - additionalPrinterColumns:
+    - description: S3 Endpoints
+      jsonPath: .status.services.serviceS3.nodePorts
+      name: S3-Endpoints
+      type: string
+    - description: STS Endpoints
+      jsonPath: .status.services.serviceSts.nodePorts
+      name: Sts-Endpoints
+      type: string
+    - description: IAM Endpoints
+      jsonPath: .status.services.serviceIam.nodePorts
+      name: Iam-Endpoints
+      type: string
+    - description: Vectors Endpoints
+      jsonPath: .status.services.serviceVectors.nodePorts
+      name: Vectors-Endpoints
+      type: string
+    - description: Syslog Endpoints
+      jsonPath: .status.services.serviceSyslog.nodePorts
+      name: Syslog-Endpoints
+      type: string
+    - description: Actual Image
+      jsonPath: .status.actualImage
+      name: Image
+      type: string
+    - description: Phase
+      jsonPath: .status.phase
+      name: Phase
+      type: string
+    - jsonPath: .metadata.creationTimestamp
+      name: Age
+      type: date
+    name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        description: NooBaa is the Schema for the NooBaas API
+        properties:
+          apiVersion:
+            description: |-
+              APIVersion defines the versioned schema of this representation of an object.
+              Servers should convert recognized schemas to the latest internal value, and
+              may reject unrecognized values.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
+            type: string
+          kind:
+            description: |-
+              Kind is a string value representing the REST resource this object represents.
+              Servers may infer this from the endpoint the client submits requests to.
+              Cannot be updated.
+              In CamelCase.
+              More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+            type: string
+          metadata:
+            type: object
+          spec:
+            description: Specification of the desired behavior of the noobaa system.
+            properties:
+              affinity:
+                description: Affinity (optional) passed through to noobaa's pods
+                properties:
+                  nodeAffinity:
+                    description: Describes node affinity scheduling rules for the
+                      pod.
                     properties:
                       preferredDuringSchedulingIgnoredDuringExecution:
                         description: |-
@@ -2533,9 +3592,51 @@ spec:
                     - hpav2
                     - keda
                     type: string
+                  metrics:
+                    description: |-
+                      Metrics (optional) the metrics to scale the endpoints on.
+                      The number of endpoints is the highest number calculated for any of the metrics.
+                      Defaults to a CPU utilization of 80%
+                    items:
+                      description: AutoscalerMetric is a metric to scale the endpoints
+                        on and its target value
+                      properties:
+                        target:
+                          description: |-
+                            Target value of the metric to keep, endpoints are added when the metric is above the target:
+                            cpu - percent of the CPU request, e.g. "80"
+                            requestsPerSecond, inflightOps - an average value per endpoint pod, e.g. "500"
+                            latencyP95 - a duration, e.g. "250ms"
+                          type: string
+                        type:
+                          description: |-
+                            Type of the metric:
+                            cpu - CPU utilization of the endpoint pods
+                            requestsPerSecond - S3 requests per second per endpoint pod
+                            latencyP95 - 95th percentile of the S3 request latency
+                            inflightOps - S3 operations in flight per endpoint pod
+                          enum:
+                          - cpu
+                          - requestsPerSecond
+                          - latencyP95
+                          - inflightOps
+                          type: string
+                      required:
+                      - target
+                      - type
+                      type: object
+                    type: array
                   prometheusNamespace:
                     description: Prometheus namespace that scrap metrics from noobaa
                     type: string
+                  scaleDownStabilizationWindowSeconds:
+                    description: |-
+                      ScaleDownStabilizationWindowSeconds (optional) the number of seconds the autoscaler looks back
+                      for a higher recommendation before removing endpoints, to avoid flapping. Defaults to 600
+                    format: int32
+                    maximum: 3600
+                    minimum: 0
+                    type: integer
                 type: object
               bucketLogging:
                 description: BucketLogging sets the configuration for bucket logging
@@ -2744,11 +3845,76 @@ spec:
               dbSpec:
                 description: DBSpec (optional) DB spec for a managed postgres cluster
                 properties:
+                  dbAutoGrow:
+                    description: |-
+                      DBAutoGrow (optional) automatically expand the database volume when its usage crosses a threshold.
+                      The storage class of the database volume must allow volume expansion.
+                    properties:
+                      growthStep:
+                        description: |-
+                          GrowthStep (optional) the size added to the database volume on each expansion,
+                          either a quantity such as 10Gi or a percentage of the current size such as 25%. Defaults to 20%.
+                        pattern: ^([1-9][0-9]*%|[0-9]+(\.[0-9]+)?(Ki|Mi|Gi|Ti|Pi|Ei|k|M|G|T|P|E)?)$
+                        type: string
+                      maxSize:
+                        description: MaxSize the maximum size to expand the database
+                          volume to
+                        minLength: 1
+                        type: string
+                      thresholdPercent:
+                        description: ThresholdPercent (optional) the used percentage
+                          of the database volume that triggers an expansion. Defaults
+                          to 80.
+                        maximum: 95
+                        minimum: 50
+                        type: integer
+                    required:
+                    - maxSize
+                    type: object
                   dbBackup:
                     description: |-
-                      DBBackup (optional) configure automatic scheduled backups of the database volume.
-                      Currently, only volume snapshots are supported.
+                      DBBackup (optional) configure automatic scheduled backups of the database,
+                      either as volume snapshots or as base backups and WAL archives in an S3 object store.
                     properties:
+                      objectStore:
+                        description: |-
+                          ObjectStore the object store backup configuration.
+                          Base backups are taken on schedule and WAL files are archived continuously to an S3 bucket,
+                          which allows recovering the database off-cluster and to a point in time.
+                          Exactly one of VolumeSnapshot or ObjectStore should be set.
+                        properties:
+                          credentialsSecret:
+                            description: |-
+                              CredentialsSecret the name of a secret in the noobaa namespace with the S3 credentials
+                              The secret should define AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, and optionally AWS_REGION
+                            minLength: 1
+                            type: string
+                          destinationPath:
+                            description: 'DestinationPath the S3 path to store the
+                              base backups and WAL files in: s3://bucket/path'
+                            pattern: ^s3://.+
+                            type: string
+                          endpoint:
+                            description: |-
+                              Endpoint (optional) the endpoint of an S3 compatible object store: https://host:port
+                              For example the S3 endpoint of a NooBaa system in another cluster. Defaults to AWS S3.
+                            type: string
+                          endpointCASecret:
+                            description: |-
+                              EndpointCASecret (optional) the name of a secret in the noobaa namespace
+                              holding the CA bundle that signed the endpoint certificate in the key ca.crt
+                            type: string
+                          retentionPolicy:
+                            description: |-
+                              RetentionPolicy (optional) the recovery window to keep base backups and WAL files for,
+                              a number followed by d, w or m for days, weeks or months, for example 30d.
+                              When not set, backups are kept in the object store until deleted manually.
+                            pattern: ^[1-9][0-9]*[dwm]$
+                            type: string
+                        required:
+                        - credentialsSecret
+                        - destinationPath
+                        type: object
                       schedule:
                         description: Schedule the schedule for the database backup
                           in cron format.
@@ -2759,7 +3925,7 @@ spec:
                       volumeSnapshot:
                         description: |-
                           VolumeSnapshot the volume snapshot backup configuration.
-                          Currently this is the only supported backup method and hence it is required.
+                          Exactly one of VolumeSnapshot or ObjectStore should be set.
                         properties:
                           maxSnapshots:
                             description: MaxSnapshots the maximum number of snapshots
@@ -2777,7 +3943,6 @@ spec:
                         type: object
                     required:
                     - schedule
-                    - volumeSnapshot
                     type: object
                   dbConf:
                     additionalProperties:
@@ -2792,16 +3957,51 @@ spec:
                       The new size should be larger than actualVolumeSize in dbStatus for the volume to be resized.
                     type: string
                   dbRecovery:
-                    description: DBRecovery (optional) configure database recovery
-                      from snapshot
+                    description: |-
+                      DBRecovery (optional) configure database recovery from a volume snapshot or from the backup object store,
+                      optionally to a point in time
                     properties:
+                      recoveryTarget:
+                        description: |-
+                          RecoveryTarget (optional) the point to stop the recovery at, by replaying the WAL files archived
+                          in the backup object store (dbBackup.objectStore).
+                          When not set, the database is recovered to the end of the volume snapshot,
+                          or to the last archived WAL file when recovering from the object store.
+                        properties:
+                          backupID:
+                            description: |-
+                              BackupID (optional) the ID of the base backup in the object store to start the recovery from,
+                              as listed in status.dbStatus.backupStatus.backups. By default, the latest base backup before TargetTime is used,
+                              or the latest base backup when recovering to a TargetLSN or a TargetName.
+                            type: string
+                          exclusive:
+                            description: Exclusive (optional) stop the recovery just before
+                              the recovery target instead of just after it
+                            type: boolean
+                          targetLSN:
+                            description: TargetLSN (optional) the write-ahead log location
+                              to recover to, for example 0/3000060
+                            pattern: ^[0-9A-Fa-f]+/[0-9A-Fa-f]+$
+                            type: string
+                          targetName:
+                            description: TargetName (optional) the named restore point to
+                              recover to, created with pg_create_restore_point()
+                            type: string
+                          targetTime:
+                            description: TargetTime (optional) the time stamp to recover
+                              to in RFC 3339 format, for example 2026-01-13T08:50:00Z
+                            type: string
+                        type: object
+                      serverName:
+                        description: |-
+                          ServerName (optional) the server name of the backups in the object store to recover from.
+                          Defaults to the server name the database is currently archived under (status.dbStatus.backupStatus.serverName).
+                        type: string
                       volumeSnapshotName:
-                        description: VolumeSnapshotName specifies the name of the
-                          volume snapshot to recover from
-                        minLength: 1
+                        description: |-
+                          VolumeSnapshotName (optional) specifies the name of the volume snapshot to recover from.
+                          When not set, the database is recovered from the base backups in the backup object store (dbBackup.objectStore).
                         type: string
-                    required:
-                    - volumeSnapshotName
                     type: object
                   dbResources:
                     description: DBResources (optional) overrides the default resource
@@ -3467,17 +4667,108 @@ spec:
                   update the admin account with new BackingStore/NamespaceStore in order to delete the DefaultBackingStore/DefaultNamespaceStore
                 nullable: true
                 type: boolean
+              networkPolicy:
+                description: |-
+                  NetworkPolicy (optional) customizes the network policies of the NooBaa pods,
+                  with additional ingress rules and a default-deny egress with an allow-list
+                properties:
+                  additionalIngress:
+                    description: AdditionalIngress (optional) ingress rules added
+                      to the network policies of the NooBaa pods
+                    items:
+                      description: NetworkPolicyIngressRule allows traffic from additional
+                        namespaces to additional ports of a NooBaa component
+                      properties:
+                        component:
+                          description: Component is the NooBaa component the rule
+                            applies to
+                          enum:
+                          - core
+                          - endpoint
+                          - db
+                          type: string
+                        namespaces:
+                          description: |-
+                            Namespaces (optional) the names of the namespaces the traffic is allowed from.
+                            When empty the traffic is allowed from all the namespaces
+                          items:
+                            type: string
+                          type: array
+                        ports:
+                          description: Ports the ports of the component pods the
+                            traffic is allowed to
+                          items:
+                            description: NetworkPolicyPort describes a port to allow
+                              traffic on
+                            properties:
+                              endPort:
+                                description: |-
+                                  endPort indicates that the range of ports from port to endPort if set, inclusive,
+                                  should be allowed by the policy. This field cannot be defined if the port field
+                                  is not defined or if the port field is defined as a named (string) port.
+                                  The endPort must be equal or greater than port.
+                                format: int32
+                                type: integer
+                              port:
+                                anyOf:
+                                - type: integer
+                                - type: string
+                                description: |-
+                                  port represents the port on the given protocol. This can either be a numerical or named
+                                  port on a pod. If this field is not provided, this matches all port names and
+                                  numbers.
+                                  If present, only traffic on the specified protocol AND port will be matched.
+                                x-kubernetes-int-or-string: true
+                              protocol:
+                                description: |-
+                                  protocol represents the protocol (TCP, UDP, or SCTP) which traffic must match.
+                                  If not specified, this field defaults to TCP.
+                                type: string
+                            type: object
+                          minItems: 1
+                          type: array
+                      required:
+                      - component
+                      - ports
+                      type: object
+                    type: array
+                  egress:
+                    description: Egress (optional) restricts the egress traffic
+                      of the NooBaa pods
+                    properties:
+                      allowedCIDRs:
+                        description: AllowedCIDRs (optional) additional IP ranges
+                          the NooBaa pods are allowed to connect to
+                        items:
+                          type: string
+                        type: array
+                      allowedHosts:
+                        description: |-
+                          AllowedHosts (optional) additional hostnames the NooBaa pods are allowed to connect to.
+                          Network policies only match IP addresses, so the operator resolves the hostnames to addresses
+                          every 2 minutes. Connections to an address that rotated in between are blocked until the next resolve,
+                          so prefer AllowedCIDRs for endpoints with rotating addresses
+                        items:
+                          type: string
+                        type: array
+                      denyByDefault:
+                        description: |-
+                          DenyByDefault (optional) when true the NooBaa, operator and CNPG controller pods
+                          are only allowed egress traffic to DNS, the kubernetes API server, the pods of the namespace,
+                          the endpoints of the BackingStores, NamespaceStores, KMS and external Postgres,
+                          and the allowed CIDRs and hosts
+                        type: boolean
+                    type: object
+                type: object
               performanceProfile:
                 description: |-
                   PerformanceProfile (optional) selects a bundle of resource and count
                   settings for the core, db and endpoint components.
                   Explicit per-component resource/count fields take precedence over the profile.
-                enum:
-                - default
-                - mixed-workload
-                - small-objects
-                - dev-env
-                - mini-env
+                  Either one of the built-in profiles: default, mixed-workload, small-objects, dev-env, mini-env,
+                  or the name of a NooBaaPerformanceProfile.
+                maxLength: 253
+                pattern: ^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
                 type: string
               pvPoolDefaultStorageClass:
                 description: |-
@@ -3539,6 +4830,120 @@ spec:
                       tokenSecretName:
                         type: string
                     type: object
+                  oidc:
+                    description: |-
+                      OIDC (optional) the OIDC identity providers trusted by the NooBaa STS service,
+                      allowing workloads to assume NooBaa roles with web identity tokens
+                    properties:
+                      enabled:
+                        description: |-
+                          Enabled renders the providers to the <name>-oidc-config secret and mounts it to the endpoint pods
+                          at /etc/noobaa-server/oidc/config. Enable it only with a core image that reads this configuration,
+                          the providers are not used by the endpoints while it is false.
+                        type: boolean
+                      providers:
+                        description: Providers is the list of trusted OIDC identity
+                          providers
+                        items:
+                          description: OIDCProviderSpec is an OIDC identity provider
+                            trusted by the NooBaa STS service
+                          properties:
+                            audiences:
+                              description: |-
+                                Audiences (optional) the accepted values of the aud claim of the tokens.
+                                Defaults to "noobaa" for kubernetes, and to any audience for the other types
+                              items:
+                                type: string
+                              type: array
+                            claimMappings:
+                              description: |-
+                                ClaimMappings are the rules that map the claims of a token to a NooBaa account.
+                                The rules are evaluated in order and the first matching rule is used, a token that matches no rule is rejected
+                              items:
+                                description: OIDCClaimMapping maps the tokens with
+                                  a matching claim to a NooBaa account
+                                properties:
+                                  account:
+                                    description: Account is the name of the NooBaa
+                                      account the matching tokens are mapped to
+                                    type: string
+                                  claim:
+                                    description: Claim (optional) the name of the
+                                      claim to match, defaults to sub
+                                    type: string
+                                  value:
+                                    description: |-
+                                      Value is the value the claim must match, a "*" matches any sequence of characters,
+                                      e.g. system:serviceaccount:apps:* for all the service accounts of the apps namespace
+                                    type: string
+                                required:
+                                - account
+                                - value
+                                type: object
+                              minItems: 1
+                              type: array
+                            clientSecret:
+                              description: |-
+                                ClientSecret (optional) refers to a secret with the client_id and client_secret keys
+                                of the client that calls the introspection endpoint, required for introspection
+                              properties:
+                                name:
+                                  description: name is unique within a namespace
+                                    to reference a secret resource.
+                                  type: string
+                                namespace:
+                                  description: namespace defines the space within
+                                    which the secret name must be unique.
+                                  type: string
+                              type: object
+                              x-kubernetes-map-type: atomic
+                            introspectionEndpoint:
+                              description: IntrospectionEndpoint (optional) overrides
+                                the introspection_endpoint of the discovery document
+                              type: string
+                            issuer:
+                              description: |-
+                                Issuer (optional for kubernetes) the issuer URL, the discovery document is read from
+                                <issuer>/.well-known/openid-configuration. Defaults to the service account issuer for kubernetes
+                              type: string
+                            jwksURI:
+                              description: JWKSURI (optional) overrides the jwks_uri
+                                of the discovery document
+                              type: string
+                            name:
+                              description: Name is a unique name of the provider
+                              maxLength: 63
+                              pattern: ^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+                              type: string
+                            type:
+                              description: |-
+                                Type of the provider:
+                                keycloak - a Keycloak realm
+                                oidc - any provider that serves an OIDC discovery document
+                                kubernetes - the service account issuer of this cluster, for projected service account tokens
+                                dex - a Dex server
+                              enum:
+                              - keycloak
+                              - oidc
+                              - kubernetes
+                              - dex
+                              type: string
+                            validation:
+                              description: |-
+                                Validation (optional) how the tokens are validated:
+                                jwks - the token signature is verified with the public keys of the provider (default)
+                                introspection - the token is sent to the introspection endpoint of the provider, only for keycloak and oidc
+                              enum:
+                              - jwks
+                              - introspection
+                              type: string
+                          required:
+                          - claimMappings
+                          - name
+                          - type
+                          type: object
+                        type: array
+                    type: object
                 type: object
               tolerations:
                 description: Tolerations (optional) passed through to noobaa's pods
@@ -3664,6 +5069,52 @@ spec:
                         items:
                           type: string
                         type: array
+                      backups:
+                        description: Backups list of the object store base backups,
+                          newest first
+                        items:
+                          description: DBObjectStoreBackup reports a base backup of
+                            the database in the object store
+                          properties:
+                            backupId:
+                              description: BackupID the ID of the backup in the object
+                                store
+                              type: string
+                            error:
+                              description: Error the error of a failed backup
+                              type: string
+                            name:
+                              description: Name the name of the backup resource
+                              type: string
+                            phase:
+                              description: Phase the phase of the backup
+                              type: string
+                            startedAt:
+                              description: StartedAt the time the backup started
+                              format: date-time
+                              type: string
+                            stoppedAt:
+                              description: StoppedAt the time the backup completed
+                                or failed
+                              format: date-time
+                              type: string
+                          required:
+                          - name
+                          type: object
+                        type: array
+                      continuousArchiving:
+                        description: ContinuousArchiving true when WAL files are archived
+                          to the object store successfully
+                        type: boolean
+                      continuousArchivingMessage:
+                        description: ContinuousArchivingMessage the reason WAL archiving
+                          to the object store is failing
+                        type: string
+                      firstRecoverabilityPoint:
+                        description: FirstRecoverabilityPoint the earliest point in
+                          time the database can be recovered to from the object store
+                        format: date-time
+                        type: string
                       lastBackupTime:
                         description: LastBackupTime timestamp of the last successful
                           backup
@@ -3674,6 +5125,12 @@ spec:
                           backup
                         format: date-time
                         type: string
+                      serverName:
+                        description: |-
+                          ServerName the server name the database is archived under in the object store.
+                          A database recovered from the object store is archived under a new server name to keep the archive it was recovered from intact.
+                          Empty means the name of the database cluster.
+                        type: string
                       totalSnapshots:
                         description: TotalSnapshots current number of snapshots
                         type: integer
@@ -3688,21 +5145,81 @@ spec:
                   dbCurrentImage:
                     description: DBCurrentImage is the image of the postgres cluster
                     type: string
+                  lastAutoGrowTime:
+                    description: LastAutoGrowTime is the time the postgres cluster
+                      volume was last expanded automatically
+                    format: date-time
+                    type: string
                   recoveryStatus:
                     description: RecoveryStatus reports the status of database recovery
                     properties:
+                      clusterPhase:
+                        description: ClusterPhase the phase of the database cluster
+                          while recovering
+                        type: string
+                      completionTime:
+                        description: CompletionTime timestamp when recovery completed
+                        format: date-time
+                        type: string
+                      message:
+                        description: Message details on the recovery progress
+                        type: string
+                      recoveryTarget:
+                        description: RecoveryTarget the point the recovery stops at
+                        properties:
+                          backupID:
+                            description: |-
+                              BackupID (optional) the ID of the base backup in the object store to start the recovery from,
+                              as listed in status.dbStatus.backupStatus.backups. By default, the latest base backup before TargetTime is used,
+                              or the latest base backup when recovering to a TargetLSN or a TargetName.
+                            type: string
+                          exclusive:
+                            description: Exclusive (optional) stop the recovery just before
+                              the recovery target instead of just after it
+                            type: boolean
+                          targetLSN:
+                            description: TargetLSN (optional) the write-ahead log location
+                              to recover to, for example 0/3000060
+                            pattern: ^[0-9A-Fa-f]+/[0-9A-Fa-f]+$
+                            type: string
+                          targetName:
+                            description: TargetName (optional) the named restore point to
+                              recover to, created with pg_create_restore_point()
+                            type: string
+                          targetTime:
+                            description: TargetTime (optional) the time stamp to recover
+                              to in RFC 3339 format, for example 2026-01-13T08:50:00Z
+                            type: string
+                        type: object
                       recoveryTime:
                         description: RecoveryTime timestamp when recovery was initiated
                         format: date-time
                         type: string
+                      serverName:
+                        description: ServerName the server name of the backups in
+                          the object store being recovered from
+                        type: string
                       snapshotName:
                         description: SnapshotName name of the snapshot being recovered
                           from
                         type: string
+                      source:
+                        description: Source the source of the recovery, VolumeSnapshot
+                          or ObjectStore
+                        type: string
                       status:
                         description: Status current recovery status
                         type: string
                     type: object
+                  volumeUsageCheckTime:
+                    description: VolumeUsageCheckTime is the time of the last auto
+                      grow check of the postgres cluster volume usage
+                    format: date-time
+                    type: string
+                  volumeUsedPercent:
+                    description: VolumeUsedPercent is the used percentage of the
+                      postgres cluster volume at the last auto grow check
+                    type: integer
                 type: object
               endpoints:
                 description: |-
@@ -4156,6 +5673,15 @@ metadata:
 spec:
 `
 
+const Sha256_deploy_crds_noobaa_io_v1alpha1_bucketreplication_cr_yaml = "df9f7ee37195a15d309dccdce8103dcec1fdfda36d32e6dd080ee80e837614b1"
+
+const File_deploy_crds_noobaa_io_v1alpha1_bucketreplication_cr_yaml = `apiVersion: noobaa.io/v1alpha1
+kind: BucketReplication
+metadata:
+  name: default
+spec: {}
+`
+
 const Sha256_deploy_crds_noobaa_io_v1alpha1_namespacestore_cr_yaml = "0938c22769bd9f2759d0ffd33b04a4650ec84dcd73508d9ef368f5908c1caec4"
 
 const File_deploy_crds_noobaa_io_v1alpha1_namespacestore_cr_yaml = `apiVersion: noobaa.io/v1alpha1
@@ -4183,7 +5709,25 @@ metadata:
 spec: {}
 `
 
-const Sha256_deploy_internal_admission_webhook_yaml = "6ac4c09a3923e2545fe484dbf68171d718669cf03e874889f44e005ed5f8529c"
+const Sha256_deploy_crds_noobaa_io_v1alpha1_noobaaperformanceprofile_cr_yaml = "c1fbc87b96d4bc63dc5a3c4471ad18575ed1c91b6dd3ef9a67f8ec137df4d993"
+
+const File_deploy_crds_noobaa_io_v1alpha1_noobaaperformanceprofile_cr_yaml = `apiVersion: noobaa.io/v1alpha1
+kind: NooBaaPerformanceProfile
+metadata:
+  name: custom
+spec: {}
+`
+
+const Sha256_deploy_crds_noobaa_io_v1alpha1_noobaaremote_cr_yaml = "90fcb915f538b6892446a5e311dc90431c7211a9c07bf5454f64d8cf5d3f37b0"
+
+const File_deploy_crds_noobaa_io_v1alpha1_noobaaremote_cr_yaml = `apiVersion: noobaa.io/v1alpha1
+kind: NooBaaRemote
+metadata:
+  name: default
+spec: {}
+`
+
+const Sha256_deploy_internal_admission_webhook_yaml = "4cc30cb1df1539c913087373729cb6bab2a5bfd9b45a323f450bef8e4fa6b108"
 
 const File_deploy_internal_admission_webhook_yaml = `apiVersion: admissionregistration.k8s.io/v1
 kind: ValidatingWebhookConfiguration
@@ -4227,6 +5771,14 @@ webhooks:
       resources:   
       - "noobaas"
       scope: "Namespaced"
+    - apiGroups:   ["noobaa.io"]
+      apiVersions: ["v1alpha1"]
+      operations:  
+      - "CREATE"
+      - "UPDATE"
+      resources:   
+      - "noobaaperformanceprofiles"
+      scope: "Cluster"
     sideEffects: None
     clientConfig:
       service:
@@ -4698,7 +6250,7 @@ spec:
           periodSeconds: 180
 `
 
-const Sha256_deploy_internal_hpav2_configmap_adapter_yaml = "8f857756f46511c8763fbc03e9373cb3eec11c2251d7a844ae4990d55208336b"
+const Sha256_deploy_internal_hpav2_configmap_adapter_yaml = "745fea94b1beb73a1196c007c9be8c2aeac4323a26022f150436af545c713719"
 
 const File_deploy_internal_hpav2_configmap_adapter_yaml = `apiVersion: v1
 kind: ConfigMap
@@ -4720,6 +6272,39 @@ data:
         matches: "^(.*)_total"
         as: "${1}_per_second"
       metricsQuery: (sum(irate(<<.Series>>{<<.LabelMatchers>>}[5m])) by (<<.GroupBy>>))
+    - seriesQuery: 'NooBaa_Endpoint_s3_requests_total{namespace="placeholder",pod=~"noobaa-endpoint-.*"}'
+      resources:
+        overrides:
+          namespace:
+            resource: namespace
+          pod:
+            resource: pod
+      name:
+        matches: "^NooBaa_Endpoint_s3_requests_total$"
+        as: "noobaa_endpoint_s3_requests_per_second"
+      metricsQuery: (sum(rate(<<.Series>>{<<.LabelMatchers>>}[2m])) by (<<.GroupBy>>))
+    - seriesQuery: 'NooBaa_Endpoint_s3_request_duration_seconds_bucket{namespace="placeholder",pod=~"noobaa-endpoint-.*"}'
+      resources:
+        overrides:
+          namespace:
+            resource: namespace
+          pod:
+            resource: pod
+      name:
+        matches: "^NooBaa_Endpoint_s3_request_duration_seconds_bucket$"
+        as: "noobaa_endpoint_s3_latency_p95_seconds"
+      metricsQuery: histogram_quantile(0.95, sum(rate(<<.Series>>{<<.LabelMatchers>>}[2m])) by (le, <<.GroupBy>>))
+    - seriesQuery: 'NooBaa_Endpoint_s3_inflight_requests{namespace="placeholder",pod=~"noobaa-endpoint-.*"}'
+      resources:
+        overrides:
+          namespace:
+            resource: namespace
+          pod:
+            resource: pod
+      name:
+        matches: "^NooBaa_Endpoint_s3_inflight_requests$"
+        as: "noobaa_endpoint_s3_inflight_requests"
+      metricsQuery: (sum(<<.Series>>{<<.LabelMatchers>>}) by (<<.GroupBy>>))
 `
 
 const Sha256_deploy_internal_hpav2_deployment_adapter_yaml = "9dc6739a1050a400b236cf6981f8f87950a9f8c72cef21a121f5107b69dfada4"
@@ -4921,6 +6506,33 @@ metadata:
     app: noobaa
 `
 
+const Sha256_deploy_internal_networkpolicy_cnpg_controller_yaml = "739c6ae61ce44234c34d1ab7c0c04ea9a1aca252d19d32e9298b7420ed4efca5"
+
+const File_deploy_internal_networkpolicy_cnpg_controller_yaml = `apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: cnpg-controller-manager
+  labels:
+    app: noobaa
+spec:
+  podSelector:
+    matchLabels:
+      app.kubernetes.io/name: cloudnative-pg
+  policyTypes:
+  - Ingress
+  ingress:
+  # the webhook server also serves the health probes, and is called by the API server
+  # and the kubelet which may use the host network, so it is open to any source
+  - ports:
+    - protocol: TCP
+      port: 9443
+  - from:
+    - namespaceSelector: {}
+    ports:
+    - protocol: TCP
+      port: 8080
+`
+
 const Sha256_deploy_internal_networkpolicy_core_yaml = "e03679b5dda3c850d4918bb9f82a08e4c07809b115cf40aa4e490a212aa3a662"
 
 const File_deploy_internal_networkpolicy_core_yaml = `apiVersion: networking.k8s.io/v1
@@ -5042,6 +6654,37 @@ spec:
       port: 14443
 `
 
+const Sha256_deploy_internal_networkpolicy_operator_yaml = "41c9849ab98c9bd139213efd91447db0f77d527cb407f9f38b6ad7c1cf84ab7c"
+
+const File_deploy_internal_networkpolicy_operator_yaml = `apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: noobaa-operator
+  labels:
+    app: noobaa
+spec:
+  podSelector:
+    matchLabels:
+      noobaa-operator: deployment
+  policyTypes:
+  - Ingress
+  ingress:
+  # the admission webhook and the health probes are called by the API server and the kubelet,
+  # which may use the host network, so these ports are open to any source
+  - ports:
+    - protocol: TCP
+      port: 8080
+    - protocol: TCP
+      port: 8081
+  - from:
+    - namespaceSelector: {}
+    ports:
+    - protocol: TCP
+      port: 8383
+    - protocol: TCP
+      port: 8384
+`
+
 const Sha256_deploy_internal_networkpolicy_pvpool_yaml = "bf0a2f786bc4e0e1f199abbd0ca2f24f186b58e21d5c95ed9b1ca8380eaed9de"
 
 const File_deploy_internal_networkpolicy_pvpool_yaml = `apiVersion: networking.k8s.io/v1
@@ -5640,6 +7283,25 @@ spec:
       name: hosted-agents-https
 `
 
+const Sha256_deploy_internal_service_operator_metrics_yaml = "5f30b70c0310b3d61cecfb247f079cc0ab1fb2abe6e84a4e70530c74a08d3907"
+
+const File_deploy_internal_service_operator_metrics_yaml = `apiVersion: v1
+kind: Service
+metadata:
+  name: noobaa-operator-metrics
+  labels:
+    app: noobaa
+    noobaa-operator-metrics-svc: "true"
+spec:
+  type: ClusterIP
+  selector:
+    noobaa-operator: deployment
+  ports:
+    - port: 8383
+      name: operator-metrics
+      targetPort: 8383
+`
+
 const Sha256_deploy_internal_service_s3_yaml = "55261b19002fbd3c780385fc8a357dc9eff691ebf52cfc0e0348af151ede5774"
 
 const File_deploy_internal_service_s3_yaml = `apiVersion: v1
@@ -5776,6 +7438,25 @@ spec:
       noobaa-mgmt-svc: "true"
 `
 
+const Sha256_deploy_internal_servicemonitor_operator_yaml = "e029ac870c1017552f96d7ae1aa8523f2bbb13b8d2fb798ee9f6a9b5ce907539"
+
+const File_deploy_internal_servicemonitor_operator_yaml = `apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: noobaa-operator-service-monitor
+  labels:
+    app: noobaa
+spec:
+  endpoints:
+  - port: operator-metrics
+    path: /metrics
+    scheme: http
+  namespaceSelector: {}
+  selector:
+    matchLabels:
+      noobaa-operator-metrics-svc: "true"
+`
+
 const Sha256_deploy_internal_servicemonitor_s3_yaml = "cff9fc9a511cc8ae5c0b957e91ab91ed0b8e9ed9e4d50094592332cea7a2ddc1"
 
 const File_deploy_internal_servicemonitor_s3_yaml = `apiVersion: monitoring.coreos.com/v1
@@ -6277,6 +7958,107 @@ spec:
       restartPolicy: Never
 `
 
+const Sha256_deploy_job_db_upgrade_check_yml = "659aa4bbb711c7f17dc48c42996d73636498938407a445ff2f9065de73856509"
+
+const File_deploy_job_db_upgrade_check_yml = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: noobaa-db-upgrade-check
+  labels:
+    app: noobaa
+spec:
+  completions: 1
+  parallelism: 1
+  backoffLimit: 0
+  activeDeadlineSeconds: 1800
+  template:
+    metadata:
+      labels:
+        app: noobaa
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        runAsUser: 26
+        runAsGroup: 26
+        fsGroup: 26
+        seccompProfile:
+          type: RuntimeDefault
+      volumes:
+      - name: pgdata
+        persistentVolumeClaim:
+          claimName: PVC_NAME_PLACEHOLDER
+      - name: report
+        emptyDir: {}
+      initContainers:
+      # lists the major version and the extensions of the target image
+      - name: target-image
+        image: TARGET_DB_IMAGE_PLACEHOLDER
+        command:
+          - /bin/bash
+          - -c
+          - |
+            postgres -V | sed -E 's/.* ([0-9]+)[.].*/\1/' > /report/target-version
+            ls /usr/share/postgresql/*/extension/*.control | xargs -n1 basename | sed 's/[.]control$//' > /report/target-extensions
+        volumeMounts:
+          - name: report
+            mountPath: /report
+      containers:
+      # starts the current postgres version on the clone of the snapshot and reports what blocks the upgrade,
+      # the checks of pg_upgrade --check that apply to the noobaa database.
+      # the report is written as key=value lines to the log of the container, since the termination message is truncated at 4 KiB,
+      # and ends with the line end=true, so a report without it is incomplete.
+      - name: db-upgrade-check
+        image: CURRENT_DB_IMAGE_PLACEHOLDER
+        env:
+          - name: PGDATA
+            value: /var/lib/postgresql/data/pgdata
+        command:
+          - /bin/bash
+          - -c
+          - |
+            report() { echo "$1"; }
+            report "version=$(cat $PGDATA/PG_VERSION)"
+            report "target_version=$(cat /report/target-version)"
+            df -Pk /var/lib/postgresql/data | awk 'NR==2 {print "volume_kb=" $2; print "volume_avail_kb=" $4}'
+
+            # the clone is a copy of an instance of the cluster, start it as a standalone primary without the cnpg configuration
+            rm -f $PGDATA/postmaster.pid $PGDATA/standby.signal $PGDATA/recovery.signal
+            : > $PGDATA/postgresql.auto.conf
+            : > /tmp/postgresql.conf
+            : > /tmp/pg_ident.conf
+            echo "local all all trust" > /tmp/pg_hba.conf
+            if ! pg_ctl -D $PGDATA -s -w -t 1200 -l /tmp/postgres.log start -o "-c config_file=/tmp/postgresql.conf -c hba_file=/tmp/pg_hba.conf -c ident_file=/tmp/pg_ident.conf -c listen_addresses= -c unix_socket_directories=/tmp"; then
+              report "error=postgres failed to start on the clone of the snapshot: $(tail -n 3 /tmp/postgres.log | tr '\n' ' ')"
+              report "end=true"
+              exit 0
+            fi
+
+            PSQL="psql -h /tmp -U postgres -X -A -t -v ON_ERROR_STOP=1"
+            report "data_bytes=$($PSQL -d postgres -c 'SELECT sum(pg_database_size(oid)) FROM pg_database')"
+            report "prepared_xacts=$($PSQL -d postgres -c 'SELECT count(*) FROM pg_prepared_xacts')"
+            for db in $($PSQL -d postgres -c "SELECT datname FROM pg_database WHERE datallowconn"); do
+              for ext in $($PSQL -d "$db" -c "SELECT extname FROM pg_extension"); do
+                report "extension=$db:$ext"
+                grep -qx "$ext" /report/target-extensions || report "missing_extension=$db:$ext"
+              done
+              for col in $($PSQL -d "$db" -c "SELECT n.nspname || '.' || c.relname || '.' || a.attname
+                  FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace
+                  WHERE c.relkind IN ('r', 'm') AND NOT a.attisdropped AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+                  AND a.atttypid::regtype::text IN ('regcollation', 'regconfig', 'regdictionary', 'regnamespace',
+                    'regoper', 'regoperator', 'regproc', 'regprocedure')"); do
+                report "reg_column=$db:$col"
+              done
+            done
+            pg_ctl -D $PGDATA -s -w -m fast stop
+            report "end=true"
+        volumeMounts:
+          - name: pgdata
+            mountPath: /var/lib/postgresql/data
+          - name: report
+            mountPath: /report
+      restartPolicy: Never
+`
+
 const Sha256_deploy_namespace_yaml = "303398323535d7f8229cb1a5378ad019cf4fa7930891688e3eea55c77e7bf69a"
 
 const File_deploy_namespace_yaml = `apiVersion: v1
@@ -7101,7 +8883,7 @@ spec:
         #     name: socket
 `
 
-const Sha256_deploy_role_yaml = "4739938379286ce9852a155f5faed0425457c7251419b5ac8ac99c7f8575a42a"
+const Sha256_deploy_role_yaml = "dd07a8f1be254888299f40ba48b832f8d866c6d982741897c289311903711b4b"
 
 const File_deploy_role_yaml = `apiVersion: rbac.authorization.k8s.io/v1
 kind: Role
@@ -7141,8 +8923,6 @@ rules:
   - pods/exec
   verbs:
   - create
-  resourceNames:
-  - noobaa-db-pg-0
 - apiGroups:
   - apps
   resources:
@@ -11,6 +11,7 @@ import (
 	"github.com/noobaa/noobaa-operator/v5/pkg/bench"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bucket"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bucketclass"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bucketreplication"
 	"github.com/noobaa/noobaa-operator/v5/pkg/cnpg"
 	"github.com/noobaa/noobaa-operator/v5/pkg/cosi"
 	"github.com/noobaa/noobaa-operator/v5/pkg/crd"
@@ -20,6 +21,7 @@ import (
 	"github.com/noobaa/noobaa-operator/v5/pkg/leaderelect"
 	"github.com/noobaa/noobaa-operator/v5/pkg/namespacestore"
 	"github.com/noobaa/noobaa-operator/v5/pkg/noobaaaccount"
+	"github.com/noobaa/noobaa-operator/v5/pkg/noobaaremote"
 	"github.com/noobaa/noobaa-operator/v5/pkg/obc"
 	"github.com/noobaa/noobaa-operator/v5/pkg/olm"
 	"github.com/noobaa/noobaa-operator/v5/pkg/operator"
@@ -135,7 +137,9 @@ Load noobaa completion to bash:
 			backingstore.Cmd(),
 			namespacestore.Cmd(),
 			bucketclass.Cmd(),
+			bucketreplication.Cmd(),
 			noobaaaccount.Cmd(),
+			noobaaremote.Cmd(),
 			obc.Cmd(),
 			cosi.Cmd(),
 			diagnostics.CmdDiagnoseDeprecated(),
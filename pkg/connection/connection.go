@@ -2,7 +2,9 @@ package connection
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/constants"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -21,11 +24,16 @@ type StoreType string
 const (
 	StoreTypeS3Compatible StoreType = "s3-compatible"
 	StoreTypeIBMCOS       StoreType = "ibm-cos"
+	StoreTypeAWSS3        StoreType = "aws-s3"
 )
 
 type matchedStore struct {
 	isBackingStore bool
 	Store          client.Object
+	storeType      StoreType
+	before         Properties
+	after          Properties
+	oldIdentity    string
 	identity       string
 	secret         string
 	authMethod     nb.CloudAuthMethod
@@ -37,16 +45,27 @@ func (m *matchedStore) name() string {
 	if m.Store == nil {
 		return ""
 	}
-	name := m.Store.GetName()
+	return m.kind() + "/" + m.Store.GetName()
+}
+
+func (m *matchedStore) kind() string {
 	if m.isBackingStore {
-		return "BackingStore/" + name
+		return "BackingStore"
 	}
-	return "NamespaceStore/" + name
+	return "NamespaceStore"
+}
+
+// selector specifies which stores are migrated, empty fields match any value
+type selector struct {
+	endpoint string
+	secret   string
+	region   string
 }
 
-type connEntry struct {
-	name         string
-	endpointType nb.EndpointType
+func (s *selector) matches(props Properties) bool {
+	return (s.endpoint == "" || props.Endpoint == s.endpoint) &&
+		(s.secret == "" || props.Secret == s.secret) &&
+		(s.region == "" || props.Region == s.region)
 }
 
 // Cmd returns a CLI command
@@ -57,6 +76,8 @@ func Cmd() *cobra.Command {
 	}
 	cmd.AddCommand(
 		CmdUpdate(),
+		CmdApply(),
+		CmdRollback(),
 	)
 	return cmd
 }
@@ -65,52 +86,186 @@ func Cmd() *cobra.Command {
 func CmdUpdate() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "update",
-		Short: `Update connection properties across all matching backing stores and namespace stores, 
-		currently the only supported property is endpoint`,
+		Short: `Update connection properties across all matching backing stores and namespace stores,
+		the endpoint, credentials secret, signature version and region can be updated`,
 		Run: RunUpdate,
 	}
-	cmd.Flags().String("old-endpoint", "", "The current endpoint URL to replace")
+	cmd.Flags().String("old-endpoint", "", "Select the stores that use this endpoint URL")
+	cmd.Flags().String("old-secret", "", "Select the stores that use this credentials secret (name or namespace/name)")
+	cmd.Flags().String("old-region", "", "Select the aws-s3 stores that use this region")
 	cmd.Flags().String("new-endpoint", "", "The new endpoint URL to set")
+	cmd.Flags().String("new-secret", "", "The new credentials secret to set (name or namespace/name)")
+	cmd.Flags().String("new-signature-version", "", "The new S3 signature version to set (v2, v4)")
+	cmd.Flags().String("new-region", "", "The new region to set on aws-s3 stores")
+	cmd.Flags().Bool("dry-run", false, "Validate the update and print the planned changes without applying them")
+	cmd.Flags().String("plan-file", "", "Save the planned changes to this file, to be applied or rolled back later")
+	return cmd
+}
+
+// CmdApply returns a CLI command
+func CmdApply() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a connection update plan saved by update --plan-file",
+		Run:   RunApply,
+	}
+	cmd.Flags().String("plan-file", "", "The plan file to apply")
+	return cmd
+}
+
+// CmdRollback returns a CLI command
+func CmdRollback() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll back a connection update plan that was applied",
+		Run:   RunRollback,
+	}
+	cmd.Flags().String("plan-file", "", "The plan file to roll back")
 	return cmd
 }
 
-// RunUpdate implements the endpoint update flow
+// RunUpdate plans the connection update of the matching stores and applies it unless in dry-run mode
 func RunUpdate(cmd *cobra.Command, args []string) {
 	log := util.Logger()
-	oldEndpoint, _ := cmd.Flags().GetString("old-endpoint")
-	newEndpoint, _ := cmd.Flags().GetString("new-endpoint")
-	oldEndpoint = strings.TrimSpace(oldEndpoint)
-	newEndpoint = strings.TrimSpace(newEndpoint)
+	flag := func(name string) string {
+		value, _ := cmd.Flags().GetString(name)
+		return strings.TrimSpace(value)
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	planFile := flag("plan-file")
+
+	sel := selector{
+		endpoint: flag("old-endpoint"),
+		region:   flag("old-region"),
+	}
+	if s := flag("old-secret"); s != "" {
+		sel.secret = secretKey(secretRef(s, options.Namespace), options.Namespace)
+	}
+	changes := Properties{
+		Endpoint: flag("new-endpoint"),
+		Region:   flag("new-region"),
+	}
+	if s := flag("new-secret"); s != "" {
+		changes.Secret = secretKey(secretRef(s, options.Namespace), options.Namespace)
+	}
+	switch sv := flag("new-signature-version"); sv {
+	case "":
+	case string(nbv1.S3SignatureVersionV2), string(nbv1.S3SignatureVersionV4):
+		changes.SignatureVersion = nbv1.S3SignatureVersion(sv)
+	default:
+		log.Fatalf(`❌ Invalid --new-signature-version %q, expected "v2" or "v4"`, sv)
+	}
 
 	// Validate flags
-	if oldEndpoint == "" || newEndpoint == "" {
-		log.Fatalf("both --old-endpoint and --new-endpoint are required")
+	if sel == (selector{}) {
+		log.Fatalf("at least one of --old-endpoint, --old-secret or --old-region is required")
+	}
+	if changes == (Properties{}) {
+		log.Fatalf("at least one of --new-endpoint, --new-secret, --new-signature-version or --new-region is required")
 	}
 
-	// List and filter all matching stores using oldEndpoint
-	matched := findMatchingStores(oldEndpoint)
+	// List and filter all matching stores
+	matched := findMatchingStores(sel)
 	if len(matched) == 0 {
-		log.Fatalf("no matching stores found for endpoint %q", oldEndpoint)
+		log.Fatalf("no matching stores found")
 	}
 
-	log.Infof("Found %d store(s) matching endpoint %q:", len(matched), oldEndpoint)
+	log.Infof("Found %d store(s) matching the selection:", len(matched))
 	for _, m := range matched {
 		log.Infof("  - %s", m.name())
 	}
 
-	// Read secrets and buckets
-	if err := loadSecretsAndBuckets(matched); err != nil {
+	sysClient, err := system.ConnectAuto()
+	if err != nil {
+		log.Fatalf("failed to connect to NooBaa system: %s", err)
+	}
+	nbClient := sysClient.NBClient
+
+	plan, matched, err := buildPlan(nbClient, matched, changes)
+	if err != nil {
+		log.Fatalf("failed to plan the connection update: %s", err)
+	}
+	if len(plan.Stores) == 0 {
+		log.Infof("All matching stores already have the requested properties, nothing to update")
+		return
+	}
+	fmt.Printf("\nPlanned changes:\n\n%s\n", plan.Diff())
+
+	// Pre-validate each store's bucket with the new connection properties
+	if err := loadCredentialsAndBuckets(matched); err != nil {
 		log.Fatalf("failed to read secrets: %s", err)
 	}
+	failedValidations := validateStores(nbClient, matched)
+	if len(failedValidations) > 0 {
+		log.Errorf("Pre-validation failed for the following stores:")
+		for _, f := range failedValidations {
+			log.Errorf("  - %s", f)
+		}
+		log.Fatalf("Aborting. No changes have been made.")
+	}
+	log.Infof("All stores passed pre-validation with the new connection properties")
+
+	if planFile != "" {
+		if err := SavePlan(plan, planFile); err != nil {
+			log.Fatalf("failed to save the plan to %q: %s", planFile, err)
+		}
+		log.Infof("Saved the plan to %q", planFile)
+	}
+
+	if dryRun {
+		log.Infof("Dry run. No changes have been made.")
+		return
+	}
+
+	applyPlan(nbClient, plan, matched)
+}
+
+// RunApply applies a saved connection update plan
+func RunApply(cmd *cobra.Command, args []string) {
+	runPlanFile(cmd, false)
+}
+
+// RunRollback applies the reverse of a saved connection update plan
+func RunRollback(cmd *cobra.Command, args []string) {
+	runPlanFile(cmd, true)
+}
+
+func runPlanFile(cmd *cobra.Command, reverse bool) {
+	log := util.Logger()
+	planFile, _ := cmd.Flags().GetString("plan-file")
+	if planFile == "" {
+		log.Fatalf("--plan-file is required")
+	}
+	plan, err := LoadPlan(planFile)
+	if err != nil {
+		log.Fatalf("failed to load the plan: %s", err)
+	}
+	if plan.Namespace != options.Namespace {
+		log.Fatalf("the plan was created for namespace %q, current namespace is %q", plan.Namespace, options.Namespace)
+	}
+	if reverse {
+		plan = plan.Reverse()
+	}
+	fmt.Printf("\nPlanned changes:\n\n%s\n", plan.Diff())
+
+	matched, err := loadPlanStores(plan)
+	if err != nil {
+		log.Fatalf("%s. No changes have been made.", err)
+	}
 
-	// Pre-validate each store's bucket on the new endpoint
 	sysClient, err := system.ConnectAuto()
 	if err != nil {
 		log.Fatalf("failed to connect to NooBaa system: %s", err)
 	}
 	nbClient := sysClient.NBClient
 
-	failedValidations := validateStores(nbClient, matched, newEndpoint)
+	if err := verifyPlanConnections(nbClient, plan); err != nil {
+		log.Fatalf("%s. No changes have been made.", err)
+	}
+	if err := loadCredentialsAndBuckets(matched); err != nil {
+		log.Fatalf("failed to read secrets: %s", err)
+	}
+	failedValidations := validateStores(nbClient, matched)
 	if len(failedValidations) > 0 {
 		log.Errorf("Pre-validation failed for the following stores:")
 		for _, f := range failedValidations {
@@ -118,7 +273,15 @@ func RunUpdate(cmd *cobra.Command, args []string) {
 		}
 		log.Fatalf("Aborting. No changes have been made.")
 	}
-	log.Infof("All stores passed pre-validation on new endpoint %q", newEndpoint)
+	log.Infof("All stores passed pre-validation with the new connection properties")
+
+	applyPlan(nbClient, plan, matched)
+}
+
+// applyPlan pauses the stores, patches their specs and updates the core connections,
+// rolling back everything that was changed if any step fails
+func applyPlan(nbClient nb.Client, plan *Plan, matched []matchedStore) {
+	log := util.Logger()
 
 	// Set pause annotation on all matching stores
 	if errs := setPauseAnnotation(matched, true); len(errs) > 0 {
@@ -127,59 +290,41 @@ func RunUpdate(cmd *cobra.Command, args []string) {
 	}
 	log.Infof("Paused reconciliation for all matching stores")
 
-	// Patch all CR specs with new endpoint
+	// Patch all CR specs with the new properties
 	var patched []matchedStore
-	if err := patchEndpoints(matched, newEndpoint, &patched); err != nil {
-		log.Errorf("failed to patch endpoints: %s", err)
-		rollback(patched, oldEndpoint, matched, nil, nil)
-		log.Fatalf("Rollback complete. Endpoint update aborted.")
-	}
-	log.Infof("Patched all %d store specs with new endpoint", len(patched))
-
-	// Discover unique connections by name from core
-	uniqueConns, err := findConnections(nbClient, oldEndpoint)
-	if err != nil {
-		log.Errorf("failed to find connections: %s", err)
-		rollback(patched, oldEndpoint, matched, nil, nil)
-		log.Fatalf("Rollback complete. Endpoint update aborted.")
+	if err := patchStores(matched, &patched); err != nil {
+		log.Errorf("failed to patch stores: %s", err)
+		rollback(patched, matched, nil, nil)
+		log.Fatalf("Rollback complete. Connection update aborted.")
 	}
-	log.Infof("Found %d unique connection(s) to update in NooBaa core", len(uniqueConns))
+	log.Infof("Patched all %d store specs with the new connection properties", len(patched))
 
 	// Update external connections, tracking successes for rollback
-	var updatedConns []connEntry
-	for _, entry := range uniqueConns {
-		err := nbClient.UpdateExternalConnectionAPI(nb.UpdateExternalConnectionParams{
-			Name: entry.name,
-			EndpointInfo: &nb.EndpointInfo{
-				Endpoint:     newEndpoint,
-				EndpointType: entry.endpointType,
-			},
-		})
-		if err != nil {
-			log.Errorf("failed to update connection %q: %s", entry.name, err)
-			rollback(patched, oldEndpoint, matched, nbClient, updatedConns)
-			log.Fatalf("Rollback complete. Endpoint update aborted.")
+	var updatedConns []ConnectionChange
+	for _, c := range plan.Connections {
+		if err := updateConnection(nbClient, c.Name, c.EndpointType, c.Before, c.After); err != nil {
+			log.Errorf("failed to update connection %q: %s", c.Name, err)
+			rollback(patched, matched, nbClient, updatedConns)
+			log.Fatalf("Rollback complete. Connection update aborted.")
 		}
-		updatedConns = append(updatedConns, entry)
-		log.Infof("Updated connection %q in NooBaa core", entry.name)
+		updatedConns = append(updatedConns, c)
+		log.Infof("Updated connection %q in NooBaa core", c.Name)
 	}
 
 	if errs := setPauseAnnotation(matched, false); len(errs) > 0 {
 		log.Warnf("failed to remove pause annotations: %s (stores were updated successfully, but annotations may need manual cleanup)", errs)
 	}
 
-	log.Infof("Endpoint update completed successfully:")
-	log.Infof("  Old endpoint: %s", oldEndpoint)
-	log.Infof("  New endpoint: %s", newEndpoint)
-	log.Infof("  Stores updated: %d", len(matched))
-	log.Infof("  Connections updated: %d", len(uniqueConns))
+	log.Infof("Connection update completed successfully:")
+	log.Infof("  Stores updated: %d", len(plan.Stores))
+	log.Infof("  Connections updated: %d", len(plan.Connections))
 }
 
 /*
 Lists the BackingStore and NamespaceStore resources in the
-configured namespace and returns the stores that match the specified endpoint.
+configured namespace and returns the stores that match the selector.
 */
-func findMatchingStores(endpoint string) []matchedStore {
+func findMatchingStores(sel selector) []matchedStore {
 	log := util.Logger()
 	// List all stores in the current namespace
 	backingStoreList := &nbv1.BackingStoreList{}
@@ -197,34 +342,153 @@ func findMatchingStores(endpoint string) []matchedStore {
 	var matched []matchedStore
 	for i := range backingStoreList.Items {
 		bs := &backingStoreList.Items[i]
-		if m, ok := matchBackingStore(bs, endpoint); ok {
+		if m, ok := matchBackingStore(bs); ok && sel.matches(m.before) {
 			matched = append(matched, m)
 		}
 	}
 	for i := range namespaceStoreList.Items {
 		ns := &namespaceStoreList.Items[i]
-		if m, ok := matchNamespaceStore(ns, endpoint); ok {
+		if m, ok := matchNamespaceStore(ns); ok && sel.matches(m.before) {
 			matched = append(matched, m)
 		}
 	}
 	return matched
 }
 
+/*
+buildPlan computes the new properties of every matched store and the core connections
+they use. Stores that already have the requested properties are left out of the plan.
+*/
+func buildPlan(nbClient nb.Client, matched []matchedStore, changes Properties) (*Plan, []matchedStore, error) {
+	plan := &Plan{
+		Namespace: options.Namespace,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	var planned []matchedStore
+	for i := range matched {
+		m := matched[i]
+		after, err := applyChanges(string(m.storeType), m.before, changes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", m.name(), err)
+		}
+		if after == m.before {
+			continue
+		}
+		m.after = after
+		if m.before.Secret != m.after.Secret {
+			m.oldIdentity, _, err = readCredentials(m.before.Secret, m.storeType)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", m.name(), err)
+			}
+		}
+		planned = append(planned, m)
+		plan.Stores = append(plan.Stores, StoreChange{
+			Kind:   m.kind(),
+			Name:   m.Store.GetName(),
+			Type:   string(m.storeType),
+			Before: m.before,
+			After:  m.after,
+		})
+	}
+	if len(planned) == 0 {
+		return plan, nil, nil
+	}
+
+	conns, err := findConnections(nbClient, planned)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan.Connections = conns
+	sortPlan(plan)
+	return plan, planned, nil
+}
+
+/*
+loadPlanStores reads the stores of a saved plan and verifies they were not
+changed since the plan was created.
+*/
+func loadPlanStores(plan *Plan) ([]matchedStore, error) {
+	var matched []matchedStore
+	for _, s := range plan.Stores {
+		var m matchedStore
+		var ok bool
+		switch s.Kind {
+		case "BackingStore":
+			bs := &nbv1.BackingStore{
+				TypeMeta:   metav1.TypeMeta{Kind: "BackingStore"},
+				ObjectMeta: metav1.ObjectMeta{Name: s.Name, Namespace: plan.Namespace},
+			}
+			if !util.KubeCheck(bs) {
+				return nil, fmt.Errorf("BackingStore %q not found", s.Name)
+			}
+			m, ok = matchBackingStore(bs)
+		case "NamespaceStore":
+			ns := &nbv1.NamespaceStore{
+				TypeMeta:   metav1.TypeMeta{Kind: "NamespaceStore"},
+				ObjectMeta: metav1.ObjectMeta{Name: s.Name, Namespace: plan.Namespace},
+			}
+			if !util.KubeCheck(ns) {
+				return nil, fmt.Errorf("NamespaceStore %q not found", s.Name)
+			}
+			m, ok = matchNamespaceStore(ns)
+		default:
+			return nil, fmt.Errorf("unsupported store kind %q in plan", s.Kind)
+		}
+		if !ok || string(m.storeType) != s.Type {
+			return nil, fmt.Errorf("%s/%s is no longer a %s store", s.Kind, s.Name, s.Type)
+		}
+		if m.before != s.Before {
+			return nil, fmt.Errorf("%s was changed since the plan was created", m.name())
+		}
+		m.after = s.After
+		matched = append(matched, m)
+	}
+	return matched, nil
+}
+
+/*
+verifyPlanConnections checks that the connections of a saved plan still exist
+in noobaa core with the endpoint they had when the plan was created.
+*/
+func verifyPlanConnections(nbClient nb.Client, plan *Plan) error {
+	systemInfo, err := nbClient.ReadSystemAPI()
+	if err != nil {
+		return fmt.Errorf("failed to read system info: %s", err)
+	}
+	for _, c := range plan.Connections {
+		found := false
+		for i := range systemInfo.Accounts {
+			account := &systemInfo.Accounts[i]
+			for j := range account.ExternalConnections.Connections {
+				conn := &account.ExternalConnections.Connections[j]
+				if conn.Name == c.Name && conn.Endpoint == c.Before.Endpoint {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("connection %q with endpoint %q was changed since the plan was created", c.Name, c.Before.Endpoint)
+		}
+	}
+	return nil
+}
+
 /*
 Validates the external connection configuration for each
-matched store for the specified endpoint.
+matched store with its new connection properties.
 It returns a list of validation errors, with each error identifying the
 store that failed validation.
 */
-func validateStores(nbClient nb.Client, matched []matchedStore, newEndpoint string) []string {
+func validateStores(nbClient nb.Client, matched []matchedStore) []string {
 	var failedValidations []string
 	for _, m := range matched {
 		params := nb.CheckExternalConnectionParams{
-			Endpoint:     newEndpoint,
+			Endpoint:     coreEndpoint(&m, m.after),
 			EndpointType: m.endpointType,
 			Identity:     nb.MaskedString(m.identity),
 			Secret:       nb.MaskedString(m.secret),
 			AuthMethod:   m.authMethod,
+			Region:       m.after.Region,
 		}
 		if m.isBackingStore {
 			params.Bucket = m.bucket
@@ -244,164 +508,184 @@ func validateStores(nbClient nb.Client, matched []matchedStore, newEndpoint stri
 
 /*
 findConnections retrieves the external connections configured in the system
-and returns the unique connections associated with the specified endpoint.
-The returned map is keyed by connection name.
+and returns the changes of the unique connections used by the matched stores.
+A connection is used by a store when it has the same endpoint, and when the
+credentials change, also the same identity.
 */
-func findConnections(nbClient nb.Client, endpoint string) (map[string]connEntry, error) {
+func findConnections(nbClient nb.Client, stores []matchedStore) ([]ConnectionChange, error) {
 	systemInfo, err := nbClient.ReadSystemAPI()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read system info: %s", err)
 	}
 
-	uniqueConns := map[string]connEntry{}
+	uniqueConns := map[string]ConnectionChange{}
 	for i := range systemInfo.Accounts {
 		account := &systemInfo.Accounts[i]
 		for j := range account.ExternalConnections.Connections {
 			conn := &account.ExternalConnections.Connections[j]
-			if conn.Endpoint == endpoint {
-				if _, exists := uniqueConns[conn.Name]; !exists {
-					uniqueConns[conn.Name] = connEntry{
-						name:         conn.Name,
-						endpointType: conn.EndpointType,
-					}
+			for k := range stores {
+				m := &stores[k]
+				if conn.EndpointType != m.endpointType || conn.Endpoint != coreEndpoint(m, m.before) {
+					continue
+				}
+				if m.before.Secret != m.after.Secret && conn.Identity != m.oldIdentity {
+					continue
 				}
+				change := ConnectionChange{
+					Name:         conn.Name,
+					EndpointType: conn.EndpointType,
+					Before:       connectionProperties(m, m.before),
+					After:        connectionProperties(m, m.after),
+				}
+				if existing, exists := uniqueConns[conn.Name]; exists && existing != change {
+					return nil, fmt.Errorf("connection %q is used by stores that are updated differently", conn.Name)
+				}
+				uniqueConns[conn.Name] = change
 			}
 		}
 	}
-	return uniqueConns, nil
+
+	var conns []ConnectionChange
+	for _, c := range uniqueConns {
+		conns = append(conns, c)
+	}
+	return conns, nil
+}
+
+// connectionProperties returns the core connection properties of a store with the given properties
+func connectionProperties(m *matchedStore, props Properties) Properties {
+	return Properties{
+		Endpoint:         coreEndpoint(m, props),
+		Secret:           props.Secret,
+		Region:           props.Region,
+		SignatureVersion: props.SignatureVersion,
+	}
+}
+
+// coreEndpoint returns the endpoint noobaa core uses for a store with the given properties
+func coreEndpoint(m *matchedStore, props Properties) string {
+	if m.storeType != StoreTypeAWSS3 {
+		return props.Endpoint
+	}
+	u := url.URL{
+		Scheme: "https",
+		Host:   "s3.amazonaws.com",
+	}
+	if awsS3 := storeAWSS3Spec(m.Store); awsS3 != nil && awsS3.SSLDisabled {
+		u.Scheme = "http"
+	}
+	if props.Region != "" {
+		u.Host = fmt.Sprintf("s3.%s.amazonaws.com", props.Region)
+	}
+	return u.String()
+}
+
+func storeAWSS3Spec(store client.Object) *nbv1.AWSS3Spec {
+	switch s := store.(type) {
+	case *nbv1.BackingStore:
+		return s.Spec.AWSS3
+	case *nbv1.NamespaceStore:
+		return s.Spec.AWSS3
+	}
+	return nil
 }
 
-func matchBackingStore(bs *nbv1.BackingStore, oldEndpoint string) (matchedStore, bool) {
+func matchBackingStore(bs *nbv1.BackingStore) (matchedStore, bool) {
 	if bs != nil {
 		switch bs.Spec.Type {
 		case nbv1.StoreTypeS3Compatible:
-			if bs.Spec.S3Compatible != nil && bs.Spec.S3Compatible.Endpoint == oldEndpoint {
-				return matchedStore{isBackingStore: true, Store: bs, endpointType: nb.EndpointTypeS3Compat}, true
+			if s := bs.Spec.S3Compatible; s != nil {
+				return matchedStore{isBackingStore: true, Store: bs, storeType: StoreTypeS3Compatible, endpointType: nb.EndpointTypeS3Compat,
+					before: Properties{Endpoint: s.Endpoint, Secret: secretKey(s.Secret, bs.Namespace), SignatureVersion: s.SignatureVersion}}, true
 			}
 		case nbv1.StoreTypeIBMCos:
-			if bs.Spec.IBMCos != nil && bs.Spec.IBMCos.Endpoint == oldEndpoint {
-				return matchedStore{isBackingStore: true, Store: bs, endpointType: nb.EndpointTypeIBMCos}, true
+			if s := bs.Spec.IBMCos; s != nil {
+				return matchedStore{isBackingStore: true, Store: bs, storeType: StoreTypeIBMCOS, endpointType: nb.EndpointTypeIBMCos,
+					before: Properties{Endpoint: s.Endpoint, Secret: secretKey(s.Secret, bs.Namespace), SignatureVersion: s.SignatureVersion}}, true
+			}
+		case nbv1.StoreTypeAWSS3:
+			// STS stores have no credentials secret and are not supported
+			if s := bs.Spec.AWSS3; s != nil && s.AWSSTSRoleARN == nil {
+				return matchedStore{isBackingStore: true, Store: bs, storeType: StoreTypeAWSS3, endpointType: nb.EndpointTypeAws,
+					before: Properties{Secret: secretKey(s.Secret, bs.Namespace), Region: s.Region}}, true
 			}
 		}
 	}
 	return matchedStore{}, false
 }
 
-func matchNamespaceStore(ns *nbv1.NamespaceStore, oldEndpoint string) (matchedStore, bool) {
+func matchNamespaceStore(ns *nbv1.NamespaceStore) (matchedStore, bool) {
 	if ns != nil {
 		switch ns.Spec.Type {
 		case nbv1.NSStoreTypeS3Compatible:
-			if ns.Spec.S3Compatible != nil && ns.Spec.S3Compatible.Endpoint == oldEndpoint {
-				return matchedStore{isBackingStore: false, Store: ns, endpointType: nb.EndpointTypeS3Compat}, true
+			if s := ns.Spec.S3Compatible; s != nil {
+				return matchedStore{isBackingStore: false, Store: ns, storeType: StoreTypeS3Compatible, endpointType: nb.EndpointTypeS3Compat,
+					before: Properties{Endpoint: s.Endpoint, Secret: secretKey(s.Secret, ns.Namespace), SignatureVersion: s.SignatureVersion}}, true
 			}
 		case nbv1.NSStoreTypeIBMCos:
-			if ns.Spec.IBMCos != nil && ns.Spec.IBMCos.Endpoint == oldEndpoint {
-				return matchedStore{isBackingStore: false, Store: ns, endpointType: nb.EndpointTypeIBMCos}, true
+			if s := ns.Spec.IBMCos; s != nil {
+				return matchedStore{isBackingStore: false, Store: ns, storeType: StoreTypeIBMCOS, endpointType: nb.EndpointTypeIBMCos,
+					before: Properties{Endpoint: s.Endpoint, Secret: secretKey(s.Secret, ns.Namespace), SignatureVersion: s.SignatureVersion}}, true
+			}
+		case nbv1.NSStoreTypeAWSS3:
+			// STS stores have no credentials secret and are not supported
+			if s := ns.Spec.AWSS3; s != nil && s.AWSSTSRoleARN == nil {
+				return matchedStore{isBackingStore: false, Store: ns, storeType: StoreTypeAWSS3, endpointType: nb.EndpointTypeAws,
+					before: Properties{Secret: secretKey(s.Secret, ns.Namespace), Region: s.Region}}, true
 			}
 		}
 	}
 	return matchedStore{}, false
 }
 
-func loadSecretsAndBuckets(stores []matchedStore) error {
+// loadCredentialsAndBuckets reads the credentials from the new secret of each store,
+// which are used for validation and for updating the core connections
+func loadCredentialsAndBuckets(stores []matchedStore) error {
 	for i := range stores {
 		m := &stores[i]
+		identity, secret, err := readCredentials(m.after.Secret, m.storeType)
+		if err != nil {
+			return fmt.Errorf("%s: %w", m.name(), err)
+		}
+		m.identity, m.secret = identity, secret
+		m.authMethod = nb.CloudAuthMethod(extractSignatureVersion(m.after.SignatureVersion))
+
+		var bucket string
 		if m.isBackingStore {
-			if err := loadBackingStoreSecretsAndBucket(m); err != nil {
-				return err
-			}
+			bucket, err = util.GetBackingStoreTargetBucket(m.Store.(*nbv1.BackingStore))
 		} else {
-			if err := loadNamespaceStoreSecretsAndBucket(m); err != nil {
-				return err
-			}
+			bucket, err = util.GetNamespaceStoreTargetBucket(m.Store.(*nbv1.NamespaceStore))
 		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", m.name(), err)
+		}
+		m.bucket = bucket
 	}
 	return nil
 }
 
-func loadBackingStoreSecretsAndBucket(m *matchedStore) error {
-	bs := m.Store
-	storeName := bs.GetName()
-	secretRef, err := util.GetBackingStoreSecret(bs.(*nbv1.BackingStore))
-	if err != nil {
-		return fmt.Errorf("BackingStore %q: %w", storeName, err)
-	}
-	secret, err := util.GetSecretFromSecretReference(secretRef)
+// readCredentials reads the identity and secret of a store type from a "namespace/name" secret
+func readCredentials(key string, storeType StoreType) (string, string, error) {
+	ref := secretRef(key, options.Namespace)
+	secret, err := util.GetSecretFromSecretReference(&ref)
 	if err != nil {
-		return fmt.Errorf("BackingStore %q: %w", storeName, err)
+		return "", "", err
 	}
 	if secret == nil {
-		return fmt.Errorf("BackingStore %q: secret not found", storeName)
+		return "", "", fmt.Errorf("secret %q not found", key)
 	}
-
-	extractStoreCredentials(m, secret)
-
-	bucket, err := util.GetBackingStoreTargetBucket(bs.(*nbv1.BackingStore))
-	if err != nil {
-		return fmt.Errorf("BackingStore %q: %w", storeName, err)
-	}
-	m.bucket = bucket
-	return nil
-}
-
-func extractStoreCredentials(m *matchedStore, secret *corev1.Secret) {
-	switch m.Store.(type) {
-	case *nbv1.BackingStore:
-		bs := m.Store.(*nbv1.BackingStore)
-		switch bs.Spec.Type {
-		case nbv1.StoreTypeS3Compatible:
-			m.identity, m.secret = extractCredentials(
-				secret,
-				StoreTypeS3Compatible,
-			)
-			if bs.Spec.S3Compatible != nil {
-				m.authMethod = nb.CloudAuthMethod(
-					extractSignatureVersion(bs.Spec.S3Compatible.SignatureVersion),
-				)
-			}
-		case nbv1.StoreTypeIBMCos:
-			m.identity, m.secret = extractCredentials(
-				secret,
-				StoreTypeIBMCOS,
-			)
-			if bs.Spec.IBMCos != nil {
-				m.authMethod = nb.CloudAuthMethod(
-					extractSignatureVersion(bs.Spec.IBMCos.SignatureVersion),
-				)
-			}
-		}
-	case *nbv1.NamespaceStore:
-		ns := m.Store.(*nbv1.NamespaceStore)
-		switch ns.Spec.Type {
-		case nbv1.NSStoreTypeS3Compatible:
-			m.identity, m.secret = extractCredentials(
-				secret,
-				StoreTypeS3Compatible,
-			)
-			if ns.Spec.S3Compatible != nil {
-				m.authMethod = nb.CloudAuthMethod(
-					extractSignatureVersion(ns.Spec.S3Compatible.SignatureVersion),
-				)
-			}
-		case nbv1.NSStoreTypeIBMCos:
-			m.identity, m.secret = extractCredentials(
-				secret,
-				StoreTypeIBMCOS,
-			)
-			if ns.Spec.IBMCos != nil {
-				m.authMethod = nb.CloudAuthMethod(
-					extractSignatureVersion(ns.Spec.IBMCos.SignatureVersion),
-				)
-			}
-		}
+	identity, secretKey := extractCredentials(secret, storeType)
+	if identity == "" || secretKey == "" {
+		return "", "", fmt.Errorf("secret %q is missing the credentials of a %s store", key, storeType)
 	}
+	return identity, secretKey, nil
 }
 
 func extractCredentials(secret *corev1.Secret, storeType StoreType) (string, string) {
 	identityKey := ""
 	secretKey := ""
 	switch storeType {
-	case StoreTypeS3Compatible:
+	case StoreTypeS3Compatible, StoreTypeAWSS3:
 		identityKey = "AWS_ACCESS_KEY_ID"
 		secretKey = "AWS_SECRET_ACCESS_KEY"
 	case StoreTypeIBMCOS:
@@ -411,32 +695,6 @@ func extractCredentials(secret *corev1.Secret, storeType StoreType) (string, str
 	return secret.StringData[identityKey], secret.StringData[secretKey]
 }
 
-func loadNamespaceStoreSecretsAndBucket(m *matchedStore) error {
-	ns := m.Store
-	storeName := ns.GetName()
-
-	secretRef, err := util.GetNamespaceStoreSecret(ns.(*nbv1.NamespaceStore))
-	if err != nil {
-		return fmt.Errorf("NamespaceStore %q: %w", storeName, err)
-	}
-	secret, err := util.GetSecretFromSecretReference(secretRef)
-	if err != nil {
-		return fmt.Errorf("NamespaceStore %q: %w", storeName, err)
-	}
-	if secret == nil {
-		return fmt.Errorf("NamespaceStore %q: secret not found", storeName)
-	}
-
-	extractStoreCredentials(m, secret)
-
-	bucket, err := util.GetNamespaceStoreTargetBucket(ns.(*nbv1.NamespaceStore))
-	if err != nil {
-		return fmt.Errorf("NamespaceStore %q: %w", storeName, err)
-	}
-	m.bucket = bucket
-	return nil
-}
-
 func extractSignatureVersion(sv nbv1.S3SignatureVersion) string {
 	svString := ""
 	switch sv {
@@ -478,29 +736,43 @@ func setPauseAnnotation(stores []matchedStore, pause bool) (errors []error) {
 	return errors
 }
 
-func patchEndpoints(stores []matchedStore, newEndpoint string, patched *[]matchedStore) error {
+// setStoreProperties sets the connection properties on the store spec
+func setStoreProperties(m *matchedStore, props Properties) error {
+	ref := secretRef(props.Secret, m.Store.GetNamespace())
+	if m.isBackingStore {
+		bs := m.Store.(*nbv1.BackingStore)
+		switch bs.Spec.Type {
+		case nbv1.StoreTypeS3Compatible:
+			bs.Spec.S3Compatible.Endpoint = props.Endpoint
+			bs.Spec.S3Compatible.SignatureVersion = props.SignatureVersion
+		case nbv1.StoreTypeIBMCos:
+			bs.Spec.IBMCos.Endpoint = props.Endpoint
+			bs.Spec.IBMCos.SignatureVersion = props.SignatureVersion
+		case nbv1.StoreTypeAWSS3:
+			bs.Spec.AWSS3.Region = props.Region
+		}
+		return util.SetBackingStoreSecretRef(bs, &ref)
+	}
+	ns := m.Store.(*nbv1.NamespaceStore)
+	switch ns.Spec.Type {
+	case nbv1.NSStoreTypeS3Compatible:
+		ns.Spec.S3Compatible.Endpoint = props.Endpoint
+		ns.Spec.S3Compatible.SignatureVersion = props.SignatureVersion
+	case nbv1.NSStoreTypeIBMCos:
+		ns.Spec.IBMCos.Endpoint = props.Endpoint
+		ns.Spec.IBMCos.SignatureVersion = props.SignatureVersion
+	case nbv1.NSStoreTypeAWSS3:
+		ns.Spec.AWSS3.Region = props.Region
+	}
+	return util.SetNamespaceStoreSecretRef(ns, &ref)
+}
+
+func patchStores(stores []matchedStore, patched *[]matchedStore) error {
 	for i := range stores {
 		m := &stores[i]
-		if m.isBackingStore {
-			bs := m.Store.(*nbv1.BackingStore)
-			switch bs.Spec.Type {
-			case nbv1.StoreTypeS3Compatible:
-				bs.Spec.S3Compatible.Endpoint = newEndpoint
-			case nbv1.StoreTypeIBMCos:
-				bs.Spec.IBMCos.Endpoint = newEndpoint
-			}
-			m.Store = bs
-		} else {
-			ns := m.Store.(*nbv1.NamespaceStore)
-			switch ns.Spec.Type {
-			case nbv1.NSStoreTypeS3Compatible:
-				ns.Spec.S3Compatible.Endpoint = newEndpoint
-			case nbv1.NSStoreTypeIBMCos:
-				ns.Spec.IBMCos.Endpoint = newEndpoint
-			}
-			m.Store = ns
+		if err := setStoreProperties(m, m.after); err != nil {
+			return err
 		}
-
 		if !util.KubeUpdate(m.Store) {
 			return fmt.Errorf("failed to patch Store %q", m.name())
 		}
@@ -509,56 +781,59 @@ func patchEndpoints(stores []matchedStore, newEndpoint string, patched *[]matche
 	return nil
 }
 
-// rollback reverts already-patched stores to the old endpoint, reverts any
+// updateConnection updates a core connection from the before to the after properties,
+// only the properties that differ are sent
+func updateConnection(nbClient nb.Client, name string, endpointType nb.EndpointType, before, after Properties) error {
+	params := nb.UpdateExternalConnectionParams{Name: name}
+	if before.Endpoint != after.Endpoint {
+		params.EndpointInfo = &nb.EndpointInfo{
+			Endpoint:     after.Endpoint,
+			EndpointType: endpointType,
+		}
+	}
+	if before.Secret != after.Secret {
+		storeType := StoreTypeS3Compatible
+		if endpointType == nb.EndpointTypeIBMCos {
+			storeType = StoreTypeIBMCOS
+		}
+		identity, secret, err := readCredentials(after.Secret, storeType)
+		if err != nil {
+			return err
+		}
+		params.Identity = nb.MaskedString(identity)
+		params.Secret = nb.MaskedString(secret)
+	}
+	if before.Region != after.Region {
+		params.Region = after.Region
+	}
+	return nbClient.UpdateExternalConnectionAPI(params)
+}
+
+// rollback reverts already-patched stores to their previous properties, reverts any
 // already-updated core connections, and removes pause annotations from all stores.
 // nbClient and updatedConns may be nil if no connections were updated yet.
-func rollback(patched []matchedStore, oldEndpoint string, allStores []matchedStore, nbClient nb.Client, updatedConns []connEntry) {
+func rollback(patched []matchedStore, allStores []matchedStore, nbClient nb.Client, updatedConns []ConnectionChange) {
 	log := util.Logger()
-	log.Infof("Rolling back %d patched store(s) to endpoint %q", len(patched), oldEndpoint)
+	log.Infof("Rolling back %d patched store(s)", len(patched))
 	for i := range patched {
 		m := &patched[i]
-		if m.isBackingStore {
-			bs := m.Store.(*nbv1.BackingStore)
-			switch bs.Spec.Type {
-			case nbv1.StoreTypeS3Compatible:
-				bs.Spec.S3Compatible.Endpoint = oldEndpoint
-			case nbv1.StoreTypeIBMCos:
-				bs.Spec.IBMCos.Endpoint = oldEndpoint
-			}
-			m.Store = bs
-		} else {
-			ns := m.Store.(*nbv1.NamespaceStore)
-			switch ns.Spec.Type {
-			case nbv1.NSStoreTypeS3Compatible:
-				ns.Spec.S3Compatible.Endpoint = oldEndpoint
-			case nbv1.NSStoreTypeIBMCos:
-				ns.Spec.IBMCos.Endpoint = oldEndpoint
-			}
-			m.Store = ns
-		}
-		if !util.KubeUpdate(m.Store) {
+		if err := setStoreProperties(m, m.before); err != nil || !util.KubeUpdate(m.Store) {
 			log.Errorf("failed to rollback Store %q", m.name())
 		}
 	}
 
 	if nbClient != nil && len(updatedConns) > 0 {
-		log.Infof("Rolling back %d updated connection(s) to endpoint %q", len(updatedConns), oldEndpoint)
+		log.Infof("Rolling back %d updated connection(s)", len(updatedConns))
 		var failedReverts []string
-		for _, entry := range updatedConns {
-			err := nbClient.UpdateExternalConnectionAPI(nb.UpdateExternalConnectionParams{
-				Name: entry.name,
-				EndpointInfo: &nb.EndpointInfo{
-					Endpoint:     oldEndpoint,
-					EndpointType: entry.endpointType,
-				},
-			})
+		for _, c := range updatedConns {
+			err := updateConnection(nbClient, c.Name, c.EndpointType, c.After, c.Before)
 			if err != nil {
-				failedReverts = append(failedReverts, entry.name)
-				log.Errorf("failed to revert connection %q: %s", entry.name, err)
+				failedReverts = append(failedReverts, c.Name)
+				log.Errorf("failed to revert connection %q: %s", c.Name, err)
 			}
 		}
 		if len(failedReverts) > 0 {
-			log.Errorf("MANUAL ACTION REQUIRED: the following connections could not be reverted and still point to the new endpoint: %v", failedReverts)
+			log.Errorf("MANUAL ACTION REQUIRED: the following connections could not be reverted and still use the new connection properties: %v", failedReverts)
 		}
 	}
 
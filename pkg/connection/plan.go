@@ -0,0 +1,182 @@
+package connection
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Properties are the connection properties of a store that can be migrated.
+// Secret is kept as a "namespace/name" reference, the plan never holds credentials.
+type Properties struct {
+	Endpoint         string                  `json:"endpoint,omitempty"`
+	Secret           string                  `json:"secret,omitempty"`
+	SignatureVersion nbv1.S3SignatureVersion `json:"signatureVersion,omitempty"`
+	Region           string                  `json:"region,omitempty"`
+}
+
+// StoreChange is the planned change of a single BackingStore or NamespaceStore
+type StoreChange struct {
+	Kind   string     `json:"kind"`
+	Name   string     `json:"name"`
+	Type   string     `json:"type"`
+	Before Properties `json:"before"`
+	After  Properties `json:"after"`
+}
+
+// ConnectionChange is the planned change of a single external connection in noobaa core.
+// The secrets are the store secrets that hold the connection credentials before and after the change.
+type ConnectionChange struct {
+	Name         string          `json:"name"`
+	EndpointType nb.EndpointType `json:"endpointType"`
+	Before       Properties      `json:"before"`
+	After        Properties      `json:"after"`
+}
+
+// Plan is a reviewed set of connection changes that can be saved to a file,
+// applied later and rolled back by applying its reverse
+type Plan struct {
+	Namespace   string             `json:"namespace"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	Stores      []StoreChange      `json:"stores"`
+	Connections []ConnectionChange `json:"connections,omitempty"`
+}
+
+// Reverse returns the plan that rolls back this plan
+func (p *Plan) Reverse() *Plan {
+	r := &Plan{
+		Namespace: p.Namespace,
+		CreatedAt: time.Now(),
+	}
+	for _, s := range p.Stores {
+		s.Before, s.After = s.After, s.Before
+		r.Stores = append(r.Stores, s)
+	}
+	for _, c := range p.Connections {
+		c.Before, c.After = c.After, c.Before
+		r.Connections = append(r.Connections, c)
+	}
+	return r
+}
+
+// Diff returns a human readable per-store and per-connection diff of the plan
+func (p *Plan) Diff() string {
+	var b strings.Builder
+	for _, s := range p.Stores {
+		fmt.Fprintf(&b, "%s/%s (%s):\n", s.Kind, s.Name, s.Type)
+		writePropertiesDiff(&b, s.Before, s.After)
+	}
+	for _, c := range p.Connections {
+		fmt.Fprintf(&b, "Connection/%s (%s):\n", c.Name, c.EndpointType)
+		writePropertiesDiff(&b, c.Before, c.After)
+	}
+	return b.String()
+}
+
+func writePropertiesDiff(b *strings.Builder, before, after Properties) {
+	diffLine := func(name, from, to string) {
+		if from != to {
+			fmt.Fprintf(b, "  %-17s %q -> %q\n", name+":", from, to)
+		}
+	}
+	diffLine("endpoint", before.Endpoint, after.Endpoint)
+	diffLine("secret", before.Secret, after.Secret)
+	diffLine("signatureVersion", string(before.SignatureVersion), string(after.SignatureVersion))
+	diffLine("region", before.Region, after.Region)
+}
+
+// SavePlan writes the plan to a YAML file
+func SavePlan(plan *Plan, path string) error {
+	bytes, err := yaml.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bytes, 0600)
+}
+
+// LoadPlan reads a plan from a YAML file
+func LoadPlan(path string) (*Plan, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{}
+	if err := yaml.UnmarshalStrict(bytes, plan); err != nil {
+		return nil, fmt.Errorf("invalid plan file %q: %w", path, err)
+	}
+	if len(plan.Stores) == 0 {
+		return nil, fmt.Errorf("plan file %q has no stores to update", path)
+	}
+	return plan, nil
+}
+
+// applyChanges returns the properties after applying the requested changes,
+// empty fields of the changes keep the current value.
+// It fails when a change is not applicable to the store type.
+func applyChanges(storeType string, before Properties, changes Properties) (Properties, error) {
+	after := before
+	isAWS := storeType == string(nbv1.StoreTypeAWSS3)
+	if changes.Endpoint != "" {
+		if isAWS {
+			return after, fmt.Errorf("the endpoint of %s stores is derived from the region, use --new-region instead", storeType)
+		}
+		after.Endpoint = changes.Endpoint
+	}
+	if changes.SignatureVersion != "" {
+		if isAWS {
+			return after, fmt.Errorf("the signature version of %s stores can not be changed", storeType)
+		}
+		after.SignatureVersion = changes.SignatureVersion
+	}
+	if changes.Region != "" {
+		if !isAWS {
+			return after, fmt.Errorf("the region of %s stores can not be changed", storeType)
+		}
+		after.Region = changes.Region
+	}
+	if changes.Secret != "" {
+		after.Secret = changes.Secret
+	}
+	return after, nil
+}
+
+// secretKey returns the "namespace/name" form of a secret reference
+func secretKey(ref corev1.SecretReference, defaultNamespace string) string {
+	if ref.Name == "" {
+		return ""
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return namespace + "/" + ref.Name
+}
+
+// secretRef parses a "namespace/name" or "name" secret into a secret reference
+func secretRef(key string, defaultNamespace string) corev1.SecretReference {
+	if i := strings.Index(key, "/"); i >= 0 {
+		return corev1.SecretReference{Namespace: key[:i], Name: key[i+1:]}
+	}
+	return corev1.SecretReference{Namespace: defaultNamespace, Name: key}
+}
+
+// sortPlan orders the plan entries so that the saved plan and the printed diff are stable
+func sortPlan(plan *Plan) {
+	sort.Slice(plan.Stores, func(i, j int) bool {
+		if plan.Stores[i].Kind != plan.Stores[j].Kind {
+			return plan.Stores[i].Kind < plan.Stores[j].Kind
+		}
+		return plan.Stores[i].Name < plan.Stores[j].Name
+	})
+	sort.Slice(plan.Connections, func(i, j int) bool {
+		return plan.Connections[i].Name < plan.Connections[j].Name
+	})
+}
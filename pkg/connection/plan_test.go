@@ -0,0 +1,213 @@
+package connection
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyChanges(t *testing.T) {
+	s3Compatible := Properties{Endpoint: "http://old:9000", Secret: "ns/old-creds", SignatureVersion: nbv1.S3SignatureVersionV4}
+	awsS3 := Properties{Secret: "ns/old-creds", Region: "us-east-1"}
+
+	tests := []struct {
+		name      string
+		storeType StoreType
+		before    Properties
+		changes   Properties
+		expected  Properties
+		wantErr   bool
+	}{
+		{
+			name:      "change endpoint and keep the rest",
+			storeType: StoreTypeS3Compatible,
+			before:    s3Compatible,
+			changes:   Properties{Endpoint: "http://new:9000"},
+			expected:  Properties{Endpoint: "http://new:9000", Secret: "ns/old-creds", SignatureVersion: nbv1.S3SignatureVersionV4},
+		},
+		{
+			name:      "change credentials and signature version",
+			storeType: StoreTypeIBMCOS,
+			before:    s3Compatible,
+			changes:   Properties{Secret: "ns/new-creds", SignatureVersion: nbv1.S3SignatureVersionV2},
+			expected:  Properties{Endpoint: "http://old:9000", Secret: "ns/new-creds", SignatureVersion: nbv1.S3SignatureVersionV2},
+		},
+		{
+			name:      "change region of aws-s3",
+			storeType: StoreTypeAWSS3,
+			before:    awsS3,
+			changes:   Properties{Region: "eu-west-1", Secret: "ns/new-creds"},
+			expected:  Properties{Secret: "ns/new-creds", Region: "eu-west-1"},
+		},
+		{
+			name:      "deny region of s3-compatible",
+			storeType: StoreTypeS3Compatible,
+			before:    s3Compatible,
+			changes:   Properties{Region: "eu-west-1"},
+			wantErr:   true,
+		},
+		{
+			name:      "deny endpoint of aws-s3",
+			storeType: StoreTypeAWSS3,
+			before:    awsS3,
+			changes:   Properties{Endpoint: "http://new:9000"},
+			wantErr:   true,
+		},
+		{
+			name:      "deny signature version of aws-s3",
+			storeType: StoreTypeAWSS3,
+			before:    awsS3,
+			changes:   Properties{SignatureVersion: nbv1.S3SignatureVersionV2},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			after, err := applyChanges(string(tc.storeType), tc.before, tc.changes)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("applyChanges() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && after != tc.expected {
+				t.Fatalf("applyChanges() = %+v, expected %+v", after, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSecretRef(t *testing.T) {
+	ref := secretRef("creds", "default-ns")
+	if ref != (corev1.SecretReference{Namespace: "default-ns", Name: "creds"}) {
+		t.Fatalf("unexpected secret reference %+v", ref)
+	}
+	ref = secretRef("other-ns/creds", "default-ns")
+	if ref != (corev1.SecretReference{Namespace: "other-ns", Name: "creds"}) {
+		t.Fatalf("unexpected secret reference %+v", ref)
+	}
+	if key := secretKey(corev1.SecretReference{Name: "creds"}, "default-ns"); key != "default-ns/creds" {
+		t.Fatalf("unexpected secret key %q", key)
+	}
+	if key := secretKey(corev1.SecretReference{}, "default-ns"); key != "" {
+		t.Fatalf("expected an empty secret key, got %q", key)
+	}
+}
+
+func testPlan() *Plan {
+	return &Plan{
+		Namespace: "noobaa",
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Stores: []StoreChange{{
+			Kind:   "BackingStore",
+			Name:   "bs1",
+			Type:   string(StoreTypeS3Compatible),
+			Before: Properties{Endpoint: "http://old:9000", Secret: "noobaa/old-creds"},
+			After:  Properties{Endpoint: "http://new:9000", Secret: "noobaa/new-creds"},
+		}},
+		Connections: []ConnectionChange{{
+			Name:         "conn1",
+			EndpointType: nb.EndpointTypeS3Compat,
+			Before:       Properties{Endpoint: "http://old:9000", Secret: "noobaa/old-creds"},
+			After:        Properties{Endpoint: "http://new:9000", Secret: "noobaa/new-creds"},
+		}},
+	}
+}
+
+func TestPlanReverse(t *testing.T) {
+	plan := testPlan()
+	reverse := plan.Reverse()
+	if reverse.Stores[0].After != plan.Stores[0].Before || reverse.Stores[0].Before != plan.Stores[0].After {
+		t.Fatalf("store change was not reversed: %+v", reverse.Stores[0])
+	}
+	if reverse.Connections[0].After != plan.Connections[0].Before || reverse.Connections[0].Before != plan.Connections[0].After {
+		t.Fatalf("connection change was not reversed: %+v", reverse.Connections[0])
+	}
+	if plan.Stores[0].After.Endpoint != "http://new:9000" {
+		t.Fatalf("reverse modified the original plan")
+	}
+}
+
+func TestPlanDiff(t *testing.T) {
+	diff := testPlan().Diff()
+	for _, expected := range []string{
+		"BackingStore/bs1 (s3-compatible):",
+		`endpoint:         "http://old:9000" -> "http://new:9000"`,
+		`secret:           "noobaa/old-creds" -> "noobaa/new-creds"`,
+		"Connection/conn1 (S3_COMPATIBLE):",
+	} {
+		if !strings.Contains(diff, expected) {
+			t.Fatalf("diff does not contain %q:\n%s", expected, diff)
+		}
+	}
+	if strings.Contains(diff, "region") || strings.Contains(diff, "signatureVersion") {
+		t.Fatalf("diff contains unchanged properties:\n%s", diff)
+	}
+}
+
+func TestSaveLoadPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	plan := testPlan()
+	if err := SavePlan(plan, path); err != nil {
+		t.Fatalf("SavePlan() error = %v", err)
+	}
+	loaded, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan() error = %v", err)
+	}
+	if loaded.Namespace != plan.Namespace || !loaded.CreatedAt.Equal(plan.CreatedAt) ||
+		loaded.Stores[0] != plan.Stores[0] || loaded.Connections[0] != plan.Connections[0] {
+		t.Fatalf("loaded plan %+v differs from saved plan %+v", loaded, plan)
+	}
+
+	if err := SavePlan(&Plan{Namespace: "noobaa"}, path); err != nil {
+		t.Fatalf("SavePlan() error = %v", err)
+	}
+	if _, err := LoadPlan(path); err == nil {
+		t.Fatalf("expected an error loading a plan without stores")
+	}
+}
+
+func TestFindConnections(t *testing.T) {
+	server := fake.NewServer()
+	server.ExternalConnections["conn1"] = &nb.ExternalConnectionInfo{
+		Name: "conn1", EndpointType: nb.EndpointTypeS3Compat, Endpoint: "http://old:9000", Identity: "key",
+	}
+	store := func(name string, after nbv1.S3SignatureVersion) matchedStore {
+		before := Properties{Endpoint: "http://old:9000", Secret: "noobaa/creds", SignatureVersion: nbv1.S3SignatureVersionV4}
+		return matchedStore{
+			isBackingStore: true,
+			Store:          &nbv1.BackingStore{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			storeType:      StoreTypeS3Compatible,
+			endpointType:   nb.EndpointTypeS3Compat,
+			before:         before,
+			after:          Properties{Endpoint: "http://new:9000", Secret: "noobaa/creds", SignatureVersion: after},
+		}
+	}
+
+	conns, err := findConnections(server.Client(), []matchedStore{store("bs1", nbv1.S3SignatureVersionV2), store("bs2", nbv1.S3SignatureVersionV2)})
+	if err != nil {
+		t.Fatalf("findConnections() error = %v", err)
+	}
+	expected := ConnectionChange{
+		Name:         "conn1",
+		EndpointType: nb.EndpointTypeS3Compat,
+		Before:       Properties{Endpoint: "http://old:9000", Secret: "noobaa/creds", SignatureVersion: nbv1.S3SignatureVersionV4},
+		After:        Properties{Endpoint: "http://new:9000", Secret: "noobaa/creds", SignatureVersion: nbv1.S3SignatureVersionV2},
+	}
+	if len(conns) != 1 || conns[0] != expected {
+		t.Fatalf("findConnections() = %+v, expected %+v", conns, expected)
+	}
+
+	// stores that share a connection cannot change it to different signature versions
+	_, err = findConnections(server.Client(), []matchedStore{store("bs1", nbv1.S3SignatureVersionV2), store("bs2", nbv1.S3SignatureVersionV4)})
+	if err == nil {
+		t.Fatalf("expected an error for a connection updated differently")
+	}
+}
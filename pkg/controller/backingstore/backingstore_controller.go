@@ -2,9 +2,11 @@ package backingstore
 
 import (
 	"context"
+	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/backingstore"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -29,12 +31,16 @@ func Add(mgr manager.Manager) error {
 		MaxConcurrentReconciles: 1,
 		Reconciler: reconcile.Func(
 			func(context context.Context, req reconcile.Request) (reconcile.Result, error) {
-				return backingstore.NewReconciler(
+				defer metrics.ObserveReconcileDuration(metrics.ControllerBackingStore, time.Now())
+				r := backingstore.NewReconciler(
 					req.NamespacedName,
 					mgr.GetClient(),
 					mgr.GetScheme(),
 					mgr.GetEventRecorder("noobaa-operator"),
-				).Reconcile()
+				)
+				// calls to noobaa core are cancelled when the operator stops
+				r.Ctx = context
+				return r.Reconcile()
 			}),
 		SkipNameValidation: &[]bool{true}[0],
 	})
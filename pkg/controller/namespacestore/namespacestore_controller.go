@@ -2,8 +2,10 @@ package namespacestore
 
 import (
 	"context"
+	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/namespacestore"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +31,7 @@ func Add(mgr manager.Manager) error {
 		MaxConcurrentReconciles: 1,
 		Reconciler: reconcile.Func(
 			func(context context.Context, req reconcile.Request) (reconcile.Result, error) {
+				defer metrics.ObserveReconcileDuration(metrics.ControllerNamespaceStore, time.Now())
 				return namespacestore.NewReconciler(
 					req.NamespacedName,
 					mgr.GetClient(),
@@ -2,8 +2,10 @@ package noobaa
 
 import (
 	"context"
+	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/system"
@@ -45,12 +47,16 @@ func Add(mgr manager.Manager) error {
 		MaxConcurrentReconciles: 1,
 		Reconciler: reconcile.Func(
 			func(context context.Context, req reconcile.Request) (reconcile.Result, error) {
-				return system.NewReconciler(
+				defer metrics.ObserveReconcileDuration(metrics.ControllerNooBaa, time.Now())
+				r := system.NewReconciler(
 					req.NamespacedName,
 					mgr.GetClient(),
 					mgr.GetScheme(),
 					mgr.GetEventRecorder("noobaa-operator"),
-				).Reconcile()
+				)
+				// calls to noobaa core are cancelled when the operator stops
+				r.Ctx = context
+				return r.Reconcile()
 			}),
 		SkipNameValidation: &[]bool{true}[0],
 	})
@@ -128,6 +134,38 @@ func Add(mgr manager.Manager) error {
 	if err != nil {
 		return err
 	}
+	// systemHandler enqueues the system for changes of resources that are not owned by the system.
+	systemHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, mo client.Object) []reconcile.Request {
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{
+				Name:      options.SystemName,
+				Namespace: options.Namespace,
+			},
+		}}
+	},
+	)
+	// Watch for NooBaaPerformanceProfile changes to apply the profile resources to the system pods.
+	// Profiles are cluster scoped and rarely change, so the system is reconciled on any profile change
+	// and the reconciler decides if the profile is relevant.
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &nbv1.NooBaaPerformanceProfile{}, systemHandler,
+		predicate.GenerationChangedPredicate{}, &logEventsPredicate))
+	if err != nil {
+		return err
+	}
+
+	// Watch for BackingStore and NamespaceStore changes to update the egress allow-list of the network policies
+	// with the endpoints of the stores.
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &nbv1.BackingStore{}, systemHandler,
+		predicate.GenerationChangedPredicate{}, &logEventsPredicate))
+	if err != nil {
+		return err
+	}
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &nbv1.NamespaceStore{}, systemHandler,
+		predicate.GenerationChangedPredicate{}, &logEventsPredicate))
+	if err != nil {
+		return err
+	}
+
 	// watch on notificationSource in order to keep the controller work queue
 	notificationSource := &NotificationSource{}
 	err = c.Watch(notificationSource)
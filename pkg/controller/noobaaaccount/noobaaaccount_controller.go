@@ -2,8 +2,10 @@ package noobaaaccount
 
 import (
 	"context"
+	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/noobaaaccount"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
 
@@ -26,12 +28,16 @@ func Add(mgr manager.Manager) error {
 		MaxConcurrentReconciles: 1,
 		Reconciler: reconcile.Func(
 			func(context context.Context, req reconcile.Request) (reconcile.Result, error) {
-				return noobaaaccount.NewReconciler(
+				defer metrics.ObserveReconcileDuration(metrics.ControllerNooBaaAccount, time.Now())
+				r := noobaaaccount.NewReconciler(
 					req.NamespacedName,
 					mgr.GetClient(),
 					mgr.GetScheme(),
 					mgr.GetEventRecorder("noobaa-operator"),
-				).Reconcile()
+				)
+				// calls to noobaa core are cancelled when the operator stops
+				r.Ctx = context
+				return r.Reconcile()
 			}),
 		SkipNameValidation: &[]bool{true}[0],
 	})
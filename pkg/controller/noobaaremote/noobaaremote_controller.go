@@ -0,0 +1,90 @@
+package noobaaremote
+
+import (
+	"context"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
+	"github.com/noobaa/noobaa-operator/v5/pkg/noobaaremote"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Add creates a Controller and adds it to the Manager.
+// The Manager will set fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+
+	// Create a controller that runs reconcile on noobaa remote
+
+	c, err := controller.New("noobaa-controller", mgr, controller.Options{
+		MaxConcurrentReconciles: 1,
+		Reconciler: reconcile.Func(
+			func(context context.Context, req reconcile.Request) (reconcile.Result, error) {
+				defer metrics.ObserveReconcileDuration(metrics.ControllerNooBaaRemote, time.Now())
+				return noobaaremote.NewReconciler(
+					req.NamespacedName,
+					mgr.GetClient(),
+					mgr.GetScheme(),
+					mgr.GetEventRecorder("noobaa-operator"),
+				).Reconcile()
+			}),
+		SkipNameValidation: &[]bool{true}[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	// Predicate that allow us to log event that are being queued
+	logEventsPredicate := util.LogEventsPredicate{}
+
+	// Predicate that allows events that only change spec, labels or finalizers and will log any allowed events
+	// This will stop infinite reconciles that triggered by status or irrelevant metadata changes
+	noobaaRemotePredicate := util.ComposePredicates(
+		predicate.GenerationChangedPredicate{},
+		util.LabelsChangedPredicate{},
+		util.FinalizersChangedPredicate{},
+	)
+
+	// Watch for changes on resources to trigger reconcile
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &nbv1.NooBaaRemote{}, &handler.EnqueueRequestForObject{},
+		noobaaRemotePredicate, &logEventsPredicate))
+	if err != nil {
+		return err
+	}
+
+	// Watch the namespace stores of the remote buckets to report their phase in the remote status
+	ownerHandler := handler.EnqueueRequestForOwner(
+		mgr.GetScheme(),
+		mgr.GetRESTMapper(),
+		&nbv1.NooBaaRemote{},
+		handler.OnlyControllerOwner(),
+	)
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &nbv1.NamespaceStore{}, ownerHandler, &logEventsPredicate))
+	if err != nil {
+		return err
+	}
+
+	// Watch the credentials secrets which are not owned by the remote, to recheck the remote when they are rotated
+	secretsHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		return noobaaremote.MapSecretToNooBaaRemotes(types.NamespacedName{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		})
+	})
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &corev1.Secret{}, secretsHandler, logEventsPredicate))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -45,6 +45,14 @@ func Add(mgr manager.Manager) error {
 		},
 	})
 
+	// Rotate the OBC credentials that set a rotation schedule
+	err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		return obc.RunCredentialRotation(ctx, mgr.GetEventRecorder("noobaa-operator"))
+	}))
+	if err != nil {
+		return err
+	}
+
 	return obc.RunProvisioner(
 		mgr.GetClient(),
 		mgr.GetScheme(),
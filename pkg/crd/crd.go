@@ -95,14 +95,17 @@ func CmdYaml() *cobra.Command {
 
 // Crds is the
 type Crds struct {
-	All               []*CRD
-	NooBaa            *CRD
-	BackingStore      *CRD
-	NamespaceStore    *CRD
-	BucketClass       *CRD
-	NooBaaAccount     *CRD
-	ObjectBucket      *CRD
-	ObjectBucketClaim *CRD
+	All                []*CRD
+	NooBaa             *CRD
+	BackingStore       *CRD
+	NamespaceStore     *CRD
+	BucketClass        *CRD
+	NooBaaAccount      *CRD
+	BucketReplication  *CRD
+	NooBaaRemote       *CRD
+	PerformanceProfile *CRD
+	ObjectBucket       *CRD
+	ObjectBucketClaim  *CRD
 }
 
 // RunCreate runs a CLI command
@@ -146,14 +149,20 @@ func LoadCrds() *Crds {
 	o5 := util.KubeObject(bundle.File_deploy_crds_noobaa_io_noobaaaccounts_yaml)
 	o6 := util.KubeObject(bundle.File_deploy_obc_objectbucket_io_objectbucketclaims_crd_yaml)
 	o7 := util.KubeObject(bundle.File_deploy_obc_objectbucket_io_objectbuckets_crd_yaml)
+	o8 := util.KubeObject(bundle.File_deploy_crds_noobaa_io_bucketreplications_yaml)
+	o9 := util.KubeObject(bundle.File_deploy_crds_noobaa_io_noobaaremotes_yaml)
+	o10 := util.KubeObject(bundle.File_deploy_crds_noobaa_io_noobaaperformanceprofiles_yaml)
 	crds := &Crds{
-		NooBaa:            o1.(*CRD),
-		BackingStore:      o2.(*CRD),
-		NamespaceStore:    o3.(*CRD),
-		BucketClass:       o4.(*CRD),
-		NooBaaAccount:     o5.(*CRD),
-		ObjectBucketClaim: o6.(*CRD),
-		ObjectBucket:      o7.(*CRD),
+		NooBaa:             o1.(*CRD),
+		BackingStore:       o2.(*CRD),
+		NamespaceStore:     o3.(*CRD),
+		BucketClass:        o4.(*CRD),
+		NooBaaAccount:      o5.(*CRD),
+		BucketReplication:  o8.(*CRD),
+		NooBaaRemote:       o9.(*CRD),
+		PerformanceProfile: o10.(*CRD),
+		ObjectBucketClaim:  o6.(*CRD),
+		ObjectBucket:       o7.(*CRD),
 	}
 	crds.All = []*CRD{
 		crds.NooBaa,
@@ -161,6 +170,9 @@ func LoadCrds() *Crds {
 		crds.NamespaceStore,
 		crds.BucketClass,
 		crds.NooBaaAccount,
+		crds.BucketReplication,
+		crds.NooBaaRemote,
+		crds.PerformanceProfile,
 		crds.ObjectBucketClaim,
 		crds.ObjectBucket,
 	}
@@ -1,9 +1,13 @@
 package diagnostics
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
@@ -26,15 +30,31 @@ func RunCollect(cmd *cobra.Command, args []string) {
 	destDir, _ := cmd.Flags().GetString("dir")
 	collectDBDump, _ := cmd.Flags().GetBool("db-dump")
 	dbDumpFrom, _ := cmd.Flags().GetString("dump-from")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	untilFlag, _ := cmd.Flags().GetString("until")
+	redact, _ := cmd.Flags().GetBool("redact")
+	now := time.Now()
 	c := Collector{
-		folderName: fmt.Sprintf("%s_%d", "noobaa_diagnostics", time.Now().Unix()),
+		folderName: fmt.Sprintf("%s_%d", "noobaa_diagnostics", now.Unix()),
 		log:        util.Logger(),
 		kubeconfig: kubeconfig,
+		redact:     redact,
+	}
+
+	var err error
+	if c.since, err = ParseTimeFlag(sinceFlag, now); err != nil {
+		c.log.Fatalf(`❌ Invalid --since %q: %s`, sinceFlag, err)
+	}
+	if c.until, err = ParseTimeFlag(untilFlag, now); err != nil {
+		c.log.Fatalf(`❌ Invalid --until %q: %s`, untilFlag, err)
+	}
+	if c.since != nil && c.until != nil && !c.since.Before(*c.until) {
+		c.log.Fatalf(`❌ --since %s must be before --until %s`, c.since.Format(time.RFC3339), c.until.Format(time.RFC3339))
 	}
 
 	c.log.Println("Running collection of diagnostics")
 
-	err := os.Mkdir(c.folderName, os.ModePerm)
+	err = os.Mkdir(c.folderName, os.ModePerm)
 	if err != nil {
 		c.log.Fatalf(`❌ Could not create directory %s, reason: %s`, c.folderName, err)
 	}
@@ -51,6 +71,16 @@ func RunCollect(cmd *cobra.Command, args []string) {
 	c.CollectPVCs(listOptions)
 	c.CollectSCC()
 
+	if c.redact {
+		c.log.Println("Redacting access keys, tokens and connection strings")
+	} else {
+		c.log.Println("⚠️  Redaction is disabled, review the diagnostics before sharing them")
+	}
+	manifest := &Manifest{CreatedAt: now, Namespace: options.Namespace, Since: c.since, Until: c.until}
+	if err := c.WriteManifest(manifest); err != nil {
+		c.log.Fatalf(`❌ Could not write diagnostics manifest, reason: %s`, err)
+	}
+
 	c.ExportDiagnostics(destDir)
 
 	// Collects db dump in addition to diagnostics.
@@ -71,11 +101,14 @@ func (c *Collector) CollectCR(list client.ObjectList) {
 
 	list.GetObjectKind().SetGroupVersionKind(gvk)
 
-	targetFile := fmt.Sprintf("%s/%s_crs.yaml", c.folderName, gvk.Kind)
+	fileName := fmt.Sprintf("%s_crs.yaml", gvk.Kind)
+	targetFile := fmt.Sprintf("%s/%s", c.folderName, fileName)
 	err := util.SaveCRsToFile(list, targetFile)
 	if err != nil {
 		c.log.Printf("got error on util.SaveCRsToFile for %v: %v", targetFile, err)
+		return
 	}
+	c.AddFile(fileName, ManifestSource{Kind: gvk.Kind, Namespace: options.Namespace})
 }
 
 // CollectCRs collects the content of multiple CR types
@@ -99,6 +132,18 @@ func (c *Collector) CollectCRs() {
 	c.CollectCR(&nbv1.NooBaaAccountList{
 		TypeMeta: metav1.TypeMeta{Kind: "NooBaaAccountList"},
 	})
+
+	c.CollectCR(&nbv1.BucketReplicationList{
+		TypeMeta: metav1.TypeMeta{Kind: "BucketReplicationList"},
+	})
+
+	c.CollectCR(&nbv1.NooBaaRemoteList{
+		TypeMeta: metav1.TypeMeta{Kind: "NooBaaRemoteList"},
+	})
+
+	c.CollectCR(&nbv1.NooBaaPerformanceProfileList{
+		TypeMeta: metav1.TypeMeta{Kind: "NooBaaPerformanceProfileList"},
+	})
 }
 
 // CollectDescribe collects output of the "describe pod" of a single pod
@@ -124,6 +169,20 @@ func (c *Collector) CollectDescribe(Kind string, Name string) {
 	if err := cmd.Run(); err != nil {
 		c.log.Printf(`❌ cannot describe %v %v in namespace %v: %v`, Kind, Name, options.Namespace, err)
 	}
+
+	source := ManifestSource{Kind: describeKinds[Kind], Name: Name}
+	if Kind == "pod" || Kind == "pvc" {
+		source.Namespace = options.Namespace
+	}
+	c.AddFile(Name+"-"+Kind+"-describe.txt", source)
+}
+
+// describeKinds maps the kinds passed to kubectl describe to the manifest source kinds
+var describeKinds = map[string]string{
+	"pod": "Pod",
+	"pv":  "PersistentVolume",
+	"pvc": "PersistentVolumeClaim",
+	"scc": "SecurityContextConstraints",
 }
 
 // CollectPodsLogs collects logs of all existing noobaa pods
@@ -141,15 +200,100 @@ func (c *Collector) CollectPodsLogs(listOptions client.ListOptions) {
 
 		c.CollectDescribe("pod", pod.Name)
 
-		podLogs, _ := util.GetPodLogs(*pod)
+		podLogs, _ := util.GetPodLogsWithOptions(*pod, c.podLogOptions())
 		for containerName, containerLog := range podLogs {
-			targetFile := fmt.Sprintf("%s/%s-%s.log", c.folderName, pod.Name, containerName)
+			fileName := fmt.Sprintf("%s-%s.log", pod.Name, containerName)
+			targetFile := fmt.Sprintf("%s/%s", c.folderName, fileName)
+			if c.until != nil {
+				containerLog = logsUntil(containerLog, *c.until)
+			}
 			err := util.SaveStreamToFile(containerLog, targetFile)
 			if err != nil {
 				c.log.Printf("got error on util.SaveStreamToFile for %v: %v", targetFile, err)
+				continue
 			}
+			c.AddFile(fileName, ManifestSource{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Container: containerName})
 		}
+
+		for _, container := range pod.Spec.Containers {
+			if container.Name == dbContainerName {
+				c.CollectDBVolumeUsage(pod)
+			}
+		}
+	}
+}
+
+// CollectDBVolumeUsage collects the usage of the data volume of a DB pod, as reported by df
+func (c *Collector) CollectDBVolumeUsage(pod *corev1.Pod) {
+	stdout, stderr, err := util.ExecCommandInPod(pod.Name, pod.Namespace, dbContainerName, []string{"df", "-k", dbDataMountPath})
+	if err != nil {
+		c.log.Printf(`❌ cannot check the DB volume usage of pod %s: %v %s`, pod.Name, err, stderr)
+		return
+	}
+	fileName := pod.Name + dbVolumeUsageSuffix
+	if err := os.WriteFile(filepath.Join(c.folderName, fileName), []byte(stdout), 0600); err != nil {
+		c.log.Printf(`❌ cannot write the DB volume usage of pod %s: %v`, pod.Name, err)
+		return
+	}
+	c.AddFile(fileName, ManifestSource{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Container: dbContainerName})
+}
+
+// podLogOptions returns the pod log options of the collection time window.
+// Timestamps are requested when the window has an end, to drop the lines written after it.
+func (c *Collector) podLogOptions() corev1.PodLogOptions {
+	logOpts := corev1.PodLogOptions{}
+	if c.since != nil {
+		sinceTime := metav1.NewTime(*c.since)
+		logOpts.SinceTime = &sinceTime
+	}
+	if c.until != nil {
+		logOpts.Timestamps = true
+	}
+	return logOpts
+}
+
+// logsUntil returns the lines of a timestamped log stream up to until,
+// lines without a timestamp are kept with the line before them
+func logsUntil(logs io.ReadCloser, until time.Time) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		defer util.SafeClose(logs, "Failed to close log stream")
+		scanner := bufio.NewScanner(logs)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if timestamp, _, found := strings.Cut(line, " "); found {
+				if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil && t.After(until) {
+					break
+				}
+			}
+			if _, err := io.WriteString(writer, line+"\n"); err != nil {
+				return
+			}
+		}
+		writer.CloseWithError(scanner.Err())
+	}()
+	return reader
+}
+
+// ParseTimeFlag parses a time window flag, either a duration relative to now like 2h
+// or an RFC3339 time, an empty value returns nil
+func ParseTimeFlag(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return nil, fmt.Errorf("duration must be positive")
+		}
+		t := now.Add(-d)
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected a duration like 2h or an RFC3339 time like 2006-01-02T15:04:05Z")
 	}
+	return &t, nil
 }
 
 // CollectPVs collects describe of PVs
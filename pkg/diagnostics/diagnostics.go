@@ -2,6 +2,8 @@
 package diagnostics
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -12,6 +14,14 @@ type Collector struct {
 	kubeconfig  string
 	kubeCommand string
 	log         *logrus.Entry
+
+	// since and until limit the collected logs to a time window, nil means unlimited
+	since  *time.Time
+	until  *time.Time
+	redact bool
+
+	// sources maps every collected file to the object it was taken from
+	sources map[string]ManifestSource
 }
 
 // Cmd returns a CLI command
@@ -25,6 +35,7 @@ func Cmd() *cobra.Command {
 		CmdDbDump(),
 		CmdAnalyze(),
 		CmdReport(),
+		CmdInspect(),
 	)
 	return cmd
 }
@@ -40,6 +51,9 @@ func CmdCollect() *cobra.Command {
 	cmd.Flags().String("dir", "", "collect noobaa diagnostics tar file into destination directory")
 	cmd.Flags().Bool("db-dump", false, "collect db dump in addition to diagnostics")
 	cmd.Flags().String("dump-from", "primary", "specifies the database type from which to collect the dump. Valid options are 'primary' and 'replica' (default: 'primary')")
+	cmd.Flags().String("since", "", "collect only logs newer than a relative duration like 2h or an RFC3339 time")
+	cmd.Flags().String("until", "", "collect only logs older than a relative duration like 30m or an RFC3339 time")
+	cmd.Flags().Bool("redact", true, "redact access keys, tokens and connection strings from the collected files")
 	return cmd
 }
 
@@ -81,6 +95,17 @@ func CmdReport() *cobra.Command {
 	return cmd
 }
 
+// CmdInspect returns a CLI command
+func CmdInspect() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <diagnostics-tarball>",
+		Short: "Inspect a collected diagnostics tarball offline and print prioritized findings",
+		Run:   RunInspect,
+	}
+	cmd.Flags().StringP("output", "o", "table", "table|json")
+	return cmd
+}
+
 // CmdAnalyzeBackingStore returns a CLI command
 func CmdAnalyzeBackingStore() *cobra.Command {
 	cmd := &cobra.Command{
@@ -145,5 +170,8 @@ func CmdDiagnoseDeprecated() *cobra.Command {
 	cmd.Flags().String("dir", "", "collect noobaa diagnose tar file into destination directory")
 	cmd.Flags().Bool("db-dump", false, "collect db dump in addition to diagnostics")
 	cmd.Flags().String("dump-from", "primary", "specifies the database type from which to collect the dump. Valid options are 'primary' and 'replica' (default: 'primary')")
+	cmd.Flags().String("since", "", "collect only logs newer than a relative duration like 2h or an RFC3339 time")
+	cmd.Flags().String("until", "", "collect only logs older than a relative duration like 30m or an RFC3339 time")
+	cmd.Flags().Bool("redact", true, "redact access keys, tokens and connection strings from the collected files")
 	return cmd
 }
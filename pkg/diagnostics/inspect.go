@@ -0,0 +1,542 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util/kms"
+
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/spf13/cobra"
+)
+
+// Severity of an inspect finding, findings are printed from the most severe
+type Severity int
+
+const (
+	// SeverityCritical findings explain an outage or data risk and should be handled first
+	SeverityCritical Severity = iota
+	// SeverityWarning findings are degraded states that may explain the issue
+	SeverityWarning
+	// SeverityInfo findings are worth a look but are not an error by themselves
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "Critical"
+	case SeverityWarning:
+		return "Warning"
+	default:
+		return "Info"
+	}
+}
+
+// MarshalJSON prints the severity name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Finding is a single problem found in a diagnostics bundle
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Object   string   `json:"object"`
+	Message  string   `json:"message"`
+	Evidence string   `json:"evidence,omitempty"`
+}
+
+// Bundle is a diagnostics tarball loaded to memory
+type Bundle struct {
+	Manifest    *Manifest
+	Files       map[string][]byte
+	CollectedAt time.Time
+}
+
+// InspectRule checks the bundle for a single kind of problem
+type InspectRule struct {
+	Name  string
+	Check func(b *Bundle) []Finding
+}
+
+// stuckPhaseThreshold is how long a CR may stay in a non ready phase before it is reported as stuck
+const stuckPhaseThreshold = 15 * time.Minute
+
+// restartCountThreshold is the restart count of a container that is reported even when it is running now
+const restartCountThreshold = 5
+
+// dbVolumeWarningPercent and dbVolumeCriticalPercent are the usage of the DB volume that is reported as near full
+const (
+	dbVolumeWarningPercent  = 85
+	dbVolumeCriticalPercent = 95
+)
+
+const (
+	// dbContainerName is the postgres container of the DB pods
+	dbContainerName = "postgres"
+	// dbDataMountPath is the mount path of the data volume in the DB pods
+	dbDataMountPath = "/var/lib/postgresql/data"
+	// dbVolumeUsageSuffix is the suffix of the files with the df output of the DB pods
+	dbVolumeUsageSuffix = "-db-volume-df.txt"
+)
+
+// InspectRules is the rule set applied by inspect
+var InspectRules = []InspectRule{
+	{Name: "cr-phase", Check: checkCRPhases},
+	{Name: "kms-status", Check: checkKMSStatus},
+	{Name: "db-cluster", Check: checkDBCluster},
+	{Name: "db-volume", Check: checkDBVolume},
+	{Name: "pod-status", Check: checkPodStatus},
+	{Name: "log-signatures", Check: checkLogSignatures},
+}
+
+// logSignature is a known error that appears in the core, endpoint or db logs
+type logSignature struct {
+	rule     string
+	severity Severity
+	re       *regexp.Regexp
+	message  string
+}
+
+var logSignatures = []logSignature{
+	{"db-volume-full", SeverityCritical, regexp.MustCompile(`(?i)no space left on device|could not extend file|disk quota exceeded`),
+		"the DB volume is full, expand the DB PVC (dbVolumeResources) before the DB stops accepting writes"},
+	{"db-unreachable", SeverityCritical, regexp.MustCompile(`(?i)ECONNREFUSED.*:5432|connection to .*5432.* failed|the database system is (starting up|shutting down|in recovery mode)`),
+		"core can not connect to the DB, check the DB pods and services"},
+	{"db-too-many-clients", SeverityWarning, regexp.MustCompile(`(?i)too many clients already|remaining connection slots are reserved`),
+		"the DB ran out of connections, check the endpoints count and DB max_connections"},
+	{"out-of-memory", SeverityCritical, regexp.MustCompile(`(?i)JavaScript heap out of memory|FATAL ERROR: .*Allocation failed`),
+		"a process ran out of memory, increase the pod memory limits"},
+	{"store-credentials", SeverityWarning, regexp.MustCompile(`InvalidAccessKeyId|SignatureDoesNotMatch|AuthorizationFailure|AuthenticationFailed`),
+		"a store rejected the credentials, check the store secrets"},
+	{"store-access-denied", SeverityWarning, regexp.MustCompile(`\bAccessDenied\b`),
+		"a store denied access, check the target bucket policy and the credentials permissions"},
+	{"store-bucket-missing", SeverityWarning, regexp.MustCompile(`\bNoSuchBucket\b|ContainerNotFound`),
+		"a store target bucket does not exist"},
+	{"dns-resolution", SeverityWarning, regexp.MustCompile(`\bENOTFOUND\b|EAI_AGAIN`),
+		"host names could not be resolved, check the cluster DNS and proxy settings"},
+	{"tls-certificate", SeverityWarning, regexp.MustCompile(`(?i)certificate has expired|self[- ]signed certificate|unable to verify the first certificate|UNABLE_TO_GET_ISSUER_CERT`),
+		"TLS verification failed, check the endpoint certificates and the trusted CA bundle"},
+	{"connection-timeout", SeverityInfo, regexp.MustCompile(`\bETIMEDOUT\b|\bECONNRESET\b`),
+		"connections timed out or were reset, check the network to the stores"},
+}
+
+// RunInspect runs a CLI command
+func RunInspect(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`❌ Missing expected arguments: <diagnostics-tarball> %s`, cmd.UsageString())
+	}
+	output, _ := cmd.Flags().GetString("output")
+	if output != "table" && output != "json" {
+		log.Fatalf(`❌ Invalid --output %q, expected table or json`, output)
+	}
+
+	b, err := LoadBundle(args[0])
+	if err != nil {
+		log.Fatalf(`❌ Could not load diagnostics bundle %s: %s`, args[0], err)
+	}
+	findings := Inspect(b)
+
+	if output == "json" {
+		if findings == nil {
+			findings = []Finding{}
+		}
+		bytes, err := json.MarshalIndent(findings, "", "  ")
+		util.Panic(err)
+		fmt.Println(string(bytes))
+		return
+	}
+
+	if len(findings) == 0 {
+		fmt.Printf("✅ No findings in %s (%d files collected at %s)\n", args[0], len(b.Files), b.CollectedAt.Format(time.RFC3339))
+		return
+	}
+	table := (&util.PrintTable{}).AddRow("SEVERITY", "RULE", "OBJECT", "MESSAGE", "EVIDENCE")
+	for _, f := range findings {
+		table.AddRow(f.Severity.String(), f.Rule, f.Object, f.Message, f.Evidence)
+	}
+	fmt.Print(table.String())
+}
+
+// LoadBundle reads a diagnostics tarball created by diagnostics collect
+func LoadBundle(tarball string) (*Bundle, error) {
+	f, err := os.Open(tarball)
+	if err != nil {
+		return nil, err
+	}
+	defer util.SafeClose(f, "Failed to close file "+tarball)
+
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer util.SafeClose(gzr, "Failed to close gzip reader")
+
+	b := &Bundle{Files: map[string][]byte{}}
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		b.Files[path.Clean(header.Name)] = data
+		if header.ModTime.After(b.CollectedAt) {
+			b.CollectedAt = header.ModTime
+		}
+	}
+
+	if data, ok := b.Files[ManifestFileName]; ok {
+		b.Manifest = &Manifest{}
+		if err := json.Unmarshal(data, b.Manifest); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ManifestFileName, err)
+		}
+		b.CollectedAt = b.Manifest.CreatedAt
+	}
+	return b, nil
+}
+
+// Inspect applies all the rules to the bundle and returns the findings, most severe first
+func Inspect(b *Bundle) []Finding {
+	var findings []Finding
+	for _, rule := range InspectRules {
+		findings = append(findings, rule.Check(b)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity < findings[j].Severity
+		}
+		if findings[i].Rule != findings[j].Rule {
+			return findings[i].Rule < findings[j].Rule
+		}
+		return findings[i].Object < findings[j].Object
+	})
+	return findings
+}
+
+// sortedFiles returns the bundle file names with the given suffix in a stable order
+func (b *Bundle) sortedFiles(suffix string) []string {
+	names := []string{}
+	for name := range b.Files {
+		if strings.HasSuffix(name, suffix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bundleObject is the part of a collected CR the rules look at,
+// all the NooBaa CRs share the phase and conditions status fields
+type bundleObject struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Status   struct {
+		Phase      string                   `json:"phase"`
+		Conditions []conditionsv1.Condition `json:"conditions"`
+		DBStatus   *nbv1.NooBaaDBStatus     `json:"dbStatus"`
+	} `json:"status"`
+}
+
+// objects returns the collected CRs of a kind, read from <Kind>List_crs.yaml
+func (b *Bundle) objects(kind string) []bundleObject {
+	data, ok := b.Files[kind+"List_crs.yaml"]
+	if !ok {
+		return nil
+	}
+	list := struct {
+		Items []bundleObject `json:"items"`
+	}{}
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		util.Logger().Warnf("Could not parse collected %s CRs: %v", kind, err)
+		return nil
+	}
+	return list.Items
+}
+
+// inspectKinds are the CR kinds collected by diagnostics collect
+var inspectKinds = []string{"NooBaa", "BackingStore", "NamespaceStore", "BucketClass", "NooBaaAccount", "BucketReplication",
+	"NooBaaRemote", "NooBaaPerformanceProfile"}
+
+func checkCRPhases(b *Bundle) []Finding {
+	var findings []Finding
+	for _, kind := range inspectKinds {
+		for _, obj := range b.objects(kind) {
+			name := kind + "/" + obj.Metadata.Name
+			phase := obj.Status.Phase
+			if phase == "Ready" || phase == "" {
+				continue
+			}
+			reason, message, since := lastCondition(&obj)
+			evidence := fmt.Sprintf("%sList_crs.yaml", kind)
+			if phase == "Rejected" {
+				findings = append(findings, Finding{
+					Severity: SeverityCritical,
+					Rule:     "cr-rejected",
+					Object:   name,
+					Message:  fmt.Sprintf("phase Rejected: %s %s", reason, message),
+					Evidence: evidence,
+				})
+				continue
+			}
+			if since.IsZero() || b.CollectedAt.Sub(since) < stuckPhaseThreshold {
+				continue
+			}
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Rule:     "cr-stuck-phase",
+				Object:   name,
+				Message:  fmt.Sprintf("phase %s for %s: %s %s", phase, b.CollectedAt.Sub(since).Round(time.Minute), reason, message),
+				Evidence: evidence,
+			})
+		}
+	}
+	return findings
+}
+
+// lastCondition returns the reason and message of the most recently changed condition
+// and the time of that change, which is when the CR moved to its current phase
+func lastCondition(obj *bundleObject) (string, string, time.Time) {
+	var last *conditionsv1.Condition
+	for i := range obj.Status.Conditions {
+		c := &obj.Status.Conditions[i]
+		if c.Type == nbv1.ConditionTypeKMSStatus || c.Type == nbv1.ConditionTypeKMSType {
+			continue
+		}
+		if last == nil || c.LastTransitionTime.After(last.LastTransitionTime.Time) {
+			last = c
+		}
+	}
+	if last == nil {
+		return "", "", obj.Metadata.CreationTimestamp.Time
+	}
+	return last.Reason, last.Message, last.LastTransitionTime.Time
+}
+
+func checkKMSStatus(b *Bundle) []Finding {
+	var findings []Finding
+	for _, obj := range b.objects("NooBaa") {
+		cond := conditionsv1.FindStatusCondition(obj.Status.Conditions, nbv1.ConditionTypeKMSStatus)
+		if cond == nil || kms.StatusValid(cond.Status) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityCritical,
+			Rule:     "kms-status",
+			Object:   "NooBaa/" + obj.Metadata.Name,
+			Message:  fmt.Sprintf("KMS condition status %s, the root master key is not available", cond.Status),
+			Evidence: "NooBaaList_crs.yaml",
+		})
+	}
+	return findings
+}
+
+func checkDBCluster(b *Bundle) []Finding {
+	var findings []Finding
+	for _, obj := range b.objects("NooBaa") {
+		db := obj.Status.DBStatus
+		if db == nil || db.DBClusterStatus != nbv1.DBClusterStatusFailed {
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityCritical,
+			Rule:     "db-cluster-failed",
+			Object:   "NooBaa/" + obj.Metadata.Name,
+			Message:  fmt.Sprintf("DB cluster status %s (volume size %s)", db.DBClusterStatus, db.ActualVolumeSize),
+			Evidence: "NooBaaList_crs.yaml",
+		})
+	}
+	return findings
+}
+
+// checkDBVolume reports DB volumes that are near full, from the df output collected from the DB pods,
+// or from the usage of the last auto grow check on the NooBaa CR when the pods were not collected
+func checkDBVolume(b *Bundle) []Finding {
+	var findings []Finding
+	collected := false
+	for _, name := range b.sortedFiles(dbVolumeUsageSuffix) {
+		usedPercent, ok := parseDFUsedPercent(string(b.Files[name]))
+		if !ok {
+			continue
+		}
+		collected = true
+		if f := dbVolumeFinding("Pod/"+strings.TrimSuffix(name, dbVolumeUsageSuffix), usedPercent, name); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	if collected {
+		return findings
+	}
+	for _, obj := range b.objects("NooBaa") {
+		db := obj.Status.DBStatus
+		if db == nil || db.VolumeUsedPercent == 0 {
+			continue
+		}
+		if f := dbVolumeFinding("NooBaa/"+obj.Metadata.Name, db.VolumeUsedPercent, "NooBaaList_crs.yaml"); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	return findings
+}
+
+// dbVolumeFinding returns a finding when the usage of the DB volume is above the warning threshold
+func dbVolumeFinding(object string, usedPercent int, evidence string) *Finding {
+	severity := SeverityWarning
+	switch {
+	case usedPercent >= dbVolumeCriticalPercent:
+		severity = SeverityCritical
+	case usedPercent < dbVolumeWarningPercent:
+		return nil
+	}
+	return &Finding{
+		Severity: severity,
+		Rule:     "db-volume-near-full",
+		Object:   object,
+		Message:  fmt.Sprintf("the DB volume is %d%% used, expand the DB PVC or enable dbSpec.dbAutoGrow", usedPercent),
+		Evidence: evidence,
+	}
+}
+
+// parseDFUsedPercent returns the Use% column of the last line of df output
+func parseDFUsedPercent(output string) (int, bool) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) < 5 {
+		return 0, false
+	}
+	usedPercent, err := strconv.Atoi(strings.TrimSuffix(fields[4], "%"))
+	if err != nil {
+		return 0, false
+	}
+	return usedPercent, true
+}
+
+var (
+	waitingReasonRegexp    = regexp.MustCompile(`Reason:\s+(CrashLoopBackOff|ImagePullBackOff|ErrImagePull|CreateContainerConfigError)`)
+	terminatedReasonRegexp = regexp.MustCompile(`Reason:\s+(OOMKilled|Error)`)
+	restartCountRegexp     = regexp.MustCompile(`Restart Count:\s+(\d+)`)
+	podPendingRegexp       = regexp.MustCompile(`(?m)^Status:\s+Pending`)
+)
+
+func checkPodStatus(b *Bundle) []Finding {
+	const suffix = "-pod-describe.txt"
+	var findings []Finding
+	for _, name := range b.sortedFiles(suffix) {
+		data := b.Files[name]
+		pod := "Pod/" + strings.TrimSuffix(name, suffix)
+
+		if m := waitingReasonRegexp.FindSubmatch(data); m != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityCritical,
+				Rule:     "pod-" + strings.ToLower(string(m[1])),
+				Object:   pod,
+				Message:  fmt.Sprintf("container is waiting in %s, see the container logs and events", m[1]),
+				Evidence: name,
+			})
+			continue
+		}
+		if podPendingRegexp.Match(data) {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Rule:     "pod-pending",
+				Object:   pod,
+				Message:  "pod is pending, see the events for scheduling or volume issues",
+				Evidence: name,
+			})
+			continue
+		}
+
+		restarts := 0
+		for _, m := range restartCountRegexp.FindAllSubmatch(data, -1) {
+			if n, err := strconv.Atoi(string(m[1])); err == nil && n > restarts {
+				restarts = n
+			}
+		}
+		if restarts < restartCountThreshold {
+			continue
+		}
+		message := fmt.Sprintf("container restarted %d times", restarts)
+		if m := terminatedReasonRegexp.FindSubmatch(data); m != nil {
+			message += fmt.Sprintf(", last termination reason %s", m[1])
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Rule:     "pod-restarts",
+			Object:   pod,
+			Message:  message,
+			Evidence: name,
+		})
+	}
+	return findings
+}
+
+func checkLogSignatures(b *Bundle) []Finding {
+	var findings []Finding
+	for _, name := range b.sortedFiles(".log") {
+		object := "Pod/" + strings.TrimSuffix(name, ".log")
+		if b.Manifest != nil {
+			for _, f := range b.Manifest.Files {
+				if f.Path == name && f.Source.Name != "" {
+					object = fmt.Sprintf("Pod/%s (%s)", f.Source.Name, f.Source.Container)
+				}
+			}
+		}
+
+		counts := make([]int, len(logSignatures))
+		firstLines := make([]int, len(logSignatures))
+		scanner := bufio.NewScanner(bytes.NewReader(b.Files[name]))
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for lineNum := 1; scanner.Scan(); lineNum++ {
+			line := scanner.Bytes()
+			for i := range logSignatures {
+				if logSignatures[i].re.Match(line) {
+					if counts[i] == 0 {
+						firstLines[i] = lineNum
+					}
+					counts[i]++
+				}
+			}
+		}
+
+		for i, sig := range logSignatures {
+			if counts[i] == 0 {
+				continue
+			}
+			findings = append(findings, Finding{
+				Severity: sig.severity,
+				Rule:     sig.rule,
+				Object:   object,
+				Message:  fmt.Sprintf("%s (%d occurrences)", sig.message, counts[i]),
+				Evidence: fmt.Sprintf("%s:%d", name, firstLines[i]),
+			})
+		}
+	}
+	return findings
+}
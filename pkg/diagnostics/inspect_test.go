@@ -0,0 +1,205 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+)
+
+const testNooBaaCRs = `apiVersion: v1
+kind: NooBaaList
+items:
+- metadata:
+    name: noobaa
+  status:
+    phase: Configuring
+    conditions:
+    - type: Progressing
+      status: "True"
+      reason: TemporaryError
+      message: failed to connect
+      lastTransitionTime: "2024-05-01T09:00:00Z"
+    - type: KMS-Status
+      status: ErrorRead
+      lastTransitionTime: "2024-05-01T09:30:00Z"
+`
+
+const testBackingStoreCRs = `apiVersion: v1
+kind: BackingStoreList
+items:
+- metadata:
+    name: bs-rejected
+  status:
+    phase: Rejected
+    conditions:
+    - type: Degraded
+      status: "True"
+      reason: InvalidCredentials
+      message: bad keys
+      lastTransitionTime: "2024-05-01T09:55:00Z"
+- metadata:
+    name: bs-creating
+  status:
+    phase: Creating
+    conditions:
+    - type: Progressing
+      status: "True"
+      lastTransitionTime: "2024-05-01T09:55:00Z"
+- metadata:
+    name: bs-ready
+  status:
+    phase: Ready
+`
+
+const testCorePodDescribe = `Name:         noobaa-core-0
+Status:       Running
+Containers:
+  core:
+    State:          Waiting
+      Reason:       CrashLoopBackOff
+    Restart Count:  12
+`
+
+const testEndpointPodDescribe = `Name:         noobaa-endpoint-1
+Status:       Running
+Containers:
+  endpoint:
+    State:          Running
+    Last State:     Terminated
+      Reason:       OOMKilled
+    Restart Count:  7
+`
+
+const testDBLog = `2024-05-01 09:58:00 LOG: checkpoint starting
+2024-05-01 09:59:00 PANIC: could not write to file "pg_wal/xlogtemp.77": No space left on device
+2024-05-01 09:59:01 PANIC: could not write to file "pg_wal/xlogtemp.78": No space left on device
+`
+
+const testDBVolumeDF = `Filesystem     1K-blocks     Used Available Use% Mounted on
+/dev/rbd0       10255636  9846412    392840  97% /var/lib/postgresql/data
+`
+
+func writeTestBundle(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "noobaa_diagnostics_1")
+	if err := os.Mkdir(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"NooBaaList_crs.yaml":                     testNooBaaCRs,
+		"BackingStoreList_crs.yaml":               testBackingStoreCRs,
+		"noobaa-core-0-pod-describe.txt":          testCorePodDescribe,
+		"noobaa-endpoint-1-pod-describe.txt":      testEndpointPodDescribe,
+		"noobaa-db-pg-0-db.log":                   testDBLog,
+		"noobaa-db-pg-0-db-volume-df.txt":         testDBVolumeDF,
+		"noobaa-endpoint-1-endpoint.log":          "all good\n",
+		"noobaa-endpoint-1-endpoint-previous.log": "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest, _ := json.Marshal(&Manifest{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Namespace: "noobaa"})
+	if err := os.WriteFile(filepath.Join(dir, ManifestFileName), manifest, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tarball := dir + ".tar.gz"
+	f, err := os.Create(tarball)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer util.SafeClose(f, "Failed to close tarball")
+	if err := util.Tar(dir, f); err != nil {
+		t.Fatal(err)
+	}
+	return tarball
+}
+
+func TestInspect(t *testing.T) {
+	b, err := LoadBundle(writeTestBundle(t))
+	if err != nil {
+		t.Fatalf("LoadBundle() error = %v", err)
+	}
+	findings := Inspect(b)
+
+	type key struct{ rule, object string }
+	found := map[key]Finding{}
+	for _, f := range findings {
+		found[key{f.Rule, f.Object}] = f
+	}
+	expected := []struct {
+		key
+		severity Severity
+	}{
+		{key{"cr-rejected", "BackingStore/bs-rejected"}, SeverityCritical},
+		{key{"cr-stuck-phase", "NooBaa/noobaa"}, SeverityWarning},
+		{key{"kms-status", "NooBaa/noobaa"}, SeverityCritical},
+		{key{"pod-crashloopbackoff", "Pod/noobaa-core-0"}, SeverityCritical},
+		{key{"pod-restarts", "Pod/noobaa-endpoint-1"}, SeverityWarning},
+		{key{"db-volume-full", "Pod/noobaa-db-pg-0-db"}, SeverityCritical},
+		{key{"db-volume-near-full", "Pod/noobaa-db-pg-0"}, SeverityCritical},
+	}
+	for _, e := range expected {
+		f, ok := found[e.key]
+		if !ok {
+			t.Fatalf("missing finding %+v in %+v", e.key, findings)
+		}
+		if f.Severity != e.severity {
+			t.Fatalf("finding %+v severity %s, expected %s", e.key, f.Severity, e.severity)
+		}
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("expected %d findings, got %+v", len(expected), findings)
+	}
+	if found[key{"db-volume-full", "Pod/noobaa-db-pg-0-db"}].Evidence != "noobaa-db-pg-0-db.log:2" {
+		t.Fatalf("unexpected evidence %+v", found[key{"db-volume-full", "Pod/noobaa-db-pg-0-db"}])
+	}
+	for i := 1; i < len(findings); i++ {
+		if findings[i-1].Severity > findings[i].Severity {
+			t.Fatalf("findings are not sorted by severity: %+v", findings)
+		}
+	}
+}
+
+func TestCheckDBVolume(t *testing.T) {
+	noobaa := func(usedPercent int) []byte {
+		return []byte(fmt.Sprintf("items:\n- metadata:\n    name: noobaa\n  status:\n    dbStatus:\n      volumeUsedPercent: %d\n", usedPercent))
+	}
+	df := func(usedPercent int) []byte {
+		return []byte(fmt.Sprintf("Filesystem 1K-blocks Used Available Use%% Mounted on\n/dev/sda 100 %d 0 %d%% /var/lib/postgresql/data\n", usedPercent, usedPercent))
+	}
+	tests := []struct {
+		name     string
+		files    map[string][]byte
+		object   string
+		severity Severity
+	}{
+		{"below threshold", map[string][]byte{"db-1-db-volume-df.txt": df(60)}, "", 0},
+		{"near full", map[string][]byte{"db-1-db-volume-df.txt": df(88)}, "Pod/db-1", SeverityWarning},
+		{"full", map[string][]byte{"db-1-db-volume-df.txt": df(99)}, "Pod/db-1", SeverityCritical},
+		{"unparsable df", map[string][]byte{"db-1-db-volume-df.txt": []byte("df: no such file")}, "", 0},
+		{"auto grow status", map[string][]byte{"NooBaaList_crs.yaml": noobaa(90)}, "NooBaa/noobaa", SeverityWarning},
+		{"df preferred over status", map[string][]byte{"NooBaaList_crs.yaml": noobaa(90), "db-1-db-volume-df.txt": df(96)}, "Pod/db-1", SeverityCritical},
+		{"df below threshold preferred over status", map[string][]byte{"NooBaaList_crs.yaml": noobaa(90), "db-1-db-volume-df.txt": df(50)}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := checkDBVolume(&Bundle{Files: tt.files})
+			if tt.object == "" {
+				if len(findings) != 0 {
+					t.Fatalf("expected no findings, got %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Object != tt.object || findings[0].Severity != tt.severity {
+				t.Fatalf("expected a %s finding for %s, got %+v", tt.severity, tt.object, findings)
+			}
+		})
+	}
+}
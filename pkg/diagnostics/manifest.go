@@ -0,0 +1,112 @@
+package diagnostics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+)
+
+// ManifestFileName is the name of the manifest file at the root of the diagnostics tarball
+const ManifestFileName = "manifest.json"
+
+// ManifestSource is the cluster object a collected file was taken from
+type ManifestSource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Container string `json:"container,omitempty"`
+}
+
+// ManifestFile describes a single file of the diagnostics tarball
+type ManifestFile struct {
+	Path       string         `json:"path"`
+	Source     ManifestSource `json:"source"`
+	Size       int64          `json:"size"`
+	SHA256     string         `json:"sha256"`
+	Redactions int            `json:"redactions,omitempty"`
+}
+
+// Manifest is the machine readable index of a diagnostics tarball
+type Manifest struct {
+	CreatedAt time.Time      `json:"createdAt"`
+	Namespace string         `json:"namespace"`
+	Since     *time.Time     `json:"since,omitempty"`
+	Until     *time.Time     `json:"until,omitempty"`
+	Redacted  bool           `json:"redacted"`
+	Files     []ManifestFile `json:"files"`
+}
+
+// AddFile records the source object of a collected file, path is relative to the collection folder
+func (c *Collector) AddFile(path string, source ManifestSource) {
+	if c.sources == nil {
+		c.sources = map[string]ManifestSource{}
+	}
+	c.sources[path] = source
+}
+
+// WriteManifest redacts the collected files when requested, then writes the manifest
+// listing every file with its source object and checksum of the final content
+func (c *Collector) WriteManifest(manifest *Manifest) error {
+	manifest.Redacted = c.redact
+	manifest.Files = []ManifestFile{}
+
+	err := filepath.Walk(c.folderName, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(c.folderName, path)
+		if err != nil {
+			return err
+		}
+		file := ManifestFile{Path: rel, Source: c.sources[rel]}
+		if file.Source.Kind == "" {
+			file.Source.Kind = "Unknown"
+		}
+		if c.redact {
+			file.Redactions, err = RedactFile(path)
+			if err != nil {
+				return err
+			}
+		}
+		file.Size, file.SHA256, err = fileChecksum(path)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, file)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+
+	bytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.folderName, ManifestFileName), bytes, 0600)
+}
+
+// fileChecksum returns the size and hex encoded sha256 of a file
+func fileChecksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer util.SafeClose(f, "Failed to close file "+path)
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -2,7 +2,6 @@ package install
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/noobaa/noobaa-operator/v5/pkg/backingstore"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bucketclass"
@@ -73,10 +72,21 @@ func CmdUpgrade() *cobra.Command {
 		Use:   "upgrade --noobaa-image <noobaa-image-path-and-tag> --operator-image <operator-image-path-and-tag>",
 		Short: "Upgrade the system, its components and CRDS",
 		Long: "The command should be used in conjunction with the global flags --noobaa-image and " +
-			"--operator-image to upgrade the system and its components to the desired versions.",
+			"--operator-image to upgrade the system and its components to the desired versions.\n\n" +
+			"The upgrade runs pre-flight checks and takes a DB snapshot before changing anything. " +
+			"Its progress is saved in the noobaa-upgrade-state config map, so running the command again " +
+			"resumes an interrupted upgrade. When the system is not ready within --ready-timeout, " +
+			"the operator image is rolled back, and the core and DB images are rolled back by restoring the DB snapshot.",
 		Run:  RunUpgrade,
 		Args: cobra.NoArgs,
 	}
+	cmd.Flags().Bool("skip-preflight", false, "Continue the upgrade when pre-flight checks fail")
+	cmd.Flags().Duration("backup-max-age", system.DefaultUpgradeBackupMaxAge, "Maximum age of the last DB backup for the pre-flight check")
+	cmd.Flags().Int("max-db-volume-used", system.DefaultUpgradeMaxDBVolumeUsedPercent, "Maximum used percent of the DB volume for the pre-flight check")
+	cmd.Flags().Bool("allow-postgres-major-upgrade", false, "Allow upgrading the postgres major version to the default version of the operator without the checks of noobaa system db-upgrade")
+	cmd.Flags().Duration("ready-timeout", DefaultUpgradeReadyTimeout, "Time for the system to be ready after the upgrade before rolling back")
+	cmd.Flags().Bool("no-rollback", false, "Do not roll back the images and the DB when the system is not ready in time")
+	cmd.Flags().Bool("restart", false, "Start over instead of resuming an upgrade that did not finish")
 	return cmd
 }
 
@@ -154,31 +164,6 @@ func RunInstall(cmd *cobra.Command, args []string) {
 	}
 }
 
-// RunUpgrade runs a CLI command
-func RunUpgrade(cmd *cobra.Command, args []string) {
-	log := util.Logger()
-	log.Printf("System versions prior to upgrade:\n")
-	system.RunSystemVersionsStatus(cmd, args)
-	log.Printf("Namespace: %s\n", options.Namespace)
-	log.Printf("CNPG upgrade:")
-	cnpg.RunUpgrade(cmd, args)
-	log.Printf("CRD upgrade:")
-	crd.RunUpgrade(cmd, args)
-	log.Printf("\nOperator upgrade:")
-	operator.RunUpgrade(cmd, args)
-	log.Printf("\nSystem apply:")
-	system.RunUpgrade(cmd, args)
-	log.Printf("")
-	util.PrintThisNoteWhenFinishedApplyingAndStartWaitLoop()
-	log.Printf("\nWaiting for the system to be ready...")
-	// Sleep to let the system get out of its old Ready state
-	time.Sleep(3 * time.Second)
-	if system.WaitReady() {
-		log.Printf("\n\n")
-		RunStatus(cmd, args)
-	}
-}
-
 // RunUninstall runs a CLI command
 func RunUninstall(cmd *cobra.Command, args []string) {
 	log := util.Logger()
@@ -0,0 +1,441 @@
+package install
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/cnpg"
+	"github.com/noobaa/noobaa-operator/v5/pkg/crd"
+	"github.com/noobaa/noobaa-operator/v5/pkg/operator"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/system"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// upgradeStateConfigMap is the config map that keeps the progress of the upgrade
+	upgradeStateConfigMap = "noobaa-upgrade-state"
+	upgradeStateKey       = "state"
+
+	// upgradeSnapshotTimeout is the time to wait for the DB snapshot before the upgrade starts
+	upgradeSnapshotTimeout = 30 * time.Minute
+
+	// DefaultUpgradeReadyTimeout is the default time for the system to become ready after the upgrade before it is rolled back
+	DefaultUpgradeReadyTimeout = 30 * time.Minute
+)
+
+// Upgrade steps in the order they run
+const (
+	UpgradeStepPreflight  = "preflight"
+	UpgradeStepDBSnapshot = "db-snapshot"
+	UpgradeStepCNPG       = "cnpg"
+	UpgradeStepCRD        = "crd"
+	UpgradeStepOperator   = "operator"
+	UpgradeStepSystem     = "system"
+	UpgradeStepWaitReady  = "wait-ready"
+)
+
+var upgradeSteps = []string{
+	UpgradeStepPreflight,
+	UpgradeStepDBSnapshot,
+	UpgradeStepCNPG,
+	UpgradeStepCRD,
+	UpgradeStepOperator,
+	UpgradeStepSystem,
+	UpgradeStepWaitReady,
+}
+
+// Upgrade phases
+const (
+	UpgradePhaseRunning    = "Running"
+	UpgradePhaseCompleted  = "Completed"
+	UpgradePhaseRolledBack = "RolledBack"
+)
+
+// UpgradeState is the progress of an upgrade.
+// It is persisted in a config map after every step, so an interrupted upgrade resumes from the first step that did not complete.
+type UpgradeState struct {
+	Phase                  string    `json:"phase"`
+	TargetOperatorImage    string    `json:"targetOperatorImage"`
+	TargetCoreImage        string    `json:"targetCoreImage"`
+	TargetDBImage          string    `json:"targetDBImage"`
+	TargetPgMajorVersion   int       `json:"targetPgMajorVersion"`
+	PreviousOperatorImage  string    `json:"previousOperatorImage,omitempty"`
+	PreviousCoreImage      string    `json:"previousCoreImage,omitempty"`
+	PreviousDBImage        string    `json:"previousDBImage,omitempty"`
+	PreviousPgMajorVersion int       `json:"previousPgMajorVersion,omitempty"`
+	DBSnapshot             string    `json:"dbSnapshot,omitempty"`
+	CompletedSteps         []string  `json:"completedSteps,omitempty"`
+	StartTime              time.Time `json:"startTime"`
+	UpdateTime             time.Time `json:"updateTime"`
+	Message                string    `json:"message,omitempty"`
+}
+
+// newUpgradeState returns the state of a new upgrade to the images of the options
+func newUpgradeState() *UpgradeState {
+	now := time.Now()
+	return &UpgradeState{
+		Phase:                UpgradePhaseRunning,
+		TargetOperatorImage:  options.OperatorImage,
+		TargetCoreImage:      options.NooBaaImage,
+		TargetDBImage:        options.DBImage,
+		TargetPgMajorVersion: options.PostgresMajorVersion,
+		StartTime:            now,
+		UpdateTime:           now,
+	}
+}
+
+// sameTarget returns true when both states upgrade to the same versions
+func (s *UpgradeState) sameTarget(other *UpgradeState) bool {
+	return s.TargetOperatorImage == other.TargetOperatorImage &&
+		s.TargetCoreImage == other.TargetCoreImage &&
+		s.TargetDBImage == other.TargetDBImage &&
+		s.TargetPgMajorVersion == other.TargetPgMajorVersion
+}
+
+// isCompleted returns true when the step completed in a previous run of the upgrade
+func (s *UpgradeState) isCompleted(step string) bool {
+	return slices.Contains(s.CompletedSteps, step)
+}
+
+// complete marks the step as completed
+func (s *UpgradeState) complete(step string) {
+	if !s.isCompleted(step) {
+		s.CompletedSteps = append(s.CompletedSteps, step)
+	}
+	s.UpdateTime = time.Now()
+}
+
+// planUpgrade returns the state to run, resuming the existing upgrade when it did not finish and has the same target.
+// A restart discards the completed steps of the existing upgrade but keeps the images and the DB snapshot to roll back to,
+// since they were taken before the existing upgrade changed anything.
+func planUpgrade(existing *UpgradeState, target *UpgradeState, restart bool) (*UpgradeState, error) {
+	if existing == nil || existing.Phase != UpgradePhaseRunning {
+		return target, nil
+	}
+	if restart {
+		target.PreviousOperatorImage = existing.PreviousOperatorImage
+		target.PreviousCoreImage = existing.PreviousCoreImage
+		target.PreviousDBImage = existing.PreviousDBImage
+		target.PreviousPgMajorVersion = existing.PreviousPgMajorVersion
+		target.DBSnapshot = existing.DBSnapshot
+		return target, nil
+	}
+	if !existing.sameTarget(target) {
+		return nil, fmt.Errorf("an upgrade to operator image %q and core image %q did not finish, "+
+			"run the upgrade with the same images to resume it or with --restart to start over",
+			existing.TargetOperatorImage, existing.TargetCoreImage)
+	}
+	return existing, nil
+}
+
+// RunUpgrade runs a CLI command.
+// The upgrade runs in steps - pre-flight checks, DB snapshot, CNPG, CRD, operator and system upgrades,
+// and waiting for the system to be ready. The progress is saved after every step so running the command
+// again resumes an interrupted upgrade. When the system does not become ready in time,
+// the operator image is rolled back, and the core and DB are rolled back by restoring the DB snapshot.
+func RunUpgrade(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+	restart, _ := cmd.Flags().GetBool("restart")
+
+	log.Printf("System versions prior to upgrade:\n")
+	system.RunSystemVersionsStatus(cmd, args)
+	log.Printf("Namespace: %s\n", options.Namespace)
+
+	sys := loadSystem()
+	if !util.KubeCheck(sys) {
+		log.Fatalf("❌ NooBaa system %q not found", options.SystemName)
+	}
+
+	state, err := planUpgrade(loadUpgradeState(), newUpgradeState(), restart)
+	if err != nil {
+		log.Fatalf("❌ %s", err)
+	}
+	if len(state.CompletedSteps) > 0 {
+		log.Printf("Resuming the upgrade started at %s, completed steps: %v", state.StartTime.Format(time.RFC3339), state.CompletedSteps)
+	}
+	if state.PreviousOperatorImage == "" {
+		state.PreviousOperatorImage = currentOperatorImage()
+	}
+	if state.PreviousCoreImage == "" {
+		state.PreviousCoreImage = currentCoreImage(sys)
+		if sys.Spec.DBSpec != nil && sys.Spec.DBSpec.DBImage != nil {
+			state.PreviousDBImage = *sys.Spec.DBSpec.DBImage
+		}
+		if sys.Spec.DBSpec != nil && sys.Spec.DBSpec.PostgresMajorVersion != nil {
+			state.PreviousPgMajorVersion = *sys.Spec.DBSpec.PostgresMajorVersion
+		}
+	}
+	saveUpgradeState(state)
+
+	for _, step := range upgradeSteps {
+		if state.isCompleted(step) {
+			log.Printf("\nUpgrade step %s: already completed", step)
+			continue
+		}
+		log.Printf("\nUpgrade step %s:", step)
+		runUpgradeStep(cmd, args, state, step)
+		state.complete(step)
+		saveUpgradeState(state)
+	}
+
+	state.Phase = UpgradePhaseCompleted
+	state.Message = "the system is ready"
+	saveUpgradeState(state)
+	log.Printf("\n\n")
+	RunStatus(cmd, args)
+}
+
+// runUpgradeStep runs a step of the upgrade, a step that fails exits the command
+func runUpgradeStep(cmd *cobra.Command, args []string, state *UpgradeState, step string) {
+	log := util.Logger()
+	switch step {
+	case UpgradeStepPreflight:
+		runUpgradePreflight(cmd, state)
+	case UpgradeStepDBSnapshot:
+		runUpgradeDBSnapshot(state)
+	case UpgradeStepCNPG:
+		cnpg.RunUpgrade(cmd, args)
+	case UpgradeStepCRD:
+		crd.RunUpgrade(cmd, args)
+	case UpgradeStepOperator:
+		operator.RunUpgrade(cmd, args)
+	case UpgradeStepSystem:
+		system.RunUpgrade(cmd, args)
+		log.Printf("")
+		util.PrintThisNoteWhenFinishedApplyingAndStartWaitLoop()
+	case UpgradeStepWaitReady:
+		runUpgradeWaitReady(cmd, state)
+	default:
+		log.Fatalf("❌ Unknown upgrade step %q", step)
+	}
+}
+
+// runUpgradePreflight runs the pre-flight checks and stops the upgrade when a check fails, unless --skip-preflight
+func runUpgradePreflight(cmd *cobra.Command, state *UpgradeState) {
+	log := util.Logger()
+	skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
+	backupMaxAge, _ := cmd.Flags().GetDuration("backup-max-age")
+	maxDBVolumeUsed, _ := cmd.Flags().GetInt("max-db-volume-used")
+	allowPgMajorUpgrade, _ := cmd.Flags().GetBool("allow-postgres-major-upgrade")
+
+	sys := loadSystem()
+	util.KubeCheck(sys)
+	checks := system.RunUpgradePreflightChecks(sys, system.UpgradePreflightOptions{
+		TargetPgMajorVersion:   state.TargetPgMajorVersion,
+		AllowPgMajorUpgrade:    allowPgMajorUpgrade,
+		BackupMaxAge:           backupMaxAge,
+		MaxDBVolumeUsedPercent: maxDBVolumeUsed,
+	})
+
+	failed := system.PrintUpgradePreflightChecks(checks)
+	if failed > 0 && !skipPreflight {
+		log.Fatalf("❌ %d pre-flight checks failed, fix them and run the upgrade again, or use --skip-preflight", failed)
+	}
+	if failed > 0 {
+		log.Warnf("⚠️  %d pre-flight checks failed, continuing with --skip-preflight", failed)
+	}
+}
+
+// runUpgradeDBSnapshot takes a DB backup with the backup method of the system before anything is upgraded
+func runUpgradeDBSnapshot(state *UpgradeState) {
+	log := util.Logger()
+	sys := loadSystem()
+	util.KubeCheck(sys)
+	if state.DBSnapshot != "" {
+		log.Printf("Keeping the DB snapshot %s of the restarted upgrade, it was taken before the system was upgraded", state.DBSnapshot)
+		return
+	}
+	if sys.Spec.DBSpec == nil || sys.Spec.ExternalPgSecret != nil || sys.Spec.DBSpec.DBBackup == nil {
+		log.Warnf("⚠️  DB backup is not configured for the system, upgrading without a DB snapshot")
+		return
+	}
+	name := fmt.Sprintf("%s-db-upgrade-%s", sys.Name, time.Now().Format("20060102150405"))
+	if err := system.CreateDBBackup(sys, name, upgradeSnapshotTimeout); err != nil {
+		log.Fatalf("❌ Failed to take a DB snapshot before the upgrade: %s", err)
+	}
+	state.DBSnapshot = name
+}
+
+// runUpgradeWaitReady waits for the system to become ready, and rolls back the images when it does not in time
+func runUpgradeWaitReady(cmd *cobra.Command, state *UpgradeState) {
+	log := util.Logger()
+	readyTimeout, _ := cmd.Flags().GetDuration("ready-timeout")
+	noRollback, _ := cmd.Flags().GetBool("no-rollback")
+
+	log.Printf("Waiting up to %s for the system to be ready...", readyTimeout)
+	// Sleep to let the system get out of its old Ready state
+	time.Sleep(3 * time.Second)
+	if waitReadyTimeout(readyTimeout) {
+		return
+	}
+
+	if noRollback {
+		state.Message = fmt.Sprintf("the system is not ready after %s", readyTimeout)
+		saveUpgradeState(state)
+		log.Fatalf("❌ The system is not ready after %s, not rolling back (--no-rollback). Run the upgrade again to keep waiting", readyTimeout)
+	}
+
+	log.Errorf("❌ The system is not ready after %s, rolling back to operator image %q", readyTimeout, state.PreviousOperatorImage)
+	if state.DBSnapshot != "" {
+		log.Warnf("⚠️  The new core may have migrated the DB, so core image %q is rolled back by restoring the DB snapshot %s "+
+			"taken before the upgrade. Changes made to the DB since the snapshot are lost", state.PreviousCoreImage, state.DBSnapshot)
+	} else {
+		log.Warnf("⚠️  The new core may have migrated the DB and no DB snapshot was taken before the upgrade, " +
+			"so the core image, DB image and postgres major version are not rolled back")
+	}
+	message, err := rollbackUpgrade(state, readyTimeout)
+	state.Phase = UpgradePhaseRolledBack
+	state.Message = fmt.Sprintf("the system was not ready after %s, %s", readyTimeout, message)
+	saveUpgradeState(state)
+	if err != nil {
+		log.Fatalf("❌ The rollback failed: %s", err)
+	}
+
+	if waitReadyTimeout(readyTimeout) {
+		log.Printf("✅ The system is ready after the rollback")
+	} else {
+		log.Errorf("❌ The system is not ready after the rollback")
+	}
+	log.Fatalf("❌ The upgrade was rolled back, %s", message)
+}
+
+// rollbackUpgrade reverts the operator deployment to the image before the upgrade, and returns a description of what was rolled back.
+// The new core runs its DB migrations when it starts, and the previous core may not run on a migrated DB,
+// so the core image, DB image and postgres major version are only reverted together with a restore of the DB snapshot
+// taken before the upgrade. Without a snapshot they are kept as is.
+// CRDs are not reverted since newer CRDs are compatible with older operators.
+func rollbackUpgrade(state *UpgradeState, timeout time.Duration) (string, error) {
+	log := util.Logger()
+
+	if state.PreviousOperatorImage != "" {
+		deployment := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "noobaa-operator", Namespace: options.Namespace},
+		}
+		if util.KubeCheck(deployment) && len(deployment.Spec.Template.Spec.Containers) > 0 {
+			deployment.Spec.Template.Spec.Containers[0].Image = state.PreviousOperatorImage
+			if !util.KubeUpdate(deployment) {
+				log.Errorf("❌ Failed to roll back the operator image to %q", state.PreviousOperatorImage)
+			}
+		}
+	}
+
+	if state.DBSnapshot == "" {
+		return "the operator image was rolled back, the core and DB were not rolled back without a DB snapshot", nil
+	}
+	sys := loadSystem()
+	if !util.KubeCheck(sys) {
+		return "the operator image was rolled back", fmt.Errorf("system %q not found", sys.Name)
+	}
+	setPreviousSystemImages(sys, state)
+	if err := system.RestoreDBUpgradeSnapshot(sys, state.DBSnapshot, timeout); err != nil {
+		return "the operator image was rolled back", err
+	}
+	return fmt.Sprintf("the images were rolled back and the DB was restored from snapshot %s", state.DBSnapshot), nil
+}
+
+// setPreviousSystemImages sets the core image, DB image and postgres major version of the system to the ones before the upgrade
+func setPreviousSystemImages(sys *nbv1.NooBaa, state *UpgradeState) {
+	if state.PreviousCoreImage != "" {
+		image := state.PreviousCoreImage
+		sys.Spec.Image = &image
+	}
+	if sys.Spec.DBSpec == nil {
+		return
+	}
+	if state.PreviousDBImage != "" {
+		dbImage := state.PreviousDBImage
+		sys.Spec.DBSpec.DBImage = &dbImage
+	}
+	if state.PreviousPgMajorVersion != 0 {
+		version := state.PreviousPgMajorVersion
+		sys.Spec.DBSpec.PostgresMajorVersion = &version
+	}
+}
+
+// waitReadyTimeout waits for the system to be ready, up to the timeout
+func waitReadyTimeout(timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return system.WaitReadyContext(ctx)
+}
+
+// currentOperatorImage returns the image of the running operator deployment
+func currentOperatorImage() string {
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "noobaa-operator", Namespace: options.Namespace},
+	}
+	if !util.KubeCheckQuiet(deployment) || len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+	return deployment.Spec.Template.Spec.Containers[0].Image
+}
+
+// currentCoreImage returns the core image the system is running
+func currentCoreImage(sys *nbv1.NooBaa) string {
+	if sys.Status.ActualImage != "" {
+		return sys.Status.ActualImage
+	}
+	if sys.Spec.Image != nil {
+		return *sys.Spec.Image
+	}
+	return ""
+}
+
+func loadSystem() *nbv1.NooBaa {
+	return &nbv1.NooBaa{
+		TypeMeta: metav1.TypeMeta{Kind: "NooBaa"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      options.SystemName,
+			Namespace: options.Namespace,
+		},
+	}
+}
+
+func upgradeStateConfigMapObj() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upgradeStateConfigMap,
+			Namespace: options.Namespace,
+			Labels:    map[string]string{"app": "noobaa"},
+		},
+	}
+}
+
+// loadUpgradeState returns the state of the last upgrade, or nil when there is none
+func loadUpgradeState() *UpgradeState {
+	log := util.Logger()
+	cm := upgradeStateConfigMapObj()
+	if !util.KubeCheckQuiet(cm) || cm.Data[upgradeStateKey] == "" {
+		return nil
+	}
+	state := &UpgradeState{}
+	if err := json.Unmarshal([]byte(cm.Data[upgradeStateKey]), state); err != nil {
+		log.Warnf("⚠️  Ignoring the state of the last upgrade in config map %s: %s", upgradeStateConfigMap, err)
+		return nil
+	}
+	return state
+}
+
+// saveUpgradeState persists the state of the upgrade
+func saveUpgradeState(state *UpgradeState) {
+	log := util.Logger()
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Fatalf("❌ Failed to encode the upgrade state: %s", err)
+	}
+	cm := upgradeStateConfigMapObj()
+	cm.Data = map[string]string{upgradeStateKey: string(data)}
+	util.KubeApply(cm)
+}
@@ -0,0 +1,74 @@
+package install
+
+import (
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+func TestPlanUpgrade(t *testing.T) {
+	target := func() *UpgradeState {
+		return &UpgradeState{Phase: UpgradePhaseRunning, TargetOperatorImage: "operator:2", TargetCoreImage: "core:2"}
+	}
+	interrupted := &UpgradeState{
+		Phase:                 UpgradePhaseRunning,
+		TargetOperatorImage:   "operator:2",
+		TargetCoreImage:       "core:2",
+		PreviousOperatorImage: "operator:1",
+		PreviousCoreImage:     "core:1",
+		PreviousDBImage:       "postgres:15",
+		DBSnapshot:            "noobaa-db-upgrade-1",
+		CompletedSteps:        []string{UpgradeStepPreflight, UpgradeStepDBSnapshot, UpgradeStepCNPG, UpgradeStepCRD, UpgradeStepOperator},
+	}
+
+	// a new upgrade after a completed one starts from the first step
+	completed := *interrupted
+	completed.Phase = UpgradePhaseCompleted
+	for _, existing := range []*UpgradeState{nil, &completed} {
+		state, err := planUpgrade(existing, target(), false)
+		if err != nil || len(state.CompletedSteps) != 0 || state.PreviousOperatorImage != "" {
+			t.Fatalf("expected a new upgrade, got %+v %v", state, err)
+		}
+	}
+
+	// an interrupted upgrade to the same images resumes
+	state, err := planUpgrade(interrupted, target(), false)
+	if err != nil || state != interrupted {
+		t.Fatalf("expected to resume the interrupted upgrade, got %+v %v", state, err)
+	}
+	if !state.isCompleted(UpgradeStepOperator) || state.isCompleted(UpgradeStepSystem) {
+		t.Fatalf("unexpected completed steps %v", state.CompletedSteps)
+	}
+
+	// an interrupted upgrade to other images must be resumed or restarted explicitly
+	other := target()
+	other.TargetCoreImage = "core:3"
+	if _, err := planUpgrade(interrupted, other, false); err == nil {
+		t.Fatalf("expected an error for an upgrade to other images")
+	}
+	state, err = planUpgrade(interrupted, other, true)
+	if err != nil || len(state.CompletedSteps) != 0 || state.PreviousOperatorImage != "operator:1" || state.PreviousCoreImage != "core:1" ||
+		state.PreviousDBImage != "postgres:15" || state.DBSnapshot != "noobaa-db-upgrade-1" {
+		t.Fatalf("expected a restart that keeps the images and the DB snapshot to roll back to, got %+v %v", state, err)
+	}
+}
+
+func TestSetPreviousSystemImages(t *testing.T) {
+	image, dbImage, version := "core:2", "postgres:17", 17
+	sys := &nbv1.NooBaa{}
+	sys.Spec.Image = &image
+	sys.Spec.DBSpec = &nbv1.NooBaaDBSpec{DBImage: &dbImage, PostgresMajorVersion: &version}
+
+	setPreviousSystemImages(sys, &UpgradeState{PreviousCoreImage: "core:1", PreviousDBImage: "postgres:16", PreviousPgMajorVersion: 16})
+	if *sys.Spec.Image != "core:1" || *sys.Spec.DBSpec.DBImage != "postgres:16" || *sys.Spec.DBSpec.PostgresMajorVersion != 16 {
+		t.Fatalf("expected the images before the upgrade, got %s %s %d",
+			*sys.Spec.Image, *sys.Spec.DBSpec.DBImage, *sys.Spec.DBSpec.PostgresMajorVersion)
+	}
+
+	// a DB image that was not recorded is kept
+	setPreviousSystemImages(sys, &UpgradeState{PreviousCoreImage: "core:0"})
+	if *sys.Spec.Image != "core:0" || *sys.Spec.DBSpec.DBImage != "postgres:16" || *sys.Spec.DBSpec.PostgresMajorVersion != 16 {
+		t.Fatalf("expected only the core image to change, got %s %s %d",
+			*sys.Spec.Image, *sys.Spec.DBSpec.DBImage, *sys.Spec.DBSpec.PostgresMajorVersion)
+	}
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Controller names used as the controller label of the reconcile metrics.
+// All the operator controllers share the same controller-runtime name,
+// so the built-in controller-runtime metrics can not tell them apart.
+const (
+	ControllerNooBaa         = "noobaa"
+	ControllerBackingStore   = "backingstore"
+	ControllerNamespaceStore = "namespacestore"
+	ControllerBucketClass    = "bucketclass"
+	ControllerNooBaaAccount  = "noobaaaccount"
+	ControllerNooBaaRemote   = "noobaaremote"
+)
+
+// Reconcile error types
+const (
+	ErrorTypeTemporary  = "temporary"
+	ErrorTypePersistent = "persistent"
+)
+
+var (
+	// phase is set to 1 for the current phase of every CR, other phases of the CR are removed
+	phase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "noobaa_operator",
+		Name:      "phase",
+		Help:      "Current phase of a NooBaa custom resource, the value is always 1",
+	}, []string{"kind", "namespace", "name", "phase"})
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "noobaa_operator",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of a single reconcile per controller",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"controller"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "noobaa_operator",
+		Name:      "reconcile_errors_total",
+		Help:      "Number of reconciles that ended with an error per controller and error type",
+	}, []string{"controller", "type"})
+
+	// kmsStatus is set to 1 for the current KMS condition status of the system
+	kmsStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "noobaa_operator",
+		Name:      "kms_status",
+		Help:      "Current KMS condition status of the NooBaa system, the value is always 1",
+	}, []string{"namespace", "name", "status"})
+
+	dbBackups = &dbBackupCollector{
+		desc: prometheus.NewDesc(
+			"noobaa_operator_db_backup_age_seconds",
+			"Seconds since the last successful backup of the NooBaa DB",
+			[]string{"namespace", "name"}, nil,
+		),
+		lastBackup: map[types.NamespacedName]time.Time{},
+	}
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(phase, reconcileDuration, reconcileErrors, kmsStatus, dbBackups)
+}
+
+// SetPhase sets the current phase of a CR
+func SetPhase(kind, namespace, name, currentPhase string) {
+	DeletePhase(kind, namespace, name)
+	phase.WithLabelValues(kind, namespace, name, currentPhase).Set(1)
+}
+
+// DeletePhase removes the phase of a CR that no longer exists
+func DeletePhase(kind, namespace, name string) {
+	phase.DeletePartialMatch(prometheus.Labels{"kind": kind, "namespace": namespace, "name": name})
+}
+
+// ObserveReconcileDuration records the duration of a reconcile that started on start,
+// it is meant to be deferred when the reconcile starts
+func ObserveReconcileDuration(controller string, start time.Time) {
+	reconcileDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+}
+
+// ReconcileError counts a reconcile that ended with err, persistent errors are
+// the ones that reject the CR and temporary errors are retried
+func ReconcileError(controller string, err error) {
+	if err == nil {
+		return
+	}
+	errType := ErrorTypeTemporary
+	if _, isPERR := err.(*util.PersistentError); isPERR {
+		errType = ErrorTypePersistent
+	}
+	reconcileErrors.WithLabelValues(controller, errType).Inc()
+}
+
+// SetKMSStatus sets the current KMS condition status of a system
+func SetKMSStatus(namespace, name, status string) {
+	DeleteKMSStatus(namespace, name)
+	kmsStatus.WithLabelValues(namespace, name, status).Set(1)
+}
+
+// DeleteKMSStatus removes the KMS condition status of a system
+func DeleteKMSStatus(namespace, name string) {
+	kmsStatus.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+// SetDBBackupTime sets the time of the last successful DB backup of a system,
+// a nil time removes the backup age of the system
+func SetDBBackupTime(namespace, name string, lastBackup *metav1.Time) {
+	dbBackups.set(types.NamespacedName{Namespace: namespace, Name: name}, lastBackup)
+}
+
+// DeleteSystem removes all the metrics of a system that no longer exists
+func DeleteSystem(namespace, name string) {
+	DeletePhase("NooBaa", namespace, name)
+	DeleteKMSStatus(namespace, name)
+	SetDBBackupTime(namespace, name, nil)
+}
+
+// dbBackupCollector computes the backup age on every scrape,
+// so it keeps growing between reconciles of the system
+type dbBackupCollector struct {
+	desc       *prometheus.Desc
+	mutex      sync.Mutex
+	lastBackup map[types.NamespacedName]time.Time
+}
+
+func (c *dbBackupCollector) set(key types.NamespacedName, lastBackup *metav1.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if lastBackup == nil || lastBackup.IsZero() {
+		delete(c.lastBackup, key)
+		return
+	}
+	c.lastBackup[key] = lastBackup.Time
+}
+
+// Describe implements prometheus.Collector
+func (c *dbBackupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *dbBackupCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, lastBackup := range c.lastBackup {
+		ch <- prometheus.MustNewConstMetric(
+			c.desc, prometheus.GaugeValue, time.Since(lastBackup).Seconds(), key.Namespace, key.Name)
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetPhase(t *testing.T) {
+	SetPhase("BackingStore", "ns", "bs1", "Creating")
+	SetPhase("BackingStore", "ns", "bs1", "Ready")
+	SetPhase("BackingStore", "ns", "bs2", "Rejected")
+
+	if got := testutil.ToFloat64(phase.WithLabelValues("BackingStore", "ns", "bs1", "Ready")); got != 1 {
+		t.Fatalf("expected the current phase to be 1, got %v", got)
+	}
+	expected := `
+# HELP noobaa_operator_phase Current phase of a NooBaa custom resource, the value is always 1
+# TYPE noobaa_operator_phase gauge
+noobaa_operator_phase{kind="BackingStore",name="bs1",namespace="ns",phase="Ready"} 1
+noobaa_operator_phase{kind="BackingStore",name="bs2",namespace="ns",phase="Rejected"} 1
+`
+	if err := testutil.CollectAndCompare(phase, strings.NewReader(expected)); err != nil {
+		t.Fatalf("unexpected phases: %v", err)
+	}
+
+	DeletePhase("BackingStore", "ns", "bs1")
+	DeletePhase("BackingStore", "ns", "bs2")
+	if count := testutil.CollectAndCount(phase); count != 0 {
+		t.Fatalf("expected no phases after delete, got %d", count)
+	}
+}
+
+func TestReconcileError(t *testing.T) {
+	ReconcileError(ControllerBucketClass, nil)
+	ReconcileError(ControllerBucketClass, fmt.Errorf("temporary"))
+	ReconcileError(ControllerBucketClass, util.NewPersistentError("Reason", "persistent"))
+	ReconcileError(ControllerBucketClass, fmt.Errorf("temporary again"))
+
+	if got := testutil.ToFloat64(reconcileErrors.WithLabelValues(ControllerBucketClass, ErrorTypeTemporary)); got != 2 {
+		t.Fatalf("expected 2 temporary errors, got %v", got)
+	}
+	if got := testutil.ToFloat64(reconcileErrors.WithLabelValues(ControllerBucketClass, ErrorTypePersistent)); got != 1 {
+		t.Fatalf("expected 1 persistent error, got %v", got)
+	}
+}
+
+func TestDeleteSystem(t *testing.T) {
+	lastBackup := metav1.NewTime(time.Now().Add(-time.Hour))
+	SetPhase("NooBaa", "ns", "noobaa", "Ready")
+	SetKMSStatus("ns", "noobaa", "Init")
+	SetKMSStatus("ns", "noobaa", "Sync")
+	SetDBBackupTime("ns", "noobaa", &lastBackup)
+
+	if count := testutil.CollectAndCount(kmsStatus); count != 1 {
+		t.Fatalf("expected a single KMS status, got %d", count)
+	}
+	if got := testutil.ToFloat64(dbBackups); got < time.Hour.Seconds() {
+		t.Fatalf("expected the backup age to be at least an hour, got %v", got)
+	}
+
+	DeleteSystem("ns", "noobaa")
+	for name, collector := range map[string]prometheus.Collector{"phase": phase, "kms_status": kmsStatus, "db_backup_age_seconds": dbBackups} {
+		if count := testutil.CollectAndCount(collector); count != 0 {
+			t.Fatalf("expected no %s metrics after delete, got %d", name, count)
+		}
+	}
+}
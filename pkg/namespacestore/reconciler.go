@@ -10,6 +10,7 @@ import (
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
 	"github.com/noobaa/noobaa-operator/v5/pkg/constants"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/system"
@@ -127,6 +128,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 
 	if !util.KubeCheck(r.NamespaceStore) {
 		log.Infof("❌ NamespaceStore %q not found.", r.NamespaceStore.Name)
+		metrics.DeletePhase("NamespaceStore", r.NamespaceStore.Namespace, r.NamespaceStore.Name)
 		return reconcile.Result{}, nil // final state
 	}
 
@@ -168,6 +170,7 @@ func (r *Reconciler) completeReconcile(err error) (reconcile.Result, error) {
 	res := reconcile.Result{}
 
 	if err != nil {
+		metrics.ReconcileError(metrics.ControllerNamespaceStore, err)
 		if perr, isPERR := err.(*util.PersistentError); isPERR {
 			r.SetPhase(nbv1.NamespaceStorePhaseRejected, perr.Reason, perr.Message)
 			log.Errorf("❌ Persistent Error: %s", err)
@@ -244,6 +247,7 @@ func (r *Reconciler) SetPhase(phase nbv1.NamespaceStorePhase, reason string, mes
 
 	r.Logger.Infof("SetPhase: %s", phase)
 	r.NamespaceStore.Status.Phase = phase
+	metrics.SetPhase("NamespaceStore", r.NamespaceStore.Namespace, r.NamespaceStore.Name, string(phase))
 	switch phase {
 	case nbv1.NamespaceStorePhaseReady:
 		util.SetAvailableCondition(c, reason, message)
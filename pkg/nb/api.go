@@ -1,9 +1,15 @@
 // Package nb makes client API calls to noobaa servers.
 package nb
 
-// Client is the interface providing typed noobaa API calls
+import "context"
+
+// Client is the interface providing typed noobaa API calls.
+// WithContext returns a client whose API calls are bound to the context,
+// for example nbClient.WithContext(ctx).ReadSystemAPI()
 type Client interface {
 	Call(req *RPCMessage, res RPCResponse) error
+	CallContext(ctx context.Context, req *RPCMessage, res RPCResponse) error
+	WithContext(ctx context.Context) Client
 
 	SetAuthToken(token string)
 	GetAuthToken() string
@@ -30,6 +36,8 @@ type Client interface {
 	CreateHostsPoolAPI(CreateHostsPoolParams) (string, error)
 	GetHostsPoolAgentConfigAPI(GetHostsPoolAgentConfigParams) (string, error)
 	UpdateHostsPoolAPI(UpdateHostsPoolParams) error
+	UpdateHostServicesAPI(UpdateHostServicesParams) error
+	DeleteHostAPI(DeleteHostParams) error
 	CreateCloudPoolAPI(CreateCloudPoolParams) error
 	UpdateCloudPoolAPI(UpdateCloudPoolParams) error
 	CreateTierAPI(CreateTierParams) error
@@ -62,8 +70,17 @@ type Client interface {
 	ValidateReplicationAPI(BucketReplicationParams) error
 	DeleteBucketReplicationAPI(DeleteBucketReplicationParams) error
 
+	SetBucketLifecycleConfigurationRulesAPI(BucketLifecycleParams) error
+	DeleteBucketLifecycleAPI(DeleteBucketLifecycleParams) error
+
+	PutBucketPolicyAPI(PutBucketPolicyParams) error
+	GetBucketPolicyAPI(GetBucketPolicyParams) (GetBucketPolicyReply, error)
+	DeleteBucketPolicyAPI(DeleteBucketPolicyParams) error
+
 	GenerateAccountKeysAPI(GenerateAccountKeysParams) error
 	UpdateAccountKeysAPI(UpdateAccountKeysParams) error
+	CreateAccessKeyAPI(CreateAccessKeyParams) error
+	DeleteAccessKeyAPI(DeleteAccessKeyParams) error
 
 	CreateVectorBucketAPI(CreateVectorBucketParams) (VectorBucketInfo, error)
 	GetVectorBucketAPI(GetVectorBucketParams) (VectorBucketInfo, error)
@@ -231,6 +248,18 @@ func (c *RPCClient) UpdateHostsPoolAPI(params UpdateHostsPoolParams) error {
 	return c.Call(req, nil)
 }
 
+// UpdateHostServicesAPI calls host_api.update_host_services()
+func (c *RPCClient) UpdateHostServicesAPI(params UpdateHostServicesParams) error {
+	req := &RPCMessage{API: "host_api", Method: "update_host_services", Params: params}
+	return c.Call(req, nil)
+}
+
+// DeleteHostAPI calls host_api.delete_host()
+func (c *RPCClient) DeleteHostAPI(params DeleteHostParams) error {
+	req := &RPCMessage{API: "host_api", Method: "delete_host", Params: params}
+	return c.Call(req, nil)
+}
+
 // CreateCloudPoolAPI calls pool_api.create_cloud_pool()
 func (c *RPCClient) CreateCloudPoolAPI(params CreateCloudPoolParams) error {
 	req := &RPCMessage{API: "pool_api", Method: "create_cloud_pool", Params: params}
@@ -424,6 +453,41 @@ func (c *RPCClient) DeleteBucketReplicationAPI(params DeleteBucketReplicationPar
 	return c.Call(req, nil)
 }
 
+// SetBucketLifecycleConfigurationRulesAPI calls bucket_api.set_bucket_lifecycle_configuration_rules()
+func (c *RPCClient) SetBucketLifecycleConfigurationRulesAPI(params BucketLifecycleParams) error {
+	req := &RPCMessage{API: "bucket_api", Method: "set_bucket_lifecycle_configuration_rules", Params: params}
+	return c.Call(req, nil)
+}
+
+// DeleteBucketLifecycleAPI calls bucket_api.delete_bucket_lifecycle()
+func (c *RPCClient) DeleteBucketLifecycleAPI(params DeleteBucketLifecycleParams) error {
+	req := &RPCMessage{API: "bucket_api", Method: "delete_bucket_lifecycle", Params: params}
+	return c.Call(req, nil)
+}
+
+// PutBucketPolicyAPI calls bucket_api.put_bucket_policy()
+func (c *RPCClient) PutBucketPolicyAPI(params PutBucketPolicyParams) error {
+	req := &RPCMessage{API: "bucket_api", Method: "put_bucket_policy", Params: params}
+	return c.Call(req, nil)
+}
+
+// GetBucketPolicyAPI calls bucket_api.get_bucket_policy()
+func (c *RPCClient) GetBucketPolicyAPI(params GetBucketPolicyParams) (GetBucketPolicyReply, error) {
+	req := &RPCMessage{API: "bucket_api", Method: "get_bucket_policy", Params: params}
+	res := &struct {
+		RPCMessage `json:",inline"`
+		Reply      GetBucketPolicyReply `json:"reply"`
+	}{}
+	err := c.Call(req, res)
+	return res.Reply, err
+}
+
+// DeleteBucketPolicyAPI calls bucket_api.delete_bucket_policy()
+func (c *RPCClient) DeleteBucketPolicyAPI(params DeleteBucketPolicyParams) error {
+	req := &RPCMessage{API: "bucket_api", Method: "delete_bucket_policy", Params: params}
+	return c.Call(req, nil)
+}
+
 // GenerateAccountKeysAPI calls account_api.generate_account_keys()
 func (c *RPCClient) GenerateAccountKeysAPI(params GenerateAccountKeysParams) error {
 	req := &RPCMessage{API: "account_api", Method: "generate_account_keys", Params: params}
@@ -436,6 +500,18 @@ func (c *RPCClient) UpdateAccountKeysAPI(params UpdateAccountKeysParams) error {
 	return c.Call(req, nil)
 }
 
+// CreateAccessKeyAPI calls account_api.create_access_key()
+func (c *RPCClient) CreateAccessKeyAPI(params CreateAccessKeyParams) error {
+	req := &RPCMessage{API: "account_api", Method: "create_access_key", Params: params}
+	return c.Call(req, nil)
+}
+
+// DeleteAccessKeyAPI calls account_api.delete_access_key()
+func (c *RPCClient) DeleteAccessKeyAPI(params DeleteAccessKeyParams) error {
+	req := &RPCMessage{API: "account_api", Method: "delete_access_key", Params: params}
+	return c.Call(req, nil)
+}
+
 // CreateVectorBucketAPI calls bucket_api.create_vector_bucket()
 func (c *RPCClient) CreateVectorBucketAPI(params CreateVectorBucketParams) (VectorBucketInfo, error) {
 	req := &RPCMessage{API: "bucket_api", Method: "create_vector_bucket", Params: params}
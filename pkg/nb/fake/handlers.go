@@ -0,0 +1,943 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+)
+
+// handle adapts a typed method implementation to a handler that decodes the json params
+func handle[P any](fn func(P) (interface{}, error)) handler {
+	return func(raw json.RawMessage) (interface{}, error) {
+		var params P
+		if len(raw) > 0 && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &params); err != nil {
+				return nil, rpcError("BAD_REQUEST", "invalid params: %v", err)
+			}
+		}
+		return fn(params)
+	}
+}
+
+// rpcError returns an error replied by the server
+func rpcError(code string, format string, args ...interface{}) error {
+	return &nb.RPCError{RPCCode: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// none is the params of methods without params
+type none struct{}
+
+func (s *Server) registerHandlers() {
+	s.handlers = map[string]handler{
+		"auth_api.read_auth": handle(s.readAuth),
+
+		"system_api.create_system":         handle(s.createSystem),
+		"system_api.read_system":           handle(s.readSystem),
+		"system_api.get_system_status":     handle(s.getSystemStatus),
+		"system_api.update_endpoint_group": handle(s.noop),
+
+		"account_api.create_account":             handle(s.createAccount),
+		"account_api.read_account":               handle(s.readAccount),
+		"account_api.list_accounts":              handle(s.listAccounts),
+		"account_api.update_account":             handle(s.updateAccount),
+		"account_api.update_account_s3_access":   handle(s.updateAccountS3Access),
+		"account_api.delete_account":             handle(s.deleteAccount),
+		"account_api.generate_account_keys":      handle(s.generateAccountKeys),
+		"account_api.update_account_keys":        handle(s.updateAccountKeys),
+		"account_api.create_access_key":          handle(s.createAccessKey),
+		"account_api.delete_access_key":          handle(s.deleteAccessKey),
+		"account_api.add_external_connection":    handle(s.addExternalConnection),
+		"account_api.check_external_connection":  handle(s.checkExternalConnection),
+		"account_api.update_external_connection": handle(s.updateExternalConnection),
+		"account_api.delete_external_connection": handle(s.deleteExternalConnection),
+
+		"pool_api.create_hosts_pool":                    handle(s.createHostsPool),
+		"pool_api.get_hosts_pool_agent_config":          handle(s.getHostsPoolAgentConfig),
+		"pool_api.update_hosts_pool":                    handle(s.updateHostsPool),
+		"pool_api.create_cloud_pool":                    handle(s.createCloudPool),
+		"pool_api.update_cloud_pool":                    handle(s.updateCloudPool),
+		"pool_api.read_pool":                            handle(s.readPool),
+		"pool_api.delete_pool":                          handle(s.deletePool),
+		"pool_api.create_namespace_resource":            handle(s.createNamespaceResource),
+		"pool_api.read_namespace_resource":              handle(s.readNamespaceResource),
+		"pool_api.get_namespace_resource_operator_info": handle(s.getNamespaceResourceOperatorInfo),
+		"pool_api.set_namespace_store_info":             handle(s.setNamespaceStoreInfo),
+		"pool_api.delete_namespace_resource":            handle(s.deleteNamespaceResource),
+
+		"host_api.list_hosts":           handle(s.listHosts),
+		"host_api.update_host_services": handle(s.updateHostServices),
+		"host_api.delete_host":          handle(s.deleteHost),
+
+		"tier_api.create_tier":                   handle(s.createTier),
+		"tiering_policy_api.create_policy":       handle(s.createTieringPolicy),
+		"tiering_policy_api.update_bucket_class": handle(s.updateBucketClass),
+
+		"bucket_api.create_bucket":                            handle(s.createBucket),
+		"bucket_api.update_bucket":                            handle(s.updateBucket),
+		"bucket_api.read_bucket":                              handle(s.readBucket),
+		"bucket_api.list_buckets":                             handle(s.listBuckets),
+		"bucket_api.delete_bucket":                            handle(s.deleteBucket),
+		"bucket_api.delete_bucket_and_objects":                handle(s.deleteBucket),
+		"bucket_api.update_all_buckets_default_pool":          handle(s.updateAllBucketsDefaultPool),
+		"bucket_api.put_bucket_replication":                   handle(s.putBucketReplication),
+		"bucket_api.get_bucket_replication":                   handle(s.getBucketReplication),
+		"bucket_api.validate_replication":                     handle(s.validateReplication),
+		"bucket_api.delete_bucket_replication":                handle(s.deleteBucketReplication),
+		"bucket_api.set_bucket_lifecycle_configuration_rules": handle(s.setBucketLifecycle),
+		"bucket_api.delete_bucket_lifecycle":                  handle(s.deleteBucketLifecycle),
+		"bucket_api.put_bucket_policy":                        handle(s.putBucketPolicy),
+		"bucket_api.get_bucket_policy":                        handle(s.getBucketPolicy),
+		"bucket_api.delete_bucket_policy":                     handle(s.deleteBucketPolicy),
+		"bucket_api.create_vector_bucket":                     handle(s.createVectorBucket),
+		"bucket_api.get_vector_bucket":                        handle(s.getVectorBucket),
+		"bucket_api.delete_vector_bucket":                     handle(s.deleteVectorBucket),
+
+		"redirector_api.register_to_cluster": handle(s.noop),
+		"redirector_api.publish_to_cluster":  handle(s.noop),
+	}
+}
+
+func (s *Server) noop(json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+// AddHost adds a host to a hosts pool, like an agent of a pv-pool pod that connected to core
+func (s *Server) AddHost(pool string, host nb.HostInfo) {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+	if host.Mode == "" {
+		host.Mode = "OPTIMAL"
+	}
+	s.Hosts[pool] = append(s.Hosts[pool], host)
+}
+
+//////////
+// AUTH //
+//////////
+
+func (s *Server) readAuth(none) (interface{}, error) {
+	reply := nb.ReadAuthReply{AuthorizedBy: "noobaa", Role: "operator"}
+	reply.Account.Name = "operator"
+	reply.Account.Email = OperatorEmail
+	reply.System.Name = SystemName
+	return reply, nil
+}
+
+////////////
+// SYSTEM //
+////////////
+
+func (s *Server) createSystem(params nb.CreateSystemParams) (interface{}, error) {
+	if _, ok := s.Accounts[params.Email]; !ok {
+		s.Accounts[params.Email] = &nb.AccountInfo{Name: params.Name, Email: params.Email, HasLogin: true, HasS3Access: true}
+	}
+	return nb.CreateSystemReply{Token: "fake-token", OperatorToken: "fake-operator-token"}, nil
+}
+
+func (s *Server) readSystem(none) (interface{}, error) {
+	info := nb.SystemInfo{Version: "fake"}
+	for _, email := range sortedKeys(s.Accounts) {
+		info.Accounts = append(info.Accounts, s.accountInfo(email))
+	}
+	for _, name := range sortedKeys(s.Buckets) {
+		info.Buckets = append(info.Buckets, *s.Buckets[name])
+	}
+	for _, name := range sortedKeys(s.Pools) {
+		info.Pools = append(info.Pools, *s.Pools[name])
+	}
+	for _, name := range sortedKeys(s.Tiers) {
+		info.Tiers = append(info.Tiers, *s.Tiers[name])
+	}
+	for _, name := range sortedKeys(s.NamespaceResources) {
+		info.NamespaceResources = append(info.NamespaceResources, *s.NamespaceResources[name])
+	}
+	return info, nil
+}
+
+func (s *Server) getSystemStatus(none) (interface{}, error) {
+	return nb.ReadySystemStatusReply{State: s.SystemState}, nil
+}
+
+//////////////
+// ACCOUNTS //
+//////////////
+
+// accountInfo returns the account with the external connections, which belong to the operator account
+func (s *Server) accountInfo(email string) nb.AccountInfo {
+	account := *s.Accounts[email]
+	account.ExternalConnections.Connections = nil
+	if email == OperatorEmail {
+		for _, name := range sortedKeys(s.ExternalConnections) {
+			account.ExternalConnections.Connections = append(account.ExternalConnections.Connections, *s.ExternalConnections[name])
+		}
+	}
+	account.ExternalConnections.Count = len(account.ExternalConnections.Connections)
+	return account
+}
+
+func (s *Server) findAccount(email string) (*nb.AccountInfo, error) {
+	account := s.Accounts[email]
+	if account == nil {
+		return nil, rpcError("NO_SUCH_ACCOUNT", "account not found %s", email)
+	}
+	return account, nil
+}
+
+func (s *Server) newAccessKeys() []nb.S3AccessKeys {
+	s.nextID++
+	return []nb.S3AccessKeys{{
+		AccessKey: nb.MaskedString(fmt.Sprintf("FAKEACCESSKEY%07d", s.nextID)),
+		SecretKey: nb.MaskedString(fmt.Sprintf("fake-secret-key-%d", s.nextID)),
+	}}
+}
+
+func (s *Server) createAccount(params nb.CreateAccountParams) (interface{}, error) {
+	if _, ok := s.Accounts[params.Email]; ok {
+		return nil, rpcError("CONFLICT", "account already exists %s", params.Email)
+	}
+	if params.DefaultResource != "" && s.Pools[params.DefaultResource] == nil && s.NamespaceResources[params.DefaultResource] == nil {
+		return nil, rpcError("BAD_REQUEST", "default resource not found %s", params.DefaultResource)
+	}
+	account := &nb.AccountInfo{
+		Name:              params.Name,
+		Email:             params.Email,
+		HasLogin:          params.HasLogin,
+		HasS3Access:       params.S3Access,
+		CanCreateBuckets:  params.AllowBucketCreate,
+		DefaultResource:   params.DefaultResource,
+		NsfsAccountConfig: params.NsfsAccountConfig,
+		ARN:               "arn:aws:iam::" + params.Name,
+	}
+	if params.S3Access {
+		account.AccessKeys = s.newAccessKeys()
+	}
+	s.Accounts[params.Email] = account
+	return nb.CreateAccountReply{
+		Id:         fmt.Sprintf("fake-account-%d", s.nextID),
+		ARN:        account.ARN,
+		Token:      "fake-token-" + params.Email,
+		AccessKeys: account.AccessKeys,
+	}, nil
+}
+
+func (s *Server) readAccount(params nb.ReadAccountParams) (interface{}, error) {
+	if _, err := s.findAccount(params.Email); err != nil {
+		return nil, err
+	}
+	return s.accountInfo(params.Email), nil
+}
+
+func (s *Server) listAccounts(nb.ListAccountsParams) (interface{}, error) {
+	reply := nb.ListAccountsReply{}
+	for _, email := range sortedKeys(s.Accounts) {
+		account := s.accountInfo(email)
+		reply.Accounts = append(reply.Accounts, &account)
+	}
+	return reply, nil
+}
+
+func (s *Server) updateAccount(params nb.UpdateAccountParams) (interface{}, error) {
+	account, err := s.findAccount(params.Email)
+	if err != nil {
+		return nil, err
+	}
+	if params.Name != nil {
+		account.Name = *params.Name
+	}
+	if params.AllowedIPs != nil {
+		account.AllowedIPs = *params.AllowedIPs
+	}
+	if params.RemoveRoleConfig {
+		account.RoleConfig = nil
+	} else if params.RoleConfig != nil {
+		roleConfig := &nb.RoleConfig{}
+		if err := recode(params.RoleConfig, roleConfig); err != nil {
+			return nil, rpcError("BAD_REQUEST", "invalid role config: %v", err)
+		}
+		account.RoleConfig = roleConfig
+	}
+	if params.NewEmail != nil && *params.NewEmail != params.Email {
+		if _, ok := s.Accounts[*params.NewEmail]; ok {
+			return nil, rpcError("CONFLICT", "account already exists %s", *params.NewEmail)
+		}
+		delete(s.Accounts, params.Email)
+		account.Email = *params.NewEmail
+		s.Accounts[account.Email] = account
+	}
+	return nil, nil
+}
+
+func (s *Server) updateAccountS3Access(params nb.UpdateAccountS3AccessParams) (interface{}, error) {
+	account, err := s.findAccount(params.Email)
+	if err != nil {
+		return nil, err
+	}
+	account.HasS3Access = params.S3Access
+	if params.DefaultResource != nil {
+		if s.Pools[*params.DefaultResource] == nil && s.NamespaceResources[*params.DefaultResource] == nil {
+			return nil, rpcError("BAD_REQUEST", "default resource not found %s", *params.DefaultResource)
+		}
+		account.DefaultResource = *params.DefaultResource
+	}
+	if params.AllowBucketCreation != nil {
+		account.CanCreateBuckets = *params.AllowBucketCreation
+	}
+	if params.NsfsAccountConfig != nil {
+		account.NsfsAccountConfig = params.NsfsAccountConfig
+	}
+	return nil, nil
+}
+
+func (s *Server) deleteAccount(params nb.DeleteAccountParams) (interface{}, error) {
+	if _, err := s.findAccount(params.Email); err != nil {
+		return nil, err
+	}
+	delete(s.Accounts, params.Email)
+	return nil, nil
+}
+
+func (s *Server) generateAccountKeys(params nb.GenerateAccountKeysParams) (interface{}, error) {
+	account, err := s.findAccount(params.Email)
+	if err != nil {
+		return nil, err
+	}
+	account.AccessKeys = s.newAccessKeys()
+	return nil, nil
+}
+
+func (s *Server) updateAccountKeys(params nb.UpdateAccountKeysParams) (interface{}, error) {
+	account, err := s.findAccount(params.Email)
+	if err != nil {
+		return nil, err
+	}
+	account.AccessKeys = []nb.S3AccessKeys{params.AccessKeys}
+	return nil, nil
+}
+
+func (s *Server) createAccessKey(params nb.CreateAccessKeyParams) (interface{}, error) {
+	account, err := s.findAccount(params.Email)
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range sortedKeys(s.Accounts) {
+		for _, keys := range s.Accounts[email].AccessKeys {
+			if keys.AccessKey == params.AccessKeys.AccessKey {
+				return nil, rpcError("CONFLICT", "access key already exists %s", params.AccessKeys.AccessKey)
+			}
+		}
+	}
+	account.AccessKeys = append(account.AccessKeys, params.AccessKeys)
+	return nil, nil
+}
+
+func (s *Server) deleteAccessKey(params nb.DeleteAccessKeyParams) (interface{}, error) {
+	account, err := s.findAccount(params.Email)
+	if err != nil {
+		return nil, err
+	}
+	for i, keys := range account.AccessKeys {
+		if keys.AccessKey == params.AccessKey {
+			account.AccessKeys = append(account.AccessKeys[:i], account.AccessKeys[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, rpcError("NO_SUCH_ACCESS_KEY", "access key not found %s", params.AccessKey)
+}
+
+//////////////////////////
+// EXTERNAL CONNECTIONS //
+//////////////////////////
+
+func (s *Server) addExternalConnection(params nb.AddExternalConnectionParams) (interface{}, error) {
+	if _, ok := s.ExternalConnections[params.Name]; ok {
+		return nil, rpcError("CONFLICT", "external connection already exists %s", params.Name)
+	}
+	s.ExternalConnections[params.Name] = &nb.ExternalConnectionInfo{
+		Name:         params.Name,
+		EndpointType: params.EndpointType,
+		Endpoint:     params.Endpoint,
+		Identity:     string(params.Identity),
+		AuthMethod:   params.AuthMethod,
+	}
+	return nil, nil
+}
+
+func (s *Server) checkExternalConnection(params nb.CheckExternalConnectionParams) (interface{}, error) {
+	return nb.CheckExternalConnectionReply{Status: s.ExternalConnectionStatus}, nil
+}
+
+func (s *Server) updateExternalConnection(params nb.UpdateExternalConnectionParams) (interface{}, error) {
+	conn := s.ExternalConnections[params.Name]
+	if conn == nil {
+		return nil, rpcError("NO_SUCH_CONNECTION", "external connection not found %s", params.Name)
+	}
+	if params.Identity != "" {
+		conn.Identity = string(params.Identity)
+	}
+	if params.EndpointInfo != nil {
+		conn.Endpoint = params.EndpointInfo.Endpoint
+		conn.EndpointType = params.EndpointInfo.EndpointType
+	}
+	return nil, nil
+}
+
+func (s *Server) deleteExternalConnection(params nb.DeleteExternalConnectionParams) (interface{}, error) {
+	if s.ExternalConnections[params.Name] == nil {
+		return nil, rpcError("NO_SUCH_CONNECTION", "external connection not found %s", params.Name)
+	}
+	delete(s.ExternalConnections, params.Name)
+	return nil, nil
+}
+
+///////////
+// POOLS //
+///////////
+
+func (s *Server) findPool(name string) (*nb.PoolInfo, error) {
+	pool := s.Pools[name]
+	if pool == nil {
+		return nil, rpcError("NO_SUCH_POOL", "pool not found %s", name)
+	}
+	return pool, nil
+}
+
+func (s *Server) checkNewResourceName(name string) error {
+	if s.Pools[name] != nil || s.NamespaceResources[name] != nil {
+		return rpcError("CONFLICT", "resource already exists %s", name)
+	}
+	return nil
+}
+
+func (s *Server) createHostsPool(params nb.CreateHostsPoolParams) (interface{}, error) {
+	if err := s.checkNewResourceName(params.Name); err != nil {
+		return nil, err
+	}
+	hostConfig := params.HostConfig
+	pool := &nb.PoolInfo{
+		Name:         params.Name,
+		ResourceType: "HOSTS",
+		Mode:         "OPTIMAL",
+		HostInfo:     &hostConfig,
+	}
+	if err := recode(map[string]interface{}{"configured_count": params.HostCount}, &pool.Hosts); err != nil {
+		return nil, err
+	}
+	s.Pools[params.Name] = pool
+	return "fake-agent-config-" + params.Name, nil
+}
+
+func (s *Server) getHostsPoolAgentConfig(params nb.GetHostsPoolAgentConfigParams) (interface{}, error) {
+	if _, err := s.findPool(params.Name); err != nil {
+		return nil, err
+	}
+	return "fake-agent-config-" + params.Name, nil
+}
+
+func (s *Server) updateHostsPool(params nb.UpdateHostsPoolParams) (interface{}, error) {
+	_, err := s.findPool(params.Name)
+	return nil, err
+}
+
+func (s *Server) createCloudPool(params nb.CreateCloudPoolParams) (interface{}, error) {
+	if err := s.checkNewResourceName(params.Name); err != nil {
+		return nil, err
+	}
+	conn := s.ExternalConnections[params.Connection]
+	if conn == nil {
+		return nil, rpcError("BAD_REQUEST", "external connection not found %s", params.Connection)
+	}
+	pool := &nb.PoolInfo{Name: params.Name, ResourceType: "CLOUD", Mode: "OPTIMAL"}
+	cloudInfo := map[string]interface{}{
+		"endpoint_type": conn.EndpointType,
+		"endpoint":      conn.Endpoint,
+		"target_bucket": params.TargetBucket,
+		"identity":      conn.Identity,
+		"auth_method":   conn.AuthMethod,
+	}
+	if err := recode(cloudInfo, &pool.CloudInfo); err != nil {
+		return nil, err
+	}
+	s.Pools[params.Name] = pool
+	return nil, nil
+}
+
+func (s *Server) updateCloudPool(params nb.UpdateCloudPoolParams) (interface{}, error) {
+	_, err := s.findPool(params.Name)
+	return nil, err
+}
+
+func (s *Server) readPool(params nb.ReadPoolParams) (interface{}, error) {
+	pool, err := s.findPool(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	return *pool, nil
+}
+
+func (s *Server) deletePool(params nb.DeletePoolParams) (interface{}, error) {
+	if _, err := s.findPool(params.Name); err != nil {
+		return nil, err
+	}
+	for _, email := range sortedKeys(s.Accounts) {
+		if s.Accounts[email].DefaultResource == params.Name {
+			return nil, rpcError("DEFAULT_RESOURCE", "pool %s is the default resource of account %s", params.Name, email)
+		}
+	}
+	for _, name := range sortedKeys(s.Tiers) {
+		for _, pool := range s.Tiers[name].AttachedPools {
+			if pool == params.Name {
+				return nil, rpcError("IN_USE", "pool %s is in use by tier %s", params.Name, name)
+			}
+		}
+	}
+	delete(s.Pools, params.Name)
+	delete(s.Hosts, params.Name)
+	return nil, nil
+}
+
+/////////////////////////
+// NAMESPACE RESOURCES //
+/////////////////////////
+
+func (s *Server) findNamespaceResource(name string) (*nb.NamespaceResourceInfo, error) {
+	nsr := s.NamespaceResources[name]
+	if nsr == nil {
+		return nil, rpcError("NO_SUCH_NAMESPACE_RESOURCE", "namespace resource not found %s", name)
+	}
+	return nsr, nil
+}
+
+func (s *Server) createNamespaceResource(params nb.CreateNamespaceResourceParams) (interface{}, error) {
+	if err := s.checkNewResourceName(params.Name); err != nil {
+		return nil, err
+	}
+	nsr := &nb.NamespaceResourceInfo{
+		Name:         params.Name,
+		Mode:         "OPTIMAL",
+		TargetBucket: params.TargetBucket,
+		AccessMode:   nbv1.AccessModeType(params.AccessMode),
+	}
+	if params.NSFSConfig == nil {
+		conn := s.ExternalConnections[params.Connection]
+		if conn == nil {
+			return nil, rpcError("BAD_REQUEST", "external connection not found %s", params.Connection)
+		}
+		nsr.EndpointType = conn.EndpointType
+		nsr.Endpoint = conn.Endpoint
+		nsr.Identity = conn.Identity
+		nsr.AuthMethod = conn.AuthMethod
+	}
+	if params.NamespaceStore != nil {
+		s.NamespaceStores[params.Name] = *params.NamespaceStore
+	}
+	s.NamespaceResources[params.Name] = nsr
+	return nil, nil
+}
+
+func (s *Server) readNamespaceResource(params nb.ReadNamespaceResourceParams) (interface{}, error) {
+	nsr, err := s.findNamespaceResource(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	return *nsr, nil
+}
+
+func (s *Server) getNamespaceResourceOperatorInfo(params nb.ReadNamespaceResourceParams) (interface{}, error) {
+	if _, err := s.findNamespaceResource(params.Name); err != nil {
+		return nil, err
+	}
+	return nb.NamespaceResourceOperatorInfo{}, nil
+}
+
+func (s *Server) setNamespaceStoreInfo(params nb.NamespaceStoreInfo) (interface{}, error) {
+	if _, err := s.findNamespaceResource(params.Name); err != nil {
+		return nil, err
+	}
+	s.NamespaceStores[params.Name] = params
+	return nil, nil
+}
+
+func (s *Server) deleteNamespaceResource(params nb.DeleteNamespaceResourceParams) (interface{}, error) {
+	if _, err := s.findNamespaceResource(params.Name); err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(s.Buckets) {
+		ns := s.Buckets[name].Namespace
+		if ns == nil {
+			continue
+		}
+		inUse := ns.WriteResource.Resource == params.Name
+		for _, r := range ns.ReadResources {
+			inUse = inUse || r.Resource == params.Name
+		}
+		if inUse {
+			return nil, rpcError("IN_USE", "namespace resource %s is in use by bucket %s", params.Name, name)
+		}
+	}
+	delete(s.NamespaceResources, params.Name)
+	delete(s.NamespaceStores, params.Name)
+	return nil, nil
+}
+
+///////////
+// HOSTS //
+///////////
+
+// findHost returns the pool and the index of a host
+func (s *Server) findHost(name string) (string, int, error) {
+	for _, pool := range sortedKeys(s.Hosts) {
+		for i := range s.Hosts[pool] {
+			if s.Hosts[pool][i].Name == name {
+				return pool, i, nil
+			}
+		}
+	}
+	return "", 0, rpcError("NO_SUCH_HOST", "host not found %s", name)
+}
+
+func (s *Server) listHosts(params nb.ListHostsParams) (interface{}, error) {
+	reply := nb.ListHostsReply{Hosts: []nb.HostInfo{}}
+	for _, pool := range params.Query.Pools {
+		reply.Hosts = append(reply.Hosts, s.Hosts[pool]...)
+	}
+	return reply, nil
+}
+
+// updateHostServices starts the decommission of a host when its storage service is disabled,
+// tests move it on by setting the mode of the host to DECOMMISSIONED
+func (s *Server) updateHostServices(params nb.UpdateHostServicesParams) (interface{}, error) {
+	pool, i, err := s.findHost(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	if params.Services.Storage == nil {
+		return nil, nil
+	}
+	host := &s.Hosts[pool][i]
+	if *params.Services.Storage {
+		host.Mode = "OPTIMAL"
+		host.DataActivity = nil
+	} else if host.Mode != "DECOMMISSIONED" {
+		host.Mode = "DECOMMISSIONING"
+		host.DataActivity = &nb.HostDataActivity{Reason: "DECOMMISSIONING"}
+	}
+	return nil, nil
+}
+
+func (s *Server) deleteHost(params nb.DeleteHostParams) (interface{}, error) {
+	pool, i, err := s.findHost(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	s.Hosts[pool] = append(s.Hosts[pool][:i], s.Hosts[pool][i+1:]...)
+	return nil, nil
+}
+
+///////////////////////////
+// TIERS & BUCKETCLASSES //
+///////////////////////////
+
+func (s *Server) createTier(params nb.CreateTierParams) (interface{}, error) {
+	if s.Tiers[params.Name] != nil {
+		return nil, rpcError("CONFLICT", "tier already exists %s", params.Name)
+	}
+	for _, pool := range params.AttachedPools {
+		if s.Pools[pool] == nil {
+			return nil, rpcError("NO_SUCH_POOL", "pool not found %s", pool)
+		}
+	}
+	s.Tiers[params.Name] = &nb.TierInfo{
+		Name:             params.Name,
+		DataPlacement:    params.DataPlacement,
+		AttachedPools:    params.AttachedPools,
+		ChunkCoderConfig: params.ChunkCoderConfig,
+	}
+	return nil, nil
+}
+
+func (s *Server) createTieringPolicy(params nb.TieringPolicyInfo) (interface{}, error) {
+	if s.TieringPolicies[params.Name] != nil {
+		return nil, rpcError("CONFLICT", "tiering policy already exists %s", params.Name)
+	}
+	for _, t := range params.Tiers {
+		if s.Tiers[t.Tier] == nil {
+			return nil, rpcError("NO_SUCH_TIER", "tier not found %s", t.Tier)
+		}
+	}
+	s.TieringPolicies[params.Name] = &params
+	return nil, nil
+}
+
+func (s *Server) updateBucketClass(params nb.UpdateBucketClassParams) (interface{}, error) {
+	if s.TieringPolicies[params.Name] == nil {
+		return nil, rpcError("NO_SUCH_TIERING_POLICY", "tiering policy not found %s", params.Name)
+	}
+	for i := range params.Tiers {
+		tier := params.Tiers[i]
+		s.Tiers[tier.Name] = &tier
+	}
+	policy := params.Policy
+	s.TieringPolicies[params.Name] = &policy
+	return nb.BucketClassInfo{}, nil
+}
+
+/////////////
+// BUCKETS //
+/////////////
+
+func (s *Server) findBucket(name string) (*nb.BucketInfo, error) {
+	bucket := s.Buckets[name]
+	if bucket == nil {
+		return nil, rpcError("NO_SUCH_BUCKET", "bucket not found %s", name)
+	}
+	return bucket, nil
+}
+
+// applyBucketParams sets the tiering and namespace configuration of the params to the bucket
+func (s *Server) applyBucketParams(bucket *nb.BucketInfo, params nb.CreateBucketParams) error {
+	if params.Tiering != "" {
+		policy := s.TieringPolicies[params.Tiering]
+		if policy == nil {
+			return rpcError("NO_SUCH_TIERING_POLICY", "tiering policy not found %s", params.Tiering)
+		}
+		bucket.Tiering = policy
+	}
+	if params.Namespace != nil {
+		resources := append([]nb.NamespaceResourceFullConfig{params.Namespace.WriteResource}, params.Namespace.ReadResources...)
+		for _, r := range resources {
+			if r.Resource != "" && s.NamespaceResources[r.Resource] == nil {
+				return rpcError("NO_SUCH_NAMESPACE_RESOURCE", "namespace resource not found %s", r.Resource)
+			}
+		}
+		bucket.Namespace = params.Namespace
+		bucket.BucketType = "NAMESPACE"
+	}
+	if params.ForceMd5Etag != nil {
+		bucket.ForceMd5Etag = params.ForceMd5Etag
+	}
+	if params.BucketClaim != nil {
+		bucket.BucketClaim = params.BucketClaim
+	}
+	if params.Quota != nil {
+		bucket.Quota = params.Quota
+	}
+	if params.ArchivePolicy != nil {
+		bucket.ArchivePolicy = params.ArchivePolicy
+	}
+	if params.RemoveArchivePolicy {
+		bucket.ArchivePolicy = nil
+	}
+	return nil
+}
+
+func (s *Server) createBucket(params nb.CreateBucketParams) (interface{}, error) {
+	if s.Buckets[params.Name] != nil {
+		return nil, rpcError("BUCKET_ALREADY_EXISTS", "bucket already exists %s", params.Name)
+	}
+	bucket := &nb.BucketInfo{Name: params.Name, BucketType: "REGULAR", Mode: "OPTIMAL"}
+	if err := s.applyBucketParams(bucket, params); err != nil {
+		return nil, err
+	}
+	s.Buckets[params.Name] = bucket
+	return nil, nil
+}
+
+func (s *Server) updateBucket(params nb.CreateBucketParams) (interface{}, error) {
+	bucket, err := s.findBucket(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	updated := *bucket
+	if err := s.applyBucketParams(&updated, params); err != nil {
+		return nil, err
+	}
+	*bucket = updated
+	return nil, nil
+}
+
+func (s *Server) readBucket(params nb.ReadBucketParams) (interface{}, error) {
+	bucket, err := s.findBucket(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	return *bucket, nil
+}
+
+func (s *Server) listBuckets(params nb.ListBucketsParams) (interface{}, error) {
+	names, token := page(sortedKeys(s.Buckets), params.ContinuationToken, params.MaxBuckets)
+	buckets := []map[string]string{}
+	for _, name := range names {
+		buckets = append(buckets, map[string]string{"name": name})
+	}
+	reply := map[string]interface{}{"buckets": buckets}
+	if token != "" {
+		reply["continuation_token"] = token
+	}
+	return reply, nil
+}
+
+func (s *Server) deleteBucket(params nb.DeleteBucketParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	delete(s.Buckets, params.Name)
+	delete(s.BucketPolicies, params.Name)
+	delete(s.BucketReplications, params.Name)
+	delete(s.BucketLifecycles, params.Name)
+	return nil, nil
+}
+
+func (s *Server) updateAllBucketsDefaultPool(params nb.UpdateDefaultResourceParams) (interface{}, error) {
+	_, err := s.findPool(params.PoolName)
+	return nil, err
+}
+
+func (s *Server) putBucketReplication(params nb.BucketReplicationParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	policy := params.ReplicationPolicy
+	s.BucketReplications[params.Name] = &policy
+	return nil, nil
+}
+
+func (s *Server) getBucketReplication(params nb.ReadBucketParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	if policy := s.BucketReplications[params.Name]; policy != nil {
+		return *policy, nil
+	}
+	return nil, nil
+}
+
+func (s *Server) validateReplication(params nb.BucketReplicationParams) (interface{}, error) {
+	_, err := s.findBucket(params.Name)
+	return nil, err
+}
+
+func (s *Server) deleteBucketReplication(params nb.DeleteBucketReplicationParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	delete(s.BucketReplications, params.Name)
+	return nil, nil
+}
+
+func (s *Server) setBucketLifecycle(params nb.BucketLifecycleParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	s.BucketLifecycles[params.Name] = params.Rules
+	return nil, nil
+}
+
+func (s *Server) deleteBucketLifecycle(params nb.DeleteBucketLifecycleParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	delete(s.BucketLifecycles, params.Name)
+	return nil, nil
+}
+
+func (s *Server) putBucketPolicy(params nb.PutBucketPolicyParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	policy := params.Policy
+	s.BucketPolicies[params.Name] = &policy
+	return nil, nil
+}
+
+func (s *Server) getBucketPolicy(params nb.GetBucketPolicyParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	policy := s.BucketPolicies[params.Name]
+	if policy == nil {
+		return nil, rpcError("NO_SUCH_BUCKET_POLICY", "bucket policy not found %s", params.Name)
+	}
+	return nb.GetBucketPolicyReply{Policy: policy}, nil
+}
+
+func (s *Server) deleteBucketPolicy(params nb.DeleteBucketPolicyParams) (interface{}, error) {
+	if _, err := s.findBucket(params.Name); err != nil {
+		return nil, err
+	}
+	delete(s.BucketPolicies, params.Name)
+	return nil, nil
+}
+
+func (s *Server) createVectorBucket(params nb.CreateVectorBucketParams) (interface{}, error) {
+	if s.VectorBuckets[params.VectorBucketName] != nil {
+		return nil, rpcError("BUCKET_ALREADY_EXISTS", "vector bucket already exists %s", params.VectorBucketName)
+	}
+	if params.NamespaceResource != nil && s.NamespaceResources[params.NamespaceResource.Resource] == nil {
+		return nil, rpcError("NO_SUCH_NAMESPACE_RESOURCE", "namespace resource not found %s", params.NamespaceResource.Resource)
+	}
+	s.nextID++
+	bucket := &nb.VectorBucketInfo{
+		Name:              params.VectorBucketName,
+		VectorDBType:      params.VectorDBType,
+		NamespaceResource: params.NamespaceResource,
+		BucketClaim:       params.BucketClaim,
+		CreationTime:      int64(s.nextID),
+	}
+	s.VectorBuckets[params.VectorBucketName] = bucket
+	return *bucket, nil
+}
+
+func (s *Server) getVectorBucket(params nb.GetVectorBucketParams) (interface{}, error) {
+	bucket := s.VectorBuckets[params.VectorBucketName]
+	if bucket == nil {
+		return nil, rpcError("NO_SUCH_BUCKET", "vector bucket not found %s", params.VectorBucketName)
+	}
+	return *bucket, nil
+}
+
+func (s *Server) deleteVectorBucket(params nb.DeleteVectorBucketParams) (interface{}, error) {
+	if s.VectorBuckets[params.VectorBucketName] == nil {
+		return nil, rpcError("NO_SUCH_BUCKET", "vector bucket not found %s", params.VectorBucketName)
+	}
+	delete(s.VectorBuckets, params.VectorBucketName)
+	return nil, nil
+}
+
+// recode converts a value to another type through its json encoding,
+// used for the anonymous structs of the api types
+func recode(from interface{}, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// page returns the sorted keys after the continuation token, up to max keys,
+// and the token of the next page - the last key returned, when more keys are left
+func page(keys []string, token *string, max *int) ([]string, string) {
+	if token != nil {
+		keys = keys[sort.SearchStrings(keys, *token):]
+		if len(keys) > 0 && keys[0] == *token {
+			keys = keys[1:]
+		}
+	}
+	if max == nil || *max <= 0 || len(keys) <= *max {
+		return keys, ""
+	}
+	keys = keys[:*max]
+	return keys, keys[len(keys)-1]
+}
+
+// sortedKeys returns the keys of a map in order, so replies are deterministic
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
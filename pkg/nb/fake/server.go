@@ -0,0 +1,252 @@
+// Package fake provides an in-memory fake of the noobaa core RPC server,
+// for unit tests of code that calls nb.Client without a running noobaa system.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+)
+
+const (
+	// Address is the rpc address of the fake server
+	Address = "fake://noobaa-mgmt/rpc/"
+
+	// SystemName is the name of the fake system
+	SystemName = "noobaa"
+
+	// OperatorEmail is the email of the account that owns the external connections,
+	// like the operator account of a real system
+	OperatorEmail = "operator@noobaa.io"
+)
+
+// Call is a recorded call of the fake server
+type Call struct {
+	API    string
+	Method string
+	Params json.RawMessage
+	Error  error
+}
+
+// DecodeParams decodes the params of the call into the params struct of the method
+func (c *Call) DecodeParams(params interface{}) error {
+	return json.Unmarshal(c.Params, params)
+}
+
+// injectedError is an error that the next calls of a method fail with
+type injectedError struct {
+	err   error
+	times int
+}
+
+// handler serves a method of the fake server, called with the server lock held
+type handler func(params json.RawMessage) (interface{}, error)
+
+// Server is an in-memory fake of the noobaa core RPC server.
+// It keeps a model of the pools, hosts, namespace resources, accounts, buckets, tiers,
+// tiering policies and external connections of a system, and serves every method of nb.Client from it.
+// All the calls are recorded, and errors can be injected per API method.
+type Server struct {
+	Lock sync.Mutex
+
+	Pools               map[string]*nb.PoolInfo
+	Hosts               map[string][]nb.HostInfo
+	NamespaceResources  map[string]*nb.NamespaceResourceInfo
+	NamespaceStores     map[string]nb.NamespaceStoreInfo
+	Accounts            map[string]*nb.AccountInfo
+	Buckets             map[string]*nb.BucketInfo
+	BucketPolicies      map[string]*nb.BucketPolicy
+	BucketReplications  map[string]*nb.ReplicationPolicy
+	BucketLifecycles    map[string][]nb.LifecycleRuleConfig
+	VectorBuckets       map[string]*nb.VectorBucketInfo
+	Tiers               map[string]*nb.TierInfo
+	TieringPolicies     map[string]*nb.TieringPolicyInfo
+	ExternalConnections map[string]*nb.ExternalConnectionInfo
+	// ExternalConnectionStatus is the status replied by check_external_connection
+	ExternalConnectionStatus nb.ExternalConnectionStatus
+	// SystemState is the state replied by get_system_status
+	SystemState string
+
+	calls    []Call
+	errors   map[string]*injectedError
+	handlers map[string]handler
+	nextID   int
+}
+
+// Conn is the in-memory connection of the fake server
+type Conn struct {
+	Server *Server
+}
+
+var _ nb.RPCConn = &Conn{}
+
+// NewServer returns a fake server of a system with the operator account
+func NewServer() *Server {
+	s := &Server{
+		Pools:                    map[string]*nb.PoolInfo{},
+		Hosts:                    map[string][]nb.HostInfo{},
+		NamespaceResources:       map[string]*nb.NamespaceResourceInfo{},
+		NamespaceStores:          map[string]nb.NamespaceStoreInfo{},
+		Accounts:                 map[string]*nb.AccountInfo{},
+		Buckets:                  map[string]*nb.BucketInfo{},
+		BucketPolicies:           map[string]*nb.BucketPolicy{},
+		BucketReplications:       map[string]*nb.ReplicationPolicy{},
+		BucketLifecycles:         map[string][]nb.LifecycleRuleConfig{},
+		VectorBuckets:            map[string]*nb.VectorBucketInfo{},
+		Tiers:                    map[string]*nb.TierInfo{},
+		TieringPolicies:          map[string]*nb.TieringPolicyInfo{},
+		ExternalConnections:      map[string]*nb.ExternalConnectionInfo{},
+		ExternalConnectionStatus: nb.ExternalConnectionSuccess,
+		SystemState:              "READY",
+		errors:                   map[string]*injectedError{},
+	}
+	s.Accounts[OperatorEmail] = &nb.AccountInfo{
+		Name:        "operator",
+		Email:       OperatorEmail,
+		HasS3Access: true,
+	}
+	s.registerHandlers()
+	return s
+}
+
+// Client returns a client whose calls are served by the fake server.
+// Retries of idempotent methods are immediate to keep the tests fast.
+func (s *Server) Client() nb.Client {
+	rpc := nb.NewRPC()
+	rpc.AddConnection(&Conn{Server: s})
+	return &nb.RPCClient{
+		RPC:    rpc,
+		Router: &nb.SimpleRouter{Address: Address},
+		Policy: nb.RPCCallPolicy{
+			Timeout:         nb.DefaultRPCCallPolicy.Timeout,
+			MaxRetries:      nb.DefaultRPCCallPolicy.MaxRetries,
+			RetryBackoff:    time.Millisecond,
+			MaxRetryBackoff: time.Millisecond,
+		},
+	}
+}
+
+// InjectError makes the next calls of the method fail with the error, or all the calls when times <= 0.
+// An *nb.RPCError is replied by the server like an error of noobaa core,
+// any other error fails the call like a transport error, for example when core is down.
+func (s *Server) InjectError(api, method string, err error, times int) {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+	s.errors[api+"."+method] = &injectedError{err: err, times: times}
+}
+
+// ClearErrors removes all the injected errors
+func (s *Server) ClearErrors() {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+	s.errors = map[string]*injectedError{}
+}
+
+// Calls returns the recorded calls in the order they were served
+func (s *Server) Calls() []Call {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the recorded calls of a method
+func (s *Server) CallsTo(api, method string) []Call {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+	var calls []Call
+	for _, c := range s.calls {
+		if c.API == api && c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Authenticate returns the email of the account that S3 requests signed with the keys are authenticated as,
+// or an empty string when the keys are not valid keys of any account
+func (s *Server) Authenticate(accessKey, secretKey string) string {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+	for email, account := range s.Accounts {
+		for _, keys := range account.AccessKeys {
+			if string(keys.AccessKey) == accessKey && string(keys.SecretKey) == secretKey {
+				return email
+			}
+		}
+	}
+	return ""
+}
+
+// ResetCalls clears the recorded calls
+func (s *Server) ResetCalls() {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+	s.calls = nil
+}
+
+// serve handles a request and returns the reply or the error of the method
+func (s *Server) serve(req *nb.RPCMessage) (interface{}, error) {
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		return nil, err
+	}
+	key := req.API + "." + req.Method
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	var reply interface{}
+	if injected := s.errors[key]; injected != nil {
+		err = injected.err
+		if injected.times > 0 {
+			injected.times--
+			if injected.times == 0 {
+				delete(s.errors, key)
+			}
+		}
+	} else if h := s.handlers[key]; h != nil {
+		reply, err = h(params)
+	} else {
+		err = &nb.RPCError{RPCCode: "NO_SUCH_RPC_SERVICE", Message: fmt.Sprintf("fake server does not implement %s()", key)}
+	}
+	s.calls = append(s.calls, Call{API: req.API, Method: req.Method, Params: params, Error: err})
+	return reply, err
+}
+
+// GetAddress returns the connection address
+func (c *Conn) GetAddress() string {
+	return Address
+}
+
+// Reconnect is doing nothing for the in-memory connection
+func (c *Conn) Reconnect() {
+}
+
+// Call serves the request by the fake server and decodes the reply into the response,
+// the same way a reply of noobaa core is decoded
+func (c *Conn) Call(ctx context.Context, req *nb.RPCMessage, res nb.RPCResponse) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	reply, err := c.Server.serve(req)
+	msg := &nb.RPCMessageReply{
+		RPCMessage: nb.RPCMessage{Op: "res", RequestID: req.RequestID},
+		Reply:      reply,
+	}
+	if err != nil {
+		rpcErr, ok := err.(*nb.RPCError)
+		if !ok {
+			return err
+		}
+		msg.Error = rpcErr
+	}
+	resBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resBytes, res)
+}
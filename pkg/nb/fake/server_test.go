@@ -0,0 +1,182 @@
+package fake
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+)
+
+func TestCloudPoolLifecycle(t *testing.T) {
+	s := NewServer()
+	c := s.Client()
+
+	if _, err := c.ReadPoolAPI(nb.ReadPoolParams{Name: "aws"}); !hasCode(err, "NO_SUCH_POOL") {
+		t.Fatalf("expected NO_SUCH_POOL, got %v", err)
+	}
+	err := c.AddExternalConnectionAPI(nb.AddExternalConnectionParams{
+		Name:         "aws",
+		EndpointType: nb.EndpointTypeAws,
+		Endpoint:     "https://s3.amazonaws.com",
+		Identity:     "AKIA",
+		Secret:       "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateCloudPoolAPI(nb.CreateCloudPoolParams{Name: "aws", Connection: "aws", TargetBucket: "target"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pool, err := c.ReadPoolAPI(nb.ReadPoolParams{Name: "aws"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.ResourceType != "CLOUD" || pool.CloudInfo == nil || pool.CloudInfo.TargetBucket != "target" || pool.CloudInfo.Identity != "AKIA" {
+		t.Fatalf("unexpected pool %+v", pool)
+	}
+
+	sys, err := c.ReadSystemAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sys.Pools) != 1 || len(sys.Accounts) != 1 || len(sys.Accounts[0].ExternalConnections.Connections) != 1 {
+		t.Fatalf("unexpected system %+v", sys)
+	}
+
+	if err := c.CreateTierAPI(nb.CreateTierParams{Name: "tier", AttachedPools: []string{"aws"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeletePoolAPI(nb.DeletePoolParams{Name: "aws"}); !hasCode(err, "IN_USE") {
+		t.Fatalf("expected IN_USE, got %v", err)
+	}
+}
+
+func TestBucketsAndAccounts(t *testing.T) {
+	s := NewServer()
+	c := s.Client()
+
+	if err := c.CreateBucketAPI(nb.CreateBucketParams{Name: "first.bucket"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateBucketAPI(nb.CreateBucketParams{Name: "first.bucket"}); !hasCode(err, "BUCKET_ALREADY_EXISTS") {
+		t.Fatalf("expected BUCKET_ALREADY_EXISTS, got %v", err)
+	}
+	if err := c.CreateBucketAPI(nb.CreateBucketParams{Name: "other", Tiering: "missing"}); !hasCode(err, "NO_SUCH_TIERING_POLICY") {
+		t.Fatalf("expected NO_SUCH_TIERING_POLICY, got %v", err)
+	}
+	buckets, err := c.ListBucketsAPI(nb.ListBucketsParams{})
+	if err != nil || len(buckets.Buckets) != 1 || buckets.Buckets[0].Name != "first.bucket" {
+		t.Fatalf("unexpected buckets %+v %v", buckets, err)
+	}
+
+	reply, err := c.CreateAccountAPI(nb.CreateAccountParams{Name: "user", Email: "user", S3Access: true})
+	if err != nil || len(reply.AccessKeys) != 1 {
+		t.Fatalf("unexpected reply %+v %v", reply, err)
+	}
+	newEmail := "user2"
+	if err := c.UpdateAccount(nb.UpdateAccountParams{Email: "user", NewEmail: &newEmail}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadAccountAPI(nb.ReadAccountParams{Email: "user"}); !hasCode(err, "NO_SUCH_ACCOUNT") {
+		t.Fatalf("expected NO_SUCH_ACCOUNT, got %v", err)
+	}
+	account, err := c.ReadAccountAPI(nb.ReadAccountParams{Email: "user2"})
+	if err != nil || account.AccessKeys[0].AccessKey != reply.AccessKeys[0].AccessKey {
+		t.Fatalf("unexpected account %+v %v", account, err)
+	}
+}
+
+func TestHostDecommission(t *testing.T) {
+	s := NewServer()
+	c := s.Client()
+	s.AddHost("pv-pool", nb.HostInfo{Name: "pv-pool-pod-0"})
+
+	storage := false
+	err := c.UpdateHostServicesAPI(nb.UpdateHostServicesParams{Name: "pv-pool-pod-0", Services: nb.HostServicesSettings{Storage: &storage}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := c.ListHostsAPI(nb.ListHostsParams{Query: nb.ListHostsQuery{Pools: []string{"pv-pool"}}})
+	if err != nil || len(hosts.Hosts) != 1 || hosts.Hosts[0].Mode != "DECOMMISSIONING" {
+		t.Fatalf("unexpected hosts %+v %v", hosts, err)
+	}
+	if err := c.DeleteHostAPI(nb.DeleteHostParams{Name: "pv-pool-pod-0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteHostAPI(nb.DeleteHostParams{Name: "pv-pool-pod-0"}); !hasCode(err, "NO_SUCH_HOST") {
+		t.Fatalf("expected NO_SUCH_HOST, got %v", err)
+	}
+}
+
+func TestInjectErrorAndRecorder(t *testing.T) {
+	s := NewServer()
+	c := s.Client()
+
+	// transport errors of read methods are retried by the client
+	s.InjectError("system_api", "read_system", errors.New("connection refused"), 2)
+	if _, err := c.ReadSystemAPI(); err != nil {
+		t.Fatalf("expected the retries to succeed, got %v", err)
+	}
+	if n := len(s.CallsTo("system_api", "read_system")); n != 3 {
+		t.Fatalf("expected 3 calls, got %d", n)
+	}
+
+	// rpc errors are replied as is
+	s.InjectError("bucket_api", "create_bucket", &nb.RPCError{RPCCode: "INTERNAL", Message: "boom"}, 0)
+	for i := 0; i < 2; i++ {
+		if err := c.CreateBucketAPI(nb.CreateBucketParams{Name: "b"}); !hasCode(err, "INTERNAL") {
+			t.Fatalf("expected INTERNAL, got %v", err)
+		}
+	}
+	s.ClearErrors()
+	if err := c.CreateBucketAPI(nb.CreateBucketParams{Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := s.CallsTo("bucket_api", "create_bucket")
+	if len(calls) != 3 || calls[0].Error == nil || calls[2].Error != nil {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+	params := nb.CreateBucketParams{}
+	if err := calls[2].DecodeParams(&params); err != nil || params.Name != "b" {
+		t.Fatalf("unexpected params %+v %v", params, err)
+	}
+
+	s.ResetCalls()
+	if len(s.Calls()) != 0 {
+		t.Fatalf("expected no calls after reset")
+	}
+}
+
+func hasCode(err error, code string) bool {
+	var rpcErr *nb.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.RPCCode == code
+}
+
+func TestListPagination(t *testing.T) {
+	s := NewServer()
+	c := s.Client()
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		s.Buckets[name] = &nb.BucketInfo{Name: name}
+	}
+
+	max := 2
+	page, err := c.ListBucketsAPI(nb.ListBucketsParams{MaxBuckets: &max})
+	if err != nil || len(page.Buckets) != 2 || page.ContinuationToken != "b" {
+		t.Fatalf("unexpected first page %+v %v", page, err)
+	}
+	page, err = c.ListBucketsAPI(nb.ListBucketsParams{MaxBuckets: &max, ContinuationToken: &page.ContinuationToken})
+	if err != nil || len(page.Buckets) != 2 || page.Buckets[0].Name != "c" || page.ContinuationToken != "d" {
+		t.Fatalf("unexpected second page %+v %v", page, err)
+	}
+
+	names, err := nb.ListAllBuckets(c, 2)
+	if err != nil || len(names) != 5 || names[4] != "e" {
+		t.Fatalf("unexpected buckets %v %v", names, err)
+	}
+	if n := len(s.CallsTo("bucket_api", "list_buckets")); n != 5 {
+		t.Fatalf("expected 3 pages after the 2 single page calls, got %d calls", n)
+	}
+}
@@ -0,0 +1,42 @@
+package nb
+
+import "fmt"
+
+// DefaultListPageSize is the number of items requested in every page of the ListAll helpers
+const DefaultListPageSize = 1000
+
+// ListAllBuckets lists the names of all the buckets, a page of pageSize buckets per call.
+// Servers that do not paginate reply all the buckets without a continuation token in the first call.
+func ListAllBuckets(c Client, pageSize int) ([]string, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	names := []string{}
+	params := ListBucketsParams{MaxBuckets: &pageSize}
+	for {
+		reply, err := c.ListBucketsAPI(params)
+		if err != nil {
+			return nil, err
+		}
+		for i := range reply.Buckets {
+			names = append(names, reply.Buckets[i].Name)
+		}
+		next, err := nextPageToken(params.ContinuationToken, reply.ContinuationToken, len(reply.Buckets))
+		if next == nil || err != nil {
+			return names, err
+		}
+		params.ContinuationToken = next
+	}
+}
+
+// nextPageToken returns the token of the next page, or nil after the last page.
+// A page that does not move the token forward is an error, to avoid listing forever.
+func nextPageToken(current *string, next string, count int) (*string, error) {
+	if next == "" {
+		return nil, nil
+	}
+	if count == 0 || (current != nil && *current == next) {
+		return nil, fmt.Errorf("list pagination did not advance from continuation token %q", next)
+	}
+	return &next, nil
+}
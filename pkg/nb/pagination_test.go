@@ -0,0 +1,32 @@
+package nb
+
+import "testing"
+
+func TestNextPageToken(t *testing.T) {
+	token := "b"
+	tests := []struct {
+		name    string
+		current *string
+		next    string
+		count   int
+		want    string
+		wantErr bool
+	}{
+		{"last page", nil, "", 2, "", false},
+		{"first page", nil, "b", 2, "b", false},
+		{"next page", &token, "d", 2, "d", false},
+		{"token did not advance", &token, "b", 2, "", true},
+		{"empty page with a token", &token, "d", 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextPageToken(tt.current, tt.next, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if (got == nil && tt.want != "") || (got != nil && *got != tt.want) {
+				t.Fatalf("expected token %q, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,7 +1,15 @@
 package nb
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -32,10 +40,18 @@ func init() {
 
 // RPC is a struct that describes the relevant fields upon handeling rpc protocol
 type RPC struct {
-	HTTPClient  http.Client
-	ConnMap     map[string]RPCConn
-	ConnMapLock sync.Mutex
-	Handler     RPCHandler
+	HTTPClient   http.Client
+	ConnMap      map[string]RPCConn
+	ConnMapLock  sync.Mutex
+	Handler      RPCHandler
+	Breakers     map[string]*RPCCircuitBreaker
+	BreakersLock sync.Mutex
+
+	// SecureHTTPClient is used for cluster service addresses once EnableTLSVerification
+	// loaded the CA that signs the service certificates, see HTTPClientFor()
+	SecureHTTPClient *http.Client
+	secureCA         []byte
+	secureLock       sync.Mutex
 }
 
 // RPCClient makes API calls to noobaa.
@@ -44,6 +60,8 @@ type RPCClient struct {
 	RPC       *RPC
 	Router    APIRouter
 	AuthToken string
+	Policy    RPCCallPolicy
+	Ctx       context.Context
 }
 
 // RPCConn is a common connection interface implemented by http and ws
@@ -52,8 +70,8 @@ type RPCConn interface {
 	GetAddress() string
 	// Reonnect should make sure the connection is ready to be used
 	Reconnect()
-	// Call sends request and receives the response
-	Call(req *RPCMessage, res RPCResponse) error
+	// Call sends request and receives the response, or fails when the context is done
+	Call(ctx context.Context, req *RPCMessage, res RPCResponse) error
 }
 
 // RPCMessage structure encoded in every RPC message
@@ -104,6 +122,14 @@ func (c *RPCClient) SetAuthToken(token string) { c.AuthToken = token }
 // GetAuthToken is getting the client token for next calls
 func (c *RPCClient) GetAuthToken() string { return c.AuthToken }
 
+// WithContext returns a copy of the client whose calls are bound to the context,
+// so every API method of the returned client fails once the context is done
+func (c *RPCClient) WithContext(ctx context.Context) Client {
+	clone := *c
+	clone.Ctx = ctx
+	return &clone
+}
+
 // Error is implementing the standard error type interface
 func (e *RPCError) Error() string { return e.Message }
 
@@ -133,21 +159,35 @@ func NewRPC() *RPC {
 		},
 		ConnMap:     make(map[string]RPCConn),
 		ConnMapLock: sync.Mutex{},
+		Breakers:    make(map[string]*RPCCircuitBreaker),
 	}
 }
 
-// NewClient initializes an RPCClient with defaults
+// NewClient initializes an RPCClient with the call policy of the options
 func NewClient(router APIRouter) Client {
 	return &RPCClient{
 		Router: router,
 		RPC:    GlobalRPC,
+		Policy: OptionsRPCCallPolicy(),
 	}
 }
 
-// Call an API method to noobaa over wss or https protocol
+// Call an API method to noobaa over wss or https protocol, bound to the context of the client.
 // The response type should be defined to include RPCResponse inline.
 // This is needed in order for json.Unmarshal() to decode into the reply structure.
 func (c *RPCClient) Call(req *RPCMessage, res RPCResponse) error {
+	ctx := c.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.CallContext(ctx, req, res)
+}
+
+// CallContext calls an API method to noobaa and fails once the context is done.
+// Every attempt is limited by the timeout of the call policy, and idempotent read methods
+// are retried with backoff on transport errors. Calls to an address whose circuit breaker
+// is open fail fast with ErrCircuitOpen.
+func (c *RPCClient) CallContext(ctx context.Context, req *RPCMessage, res RPCResponse) error {
 	if res == nil {
 		res = &RPCMessage{}
 	}
@@ -158,16 +198,45 @@ func (c *RPCClient) Call(req *RPCMessage, res RPCResponse) error {
 	}
 
 	address := c.Router.GetAddress(api)
+	breaker := c.RPC.GetCircuitBreaker(address)
+	policy := c.Policy.withDefaults()
+	retries := 0
+	if IsIdempotentMethod(method) {
+		retries = policy.MaxRetries
+	}
 
 	// u := address + strings.TrimSuffix(api, "_api") + "/" + method
 	u := strings.TrimSuffix(api, "_api") + "." + method + "()"
 	logrus.Infof("✈️  RPC: %s Request: %+v", u, req.Params)
 
-	conn := c.RPC.GetConnection(address)
-	err := conn.Call(req, res)
-	if err != nil {
-		logrus.Errorf("⚠️  RPC: %s Call failed: %s", u, err)
-		return err
+	for attempt := 0; ; attempt++ {
+		err := breaker.Allow()
+		if err != nil {
+			logrus.Errorf("⚠️  RPC: %s Call failed: %s", u, err)
+			return err
+		}
+
+		err = c.callOnce(ctx, policy, address, req, res)
+		if err == nil {
+			breaker.Success()
+			break
+		}
+		if ctx.Err() != nil {
+			// the caller gave up, which says nothing about the health of the server
+			breaker.Cancel()
+			logrus.Errorf("⚠️  RPC: %s Call failed: %s", u, err)
+			return err
+		}
+		breaker.Failure()
+		if attempt >= retries {
+			logrus.Errorf("⚠️  RPC: %s Call failed: %s", u, err)
+			return err
+		}
+		delay := policy.Backoff(attempt)
+		logrus.Warnf("⚠️  RPC: %s Call failed: %s, retry %d/%d in %s", u, err, attempt+1, retries, delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
 	}
 
 	r := res.Response()
@@ -180,25 +249,113 @@ func (c *RPCClient) Call(req *RPCMessage, res RPCResponse) error {
 	return nil
 }
 
+// callOnce sends a single attempt of the request, limited by the timeout of the policy
+func (c *RPCClient) callOnce(ctx context.Context, policy RPCCallPolicy, address string, req *RPCMessage, res RPCResponse) error {
+	attemptCtx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+	conn := c.RPC.GetConnection(address)
+	err := conn.Call(attemptCtx, req, res)
+	if err != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("RPC: no reply from %s within %s: %w", address, policy.Timeout, err)
+	}
+	return err
+}
+
 // GetConnection finds the connection related to the pending request or creates a new one
 func (r *RPC) GetConnection(address string) RPCConn {
-	var conn RPCConn
-	if strings.HasPrefix(address, "wss:") || strings.HasPrefix(address, "ws:") {
-		r.ConnMapLock.Lock()
-		conn = r.ConnMap[address]
-		if conn == nil {
-			conn = NewRPCConnWS(r, address)
-			logrus.Warnf("RPC: GetConnection creating connection to %s %p", address, conn)
-			r.ConnMap[address] = conn
-		}
-		r.ConnMapLock.Unlock()
-	} else {
+	r.ConnMapLock.Lock()
+	conn := r.ConnMap[address]
+	if conn == nil && (strings.HasPrefix(address, "wss:") || strings.HasPrefix(address, "ws:")) {
+		conn = NewRPCConnWS(r, address)
+		logrus.Warnf("RPC: GetConnection creating connection to %s %p", address, conn)
+		r.ConnMap[address] = conn
+	}
+	r.ConnMapLock.Unlock()
+	if conn == nil {
 		// http connections are transient and not inserted to ConnMap!
 		conn = NewRPCConnHTTP(r, address)
 	}
 	return conn
 }
 
+// AddConnection registers a connection that GetConnection returns for its address,
+// used for connections that are not dialed by the RPC such as in-memory fakes
+func (r *RPC) AddConnection(conn RPCConn) {
+	r.ConnMapLock.Lock()
+	r.ConnMap[conn.GetAddress()] = conn
+	r.ConnMapLock.Unlock()
+}
+
+// GetCircuitBreaker returns the circuit breaker of the address, creating it on first use
+func (r *RPC) GetCircuitBreaker(address string) *RPCCircuitBreaker {
+	r.BreakersLock.Lock()
+	defer r.BreakersLock.Unlock()
+	if r.Breakers == nil {
+		r.Breakers = make(map[string]*RPCCircuitBreaker)
+	}
+	breaker := r.Breakers[address]
+	if breaker == nil {
+		breaker = NewRPCCircuitBreaker(address)
+		r.Breakers[address] = breaker
+	}
+	return breaker
+}
+
+// EnableTLSVerification loads the CA file that signs the cluster service certificates,
+// such as the service-serving CA of openshift, and verifies the certificates of the connections
+// to cluster service addresses from now on. Loading the same CA again keeps the current client
+// and its pooled connections.
+func (r *RPC) EnableTLSVerification(caFile string) error {
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return err
+	}
+	r.secureLock.Lock()
+	defer r.secureLock.Unlock()
+	if r.SecureHTTPClient != nil && bytes.Equal(ca, r.secureCA) {
+		return nil
+	}
+	rootCAs, _ := x509.SystemCertPool()
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(ca) {
+		return fmt.Errorf("RPC: failed to append %q to RootCAs", caFile)
+	}
+	r.SecureHTTPClient = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+		},
+	}
+	r.secureCA = ca
+	logrus.Infof("RPC: verifying the TLS certificates of cluster services using %q", caFile)
+	return nil
+}
+
+// HTTPClientFor returns the http client for the address.
+// Only cluster service addresses (*.svc, *.svc.cluster.local) are verified, since these are the
+// names that the service-serving CA signs, while port-forwarded and external addresses of
+// remote systems keep using the insecure client.
+func (r *RPC) HTTPClientFor(address string) *http.Client {
+	r.secureLock.Lock()
+	defer r.secureLock.Unlock()
+	if r.SecureHTTPClient != nil && isClusterServiceAddress(address) {
+		return r.SecureHTTPClient
+	}
+	return &r.HTTPClient
+}
+
+// isClusterServiceAddress returns true when the address host is a cluster service DNS name
+func isClusterServiceAddress(address string) bool {
+	u, err := url.Parse(address)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	return strings.HasSuffix(host, ".svc") || strings.HasSuffix(host, ".svc.cluster.local")
+}
+
 // RemoveConnection removes the connection from the RPC connections map and start reconnecting
 func (r *RPC) RemoveConnection(conn RPCConn) {
 	r.ConnMapLock.Lock()
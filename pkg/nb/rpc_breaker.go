@@ -0,0 +1,96 @@
+package nb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// RPCCircuitBreakerThreshold is the number of consecutive failed calls that opens the circuit
+	RPCCircuitBreakerThreshold = 5
+
+	// RPCCircuitBreakerCooldown is the time calls fail fast before a probe call is let through
+	RPCCircuitBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned for calls that are not sent because the server is considered down
+var ErrCircuitOpen = errors.New("RPC: circuit breaker is open")
+
+// RPCCircuitBreaker fails calls fast while the server of an address is down.
+// After RPCCircuitBreakerThreshold consecutive transport failures the circuit opens and calls fail
+// with ErrCircuitOpen for RPCCircuitBreakerCooldown. Then a single probe call is let through (half open),
+// which closes the circuit when it succeeds or opens it for another cooldown when it fails.
+type RPCCircuitBreaker struct {
+	Address   string
+	Threshold int
+	Cooldown  time.Duration
+
+	lock      sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+// NewRPCCircuitBreaker returns a closed circuit breaker with the default threshold and cooldown
+func NewRPCCircuitBreaker(address string) *RPCCircuitBreaker {
+	return &RPCCircuitBreaker{
+		Address:   address,
+		Threshold: RPCCircuitBreakerThreshold,
+		Cooldown:  RPCCircuitBreakerCooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen when the call should not be sent
+func (b *RPCCircuitBreaker) Allow() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.failures < b.Threshold {
+		return nil
+	}
+	if remaining := b.openUntil.Sub(b.now()); remaining > 0 {
+		return fmt.Errorf("%w for %s, retry in %s", ErrCircuitOpen, b.Address, remaining.Round(time.Second))
+	}
+	if b.probing {
+		return fmt.Errorf("%w for %s, waiting for a probe call", ErrCircuitOpen, b.Address)
+	}
+	b.probing = true
+	return nil
+}
+
+// Success closes the circuit
+func (b *RPCCircuitBreaker) Success() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.failures >= b.Threshold {
+		logrus.Infof("RPC: circuit breaker for %s closed", b.Address)
+	}
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure counts a failed call and opens the circuit when reaching the threshold
+func (b *RPCCircuitBreaker) Failure() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.Threshold {
+		b.openUntil = b.now().Add(b.Cooldown)
+		logrus.Warnf("RPC: circuit breaker for %s open after %d failed calls, failing fast for %s",
+			b.Address, b.failures, b.Cooldown)
+	}
+}
+
+// Cancel releases a call that ended without telling whether the server is healthy,
+// such as a call whose context was canceled by the caller
+func (b *RPCCircuitBreaker) Cancel() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.probing = false
+}
@@ -0,0 +1,101 @@
+package nb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// rpcFrameHeaderSize is the size of the frame header - the version number and the body length
+const rpcFrameHeaderSize = 8
+
+// EncodeRPCFrame encodes a message to the binary frame of the websocket transport:
+//
+//	| version (uint32 BE) | body length (uint32 BE) | json body | buffers |
+//
+// The buffers are appended as raw bytes after the json body, in the order they are listed
+// in the buffers field of the message, so bulk data is not encoded as json.
+func EncodeRPCFrame(msg interface{}, buffers []RPCBuffer) ([]byte, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > RPCMaxMessageSize {
+		return nil, fmt.Errorf("RPC: message body too big %d", len(body))
+	}
+	size := rpcFrameHeaderSize + len(body)
+	for i := range buffers {
+		if int(buffers[i].Length) != len(buffers[i].Buffer) {
+			return nil, fmt.Errorf("RPC: buffer %q length %d does not match its data %d",
+				buffers[i].Name, buffers[i].Length, len(buffers[i].Buffer))
+		}
+		size += len(buffers[i].Buffer)
+	}
+	if size > RPCMaxMessageSize {
+		return nil, fmt.Errorf("RPC: message too big %d", size)
+	}
+	frame := make([]byte, rpcFrameHeaderSize, size)
+	binary.BigEndian.PutUint32(frame[0:4], RPCVersionNumber)
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(body)))
+	frame = append(frame, body...)
+	for i := range buffers {
+		frame = append(frame, buffers[i].Buffer...)
+	}
+	return frame, nil
+}
+
+// DecodeRPCFrame decodes a binary frame of the websocket transport.
+// The json body is kept in RawBytes so the reply can be decoded later to the response type of the caller,
+// and the buffers of the message are sliced from the frame without copying.
+func DecodeRPCFrame(frame []byte) (*RPCMessage, error) {
+	if len(frame) < rpcFrameHeaderSize {
+		return nil, fmt.Errorf("RPC Error: short read")
+	}
+	rpcVersionNumber := binary.BigEndian.Uint32(frame[0:4])
+	bodySize := binary.BigEndian.Uint32(frame[4:8])
+	if rpcVersionNumber != RPCVersionNumber {
+		return nil, fmt.Errorf("RPC: mismatch RPC version number expected %d received %d", RPCVersionNumber, rpcVersionNumber)
+	}
+	if bodySize > RPCMaxMessageSize {
+		return nil, fmt.Errorf("RPC: message body too big %d", bodySize)
+	}
+	if uint64(len(frame)-rpcFrameHeaderSize) < uint64(bodySize) {
+		return nil, fmt.Errorf("RPC Error: short read")
+	}
+
+	msgBytes := frame[rpcFrameHeaderSize : rpcFrameHeaderSize+int(bodySize)]
+	msg := &RPCMessage{}
+	if err := json.Unmarshal(msgBytes, msg); err != nil {
+		return nil, err
+	}
+	msg.RawBytes = msgBytes
+
+	buffers := frame[rpcFrameHeaderSize+int(bodySize):]
+	total := 0
+	for i := range msg.Buffers {
+		if msg.Buffers[i].Length < 0 {
+			return nil, fmt.Errorf("RPC: invalid buffer length %d", msg.Buffers[i].Length)
+		}
+		total += int(msg.Buffers[i].Length)
+	}
+	if total > len(buffers) {
+		return nil, fmt.Errorf("RPC Error: short read of buffers %d < %d", len(buffers), total)
+	}
+	if len(msg.Buffers) > 0 {
+		msg.SetBuffers(buffers)
+	}
+	return msg, nil
+}
+
+// DecodeReply decodes the json body of a received message into the response of the caller,
+// and assigns the buffers of the message to the response
+func (msg *RPCMessage) DecodeReply(res RPCResponse) error {
+	if err := json.Unmarshal(msg.RawBytes, res); err != nil {
+		return err
+	}
+	r := res.Response()
+	if r != msg && len(msg.Buffers) > 0 {
+		r.Buffers = msg.Buffers
+	}
+	return nil
+}
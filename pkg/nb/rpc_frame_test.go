@@ -0,0 +1,159 @@
+package nb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"nhooyr.io/websocket"
+)
+
+func TestRPCFrameRoundTrip(t *testing.T) {
+	data := []byte("0123456789")
+	msg := &RPCMessage{
+		Op:        "res",
+		RequestID: "ws://noobaa-mgmt-7",
+		Buffers:   []RPCBuffer{{Name: "a", Length: 4, Buffer: data[:4]}, {Name: "b", Length: 6, Buffer: data[4:]}},
+	}
+	frame, err := EncodeRPCFrame(msg, msg.Buffers)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	decoded, err := DecodeRPCFrame(frame)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if decoded.Op != msg.Op || decoded.RequestID != msg.RequestID || len(decoded.Buffers) != 2 {
+		t.Fatalf("decoded message %+v does not match %+v", decoded, msg)
+	}
+	for i := range msg.Buffers {
+		if !bytes.Equal(decoded.Buffers[i].Buffer, msg.Buffers[i].Buffer) {
+			t.Fatalf("buffer %d: got %q expected %q", i, decoded.Buffers[i].Buffer, msg.Buffers[i].Buffer)
+		}
+	}
+
+	res := &RPCMessageReply{}
+	if err := decoded.DecodeReply(res); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if res.RequestID != msg.RequestID || len(res.Buffers) != 2 || !bytes.Equal(res.Buffers[1].Buffer, data[4:]) {
+		t.Fatalf("decoded reply %+v does not match %+v", res, msg)
+	}
+}
+
+func TestRPCFrameErrors(t *testing.T) {
+	valid, err := EncodeRPCFrame(&RPCMessage{Op: "res", Buffers: []RPCBuffer{{Name: "a", Length: 4}}}, []RPCBuffer{{Name: "a", Length: 4, Buffer: []byte("data")}})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	badVersion := append([]byte(nil), valid...)
+	binary.BigEndian.PutUint32(badVersion[0:4], RPCVersionNumber+1)
+	badSize := append([]byte(nil), valid...)
+	binary.BigEndian.PutUint32(badSize[4:8], RPCMaxMessageSize+1)
+
+	tests := []struct {
+		name  string
+		frame []byte
+		err   string
+	}{
+		{"short header", valid[:4], "short read"},
+		{"bad version", badVersion, "mismatch RPC version"},
+		{"body too big", badSize, "too big"},
+		{"short body", valid[:rpcFrameHeaderSize+2], "short read"},
+		{"short buffers", valid[:len(valid)-1], "short read of buffers"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeRPCFrame(tt.frame)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	if _, err := EncodeRPCFrame(&RPCMessage{}, []RPCBuffer{{Name: "a", Length: 5, Buffer: []byte("data")}}); err == nil {
+		t.Fatalf("expected an error for a buffer length that does not match its data")
+	}
+}
+
+// TestRPCConnWSMultiplexedCalls sends concurrent calls over one websocket connection,
+// and replies them in reverse order, so every reply must be matched to its call by the request id
+func TestRPCConnWSMultiplexedCalls(t *testing.T) {
+	const calls = 8
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close(websocket.StatusNormalClosure, "")
+		ctx := r.Context()
+		var reqs []*RPCMessage
+		for len(reqs) < calls {
+			_, frame, err := ws.Read(ctx)
+			if err != nil {
+				return
+			}
+			req, err := DecodeRPCFrame(frame)
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+				return
+			}
+			reqs = append(reqs, req)
+		}
+		for i := len(reqs) - 1; i >= 0; i-- {
+			params := ReadBucketParams{}
+			if err := reqs[i].DecodeReply(&struct {
+				RPCMessage `json:",inline"`
+				Params     *ReadBucketParams `json:"params"`
+			}{Params: &params}); err != nil {
+				t.Errorf("unexpected error %v", err)
+				return
+			}
+			reply := &RPCMessageReply{
+				RPCMessage: RPCMessage{Op: "res", RequestID: reqs[i].RequestID},
+				Reply:      BucketInfo{Name: params.Name},
+			}
+			frame, _ := EncodeRPCFrame(reply, nil)
+			if err := ws.Write(ctx, websocket.MessageBinary, frame); err != nil {
+				return
+			}
+		}
+		// wait for the client to close the connection
+		_, _, _ = ws.Read(ctx)
+	}))
+	defer server.Close()
+
+	c := &RPCClient{
+		RPC:    NewRPC(),
+		Router: &SimpleRouter{Address: "ws" + strings.TrimPrefix(server.URL, "http")},
+		Policy: testPolicy,
+	}
+	defer func() {
+		if conn, ok := c.RPC.GetConnection(c.Router.GetAddress("bucket_api")).(*RPCConnWS); ok {
+			conn.Close()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	names := make([]string, calls)
+	errs := make([]error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			info, err := c.ReadBucketAPI(ReadBucketParams{Name: "bucket-" + string(rune('a'+i))})
+			names[i], errs[i] = info.Name, err
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < calls; i++ {
+		expected := "bucket-" + string(rune('a'+i))
+		if errs[i] != nil || names[i] != expected {
+			t.Fatalf("call %d: expected %q, got %q error %v", i, expected, names[i], errs[i])
+		}
+	}
+}
@@ -2,6 +2,7 @@ package nb
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -35,14 +36,14 @@ func (c *RPCConnHTTP) Reconnect() {
 }
 
 // Call calls an API method to noobaa over https
-func (c *RPCConnHTTP) Call(req *RPCMessage, res RPCResponse) error {
+func (c *RPCConnHTTP) Call(ctx context.Context, req *RPCMessage, res RPCResponse) error {
 	reqBytes, err := json.Marshal(req)
 	util.Panic(err)
 
-	httpRequest, err := http.NewRequest("PUT", c.Address, bytes.NewReader(reqBytes))
+	httpRequest, err := http.NewRequestWithContext(ctx, "PUT", c.Address, bytes.NewReader(reqBytes))
 	util.Panic(err)
 
-	httpResponse, err := c.RPC.HTTPClient.Do(httpRequest)
+	httpResponse, err := c.RPC.HTTPClientFor(c.Address).Do(httpRequest)
 	defer func() {
 		if httpResponse != nil && httpResponse.Body != nil {
 			util.SafeClose(httpResponse.Body, "Failed to close HTTP response body")
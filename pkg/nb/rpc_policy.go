@@ -0,0 +1,93 @@
+package nb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+)
+
+// RPCCallPolicy defines the deadline and the retries of the calls of a client
+type RPCCallPolicy struct {
+	// Timeout limits every attempt of a call, in addition to the deadline of the call context
+	Timeout time.Duration
+	// MaxRetries is the number of retries of idempotent methods after transport errors
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for every further retry
+	RetryBackoff time.Duration
+	// MaxRetryBackoff caps the delay between retries
+	MaxRetryBackoff time.Duration
+}
+
+// DefaultRPCCallPolicy is the call policy of new clients
+var DefaultRPCCallPolicy = RPCCallPolicy{
+	Timeout:         RPCSendTimeout,
+	MaxRetries:      3,
+	RetryBackoff:    500 * time.Millisecond,
+	MaxRetryBackoff: 10 * time.Second,
+}
+
+// OptionsRPCCallPolicy returns DefaultRPCCallPolicy with the timeout and the retries of the --rpc-timeout
+// and --rpc-max-retries options, which the operator loads from its NOOBAA_RPC_TIMEOUT and NOOBAA_RPC_MAX_RETRIES env
+func OptionsRPCCallPolicy() RPCCallPolicy {
+	policy := DefaultRPCCallPolicy
+	policy.Timeout = options.RPCTimeout
+	policy.MaxRetries = options.RPCMaxRetries
+	return policy
+}
+
+// idempotentMethodPrefixes are the prefixes of the noobaa API methods that only read state
+// and are therefore safe to send again after a transport error
+var idempotentMethodPrefixes = []string{"read_", "list_", "get_"}
+
+// IsIdempotentMethod returns true for API methods that can be retried safely
+func IsIdempotentMethod(method string) bool {
+	for _, prefix := range idempotentMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// withDefaults fills the unset fields of the policy from DefaultRPCCallPolicy
+func (p RPCCallPolicy) withDefaults() RPCCallPolicy {
+	if p.Timeout <= 0 {
+		p.Timeout = DefaultRPCCallPolicy.Timeout
+	}
+	if p.MaxRetries < 0 {
+		p.MaxRetries = 0
+	}
+	if p.RetryBackoff <= 0 {
+		p.RetryBackoff = DefaultRPCCallPolicy.RetryBackoff
+	}
+	if p.MaxRetryBackoff <= 0 {
+		p.MaxRetryBackoff = DefaultRPCCallPolicy.MaxRetryBackoff
+	}
+	return p
+}
+
+// Backoff returns the delay before the retry that follows the failed attempt (counted from 0)
+func (p RPCCallPolicy) Backoff(attempt int) time.Duration {
+	delay := p.RetryBackoff
+	for i := 0; i < attempt && delay < p.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxRetryBackoff {
+		delay = p.MaxRetryBackoff
+	}
+	return delay
+}
+
+// sleepContext waits for the delay or until the context is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,321 @@
+package nb
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+)
+
+// testPolicy keeps the retries of the tests fast
+var testPolicy = RPCCallPolicy{
+	Timeout:         time.Second,
+	MaxRetries:      2,
+	RetryBackoff:    time.Millisecond,
+	MaxRetryBackoff: time.Millisecond,
+}
+
+// newTestClient returns a client of a private RPC that calls the http server
+func newTestClient(server *httptest.Server) *RPCClient {
+	return &RPCClient{
+		RPC:    NewRPC(),
+		Router: &SimpleRouter{Address: server.URL},
+		Policy: testPolicy,
+	}
+}
+
+// writeRPCReply writes an http rpc reply the way the noobaa server does
+func writeRPCReply(w http.ResponseWriter, reply interface{}) {
+	body, _ := json.Marshal(reply)
+	w.Header().Set("X-Noobaa-Rpc-Body-Len", strconv.Itoa(len(body)))
+	_, _ = w.Write(body)
+}
+
+func TestIsIdempotentMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"read_system", true},
+		{"list_accounts", true},
+		{"get_bucket_policy", true},
+		{"create_bucket", false},
+		{"update_account_s3_access", false},
+		{"delete_host", false},
+	}
+	for _, tt := range tests {
+		if got := IsIdempotentMethod(tt.method); got != tt.want {
+			t.Errorf("IsIdempotentMethod(%q) = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestRPCCallPolicyBackoff(t *testing.T) {
+	p := RPCCallPolicy{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: time.Second}
+	want := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for attempt, w := range want {
+		if got := p.Backoff(attempt); got != w*time.Millisecond {
+			t.Errorf("Backoff(%d) = %s, want %s", attempt, got, w*time.Millisecond)
+		}
+	}
+}
+
+func TestOptionsRPCCallPolicy(t *testing.T) {
+	defer func(timeout time.Duration, retries int) {
+		options.RPCTimeout, options.RPCMaxRetries = timeout, retries
+	}(options.RPCTimeout, options.RPCMaxRetries)
+	options.RPCTimeout, options.RPCMaxRetries = 30*time.Second, 0
+
+	c := NewClient(&SimpleRouter{}).(*RPCClient)
+	if c.Policy.Timeout != 30*time.Second || c.Policy.MaxRetries != 0 ||
+		c.Policy.RetryBackoff != DefaultRPCCallPolicy.RetryBackoff {
+		t.Fatalf("expected the call policy of the options, got %+v", c.Policy)
+	}
+}
+
+func TestRPCCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewRPCCircuitBreaker("wss://core")
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < RPCCircuitBreakerThreshold; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("call %d: unexpected error %v", i, err)
+		}
+		b.Failure()
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to be open, got %v", err)
+	}
+
+	// after the cooldown a single probe is let through
+	now = now.Add(RPCCircuitBreakerCooldown)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected a probe call, got %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected calls to fail during the probe, got %v", err)
+	}
+
+	// a failed probe opens the circuit for another cooldown
+	b.Failure()
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to open again, got %v", err)
+	}
+
+	// a successful probe closes the circuit
+	now = now.Add(RPCCircuitBreakerCooldown)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected a probe call, got %v", err)
+	}
+	b.Success()
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected the circuit to be closed, got %v", err)
+	}
+}
+
+func TestCallContextRetriesIdempotentMethods(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a reply without the body length header fails as a transport error
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeRPCReply(w, map[string]interface{}{"op": "res", "reply": map[string]string{"version": "5.18.0"}})
+	}))
+	defer server.Close()
+	c := newTestClient(server)
+
+	info, err := c.ReadSystemAPI()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if info.Version != "5.18.0" || atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("expected a reply after 3 calls, got %+v after %d calls", info, calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if err := c.CreateBucketAPI(CreateBucketParams{Name: "first.bucket"}); err == nil {
+		t.Fatalf("expected the create call to fail")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected a non idempotent method to be sent once, got %d calls", n)
+	}
+}
+
+func TestCallContextDoesNotRetryRPCErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeRPCReply(w, map[string]interface{}{
+			"op":    "res",
+			"error": map[string]string{"rpc_code": "NO_SUCH_BUCKET", "message": "no such bucket"},
+		})
+	}))
+	defer server.Close()
+	c := newTestClient(server)
+
+	_, err := c.ReadBucketAPI(ReadBucketParams{Name: "missing"})
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.RPCCode != "NO_SUCH_BUCKET" {
+		t.Fatalf("expected NO_SUCH_BUCKET, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected an rpc error not to be retried, got %d calls", n)
+	}
+}
+
+func TestCallContextTimeoutAndCircuitBreaker(t *testing.T) {
+	release := make(chan struct{})
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// the server notices a closed connection only after the request body was read
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	c := newTestClient(server)
+	c.Policy.Timeout = 20 * time.Millisecond
+	c.Policy.MaxRetries = 0
+
+	for i := 0; i < RPCCircuitBreakerThreshold; i++ {
+		start := time.Now()
+		err := c.CreateBucketAPI(CreateBucketParams{Name: "first.bucket"})
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: expected a timeout, got %v", i, err)
+		}
+		if took := time.Since(start); took > 5*time.Second {
+			t.Fatalf("call %d: the timeout did not stop the call, took %s", i, took)
+		}
+	}
+
+	err := c.CreateBucketAPI(CreateBucketParams{Name: "first.bucket"})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to be open, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != RPCCircuitBreakerThreshold {
+		t.Fatalf("expected an open circuit not to send calls, got %d calls", n)
+	}
+}
+
+func TestWithContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	c := newTestClient(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.WithContext(ctx).ReadSystemAPI()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline, got %v", err)
+	}
+	if c.Ctx != nil {
+		t.Fatalf("expected WithContext not to modify the original client")
+	}
+	// a canceled caller does not count as a failure of the server
+	if b := c.RPC.GetCircuitBreaker(server.URL); b.failures != 0 {
+		t.Fatalf("expected no failures, got %d", b.failures)
+	}
+}
+
+func TestEnableTLSVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "service-ca.crt")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRPC()
+	if r.HTTPClientFor("wss://noobaa-mgmt.test.svc.cluster.local:443/rpc/") != &r.HTTPClient {
+		t.Fatalf("expected the insecure client before enabling verification")
+	}
+	if err := r.EnableTLSVerification(filepath.Join(t.TempDir(), "missing.crt")); !os.IsNotExist(err) {
+		t.Fatalf("expected a missing CA file error, got %v", err)
+	}
+	if err := r.EnableTLSVerification(caFile); err != nil {
+		t.Fatal(err)
+	}
+	secure := r.SecureHTTPClient
+	if err := r.EnableTLSVerification(caFile); err != nil || r.SecureHTTPClient != secure {
+		t.Fatalf("expected reloading the same CA to keep the client, got %v", err)
+	}
+
+	if r.HTTPClientFor("wss://noobaa-mgmt.test.svc.cluster.local:443/rpc/") != secure {
+		t.Fatalf("expected the secure client for a cluster service address")
+	}
+	if r.HTTPClientFor("wss://noobaa-mgmt.test.svc:443/rpc/") != secure {
+		t.Fatalf("expected the secure client for a short cluster service address")
+	}
+	if r.HTTPClientFor("wss://localhost:12345/rpc/") != &r.HTTPClient {
+		t.Fatalf("expected the insecure client for a port-forward address")
+	}
+
+	res, err := secure.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the certificate to be verified by the CA, got %v", err)
+	}
+	res.Body.Close()
+
+	// a CA that did not sign the certificate of the server rejects it
+	otherFile := filepath.Join(t.TempDir(), "other-ca.crt")
+	if err := os.WriteFile(otherFile, newTestCA(t), 0600); err != nil {
+		t.Fatal(err)
+	}
+	other := NewRPC()
+	if err := other.EnableTLSVerification(otherFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.SecureHTTPClient.Get(server.URL); err == nil {
+		t.Fatalf("expected a certificate of another CA to be rejected")
+	}
+}
+
+// newTestCA returns the PEM of a new self signed CA certificate
+func newTestCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
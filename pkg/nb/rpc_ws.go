@@ -2,10 +2,7 @@ package nb
 
 import (
 	"context"
-	"encoding/binary"
-	"encoding/json"
 	"fmt"
-	"io"
 	"sync"
 	"time"
 
@@ -34,11 +31,14 @@ type RPCConnWS struct {
 	cancelPings     context.CancelFunc
 }
 
-// RPCPendingRequest is a struct that describes the fields related to an rpc pending requests
+// RPCPendingRequest is a struct that describes the fields related to an rpc pending requests.
+// The reply is decoded by the caller and not by the connection reader,
+// so large replies of concurrent requests do not hold back the other requests of the connection.
 type RPCPendingRequest struct {
 	Conn      *RPCConnWS
 	Req       *RPCMessage
 	Res       RPCResponse
+	Reply     *RPCMessage
 	ReplyChan chan error
 }
 
@@ -71,7 +71,7 @@ func (c *RPCConnWS) Reconnect() {
 }
 
 // Call calls an API method to noobaa over wss
-func (c *RPCConnWS) Call(req *RPCMessage, res RPCResponse) error {
+func (c *RPCConnWS) Call(ctx context.Context, req *RPCMessage, res RPCResponse) error {
 
 	c.Lock.Lock()
 
@@ -81,16 +81,28 @@ func (c *RPCConnWS) Call(req *RPCMessage, res RPCResponse) error {
 		return err
 	}
 
-	replyChan := c.NewRequest(req, res)
+	pending := c.NewRequest(req, res)
+	replyChan := pending.ReplyChan
 
 	c.Lock.Unlock()
 
-	err = c.SendMessage(req)
+	err = c.SendMessage(ctx, req)
 	if err != nil {
+		c.RemoveRequest(req.RequestID)
 		return err
 	}
 
-	return <-replyChan
+	select {
+	case err := <-replyChan:
+		if err != nil {
+			return err
+		}
+		return pending.Reply.DecodeReply(res)
+	case <-ctx.Done():
+		// a late reply for the removed request is logged and dropped by HandleResponse
+		c.RemoveRequest(req.RequestID)
+		return fmt.Errorf("RPC: request %s %s %s.%s() abandoned: %w", c.Address, req.RequestID, req.API, req.Method, ctx.Err())
+	}
 }
 
 // ConnectUnderLock is opening a ws connection for new connection or after the previous one closed
@@ -113,7 +125,7 @@ func (c *RPCConnWS) ConnectUnderLock() error {
 	logrus.Infof("RPC: Connecting websocket (%p) %+v", c, c)
 	dialCtx, dialCancel := context.WithTimeout(context.Background(), connectTimeout)
 	defer dialCancel()
-	ws, _, err := websocket.Dial(dialCtx, c.Address, &websocket.DialOptions{HTTPClient: &c.RPC.HTTPClient})
+	ws, _, err := websocket.Dial(dialCtx, c.Address, &websocket.DialOptions{HTTPClient: c.RPC.HTTPClientFor(c.Address)})
 	if err != nil {
 		c.CloseUnderLock()
 		return err
@@ -200,7 +212,7 @@ func (c *RPCConnWS) CloseUnderLock() {
 }
 
 // NewRequest initializes the request id and register it on the connection pending requests
-func (c *RPCConnWS) NewRequest(req *RPCMessage, res RPCResponse) chan error {
+func (c *RPCConnWS) NewRequest(req *RPCMessage, res RPCResponse) *RPCPendingRequest {
 	pending := &RPCPendingRequest{
 		Req:       req,
 		Res:       res,
@@ -210,44 +222,30 @@ func (c *RPCConnWS) NewRequest(req *RPCMessage, res RPCResponse) chan error {
 	req.RequestID = fmt.Sprintf("%s-%d", c.Address, c.NextRequestID)
 	c.NextRequestID++
 	c.PendingRequests[req.RequestID] = pending
-	return pending.ReplyChan
+	return pending
 }
 
-// SendMessage sends the pending request
-func (c *RPCConnWS) SendMessage(msg interface{}) error {
-	ctx, cancel := context.WithTimeout(context.TODO(), RPCSendTimeout)
-	defer cancel()
-	writer, err := c.WS.Writer(ctx, websocket.MessageBinary)
-	if err != nil {
-		return err
-	}
-
-	reqBytes, err := json.Marshal(msg)
-	if err != nil {
-		return err
-	}
-
-	err = binary.Write(writer, binary.BigEndian, RPCVersionNumber)
-	if err != nil {
-		return err
-	}
-
-	err = binary.Write(writer, binary.BigEndian, uint32(len(reqBytes)))
-	if err != nil {
-		return err
-	}
+// RemoveRequest unregisters a pending request that is no longer waited for
+func (c *RPCConnWS) RemoveRequest(reqid string) {
+	c.Lock.Lock()
+	delete(c.PendingRequests, reqid)
+	c.Lock.Unlock()
+}
 
-	_, err = writer.Write(reqBytes)
-	if err != nil {
-		return err
+// SendMessage sends a message in a single binary frame, see EncodeRPCFrame.
+// The message is encoded before writing so concurrent senders only wait for each other's socket writes.
+func (c *RPCConnWS) SendMessage(ctx context.Context, msg interface{}) error {
+	var buffers []RPCBuffer
+	if r, ok := msg.(RPCResponse); ok {
+		buffers = r.Response().Buffers
 	}
-
-	err = writer.Close()
+	frame, err := EncodeRPCFrame(msg, buffers)
 	if err != nil {
 		return err
 	}
-
-	return nil
+	ctx, cancel := context.WithTimeout(ctx, RPCSendTimeout)
+	defer cancel()
+	return c.WS.Write(ctx, websocket.MessageBinary, frame)
 }
 
 // ReadMessages handles incoming messages
@@ -279,57 +277,13 @@ func (c *RPCConnWS) ReadMessages() {
 	}
 }
 
-// ReadMessage handles a message
+// ReadMessage reads and decodes the next frame of the connection
 func (c *RPCConnWS) ReadMessage() (*RPCMessage, error) {
-	_, reader, err := c.WS.Reader(context.TODO())
+	_, frame, err := c.WS.Read(context.TODO())
 	if err != nil {
 		return nil, err
 	}
-
-	var rpcVersionNumber uint32
-	var bodySize uint32
-
-	if err := binary.Read(reader, binary.BigEndian, &rpcVersionNumber); err != nil {
-		return nil, err
-	}
-	if err := binary.Read(reader, binary.BigEndian, &bodySize); err != nil {
-		return nil, err
-	}
-	if rpcVersionNumber != RPCVersionNumber {
-		return nil, fmt.Errorf("RPC: mismatch RPC version number expected %d received %d", RPCVersionNumber, rpcVersionNumber)
-	}
-	if bodySize > RPCMaxMessageSize {
-		return nil, fmt.Errorf("RPC: message body too big %d", bodySize)
-	}
-
-	msgBytes := make([]byte, bodySize)
-	n, err := io.ReadFull(reader, msgBytes)
-	if err != nil {
-		return nil, err
-	}
-	if n < int(bodySize) {
-		return nil, fmt.Errorf("RPC Error: short read")
-	}
-
-	msg := &RPCMessage{}
-	if err = json.Unmarshal(msgBytes, &msg); err != nil {
-		return nil, err
-	}
-	msg.RawBytes = msgBytes
-
-	// Read message buffers if any
-	if len(msg.Buffers) > 0 {
-		buffers, err := io.ReadAll(reader)
-		// if err != nil && err.Error() != "failed to read: cannot use EOFed reader" {
-		if err != nil {
-			return nil, err
-		}
-		if buffers != nil {
-			msg.SetBuffers(buffers)
-		}
-	}
-
-	return msg, nil
+	return DecodeRPCFrame(frame)
 }
 
 // HandleRequest handles an incoming message of type request
@@ -355,7 +309,7 @@ func (c *RPCConnWS) HandleRequest(req *RPCMessage) {
 			res.Reply = reply
 		}
 
-		err = c.SendMessage(res)
+		err = c.SendMessage(context.TODO(), res)
 		if err != nil {
 			c.Close()
 		}
@@ -372,14 +326,14 @@ func (c *RPCConnWS) HandleResponse(msg *RPCMessage) {
 	if pending == nil {
 		logrus.Errorf("RPC: no pending request for %s %s", c.Address, msg.RequestID)
 	} else {
-		err := json.Unmarshal(msg.RawBytes, pending.Res)
-		pending.ReplyChan <- err
+		pending.Reply = msg
+		pending.ReplyChan <- nil
 	}
 }
 
 // HandlePing handles an incoming message of type ping
 func (c *RPCConnWS) HandlePing(msg *RPCMessage) {
-	err := c.SendMessage(&RPCMessage{
+	err := c.SendMessage(context.TODO(), &RPCMessage{
 		Op:        "pong",
 		RequestID: msg.RequestID,
 		Took:      0,
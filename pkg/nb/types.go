@@ -64,6 +64,7 @@ type AccountInfo struct {
 	// +nullable
 	// +optional
 	NsfsAccountConfig *nbv1.AccountNsfsConfig `json:"nsfs_account_config,omitempty"`
+	RoleConfig        *RoleConfig             `json:"role_config,omitempty"`
 }
 
 // BucketInfo is a struct of bucket info returned by the API
@@ -301,10 +302,12 @@ type ListBucketsParams struct {
 }
 
 // ListBucketsReply is the reply of bucket_api.list_buckets()
+// ContinuationToken is set when more buckets are left after a page of MaxBuckets.
 type ListBucketsReply struct {
 	Buckets []struct {
 		Name string `json:"name"`
 	} `json:"buckets"`
+	ContinuationToken string `json:"continuation_token,omitempty"`
 }
 
 // ListHostsParams is the params to host_api.list_hosts()
@@ -324,6 +327,31 @@ type ListHostsReply struct {
 
 // HostInfo is the information of a host(partial)
 type HostInfo struct {
+	Name         string            `json:"name"`
+	Mode         string            `json:"mode,omitempty"`
+	DataActivity *HostDataActivity `json:"data_activity,omitempty"`
+}
+
+// HostDataActivity is the data activity of a host, such as the evacuation of a decommissioning host
+type HostDataActivity struct {
+	Reason    string  `json:"reason"`
+	Progress  float64 `json:"progress"`
+	Completed bool    `json:"completed,omitempty"`
+}
+
+// UpdateHostServicesParams is the params of host_api.update_host_services()
+type UpdateHostServicesParams struct {
+	Name     string               `json:"name"`
+	Services HostServicesSettings `json:"services"`
+}
+
+// HostServicesSettings are the services of a host, disabling the storage service decommissions the host
+type HostServicesSettings struct {
+	Storage *bool `json:"storage,omitempty"`
+}
+
+// DeleteHostParams is the params of host_api.delete_host()
+type DeleteHostParams struct {
 	Name string `json:"name"`
 }
 
@@ -447,6 +475,19 @@ type UpdateAccountKeysParams struct {
 	AccessKeys S3AccessKeys `json:"access_keys"`
 }
 
+// CreateAccessKeyParams is the params of account_api.create_access_key(),
+// it adds the keys to the account next to its current keys
+type CreateAccessKeyParams struct {
+	Email      string       `json:"email"`
+	AccessKeys S3AccessKeys `json:"access_keys"`
+}
+
+// DeleteAccessKeyParams is the params of account_api.delete_access_key()
+type DeleteAccessKeyParams struct {
+	Email     string       `json:"email"`
+	AccessKey MaskedString `json:"access_key"`
+}
+
 // BackingStoreInfo describes backingstore info
 type BackingStoreInfo struct {
 	// Name describes backingstore name
@@ -598,6 +639,25 @@ type UpdateAccountParams struct {
 	RemoveRoleConfig bool        `json:"remove_role_config,omitempty"`
 }
 
+// RoleConfig is the role config of an account that is assumed through the STS
+type RoleConfig struct {
+	RoleName         string           `json:"role_name"`
+	AssumeRolePolicy AssumeRolePolicy `json:"assume_role_policy"`
+}
+
+// AssumeRolePolicy is the trust policy of a role config
+type AssumeRolePolicy struct {
+	Version   string                      `json:"version,omitempty"`
+	Statement []AssumeRolePolicyStatement `json:"statement"`
+}
+
+// AssumeRolePolicyStatement is a statement of the trust policy of a role config
+type AssumeRolePolicyStatement struct {
+	Effect    string   `json:"effect"`
+	Action    []string `json:"action"`
+	Principal []string `json:"principal"`
+}
+
 // UpdateAccountS3AccessParams is the params of account_api.update_account_s3_access()
 type UpdateAccountS3AccessParams struct {
 	Email               string                  `json:"email"`
@@ -632,11 +692,121 @@ type ReplicationPolicy struct {
 	LogReplicationInfo interface{}   `json:"log_replication_info,omitempty"`
 }
 
+// ReplicationRule is a single rule of a bucket replication policy
+type ReplicationRule struct {
+	RuleID            string                 `json:"rule_id"`
+	DestinationBucket string                 `json:"destination_bucket"`
+	Filter            *ReplicationRuleFilter `json:"filter,omitempty"`
+	SyncDeletions     bool                   `json:"sync_deletions,omitempty"`
+	SyncVersions      bool                   `json:"sync_versions,omitempty"`
+}
+
+// ReplicationRuleFilter selects the objects a replication rule applies to
+type ReplicationRuleFilter struct {
+	Prefix string `json:"prefix"`
+}
+
+// LogReplicationInfo is the log based replication configuration of a bucket
+type LogReplicationInfo struct {
+	EndpointType string        `json:"endpoint_type,omitempty"`
+	LogsLocation *LogsLocation `json:"logs_location,omitempty"`
+}
+
+// LogsLocation is the location of the bucket logs used for log based replication
+type LogsLocation struct {
+	LogsBucket string `json:"logs_bucket,omitempty"`
+	Prefix     string `json:"prefix,omitempty"`
+}
+
 // DeleteBucketReplicationParams is the params of bucket_api.delete_bucket_replication()
 type DeleteBucketReplicationParams struct {
 	Name string `json:"name"`
 }
 
+// BucketLifecycleParams is the params of bucket_api.set_bucket_lifecycle_configuration_rules()
+type BucketLifecycleParams struct {
+	Name  string                `json:"name"`
+	Rules []LifecycleRuleConfig `json:"rules"`
+}
+
+// LifecycleRuleConfig is a single lifecycle rule of a bucket
+type LifecycleRuleConfig struct {
+	ID                             string                                `json:"id"`
+	Status                         string                                `json:"status"`
+	Filter                         LifecycleRuleFilter                   `json:"filter"`
+	Expiration                     *LifecycleExpiration                  `json:"expiration,omitempty"`
+	NoncurrentVersionExpiration    *LifecycleNoncurrentVersionExpiration `json:"noncurrent_version_expiration,omitempty"`
+	AbortIncompleteMultipartUpload *LifecycleAbortIncompleteMultipart    `json:"abort_incomplete_multipart_upload,omitempty"`
+}
+
+// LifecycleRuleFilter selects the objects a lifecycle rule applies to
+type LifecycleRuleFilter struct {
+	Prefix string `json:"prefix"`
+}
+
+// LifecycleExpiration is the expiration of current objects
+type LifecycleExpiration struct {
+	Days int `json:"days"`
+}
+
+// LifecycleNoncurrentVersionExpiration is the expiration of noncurrent object versions
+type LifecycleNoncurrentVersionExpiration struct {
+	NoncurrentDays          int `json:"noncurrent_days"`
+	NewerNoncurrentVersions int `json:"newer_noncurrent_versions,omitempty"`
+}
+
+// LifecycleAbortIncompleteMultipart is the abort of incomplete multipart uploads
+type LifecycleAbortIncompleteMultipart struct {
+	DaysAfterInitiation int `json:"days_after_initiation"`
+}
+
+// DeleteBucketLifecycleParams is the params of bucket_api.delete_bucket_lifecycle()
+type DeleteBucketLifecycleParams struct {
+	Name string `json:"name"`
+}
+
+// PutBucketPolicyParams is the params of bucket_api.put_bucket_policy()
+type PutBucketPolicyParams struct {
+	Name   string       `json:"name"`
+	Policy BucketPolicy `json:"policy"`
+}
+
+// GetBucketPolicyParams is the params of bucket_api.get_bucket_policy()
+type GetBucketPolicyParams struct {
+	Name string `json:"name"`
+}
+
+// GetBucketPolicyReply is the reply of bucket_api.get_bucket_policy()
+type GetBucketPolicyReply struct {
+	Policy *BucketPolicy `json:"policy,omitempty"`
+}
+
+// DeleteBucketPolicyParams is the params of bucket_api.delete_bucket_policy()
+type DeleteBucketPolicyParams struct {
+	Name string `json:"name"`
+}
+
+// BucketPolicy is an S3 bucket policy document
+type BucketPolicy struct {
+	Version   string                  `json:"Version,omitempty"`
+	Statement []BucketPolicyStatement `json:"Statement"`
+}
+
+// BucketPolicyStatement is a single statement of a bucket policy.
+// The elements that can be either a string or a list are kept as interface{}
+// so that statements not managed by the operator are preserved as is.
+type BucketPolicyStatement struct {
+	Sid          string                 `json:"Sid,omitempty"`
+	Effect       string                 `json:"Effect"`
+	Principal    interface{}            `json:"Principal,omitempty"`
+	NotPrincipal interface{}            `json:"NotPrincipal,omitempty"`
+	Action       interface{}            `json:"Action,omitempty"`
+	NotAction    interface{}            `json:"NotAction,omitempty"`
+	Resource     interface{}            `json:"Resource,omitempty"`
+	NotResource  interface{}            `json:"NotResource,omitempty"`
+	Condition    map[string]interface{} `json:"Condition,omitempty"`
+}
+
 // BucketClassInfo is the is the reply of tiering_policy_api.update_bucket_class()
 type BucketClassInfo struct {
 	ErrorMessage   string                  `json:"error_message"`
@@ -62,6 +62,9 @@ func CmdCreate() *cobra.Command {
 	cmd.Flags().Int("gid", -1, "Set the nsfs gid")
 	cmd.Flags().String("new_buckets_path", "/", "Change the path where new buckets will be created")
 	cmd.Flags().Bool("nsfs_only", true, "Set if this account is used only for nsfs")
+	cmd.Flags().String("credential_rotation_schedule", "", "Set a cron schedule to rotate the account S3 keys, e.g. \"0 0 1 */3 *\"")
+	cmd.Flags().Duration("credential_rotation_grace_period", util.DefaultCredentialRotationGracePeriod,
+		"Set the time both the current and the rotated S3 keys are valid before the current keys are removed")
 	return cmd
 }
 
@@ -167,6 +170,8 @@ func RunCreate(cmd *cobra.Command, args []string) {
 
 	newBucketsPath, _ := cmd.Flags().GetString("new_buckets_path")
 	nsfsOnly, _ := cmd.Flags().GetBool("nsfs_only")
+	rotationSchedule, _ := cmd.Flags().GetString("credential_rotation_schedule")
+	rotationGracePeriod, _ := cmd.Flags().GetDuration("credential_rotation_grace_period")
 
 	// Check and get system
 	o := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaa_cr_yaml)
@@ -200,6 +205,16 @@ func RunCreate(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if rotationSchedule != "" {
+		noobaaAccount.Spec.CredentialRotation = &nbv1.CredentialRotationSpec{
+			Schedule:    rotationSchedule,
+			GracePeriod: &metav1.Duration{Duration: rotationGracePeriod},
+		}
+		if err := validations.ValidateAccountCredentialRotation(*noobaaAccount); err != nil {
+			log.Fatalf(`❌ %s`, err.Error())
+		}
+	}
+
 	if !util.KubeCheck(sys) {
 		log.Fatalf(`❌ Could not find NooBaa system %q in namespace %q`, sys.Name, sys.Namespace)
 	}
@@ -811,3 +826,118 @@ func ValidateAccessKeys(accessKeys nb.S3AccessKeys) {
 		log.Fatalf(`❌ Account secret length must be 40, and must contain only alpha-numeric chars, "+", "/"`)
 	}
 }
+
+// The keys of the credentials secret that hold the new S3 keys during the grace period of a scheduled rotation,
+// and the access key of the previous keys until it is removed from the account after the grace period
+const (
+	NextAccessKeyIDSecretKey     = "NEXT_AWS_ACCESS_KEY_ID"
+	NextSecretAccessKeySecretKey = "NEXT_AWS_SECRET_ACCESS_KEY"
+	PreviousAccessKeyIDSecretKey = "PREVIOUS_AWS_ACCESS_KEY_ID"
+)
+
+// StageRotatedAccountKeys puts new random S3 keys in the credentials secret next to its current keys.
+// Keys that were staged before are removed from the account first. The new keys are added to the account
+// by AddStagedAccountKeys once the secret is updated, so that the account never holds keys that no secret references.
+// The secret is expected to be loaded with util.KubeCheck and is updated only in memory.
+func StageRotatedAccountKeys(nbClient nb.Client, accountName string, secret *corev1.Secret) error {
+	if err := DiscardStagedAccountKeys(nbClient, accountName, secret); err != nil {
+		return err
+	}
+	accessKey, secretKey := util.RandomS3Credentials()
+	if secret.StringData == nil {
+		secret.StringData = map[string]string{}
+	}
+	secret.StringData[NextAccessKeyIDSecretKey] = accessKey
+	secret.StringData[NextSecretAccessKeySecretKey] = secretKey
+	return nil
+}
+
+// AddStagedAccountKeys adds the keys staged in the credentials secret to the noobaa account next to its current keys.
+// Both keys are valid until the rotation is activated. When adding the keys fails they are removed from the account
+// in case the call failed after they were added, and from the secret, which the caller should update again.
+// The secret is expected to be loaded with util.KubeCheck and is updated only in memory.
+func AddStagedAccountKeys(nbClient nb.Client, accountName string, secret *corev1.Secret) error {
+	if !HasStagedAccountKeys(secret) {
+		return fmt.Errorf("secret %q has no staged keys to add", secret.Name)
+	}
+	err := nbClient.CreateAccessKeyAPI(nb.CreateAccessKeyParams{
+		Email: accountName,
+		AccessKeys: nb.S3AccessKeys{
+			AccessKey: nb.MaskedString(secret.StringData[NextAccessKeyIDSecretKey]),
+			SecretKey: nb.MaskedString(secret.StringData[NextSecretAccessKeySecretKey]),
+		},
+	})
+	if err == nil {
+		return nil
+	}
+	if discardErr := DiscardStagedAccountKeys(nbClient, accountName, secret); discardErr != nil {
+		util.Logger().Errorf("failed to discard the staged keys of account %q: %v", accountName, discardErr)
+	}
+	return fmt.Errorf("failed to add the rotated keys to account %q: %w", accountName, err)
+}
+
+// HasStagedAccountKeys returns true if the credentials secret holds staged keys of a rotation
+func HasStagedAccountKeys(secret *corev1.Secret) bool {
+	return secret.StringData[NextAccessKeyIDSecretKey] != "" && secret.StringData[NextSecretAccessKeySecretKey] != ""
+}
+
+// DiscardStagedAccountKeys removes the staged keys of a rotation from the noobaa account and from the credentials secret.
+// The secret is expected to be loaded with util.KubeCheck and is updated only in memory.
+func DiscardStagedAccountKeys(nbClient nb.Client, accountName string, secret *corev1.Secret) error {
+	if err := deleteAccountAccessKey(nbClient, accountName, secret.StringData[NextAccessKeyIDSecretKey]); err != nil {
+		return err
+	}
+	delete(secret.StringData, NextAccessKeyIDSecretKey)
+	delete(secret.StringData, NextSecretAccessKeySecretKey)
+	return nil
+}
+
+// ActivateRotatedAccountKeys makes the keys staged in the credentials secret its current keys,
+// and keeps the access key of the previous keys so that RemovePreviousAccountKeys removes it from the account
+// once the secret is updated. Both keys stay valid in noobaa until then.
+// The secret is expected to be loaded with util.KubeCheck and is updated only in memory.
+func ActivateRotatedAccountKeys(secret *corev1.Secret) error {
+	if !HasStagedAccountKeys(secret) {
+		return fmt.Errorf("secret %q has no staged keys to activate", secret.Name)
+	}
+	secret.StringData[PreviousAccessKeyIDSecretKey] = secret.StringData["AWS_ACCESS_KEY_ID"]
+	secret.StringData["AWS_ACCESS_KEY_ID"] = secret.StringData[NextAccessKeyIDSecretKey]
+	secret.StringData["AWS_SECRET_ACCESS_KEY"] = secret.StringData[NextSecretAccessKeySecretKey]
+	delete(secret.StringData, NextAccessKeyIDSecretKey)
+	delete(secret.StringData, NextSecretAccessKeySecretKey)
+	return nil
+}
+
+// HasPreviousAccountKeys returns true if the previous keys of an activated rotation were not removed from the account yet
+func HasPreviousAccountKeys(secret *corev1.Secret) bool {
+	return secret.StringData[PreviousAccessKeyIDSecretKey] != ""
+}
+
+// RemovePreviousAccountKeys removes the previous keys of an activated rotation from the noobaa account,
+// after which only the current keys of the secret are valid.
+// The secret is expected to be loaded with util.KubeCheck and is updated only in memory.
+func RemovePreviousAccountKeys(nbClient nb.Client, accountName string, secret *corev1.Secret) error {
+	if err := deleteAccountAccessKey(nbClient, accountName, secret.StringData[PreviousAccessKeyIDSecretKey]); err != nil {
+		return err
+	}
+	delete(secret.StringData, PreviousAccessKeyIDSecretKey)
+	return nil
+}
+
+// deleteAccountAccessKey removes the access key from the noobaa account, a key that was already removed is ignored
+func deleteAccountAccessKey(nbClient nb.Client, accountName string, accessKey string) error {
+	if accessKey == "" {
+		return nil
+	}
+	err := nbClient.DeleteAccessKeyAPI(nb.DeleteAccessKeyParams{
+		Email:     accountName,
+		AccessKey: nb.MaskedString(accessKey),
+	})
+	if rpcErr, ok := err.(*nb.RPCError); ok && rpcErr.RPCCode == "NO_SUCH_ACCESS_KEY" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove an access key of account %q: %w", accountName, err)
+	}
+	return nil
+}
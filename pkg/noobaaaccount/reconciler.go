@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/system"
@@ -91,6 +93,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 
 	if r.NooBaaAccount.UID == "" {
 		log.Infof("NooBaaAccount %q not found or deleted. Skip reconcile.", r.NooBaaAccount.Name)
+		metrics.DeletePhase("NooBaaAccount", r.NooBaaAccount.Namespace, r.NooBaaAccount.Name)
 		return reconcile.Result{}, nil
 	}
 
@@ -112,6 +115,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 		err = r.ReconcilePhases()
 	}
 	if err != nil {
+		metrics.ReconcileError(metrics.ControllerNooBaaAccount, err)
 		if perr, isPERR := err.(*util.PersistentError); isPERR {
 			r.SetPhase(nbv1.NooBaaAccountPhaseRejected, perr.Reason, perr.Message)
 			log.Errorf("❌ Persistent Error: %s", err)
@@ -131,6 +135,10 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 			"noobaa operator completed reconcile - noobaa account is ready",
 		)
 		log.Infof("✅ Done")
+		// wake up for the next step of the scheduled credentials rotation
+		if rotation := r.NooBaaAccount.Status.CredentialRotation; rotation != nil && rotation.NextRotationTime != nil {
+			res.RequeueAfter = max(time.Until(rotation.NextRotationTime.Time), time.Second)
+		}
 	}
 
 	err = r.UpdateStatus()
@@ -172,6 +180,7 @@ func (r *Reconciler) SetPhase(phase nbv1.NooBaaAccountPhase, reason string, mess
 
 	r.Logger.Infof("SetPhase: %s", phase)
 	r.NooBaaAccount.Status.Phase = phase
+	metrics.SetPhase("NooBaaAccount", r.NooBaaAccount.Namespace, r.NooBaaAccount.Name, string(phase))
 	switch phase {
 	case nbv1.NooBaaAccountPhaseReady:
 		util.SetAvailableCondition(c, reason, message)
@@ -216,6 +225,15 @@ func (r *Reconciler) ReconcilePhaseVerifying() error {
 			return util.NewPersistentError("InvalidDefaultResource", err.Error())
 		}
 	}
+	if err := validations.ValidateAccountPermissions(*r.NooBaaAccount); err != nil {
+		return util.NewPersistentError("InvalidPermissions", err.Error())
+	}
+	if err := validations.ValidateAccountCredentialRotation(*r.NooBaaAccount); err != nil {
+		return util.NewPersistentError("InvalidCredentialRotation", err.Error())
+	}
+	if err := validations.ValidateAccountSTSRole(*r.NooBaaAccount); err != nil {
+		return util.NewPersistentError("InvalidSTSRole", err.Error())
+	}
 
 	return nil
 }
@@ -249,7 +267,7 @@ func (r *Reconciler) ReconcilePhaseConfiguring() error {
 	if err != nil {
 		return err
 	}
-	r.NBClient = sysClient.NBClient
+	r.NBClient = sysClient.NBClient.WithContext(r.Ctx)
 
 	if r.NooBaaAccountInfo != nil {
 		if err := r.UpdateNooBaaAccount(); err != nil {
@@ -262,7 +280,19 @@ func (r *Reconciler) ReconcilePhaseConfiguring() error {
 	}
 
 	// Ensure credentials Secret exists before Ready
-	return r.ensureNoobaaAccountSecret()
+	if err := r.ensureNoobaaAccountSecret(); err != nil {
+		return err
+	}
+
+	if err := r.ReconcilePermissions(); err != nil {
+		return err
+	}
+
+	if err := r.ReconcileSTSRole(); err != nil {
+		return err
+	}
+
+	return r.ReconcileCredentialRotation()
 }
 
 // ReconcileDeletion handles the deletion of a noobaa account using the noobaa api
@@ -290,7 +320,17 @@ func (r *Reconciler) ReconcileDeletion() error {
 	if err != nil {
 		return err
 	}
-	r.NBClient = sysClient.NBClient
+	r.NBClient = sysClient.NBClient.WithContext(r.Ctx)
+
+	// remove the account statements from the bucket policies before the account is gone
+	for _, bucketName := range r.NooBaaAccount.Status.PermittedBuckets {
+		if err := r.updateAccountBucketPolicy(bucketName, nil); err != nil && !isNoSuchBucket(err) {
+			return fmt.Errorf("failed to remove account %q from the policy of bucket %q. got error: %v",
+				r.NooBaaAccount.Name, bucketName, err)
+		}
+	}
+	r.NooBaaAccount.Status.PermittedBuckets = nil
+
 	err = r.NBClient.DeleteAccountAPI(nb.DeleteAccountParams{Email: r.NooBaaAccount.Name})
 	if err != nil {
 		if nbErr, ok := err.(*nb.RPCError); ok && nbErr.RPCCode == "NO_SUCH_ACCOUNT" {
@@ -455,7 +495,7 @@ func (r *Reconciler) ReadSystemInfo() error {
 	if err != nil {
 		return err
 	}
-	r.NBClient = sysClient.NBClient
+	r.NBClient = sysClient.NBClient.WithContext(r.Ctx)
 
 	systemInfo, err := r.NBClient.ReadSystemAPI()
 	if err != nil {
@@ -489,3 +529,344 @@ func IsBucketInBucketsArray(buckets []nb.BucketInfo, bucketName string) bool {
 	}
 	return false
 }
+
+// ReconcilePermissions applies the account permissions as statements in the bucket policies
+// of the permitted buckets, and removes the statements from buckets that are no longer permitted.
+// Missing buckets are retried so the permissions are restored when the buckets are (re)created.
+func (r *Reconciler) ReconcilePermissions() error {
+	log := r.Logger
+	perms := r.NooBaaAccount.Spec.Permissions
+
+	desired := map[string]*nb.BucketPolicyStatement{}
+	if perms != nil {
+		for i := range perms.Buckets {
+			p := &perms.Buckets[i]
+			desired[p.BucketName] = CreateBucketPolicyStatement(r.NooBaaAccount.Name, p, perms.AllowedSourceCIDRs)
+		}
+	}
+
+	permitted := []string{}
+	missing := []string{}
+
+	for _, bucketName := range r.NooBaaAccount.Status.PermittedBuckets {
+		if desired[bucketName] != nil {
+			continue
+		}
+		if err := r.updateAccountBucketPolicy(bucketName, nil); err != nil && !isNoSuchBucket(err) {
+			// status is left as is so that the removal is retried
+			return fmt.Errorf("failed to remove account %q from the policy of bucket %q. got error: %v",
+				r.NooBaaAccount.Name, bucketName, err)
+		}
+		log.Infof("✅ Removed account %q from the policy of bucket %q", r.NooBaaAccount.Name, bucketName)
+	}
+
+	if perms != nil {
+		for i := range perms.Buckets {
+			bucketName := perms.Buckets[i].BucketName
+			if err := r.updateAccountBucketPolicy(bucketName, desired[bucketName]); err != nil {
+				if isNoSuchBucket(err) {
+					missing = append(missing, bucketName)
+					continue
+				}
+				// buckets that were permitted before are kept in status until they are handled
+				for _, b := range r.NooBaaAccount.Status.PermittedBuckets {
+					if desired[b] != nil && !util.Contains(permitted, b) {
+						permitted = append(permitted, b)
+					}
+				}
+				r.NooBaaAccount.Status.PermittedBuckets = permitted
+				return fmt.Errorf("failed to apply account %q permissions on bucket %q. got error: %v",
+					r.NooBaaAccount.Name, bucketName, err)
+			}
+			permitted = append(permitted, bucketName)
+		}
+	}
+
+	r.NooBaaAccount.Status.PermittedBuckets = permitted
+	if len(missing) > 0 {
+		return fmt.Errorf("account %q permissions are waiting for missing buckets %v", r.NooBaaAccount.Name, missing)
+	}
+	if len(permitted) > 0 {
+		log.Infof("✅ Applied account %q permissions on buckets %v", r.NooBaaAccount.Name, permitted)
+	}
+	return nil
+}
+
+// updateAccountBucketPolicy replaces the statement of the account in the bucket policy,
+// a nil statement removes it. Statements of other accounts and users are kept as is.
+func (r *Reconciler) updateAccountBucketPolicy(bucketName string, statement *nb.BucketPolicyStatement) error {
+	reply, err := r.NBClient.GetBucketPolicyAPI(nb.GetBucketPolicyParams{Name: bucketName})
+	if err != nil {
+		if rpcErr, isRPCErr := err.(*nb.RPCError); !isRPCErr || rpcErr.RPCCode != "NO_SUCH_BUCKET_POLICY" {
+			return err
+		}
+	}
+
+	policy := MergeBucketPolicyStatement(reply.Policy, AccountStatementSid(r.NooBaaAccount.Name), statement)
+	if len(policy.Statement) == 0 {
+		if reply.Policy == nil {
+			return nil
+		}
+		return r.NBClient.DeleteBucketPolicyAPI(nb.DeleteBucketPolicyParams{Name: bucketName})
+	}
+	return r.NBClient.PutBucketPolicyAPI(nb.PutBucketPolicyParams{Name: bucketName, Policy: *policy})
+}
+
+// AccountStatementSid returns the Sid of the bucket policy statement managed for the account
+func AccountStatementSid(accountName string) string {
+	return fmt.Sprintf("noobaa-account-%s", accountName)
+}
+
+// CreateBucketPolicyStatement returns the bucket policy statement that grants the account the permission on the bucket
+func CreateBucketPolicyStatement(accountName string, perm *nbv1.AccountBucketPermission, sourceCIDRs []string) *nb.BucketPolicyStatement {
+	actions := []string{
+		"s3:GetObject",
+		"s3:GetObjectVersion",
+		"s3:GetBucketLocation",
+		"s3:ListBucket",
+		"s3:ListBucketVersions",
+	}
+	if perm.Access == nbv1.BucketAccessReadWrite {
+		actions = append(actions,
+			"s3:PutObject",
+			"s3:DeleteObject",
+			"s3:DeleteObjectVersion",
+			"s3:AbortMultipartUpload",
+			"s3:ListMultipartUploadParts",
+			"s3:ListBucketMultipartUploads",
+		)
+	}
+	statement := &nb.BucketPolicyStatement{
+		Sid:       AccountStatementSid(accountName),
+		Effect:    "Allow",
+		Principal: map[string]interface{}{"AWS": []string{accountName}},
+		Action:    actions,
+		Resource: []string{
+			fmt.Sprintf("arn:aws:s3:::%s", perm.BucketName),
+			fmt.Sprintf("arn:aws:s3:::%s/*", perm.BucketName),
+		},
+	}
+	if len(sourceCIDRs) > 0 {
+		statement.Condition = map[string]interface{}{
+			"IpAddress": map[string]interface{}{"aws:SourceIp": sourceCIDRs},
+		}
+	}
+	return statement
+}
+
+// MergeBucketPolicyStatement returns a copy of the policy where the statements with the given sid
+// are replaced by the statement, or removed when the statement is nil
+func MergeBucketPolicyStatement(policy *nb.BucketPolicy, sid string, statement *nb.BucketPolicyStatement) *nb.BucketPolicy {
+	merged := &nb.BucketPolicy{Version: "2012-10-17", Statement: []nb.BucketPolicyStatement{}}
+	if policy != nil {
+		if policy.Version != "" {
+			merged.Version = policy.Version
+		}
+		for _, s := range policy.Statement {
+			if s.Sid != sid {
+				merged.Statement = append(merged.Statement, s)
+			}
+		}
+	}
+	if statement != nil {
+		merged.Statement = append(merged.Statement, *statement)
+	}
+	return merged
+}
+
+// ReconcileSTSRole applies the STS role of the account spec as the role config of the account,
+// and removes the role config when the STS role is removed from the spec.
+// noobaa normalizes the role config it reports, so the spec is compared with the role that was last applied
+// as recorded in the status, and the role config is applied again when the spec changed or noobaa has none.
+func (r *Reconciler) ReconcileSTSRole() error {
+	log := r.Logger
+	role := r.NooBaaAccount.Spec.STSRole
+
+	if role == nil {
+		if r.NooBaaAccount.Status.STSRole == nil {
+			return nil
+		}
+		err := r.NBClient.UpdateAccount(nb.UpdateAccountParams{Email: r.NooBaaAccount.Name, RemoveRoleConfig: true})
+		if err != nil {
+			return fmt.Errorf("failed to remove the role config of account %q. got error: %v", r.NooBaaAccount.Name, err)
+		}
+		r.NooBaaAccount.Status.STSRole = nil
+		log.Infof("✅ Removed the role config of account %q", r.NooBaaAccount.Name)
+		r.recordEvent(corev1.EventTypeNormal, "STSRoleRemoved", "The STS role of the account was removed")
+		return nil
+	}
+
+	roleConfig := CreateRoleConfig(r.NooBaaAccount.Name, role)
+	if IsSTSRoleApplied(r.NooBaaAccount.Status.STSRole, roleConfig.RoleName, role) &&
+		r.NooBaaAccountInfo != nil && r.NooBaaAccountInfo.RoleConfig != nil {
+		return nil
+	}
+
+	err := r.NBClient.UpdateAccount(nb.UpdateAccountParams{Email: r.NooBaaAccount.Name, RoleConfig: roleConfig})
+	if err != nil {
+		r.recordEvent(corev1.EventTypeWarning, "STSRoleFailed", err.Error())
+		return fmt.Errorf("failed to apply the role config of account %q. got error: %v", r.NooBaaAccount.Name, err)
+	}
+	r.NooBaaAccount.Status.STSRole = &nbv1.AccountSTSRoleStatus{
+		RoleName:          roleConfig.RoleName,
+		TrustedPrincipals: role.TrustedPrincipals,
+		AllowedActions:    role.AllowedActions,
+		LastAppliedTime:   &metav1.Time{Time: time.Now()},
+	}
+	log.Infof("✅ Applied the role config %q of account %q", roleConfig.RoleName, r.NooBaaAccount.Name)
+	r.recordEvent(corev1.EventTypeNormal, "STSRoleApplied",
+		fmt.Sprintf("The STS role %q can be assumed by %v", roleConfig.RoleName, role.TrustedPrincipals))
+	return nil
+}
+
+// IsSTSRoleApplied returns true if the status records that the role of the spec, named roleName, was applied
+func IsSTSRoleApplied(status *nbv1.AccountSTSRoleStatus, roleName string, role *nbv1.AccountSTSRole) bool {
+	return status != nil && status.RoleName == roleName &&
+		slices.Equal(status.TrustedPrincipals, role.TrustedPrincipals) &&
+		slices.Equal(status.AllowedActions, role.AllowedActions)
+}
+
+// CreateRoleConfig returns the role config of the account STS role
+func CreateRoleConfig(accountName string, role *nbv1.AccountSTSRole) *nb.RoleConfig {
+	roleName := role.RoleName
+	if roleName == "" {
+		roleName = accountName
+	}
+	actions := role.AllowedActions
+	if len(actions) == 0 {
+		actions = []string{"sts:AssumeRole"}
+	}
+	roleConfig := &nb.RoleConfig{
+		RoleName: roleName,
+		AssumeRolePolicy: nb.AssumeRolePolicy{
+			Version: "2012-10-17",
+			Statement: []nb.AssumeRolePolicyStatement{{
+				Effect:    "allow",
+				Action:    actions,
+				Principal: role.TrustedPrincipals,
+			}},
+		},
+	}
+	return roleConfig
+}
+
+func isNoSuchBucket(err error) bool {
+	rpcErr, isRPCErr := err.(*nb.RPCError)
+	return isRPCErr && rpcErr.RPCCode == "NO_SUCH_BUCKET"
+}
+
+// ReconcileCredentialRotation rotates the account S3 keys according to the credential rotation schedule.
+// When the schedule is due new keys are added to the account and staged in the credentials secret,
+// and both the current and the new keys are valid during the grace period. After the grace period
+// the new keys become the current keys of the secret and the previous keys are removed from the account.
+func (r *Reconciler) ReconcileCredentialRotation() error {
+	log := r.Logger
+	rotation := r.NooBaaAccount.Spec.CredentialRotation
+	status := r.NooBaaAccount.Status.CredentialRotation
+
+	if rotation == nil {
+		if status != nil && status.PendingSince != nil && util.KubeCheck(r.Secret) {
+			// the rotation was disabled during its grace period, drop the staged keys
+			if err := DiscardStagedAccountKeys(r.NBClient, r.NooBaaAccount.Name, r.Secret); err != nil {
+				return err
+			}
+			if !util.KubeUpdate(r.Secret) {
+				return fmt.Errorf("failed to remove the staged keys from secret %q", r.Secret.Name)
+			}
+		}
+		r.NooBaaAccount.Status.CredentialRotation = nil
+		return nil
+	}
+
+	if status == nil {
+		status = &nbv1.CredentialRotationStatus{}
+		r.NooBaaAccount.Status.CredentialRotation = status
+	}
+	gracePeriod := util.CredentialRotationGracePeriod(rotation.GracePeriod)
+	since := r.NooBaaAccount.CreationTimestamp.Time
+	if status.LastRotationTime != nil {
+		since = status.LastRotationTime.Time
+	}
+	pendingSince := time.Time{}
+	if status.PendingSince != nil {
+		pendingSince = status.PendingSince.Time
+	}
+
+	now := time.Now()
+	step, nextTime, err := util.NextCredentialRotationStep(rotation.Schedule, gracePeriod, since, pendingSince, now)
+	if err != nil {
+		return util.NewPersistentError("InvalidCredentialRotation", err.Error())
+	}
+	if step == util.CredentialRotationWait {
+		status.NextRotationTime = &metav1.Time{Time: nextTime}
+		return nil
+	}
+
+	if !util.KubeCheck(r.Secret) {
+		return fmt.Errorf("failed to load the credentials secret %q of account %q", r.Secret.Name, r.NooBaaAccount.Name)
+	}
+
+	// staged keys can go missing if the secret was recreated, in that case the grace period starts over.
+	// previous keys mean the rotation was activated and only their removal from the account is left.
+	if step == util.CredentialRotationStage || (!HasStagedAccountKeys(r.Secret) && !HasPreviousAccountKeys(r.Secret)) {
+		if err := StageRotatedAccountKeys(r.NBClient, r.NooBaaAccount.Name, r.Secret); err != nil {
+			r.recordEvent(corev1.EventTypeWarning, "CredentialRotationFailed", err.Error())
+			return err
+		}
+		if !util.KubeUpdate(r.Secret) {
+			return fmt.Errorf("failed to stage the rotated keys in secret %q", r.Secret.Name)
+		}
+		// the update replies the secret data, keep reading its keys as string data
+		util.SecretResetStringDataFromData(r.Secret)
+		if err := AddStagedAccountKeys(r.NBClient, r.NooBaaAccount.Name, r.Secret); err != nil {
+			r.recordEvent(corev1.EventTypeWarning, "CredentialRotationFailed", err.Error())
+			// the staged keys were discarded, the rotation is staged again on the next reconcile
+			if !util.KubeUpdate(r.Secret) {
+				log.Errorf("failed to remove the discarded keys from secret %q", r.Secret.Name)
+			}
+			return err
+		}
+		status.PendingSince = &metav1.Time{Time: now}
+		status.NextRotationTime = &metav1.Time{Time: now.Add(gracePeriod)}
+		log.Infof("✅ Staged rotated keys of account %q in secret %q until %v", r.NooBaaAccount.Name, r.Secret.Name, status.NextRotationTime)
+		r.recordEvent(corev1.EventTypeNormal, "CredentialRotationStarted",
+			fmt.Sprintf("New S3 keys were published in secret %q and are valid now, the current keys stay valid until %s",
+				r.Secret.Name, status.NextRotationTime.Format(time.RFC3339)))
+		if gracePeriod > 0 {
+			return nil
+		}
+	}
+
+	if HasStagedAccountKeys(r.Secret) {
+		if err := ActivateRotatedAccountKeys(r.Secret); err != nil {
+			return err
+		}
+		if !util.KubeUpdate(r.Secret) {
+			return fmt.Errorf("failed to update secret %q with the rotated keys", r.Secret.Name)
+		}
+		util.SecretResetStringDataFromData(r.Secret)
+	}
+	if err := RemovePreviousAccountKeys(r.NBClient, r.NooBaaAccount.Name, r.Secret); err != nil {
+		r.recordEvent(corev1.EventTypeWarning, "CredentialRotationFailed", err.Error())
+		return err
+	}
+	if !util.KubeUpdate(r.Secret) {
+		return fmt.Errorf("failed to remove the previous keys from secret %q", r.Secret.Name)
+	}
+
+	status.LastRotationTime = &metav1.Time{Time: now}
+	status.PendingSince = nil
+	_, nextTime, _ = util.NextCredentialRotationStep(rotation.Schedule, gracePeriod, now, time.Time{}, now)
+	status.NextRotationTime = &metav1.Time{Time: nextTime}
+	log.Infof("✅ Rotated the keys of account %q, next rotation at %v", r.NooBaaAccount.Name, nextTime)
+	r.recordEvent(corev1.EventTypeNormal, "CredentialsRotated",
+		fmt.Sprintf("The S3 keys in secret %q were rotated, the previous keys are no longer valid", r.Secret.Name))
+
+	return nil
+}
+
+func (r *Reconciler) recordEvent(eventType string, reason string, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(r.NooBaaAccount, nil, eventType, reason, reason, "%s", message)
+	}
+}
@@ -0,0 +1,234 @@
+package noobaaaccount
+
+import (
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb/fake"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFakeReconciler(server *fake.Server) *Reconciler {
+	return &Reconciler{
+		NBClient:      server.Client(),
+		NooBaaAccount: &nbv1.NooBaaAccount{ObjectMeta: metav1.ObjectMeta{Name: "user"}},
+	}
+}
+
+func TestUpdateAccountBucketPolicy(t *testing.T) {
+	server := fake.NewServer()
+	r := newFakeReconciler(server)
+	if err := r.NBClient.CreateBucketAPI(nb.CreateBucketParams{Name: "first.bucket"}); err != nil {
+		t.Fatal(err)
+	}
+	other := nb.BucketPolicyStatement{Sid: "other", Effect: "Allow", Principal: "*", Action: "s3:GetObject"}
+	server.BucketPolicies["first.bucket"] = &nb.BucketPolicy{Version: "2012-10-17", Statement: []nb.BucketPolicyStatement{other}}
+
+	perm := &nbv1.AccountBucketPermission{BucketName: "first.bucket", Access: nbv1.BucketAccessReadOnly}
+	if err := r.updateAccountBucketPolicy("first.bucket", CreateBucketPolicyStatement("user", perm, nil)); err != nil {
+		t.Fatal(err)
+	}
+	policy := server.BucketPolicies["first.bucket"]
+	if policy == nil || len(policy.Statement) != 2 || policy.Statement[0].Sid != "other" ||
+		policy.Statement[1].Sid != AccountStatementSid("user") {
+		t.Fatalf("expected the account statement to be added next to the other statement, got %+v", policy)
+	}
+
+	// removing the statement of the account keeps the other statement
+	if err := r.updateAccountBucketPolicy("first.bucket", nil); err != nil {
+		t.Fatal(err)
+	}
+	policy = server.BucketPolicies["first.bucket"]
+	if policy == nil || len(policy.Statement) != 1 || policy.Statement[0].Sid != "other" {
+		t.Fatalf("expected only the other statement to be left, got %+v", policy)
+	}
+
+	// removing the last statement deletes the policy
+	server.BucketPolicies["first.bucket"] = &nb.BucketPolicy{Statement: []nb.BucketPolicyStatement{*CreateBucketPolicyStatement("user", perm, nil)}}
+	if err := r.updateAccountBucketPolicy("first.bucket", nil); err != nil {
+		t.Fatal(err)
+	}
+	if server.BucketPolicies["first.bucket"] != nil || len(server.CallsTo("bucket_api", "delete_bucket_policy")) != 1 {
+		t.Fatalf("expected the policy to be deleted")
+	}
+
+	// a bucket without a policy and without a statement of the account is left untouched
+	server.ResetCalls()
+	if err := r.updateAccountBucketPolicy("first.bucket", nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls := server.Calls(); len(calls) != 1 || calls[0].Method != "get_bucket_policy" {
+		t.Fatalf("expected only the policy to be read, got %+v", calls)
+	}
+}
+
+func TestUpdateAccountBucketPolicyMissingBucket(t *testing.T) {
+	r := newFakeReconciler(fake.NewServer())
+	perm := &nbv1.AccountBucketPermission{BucketName: "missing", Access: nbv1.BucketAccessReadWrite}
+	err := r.updateAccountBucketPolicy("missing", CreateBucketPolicyStatement("user", perm, nil))
+	if !isNoSuchBucket(err) {
+		t.Fatalf("expected NO_SUCH_BUCKET, got %v", err)
+	}
+}
+
+func TestCredentialRotationKeysOverlap(t *testing.T) {
+	server := fake.NewServer()
+	client := server.Client()
+	reply, err := client.CreateAccountAPI(nb.CreateAccountParams{Name: "user", Email: "user", S3Access: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKeys := reply.AccessKeys[0]
+	secret := &corev1.Secret{StringData: map[string]string{
+		"AWS_ACCESS_KEY_ID":     string(oldKeys.AccessKey),
+		"AWS_SECRET_ACCESS_KEY": string(oldKeys.SecretKey),
+	}}
+
+	// staging puts the new keys in the secret, they are valid once they are added to the account
+	if err := StageRotatedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.Accounts["user"].AccessKeys) != 1 {
+		t.Fatalf("expected the staged keys not to be added to the account before the secret is updated")
+	}
+	// both keys are valid during the grace period
+	if err := AddStagedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	newAccessKey, newSecretKey := secret.StringData[NextAccessKeyIDSecretKey], secret.StringData[NextSecretAccessKeySecretKey]
+	if server.Authenticate(string(oldKeys.AccessKey), string(oldKeys.SecretKey)) != "user" ||
+		server.Authenticate(newAccessKey, newSecretKey) != "user" {
+		t.Fatalf("expected both the current and the staged keys to be valid during the grace period")
+	}
+
+	// staging again replaces the staged keys
+	if err := StageRotatedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddStagedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	if server.Authenticate(newAccessKey, newSecretKey) != "" || len(server.Accounts["user"].AccessKeys) != 2 {
+		t.Fatalf("expected the keys staged before to be removed, got %+v", server.Accounts["user"].AccessKeys)
+	}
+	newAccessKey, newSecretKey = secret.StringData[NextAccessKeyIDSecretKey], secret.StringData[NextSecretAccessKeySecretKey]
+
+	// activation makes the new keys current in the secret, the previous keys stay valid until they are removed
+	if err := ActivateRotatedAccountKeys(secret); err != nil {
+		t.Fatal(err)
+	}
+	if secret.StringData["AWS_ACCESS_KEY_ID"] != newAccessKey || HasStagedAccountKeys(secret) || !HasPreviousAccountKeys(secret) {
+		t.Fatalf("unexpected secret after activation %+v", secret.StringData)
+	}
+	if server.Authenticate(string(oldKeys.AccessKey), string(oldKeys.SecretKey)) != "user" {
+		t.Fatalf("expected the previous keys to be valid until they are removed")
+	}
+
+	// removal of the previous keys is retried until it succeeds
+	server.InjectError("account_api", "delete_access_key", &nb.RPCError{RPCCode: "INTERNAL"}, 1)
+	if err := RemovePreviousAccountKeys(client, "user", secret); err == nil || !HasPreviousAccountKeys(secret) {
+		t.Fatalf("expected the removal to fail and the previous keys to be kept, got %v", err)
+	}
+	if err := RemovePreviousAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	if server.Authenticate(string(oldKeys.AccessKey), string(oldKeys.SecretKey)) != "" ||
+		server.Authenticate(newAccessKey, newSecretKey) != "user" || HasPreviousAccountKeys(secret) {
+		t.Fatalf("expected only the new keys to be valid after the grace period")
+	}
+
+	// a key that was already removed is ignored
+	secret.StringData[PreviousAccessKeyIDSecretKey] = string(oldKeys.AccessKey)
+	if err := RemovePreviousAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscardStagedAccountKeys(t *testing.T) {
+	server := fake.NewServer()
+	client := server.Client()
+	if _, err := client.CreateAccountAPI(nb.CreateAccountParams{Name: "user", Email: "user", S3Access: true}); err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{}
+	if err := StageRotatedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddStagedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := DiscardStagedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	if HasStagedAccountKeys(secret) || len(server.Accounts["user"].AccessKeys) != 1 {
+		t.Fatalf("expected the staged keys to be removed from the account and the secret")
+	}
+}
+
+func TestAddStagedAccountKeysFailure(t *testing.T) {
+	server := fake.NewServer()
+	client := server.Client()
+	if _, err := client.CreateAccountAPI(nb.CreateAccountParams{Name: "user", Email: "user", S3Access: true}); err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{}
+	if err := StageRotatedAccountKeys(client, "user", secret); err != nil {
+		t.Fatal(err)
+	}
+	server.InjectError("account_api", "create_access_key", &nb.RPCError{RPCCode: "INTERNAL"}, 1)
+	if err := AddStagedAccountKeys(client, "user", secret); err == nil {
+		t.Fatalf("expected adding the staged keys to fail")
+	}
+	if HasStagedAccountKeys(secret) || len(server.Accounts["user"].AccessKeys) != 1 {
+		t.Fatalf("expected the staged keys to be removed from the secret and the account")
+	}
+}
+
+func TestReconcileSTSRole(t *testing.T) {
+	server := fake.NewServer()
+	r := newFakeReconciler(server)
+	r.Logger = logrus.WithField("test", t.Name())
+	if _, err := r.NBClient.CreateAccountAPI(nb.CreateAccountParams{Name: "user", Email: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	r.NooBaaAccount.Spec.STSRole = &nbv1.AccountSTSRole{TrustedPrincipals: []string{"account1"}}
+	if err := r.ReconcileSTSRole(); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.CallsTo("account_api", "update_account")) != 1 || r.NooBaaAccount.Status.STSRole == nil {
+		t.Fatalf("expected the role config to be applied")
+	}
+
+	// noobaa reports a normalized role config, the role is not applied again while the spec is unchanged
+	server.ResetCalls()
+	r.NooBaaAccountInfo = &nb.AccountInfo{RoleConfig: &nb.RoleConfig{RoleName: "USER"}}
+	if err := r.ReconcileSTSRole(); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.CallsTo("account_api", "update_account")) != 0 {
+		t.Fatalf("expected the applied role not to be applied again")
+	}
+
+	// a changed spec is applied again
+	r.NooBaaAccount.Spec.STSRole.AllowedActions = []string{"sts:AssumeRoleWithWebIdentity"}
+	if err := r.ReconcileSTSRole(); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.CallsTo("account_api", "update_account")) != 1 ||
+		len(r.NooBaaAccount.Status.STSRole.AllowedActions) != 1 {
+		t.Fatalf("expected the changed role to be applied, got %+v", r.NooBaaAccount.Status.STSRole)
+	}
+
+	// a role config that was removed in noobaa is applied again
+	server.ResetCalls()
+	r.NooBaaAccountInfo = &nb.AccountInfo{}
+	if err := r.ReconcileSTSRole(); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.CallsTo("account_api", "update_account")) != 1 {
+		t.Fatalf("expected the missing role config to be applied again")
+	}
+}
@@ -0,0 +1,432 @@
+package noobaaremote
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/validations"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	sigyaml "sigs.k8s.io/yaml"
+)
+
+var ctx = context.TODO()
+
+// Cmd returns a CLI command
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remote",
+		Short: "Manage remote noobaa systems federated into this system",
+	}
+	cmd.AddCommand(
+		CmdCreate(),
+		CmdDelete(),
+		CmdStatus(),
+		CmdList(),
+		CmdReconcile(),
+	)
+	return cmd
+}
+
+// CmdCreate returns a CLI command
+func CmdCreate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <noobaa-remote-name>",
+		Short: "Create noobaa remote",
+		Run:   RunCreate,
+	}
+	cmd.Flags().String("mgmt-endpoint", "", "The management endpoint of the remote noobaa system: https://host:port")
+	cmd.Flags().String("s3-endpoint", "", "The S3 endpoint of the remote noobaa system: https://host:port")
+	cmd.Flags().String("access-key", "", `Access key of an account on the remote system (paste from the remote "noobaa account status")`)
+	cmd.Flags().String("secret-key", "", `Secret key of an account on the remote system (paste from the remote "noobaa account status")`)
+	cmd.Flags().String("secret-name", "",
+		`The name of a secret with the remote account credentials (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY), instead of --access-key and --secret-key`)
+	cmd.Flags().String("signature-version", "", "The S3 signature version v4|v2 (default v4 for https endpoints and v2 for http)")
+	cmd.Flags().StringSlice("bucket", nil, "The name of a remote bucket to expose as a namespace store, repeat the flag for more buckets")
+	cmd.Flags().String("access-mode", "read-write", `The access mode of the namespace stores of the buckets read-write|read-only`)
+	return cmd
+}
+
+// CmdDelete returns a CLI command
+func CmdDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <noobaa-remote-name>",
+		Short: "Delete noobaa remote and the namespace stores of its buckets",
+		Run:   RunDelete,
+	}
+	return cmd
+}
+
+// CmdStatus returns a CLI command
+func CmdStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status <noobaa-remote-name>",
+		Short: "Status noobaa remote",
+		Run:   RunStatus,
+	}
+	return cmd
+}
+
+// CmdList returns a CLI command
+func CmdList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List noobaa remotes",
+		Run:   RunList,
+	}
+	return cmd
+}
+
+// CmdReconcile returns a CLI command
+func CmdReconcile() *cobra.Command {
+	cmd := &cobra.Command{
+		Hidden: true,
+		Use:    "reconcile",
+		Short:  "Runs a reconcile attempt like noobaa-operator",
+		Run:    RunReconcile,
+	}
+	return cmd
+}
+
+// RunCreate runs a CLI command
+func RunCreate(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`❌ Missing expected arguments: <noobaa-remote-name> %s`, cmd.UsageString())
+	}
+	name := args[0]
+
+	mgmtEndpoint := util.GetFlagStringOrPrompt(cmd, "mgmt-endpoint")
+	s3Endpoint := util.GetFlagStringOrPrompt(cmd, "s3-endpoint")
+	secretName, _ := cmd.Flags().GetString("secret-name")
+	sigVer, _ := cmd.Flags().GetString("signature-version")
+	buckets, _ := cmd.Flags().GetStringSlice("bucket")
+	cmdAccessMode, _ := cmd.Flags().GetString("access-mode")
+	accessMode := nbv1.AccessModeReadWrite
+	if cmdAccessMode == "read-only" {
+		accessMode = nbv1.AccessModeReadOnly
+	} else if cmdAccessMode != "read-write" {
+		log.Fatalf(`❌ Invalid --access-mode %q, should be read-write or read-only`, cmdAccessMode)
+	}
+
+	// Check and get system
+	o := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaa_cr_yaml)
+	sys := o.(*nbv1.NooBaa)
+	sys.Name = options.SystemName
+	sys.Namespace = options.Namespace
+
+	o = util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml)
+	secret := o.(*corev1.Secret)
+	secret.Name = fmt.Sprintf("noobaa-remote-%s", name)
+	secret.Namespace = options.Namespace
+	secret.StringData = map[string]string{}
+	secret.Data = nil
+
+	if secretName == "" {
+		secret.StringData["AWS_ACCESS_KEY_ID"] = util.GetFlagStringOrPromptPassword(cmd, "access-key")
+		secret.StringData["AWS_SECRET_ACCESS_KEY"] = util.GetFlagStringOrPromptPassword(cmd, "secret-key")
+	} else {
+		util.VerifyCredsInSecret(secretName, options.Namespace, []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"})
+		secret.Name = secretName
+	}
+
+	o = util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaremote_cr_yaml)
+	remote := o.(*nbv1.NooBaaRemote)
+	remote.Name = name
+	remote.Namespace = options.Namespace
+	remote.Spec = nbv1.NooBaaRemoteSpec{
+		MgmtEndpoint:     mgmtEndpoint,
+		S3Endpoint:       s3Endpoint,
+		SignatureVersion: nbv1.S3SignatureVersion(sigVer),
+		Secret: corev1.SecretReference{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+	}
+	for _, bucket := range buckets {
+		remote.Spec.Buckets = append(remote.Spec.Buckets, nbv1.NooBaaRemoteBucket{
+			Name:       bucket,
+			AccessMode: accessMode,
+		})
+	}
+
+	if err := validations.ValidateNooBaaRemote(remote); err != nil {
+		log.Fatalf(`❌ %s`, err.Error())
+	}
+
+	if !util.KubeCheck(sys) {
+		log.Fatalf(`❌ Could not find NooBaa system %q in namespace %q`, sys.Name, sys.Namespace)
+	}
+
+	err := util.KubeClient().Get(util.Context(), util.ObjectKey(remote), remote)
+	if err == nil {
+		log.Fatalf(`❌ NooBaaRemote %q already exists in namespace %q`, remote.Name, remote.Namespace)
+	}
+
+	// Create noobaa remote CR
+	util.Panic(controllerutil.SetControllerReference(sys, remote, scheme.Scheme))
+	if !util.KubeCreateFailExisting(remote) {
+		log.Fatalf(`❌ Could not create NooBaaRemote %q in Namespace %q (conflict)`, remote.Name, remote.Namespace)
+	}
+
+	if secretName == "" {
+		// Create secret
+		util.Panic(controllerutil.SetControllerReference(remote, secret, scheme.Scheme))
+		if !util.KubeCreateFailExisting(secret) {
+			log.Fatalf(`❌ Could not create Secret %q in Namespace %q (conflict)`, secret.Name, secret.Namespace)
+		}
+	}
+
+	log.Printf("")
+	util.PrintThisNoteWhenFinishedApplyingAndStartWaitLoop()
+	log.Printf("")
+	log.Printf("NooBaaRemote Wait Ready:")
+	if WaitReady(remote) {
+		log.Printf("")
+		log.Printf("")
+		RunStatus(cmd, args)
+	}
+}
+
+// RunDelete runs a CLI command
+func RunDelete(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`❌ Missing expected arguments: <noobaa-remote-name> %s`, cmd.UsageString())
+	}
+
+	o := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaremote_cr_yaml)
+	remote := o.(*nbv1.NooBaaRemote)
+	remote.Name = args[0]
+	remote.Namespace = options.Namespace
+
+	if !util.KubeDelete(remote) {
+		log.Fatalf(`❌ Could not delete NooBaaRemote %q in namespace %q`,
+			remote.Name, remote.Namespace)
+	}
+}
+
+// RunStatus runs a CLI command
+func RunStatus(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`❌ Missing expected arguments: <noobaa-remote-name> %s`, cmd.UsageString())
+	}
+
+	o := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaremote_cr_yaml)
+	remote := o.(*nbv1.NooBaaRemote)
+	remote.Name = args[0]
+	remote.Namespace = options.Namespace
+
+	if !util.KubeCheck(remote) {
+		log.Fatalf(`❌ Could not get NooBaaRemote %q in namespace %q`,
+			remote.Name, remote.Namespace)
+	}
+
+	CheckPhase(remote)
+
+	fmt.Println()
+	fmt.Println("# NooBaaRemote spec:")
+	output, err := sigyaml.Marshal(remote.Spec)
+	util.Panic(err)
+	fmt.Print(string(output))
+	fmt.Println()
+
+	reachability := remote.Status.Reachability
+	fmt.Println("# NooBaaRemote reachability:")
+	fmt.Printf("  %-20s : %t\n", "Mgmt Reachable", reachability.MgmtReachable)
+	fmt.Printf("  %-20s : %t (%s)\n", "S3 Reachable", reachability.S3Reachable, reachability.S3Status)
+	lastCheck := "never"
+	if reachability.LastCheckTime != nil {
+		lastCheck = reachability.LastCheckTime.Time.Format(time.RFC3339)
+	}
+	fmt.Printf("  %-20s : %s\n", "Last Check", lastCheck)
+	if reachability.Message != "" {
+		fmt.Printf("  %-20s : %s\n", "Message", reachability.Message)
+	}
+	fmt.Println()
+
+	if len(remote.Status.NamespaceStores) > 0 {
+		fmt.Println("# NooBaaRemote namespace stores:")
+		table := (&util.PrintTable{}).AddRow("NAME", "REMOTE-BUCKET", "PHASE")
+		for _, nsStore := range remote.Status.NamespaceStores {
+			table.AddRow(nsStore.Name, nsStore.Bucket, string(nsStore.Phase))
+		}
+		fmt.Print(table.String())
+		fmt.Println()
+	}
+}
+
+// WaitReady waits until the noobaa remote phase changes to ready by the operator
+func WaitReady(remote *nbv1.NooBaaRemote) bool {
+	log := util.Logger()
+	klient := util.KubeClient()
+
+	interval := time.Duration(3)
+
+	err := wait.PollUntilContextCancel(ctx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		err := klient.Get(util.Context(), util.ObjectKey(remote), remote)
+		if err != nil {
+			log.Printf("⏳ Failed to get NooBaaRemote: %s", err)
+			return false, nil
+		}
+		CheckPhase(remote)
+		if remote.Status.Phase == nbv1.NooBaaRemotePhaseRejected {
+			return false, fmt.Errorf("NooBaaRemotePhaseRejected")
+		}
+		if remote.Status.Phase != nbv1.NooBaaRemotePhaseReady {
+			return false, nil
+		}
+		return true, nil
+	})
+	return err == nil
+}
+
+// CheckPhase prints the phase and reason for it
+func CheckPhase(remote *nbv1.NooBaaRemote) {
+	log := util.Logger()
+
+	reason := "waiting..."
+	for _, c := range remote.Status.Conditions {
+		if c.Type == "Available" {
+			reason = fmt.Sprintf("%s %s", c.Reason, c.Message)
+		}
+	}
+
+	switch remote.Status.Phase {
+
+	case nbv1.NooBaaRemotePhaseReady:
+		log.Printf("✅ NooBaaRemote %q Phase is Ready", remote.Name)
+
+	case nbv1.NooBaaRemotePhaseRejected:
+		log.Errorf("❌ NooBaaRemote %q Phase is %q: %s", remote.Name, remote.Status.Phase, reason)
+
+	case nbv1.NooBaaRemotePhaseUnreachable:
+		log.Warnf("⚠️  NooBaaRemote %q Phase is %q: %s", remote.Name, remote.Status.Phase, remote.Status.Reachability.Message)
+
+	case nbv1.NooBaaRemotePhaseVerifying:
+		fallthrough
+	case nbv1.NooBaaRemotePhaseConfiguring:
+		fallthrough
+	default:
+		log.Printf("⏳ NooBaaRemote %q Phase is %q: %s", remote.Name, remote.Status.Phase, reason)
+	}
+}
+
+// RunList runs a CLI command
+func RunList(cmd *cobra.Command, args []string) {
+	list := &nbv1.NooBaaRemoteList{
+		TypeMeta: metav1.TypeMeta{Kind: "NooBaaRemoteList"},
+	}
+	if !util.KubeList(list, &client.ListOptions{Namespace: options.Namespace}) {
+		return
+	}
+	if len(list.Items) == 0 {
+		fmt.Printf("No noobaa remotes found.\n")
+		return
+	}
+	table := (&util.PrintTable{}).AddRow(
+		"NAME",
+		"S3-ENDPOINT",
+		"MGMT-REACHABLE",
+		"S3-REACHABLE",
+		"BUCKETS",
+		"PHASE",
+		"LAST-CHECK",
+		"AGE",
+	)
+	for i := range list.Items {
+		remote := &list.Items[i]
+		reachability := remote.Status.Reachability
+		lastCheck := "never"
+		if reachability.LastCheckTime != nil {
+			lastCheck = time.Since(reachability.LastCheckTime.Time).Round(time.Second).String()
+		}
+		table.AddRow(
+			remote.Name,
+			remote.Spec.S3Endpoint,
+			fmt.Sprint(reachability.MgmtReachable),
+			fmt.Sprint(reachability.S3Reachable),
+			fmt.Sprint(len(remote.Spec.Buckets)),
+			string(remote.Status.Phase),
+			lastCheck,
+			time.Since(remote.CreationTimestamp.Time).Round(time.Second).String(),
+		)
+	}
+	fmt.Print(table.String())
+}
+
+// RunReconcile runs a CLI command
+func RunReconcile(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+	if len(args) != 1 || args[0] == "" {
+		log.Fatalf(`Missing expected arguments: <noobaa-remote-name> %s`, cmd.UsageString())
+	}
+	remoteName := args[0]
+	klient := util.KubeClient()
+	interval := time.Duration(3)
+	util.Panic(wait.PollUntilContextCancel(ctx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: options.Namespace,
+				Name:      remoteName,
+			},
+		}
+		res, err := NewReconciler(req.NamespacedName, klient, scheme.Scheme, nil).Reconcile()
+		if err != nil {
+			return false, err
+		}
+		// ready remotes are requeued for the periodic health check, so only short requeues are retried here
+		if res.RequeueAfter != 0 && res.RequeueAfter < UnreachableRetryInterval {
+			log.Printf("\nRetrying in %d seconds\n", interval)
+			return false, nil
+		}
+		return true, nil
+	}))
+}
+
+// MapSecretToNooBaaRemotes returns a list of noobaa remotes that use the secret for their credentials
+func MapSecretToNooBaaRemotes(secret types.NamespacedName) []reconcile.Request {
+	log := util.Logger()
+	list := &nbv1.NooBaaRemoteList{
+		TypeMeta: metav1.TypeMeta{Kind: "NooBaaRemoteList"},
+	}
+	if !util.KubeList(list, &client.ListOptions{Namespace: secret.Namespace}) {
+		log.Infof("Could not found noobaa remotes in namespace %q, while trying to find NooBaaRemote that uses %s secret", secret.Namespace, secret.Name)
+		return nil
+	}
+
+	reqs := []reconcile.Request{}
+	for i := range list.Items {
+		remote := &list.Items[i]
+		secretNamespace := remote.Spec.Secret.Namespace
+		if secretNamespace == "" {
+			secretNamespace = remote.Namespace
+		}
+		if remote.Spec.Secret.Name == secret.Name && secretNamespace == secret.Namespace {
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      remote.Name,
+					Namespace: remote.Namespace,
+				},
+			})
+		}
+	}
+	return reqs
+}
@@ -0,0 +1,474 @@
+package noobaaremote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
+	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/system"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/validations"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// LabelNooBaaRemote is set on the namespace stores created for a noobaa remote with the remote name
+	LabelNooBaaRemote = "noobaa-remote"
+
+	// HealthCheckInterval is the interval of the reachability checks of a ready remote
+	HealthCheckInterval = 5 * time.Minute
+
+	// UnreachableRetryInterval is the interval of the reachability checks of an unreachable remote
+	UnreachableRetryInterval = time.Minute
+)
+
+// ErrRemoteUnreachable is returned when the S3 endpoint of the remote system failed the health check
+var ErrRemoteUnreachable = errors.New("remote system is unreachable")
+
+// Reconciler is the context for loading or reconciling a noobaa remote
+type Reconciler struct {
+	Request  types.NamespacedName
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Ctx      context.Context
+	Logger   *logrus.Entry
+	Recorder events.EventRecorder
+
+	NBClient nb.Client
+
+	NooBaaRemote *nbv1.NooBaaRemote
+	NooBaa       *nbv1.NooBaa
+	Secret       *corev1.Secret
+}
+
+// NewReconciler initializes a reconciler to be used for loading or reconciling a noobaa remote
+func NewReconciler(
+	req types.NamespacedName,
+	client client.Client,
+	scheme *runtime.Scheme,
+	recorder events.EventRecorder,
+) *Reconciler {
+
+	r := &Reconciler{
+		Request:      req,
+		Client:       client,
+		Scheme:       scheme,
+		Recorder:     recorder,
+		Ctx:          context.TODO(),
+		Logger:       logrus.WithField("noobaaremote", req.Namespace+"/"+req.Name),
+		NooBaaRemote: util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaremote_cr_yaml).(*nbv1.NooBaaRemote),
+		NooBaa:       util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaa_cr_yaml).(*nbv1.NooBaa),
+		Secret:       util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml).(*corev1.Secret),
+	}
+
+	// Set Namespace
+	r.NooBaaRemote.Namespace = r.Request.Namespace
+	r.NooBaa.Namespace = options.Namespace
+
+	// Set Names
+	r.NooBaaRemote.Name = r.Request.Name
+	r.NooBaa.Name = options.SystemName
+
+	return r
+}
+
+// Reconcile reads that state of the cluster for a NooBaaRemote object,
+// and makes changes based on the state read and what is in the NooBaaRemote.Spec.
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+// Ready and unreachable remotes are requeued periodically to refresh their reachability.
+func (r *Reconciler) Reconcile() (reconcile.Result, error) {
+	res := reconcile.Result{}
+	log := r.Logger
+	log.Infof("Start NooBaaRemote Reconcile...")
+
+	if !util.KubeCheck(r.NooBaaRemote) {
+		log.Infof("❌ NooBaaRemote %q not found or deleted.", r.NooBaaRemote.Name)
+		metrics.DeletePhase("NooBaaRemote", r.NooBaaRemote.Namespace, r.NooBaaRemote.Name)
+		return res, nil
+	}
+
+	if r.NooBaaRemote.DeletionTimestamp != nil {
+		// the namespace stores are owned by the remote and are garbage collected by kubernetes
+		log.Infof("NooBaaRemote %q is being deleted. Skip reconcile.", r.NooBaaRemote.Name)
+		return res, nil
+	}
+
+	if !system.CheckSystem(r.NooBaa) {
+		log.Infof("NooBaa not found or already deleted. Skip reconcile.")
+		return res, nil
+	}
+
+	err := r.ReconcilePhases()
+	if err != nil {
+		metrics.ReconcileError(metrics.ControllerNooBaaRemote, err)
+		if perr, isPERR := err.(*util.PersistentError); isPERR {
+			r.SetPhase(nbv1.NooBaaRemotePhaseRejected, perr.Reason, perr.Message)
+			log.Errorf("❌ Persistent Error: %s", err)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(r.NooBaaRemote, nil, corev1.EventTypeWarning, perr.Reason, perr.Reason, perr.Message)
+			}
+		} else if errors.Is(err, ErrRemoteUnreachable) {
+			res.RequeueAfter = UnreachableRetryInterval
+			r.SetPhase(nbv1.NooBaaRemotePhaseUnreachable, "RemoteUnreachable", err.Error())
+			log.Warnf("⏳ Remote Unreachable: %s", err)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(r.NooBaaRemote, nil, corev1.EventTypeWarning, "RemoteUnreachable", "RemoteUnreachable", err.Error())
+			}
+		} else {
+			res.RequeueAfter = 3 * time.Second
+			// leave current phase as is
+			r.SetPhase("", "TemporaryError", err.Error())
+			log.Warnf("⏳ Temporary Error: %s", err)
+		}
+	} else {
+		res.RequeueAfter = HealthCheckInterval
+		r.SetPhase(
+			nbv1.NooBaaRemotePhaseReady,
+			"NooBaaRemotePhaseReady",
+			"noobaa operator completed reconcile - noobaa remote is ready",
+		)
+		log.Infof("✅ Done")
+	}
+
+	err = r.UpdateStatus()
+	// if updateStatus will fail to update the CR for any reason we will continue to requeue the reconcile
+	// until the spec status will reflect the actual status of the noobaa remote
+	if err != nil {
+		res.RequeueAfter = 3 * time.Second
+		log.Warnf("⏳ Temporary Error: %s", err)
+	}
+	return res, nil
+}
+
+// ReconcilePhases runs the reconcile flow and populates NooBaaRemote.Status.
+func (r *Reconciler) ReconcilePhases() error {
+
+	if err := r.ReconcilePhaseVerifying(); err != nil {
+		return err
+	}
+	if err := r.ReconcilePhaseConfiguring(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetPhase updates the status phase and conditions
+func (r *Reconciler) SetPhase(phase nbv1.NooBaaRemotePhase, reason string, message string) {
+
+	c := &r.NooBaaRemote.Status.Conditions
+
+	if phase == "" {
+		r.Logger.Infof("SetPhase: temporary error during phase %q", r.NooBaaRemote.Status.Phase)
+		util.SetProgressingCondition(c, reason, message)
+		return
+	}
+
+	r.Logger.Infof("SetPhase: %s", phase)
+	r.NooBaaRemote.Status.Phase = phase
+	metrics.SetPhase("NooBaaRemote", r.NooBaaRemote.Namespace, r.NooBaaRemote.Name, string(phase))
+	switch phase {
+	case nbv1.NooBaaRemotePhaseReady:
+		util.SetAvailableCondition(c, reason, message)
+	case nbv1.NooBaaRemotePhaseRejected:
+		util.SetErrorCondition(c, reason, message)
+	case nbv1.NooBaaRemotePhaseUnreachable:
+		util.SetErrorCondition(c, reason, message)
+	default:
+		util.SetProgressingCondition(c, reason, message)
+	}
+}
+
+// UpdateStatus updates the noobaa remote status in kubernetes from the memory
+func (r *Reconciler) UpdateStatus() error {
+	err := r.Client.Status().Update(r.Ctx, r.NooBaaRemote)
+	if err != nil {
+		r.Logger.Errorf("UpdateStatus: %s", err)
+		return err
+	}
+	r.Logger.Infof("UpdateStatus: Done")
+	return nil
+}
+
+// ReconcilePhaseVerifying validates the spec, loads the credentials and checks the remote system reachability
+func (r *Reconciler) ReconcilePhaseVerifying() error {
+
+	r.SetPhase(
+		nbv1.NooBaaRemotePhaseVerifying,
+		"NooBaaRemotePhaseVerifying",
+		"noobaa operator started phase 1/2 - \"Verifying\"",
+	)
+
+	if err := validations.ValidateNooBaaRemote(r.NooBaaRemote); err != nil {
+		return util.NewPersistentError("ValidationError", err.Error())
+	}
+
+	if r.NooBaa.UID == "" {
+		return util.NewPersistentError("MissingSystem",
+			fmt.Sprintf("NooBaa system %q not found or deleted", r.NooBaa.Name))
+	}
+
+	r.Secret.Name = r.NooBaaRemote.Spec.Secret.Name
+	r.Secret.Namespace = r.NooBaaRemote.Spec.Secret.Namespace
+	if r.Secret.Namespace == "" {
+		r.Secret.Namespace = r.NooBaaRemote.Namespace
+	}
+	if !util.KubeCheck(r.Secret) {
+		return fmt.Errorf("NooBaaRemote %q secret %q not found in namespace %q",
+			r.NooBaaRemote.Name, r.Secret.Name, r.Secret.Namespace)
+	}
+	for _, key := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"} {
+		if r.Secret.StringData[key] == "" {
+			r.Secret.StringData[key] = util.MapAlternateKeysValue(r.Secret.StringData, key)
+		}
+		if r.Secret.StringData[key] == "" {
+			return util.NewPersistentError("InvalidSecret",
+				fmt.Sprintf("NooBaaRemote %q secret %q is missing %s", r.NooBaaRemote.Name, r.Secret.Name, key))
+		}
+	}
+
+	sysClient, err := system.Connect(false)
+	if err != nil {
+		return err
+	}
+	r.NBClient = sysClient.NBClient
+
+	return r.CheckReachability()
+}
+
+// CheckReachability checks the remote management endpoint from the operator and the remote S3 endpoint
+// from the local noobaa system, and records the result in the status.
+// Only the S3 endpoint is required for the namespace stores, so an unreachable management endpoint is just reported.
+func (r *Reconciler) CheckReachability() error {
+	reachability := &r.NooBaaRemote.Status.Reachability
+	now := metav1.Now()
+	reachability.LastCheckTime = &now
+	reachability.Message = ""
+	messages := []string{}
+
+	mgmtErr := r.checkMgmtEndpoint()
+	reachability.MgmtReachable = mgmtErr == nil
+	if mgmtErr != nil {
+		r.Logger.Warnf("NooBaaRemote %q management endpoint is unreachable: %s", r.NooBaaRemote.Name, mgmtErr)
+		messages = append(messages, fmt.Sprintf("management endpoint: %s", mgmtErr))
+	}
+
+	res, err := r.NBClient.CheckExternalConnectionAPI(nb.CheckExternalConnectionParams{
+		Name:                   "noobaa-remote-" + r.NooBaaRemote.Name,
+		EndpointType:           nb.EndpointTypeS3Compat,
+		Endpoint:               r.NooBaaRemote.Spec.S3Endpoint,
+		Identity:               nb.MaskedString(r.Secret.StringData["AWS_ACCESS_KEY_ID"]),
+		Secret:                 nb.MaskedString(r.Secret.StringData["AWS_SECRET_ACCESS_KEY"]),
+		AuthMethod:             getAuthMethod(r.NooBaaRemote.Spec.SignatureVersion),
+		IgnoreNameAlreadyExist: true,
+	})
+	if err != nil {
+		reachability.Message = strings.Join(messages, "; ")
+		return err
+	}
+	reachability.S3Status = string(res.Status)
+	reachability.S3Reachable = res.Status == nb.ExternalConnectionSuccess
+
+	switch res.Status {
+
+	case nb.ExternalConnectionSuccess:
+		// good
+
+	case nb.ExternalConnectionInvalidCredentials:
+		fallthrough
+	case nb.ExternalConnectionTimeSkew:
+		fallthrough
+	case nb.ExternalConnectionNotSupported:
+		messages = append(messages, fmt.Sprintf("S3 endpoint: %s", res.Status))
+		reachability.Message = strings.Join(messages, "; ")
+		return util.NewPersistentError(string(res.Status),
+			fmt.Sprintf("NooBaaRemote %q invalid connection to S3 endpoint %q: %s", r.NooBaaRemote.Name, r.NooBaaRemote.Spec.S3Endpoint, res.Status))
+
+	case nb.ExternalConnectionTimeout:
+		fallthrough
+	case nb.ExternalConnectionInvalidEndpoint:
+		fallthrough
+	case nb.ExternalConnectionUnknownFailure:
+		fallthrough
+	default:
+		messages = append(messages, fmt.Sprintf("S3 endpoint: %s %s", res.Status, res.Error.Message))
+		reachability.Message = strings.Join(messages, "; ")
+		return fmt.Errorf("%w: S3 endpoint %q status %s %s",
+			ErrRemoteUnreachable, r.NooBaaRemote.Spec.S3Endpoint, res.Status, res.Error.Message)
+	}
+
+	reachability.Message = strings.Join(messages, "; ")
+	return nil
+}
+
+// ReconcilePhaseConfiguring creates a namespace store for every remote bucket and removes
+// the namespace stores of buckets that were removed from the spec
+func (r *Reconciler) ReconcilePhaseConfiguring() error {
+
+	r.SetPhase(
+		nbv1.NooBaaRemotePhaseConfiguring,
+		"NooBaaRemotePhaseConfiguring",
+		"noobaa operator started phase 2/2 - \"Configuring\"",
+	)
+
+	existing, err := r.listNamespaceStores()
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]bool{}
+	statuses := []nbv1.NooBaaRemoteNamespaceStore{}
+	for i := range r.NooBaaRemote.Spec.Buckets {
+		bucket := &r.NooBaaRemote.Spec.Buckets[i]
+		nsStore, err := r.reconcileNamespaceStore(bucket)
+		if err != nil {
+			return err
+		}
+		desired[nsStore.Name] = true
+		statuses = append(statuses, nbv1.NooBaaRemoteNamespaceStore{
+			Name:   nsStore.Name,
+			Bucket: bucket.Name,
+			Phase:  nsStore.Status.Phase,
+		})
+	}
+
+	for i := range existing {
+		nsStore := &existing[i]
+		if desired[nsStore.Name] || nsStore.DeletionTimestamp != nil {
+			continue
+		}
+		r.Logger.Infof("Deleting NamespaceStore %q of a bucket that was removed from the remote", nsStore.Name)
+		if err := r.Client.Delete(r.Ctx, nsStore); err != nil {
+			return fmt.Errorf("failed to delete NamespaceStore %q: %w", nsStore.Name, err)
+		}
+	}
+
+	r.NooBaaRemote.Status.NamespaceStores = statuses
+	r.NooBaaRemote.Status.ObservedGeneration = r.NooBaaRemote.Generation
+
+	return nil
+}
+
+// reconcileNamespaceStore creates the namespace store of a remote bucket if it does not exist.
+// The namespace store reconciler creates the external connection and namespace resource on the local system.
+func (r *Reconciler) reconcileNamespaceStore(bucket *nbv1.NooBaaRemoteBucket) (*nbv1.NamespaceStore, error) {
+	nsStore := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_namespacestore_cr_yaml).(*nbv1.NamespaceStore)
+	nsStore.Name = util.GetNooBaaRemoteNamespaceStoreName(r.NooBaaRemote, bucket)
+	nsStore.Namespace = r.NooBaaRemote.Namespace
+
+	if util.KubeCheckQuiet(nsStore) {
+		if !metav1.IsControlledBy(nsStore, r.NooBaaRemote) {
+			return nil, util.NewPersistentError("NamespaceStoreConflict",
+				fmt.Sprintf("NamespaceStore %q of remote bucket %q already exists and is not owned by NooBaaRemote %q",
+					nsStore.Name, bucket.Name, r.NooBaaRemote.Name))
+		}
+		// namespace stores do not support changing their endpoint or target bucket, so existing stores are kept as is
+		s3 := nsStore.Spec.S3Compatible
+		if s3 == nil || s3.Endpoint != r.NooBaaRemote.Spec.S3Endpoint || s3.TargetBucket != bucket.Name {
+			r.Logger.Warnf("NamespaceStore %q does not match remote bucket %q, remove the bucket from the remote and add it again to recreate it",
+				nsStore.Name, bucket.Name)
+		}
+		return nsStore, nil
+	}
+
+	accessMode := bucket.AccessMode
+	if accessMode == "" {
+		accessMode = nbv1.AccessModeReadWrite
+	}
+	nsStore.Labels = map[string]string{LabelNooBaaRemote: r.NooBaaRemote.Name}
+	nsStore.Spec = nbv1.NamespaceStoreSpec{
+		Type:       nbv1.NSStoreTypeS3Compatible,
+		AccessMode: accessMode,
+		S3Compatible: &nbv1.S3CompatibleSpec{
+			TargetBucket:     bucket.Name,
+			Endpoint:         r.NooBaaRemote.Spec.S3Endpoint,
+			SignatureVersion: r.NooBaaRemote.Spec.SignatureVersion,
+			Secret: corev1.SecretReference{
+				Name:      r.Secret.Name,
+				Namespace: r.Secret.Namespace,
+			},
+		},
+	}
+	if err := validations.ValidateNamespaceStore(nsStore); err != nil {
+		return nil, util.NewPersistentError("InvalidNamespaceStore", err.Error())
+	}
+	if err := controllerutil.SetControllerReference(r.NooBaaRemote, nsStore, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Client.Create(r.Ctx, nsStore); err != nil {
+		return nil, fmt.Errorf("failed to create NamespaceStore %q: %w", nsStore.Name, err)
+	}
+	r.Logger.Infof("✅ Created NamespaceStore %q for remote bucket %q", nsStore.Name, bucket.Name)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(r.NooBaaRemote, nil, corev1.EventTypeNormal, "NamespaceStoreCreated", "NamespaceStoreCreated",
+			fmt.Sprintf("Created NamespaceStore %q for remote bucket %q", nsStore.Name, bucket.Name))
+	}
+	return nsStore, nil
+}
+
+// listNamespaceStores returns the namespace stores owned by the noobaa remote
+func (r *Reconciler) listNamespaceStores() ([]nbv1.NamespaceStore, error) {
+	list := &nbv1.NamespaceStoreList{}
+	err := r.Client.List(r.Ctx, list,
+		client.InNamespace(r.NooBaaRemote.Namespace),
+		client.MatchingLabels{LabelNooBaaRemote: r.NooBaaRemote.Name},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list NamespaceStores of NooBaaRemote %q: %w", r.NooBaaRemote.Name, err)
+	}
+	owned := []nbv1.NamespaceStore{}
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], r.NooBaaRemote) {
+			owned = append(owned, list.Items[i])
+		}
+	}
+	return owned, nil
+}
+
+// checkMgmtEndpoint returns nil when the remote management endpoint responds to http requests.
+// Remote systems usually serve certificates signed by the CA of their own cluster, so the certificate is not verified.
+func (r *Reconciler) checkMgmtEndpoint() error {
+	httpClient := &http.Client{
+		Transport: util.InsecureHTTPTransport,
+		Timeout:   10 * time.Second,
+	}
+	res, err := httpClient.Get(strings.TrimSuffix(r.NooBaaRemote.Spec.MgmtEndpoint, "/") + "/version")
+	if err != nil {
+		return err
+	}
+	util.SafeClose(res.Body, "Failed to close management endpoint response body")
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("responded with %s", res.Status)
+	}
+	return nil
+}
+
+// getAuthMethod returns the auth method of the S3 signature version, empty lets noobaa choose by the endpoint
+func getAuthMethod(signature nbv1.S3SignatureVersion) nb.CloudAuthMethod {
+	switch signature {
+	case nbv1.S3SignatureVersionV4:
+		return nb.CloudAuthMethodAwsV4
+	case nbv1.S3SignatureVersionV2:
+		return nb.CloudAuthMethodAwsV2
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,226 @@
+package obc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	obv1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/noobaaaccount"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/system"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+const (
+	// credentialRotationInterval is how often the OBCs are checked for a due credentials rotation
+	credentialRotationInterval = 5 * time.Minute
+
+	// lastCredentialRotationAnnotation on the OBC secret holds the time of the last rotation
+	lastCredentialRotationAnnotation = "noobaa.io/last-credential-rotation"
+
+	// credentialRotationPendingAnnotation on the OBC secret holds the time the rotated keys were staged
+	credentialRotationPendingAnnotation = "noobaa.io/credential-rotation-pending-since"
+)
+
+// RunCredentialRotation periodically rotates the credentials of the OBCs that set the
+// credentialRotationSchedule additional config, until the context is done
+func RunCredentialRotation(ctx context.Context, recorder events.EventRecorder) error {
+	log := logrus.WithField("obc", "credential-rotation")
+	ticker := time.NewTicker(credentialRotationInterval)
+	defer ticker.Stop()
+	for {
+		RotateCredentials(log, recorder)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateCredentials runs the due steps of the credentials rotation of all the OBCs provisioned by noobaa
+func RotateCredentials(log *logrus.Entry, recorder events.EventRecorder) {
+	list := &nbv1.ObjectBucketClaimList{
+		TypeMeta: metav1.TypeMeta{Kind: "ObjectBucketClaim"},
+	}
+	if !util.KubeList(list) {
+		return
+	}
+
+	var sysClient *system.Client
+	provisionerByClass := map[string]string{}
+
+	for i := range list.Items {
+		obc := &list.Items[i]
+		if obc.Spec.AdditionalConfig["credentialRotationSchedule"] == "" ||
+			obc.Status.Phase != obv1.ObjectBucketClaimStatusPhaseBound {
+			continue
+		}
+
+		className := obc.Spec.StorageClassName
+		if _, ok := provisionerByClass[className]; !ok {
+			sc := &storagev1.StorageClass{
+				TypeMeta:   metav1.TypeMeta{Kind: "StorageClass"},
+				ObjectMeta: metav1.ObjectMeta{Name: className},
+			}
+			if util.KubeCheckQuiet(sc) {
+				provisionerByClass[className] = sc.Provisioner
+			} else {
+				provisionerByClass[className] = ""
+			}
+		}
+		if provisionerByClass[className] != options.ObjectBucketProvisionerName() {
+			continue
+		}
+
+		if sysClient == nil {
+			var err error
+			sysClient, err = system.Connect(false)
+			if err != nil {
+				log.Errorf("Failed to connect to the system for credentials rotation: %v", err)
+				return
+			}
+		}
+
+		if err := rotateOBCCredentials(log, recorder, sysClient, obc, time.Now()); err != nil {
+			log.Errorf("Failed to rotate the credentials of OBC %s/%s: %v", obc.Namespace, obc.Name, err)
+			if recorder != nil {
+				recorder.Eventf(obc, nil, corev1.EventTypeWarning, "CredentialRotationFailed", "CredentialRotationFailed", "%s", err.Error())
+			}
+		}
+	}
+}
+
+// rotateOBCCredentials runs the due step of the credentials rotation of a single OBC.
+// The rotation state is kept in annotations of the OBC secret so that it is updated together with the keys.
+func rotateOBCCredentials(
+	log *logrus.Entry,
+	recorder events.EventRecorder,
+	sysClient *system.Client,
+	obc *nbv1.ObjectBucketClaim,
+	now time.Time,
+) error {
+	schedule := obc.Spec.AdditionalConfig["credentialRotationSchedule"]
+	gracePeriod, err := parseCredentialRotationGracePeriod(obc.Spec.AdditionalConfig["credentialRotationGracePeriod"])
+	if err != nil {
+		return err
+	}
+
+	ob := &nbv1.ObjectBucket{
+		TypeMeta:   metav1.TypeMeta{Kind: "ObjectBucket"},
+		ObjectMeta: metav1.ObjectMeta{Name: obc.Spec.ObjectBucketName},
+	}
+	if ob.Name == "" || !util.KubeCheckQuiet(ob) {
+		return fmt.Errorf("could not find the ObjectBucket %q of the OBC", ob.Name)
+	}
+	accountName := ob.Spec.AdditionalState["account"]
+	if accountName == "" {
+		return fmt.Errorf("ObjectBucket %q has no account", ob.Name)
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: obc.Name, Namespace: obc.Namespace},
+	}
+	if !util.KubeCheckQuiet(secret) {
+		return fmt.Errorf("could not find the secret %q of the OBC", secret.Name)
+	}
+	util.SecretResetStringDataFromData(secret)
+
+	since := obc.CreationTimestamp.Time
+	if t, err := time.Parse(time.RFC3339, secret.Annotations[lastCredentialRotationAnnotation]); err == nil {
+		since = t
+	}
+	pendingSince := time.Time{}
+	if t, err := time.Parse(time.RFC3339, secret.Annotations[credentialRotationPendingAnnotation]); err == nil {
+		pendingSince = t
+	}
+
+	step, _, err := util.NextCredentialRotationStep(schedule, gracePeriod, since, pendingSince, now)
+	if err != nil {
+		return err
+	}
+	if step == util.CredentialRotationWait {
+		return nil
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+
+	// staged keys can go missing if the secret was recreated, in that case the grace period starts over.
+	// previous keys mean the rotation was activated and only their removal from the account is left.
+	if step == util.CredentialRotationStage ||
+		(!noobaaaccount.HasStagedAccountKeys(secret) && !noobaaaccount.HasPreviousAccountKeys(secret)) {
+		if err := noobaaaccount.StageRotatedAccountKeys(sysClient.NBClient, accountName, secret); err != nil {
+			return err
+		}
+		if !util.KubeUpdate(secret) {
+			return fmt.Errorf("failed to stage the rotated keys in secret %q", secret.Name)
+		}
+		// the update replies the secret data, keep reading its keys as string data
+		util.SecretResetStringDataFromData(secret)
+		if err := noobaaaccount.AddStagedAccountKeys(sysClient.NBClient, accountName, secret); err != nil {
+			// the staged keys were discarded, the rotation is staged again on the next check
+			if !util.KubeUpdate(secret) {
+				log.Errorf("failed to remove the discarded keys from secret %q", secret.Name)
+			}
+			return err
+		}
+		// the grace period starts once the staged keys are valid
+		secret.Annotations[credentialRotationPendingAnnotation] = now.Format(time.RFC3339)
+		if !util.KubeUpdate(secret) {
+			return fmt.Errorf("failed to mark the rotation of secret %q as pending", secret.Name)
+		}
+		util.SecretResetStringDataFromData(secret)
+		log.Infof("✅ Staged rotated keys of OBC %s/%s", obc.Namespace, obc.Name)
+		if recorder != nil {
+			recorder.Eventf(obc, nil, corev1.EventTypeNormal, "CredentialRotationStarted", "CredentialRotationStarted",
+				"New S3 keys were published in secret %q and are valid now, the current keys stay valid until %s",
+				secret.Name, now.Add(gracePeriod).Format(time.RFC3339))
+		}
+		if gracePeriod > 0 {
+			return nil
+		}
+	}
+
+	if noobaaaccount.HasStagedAccountKeys(secret) {
+		if err := noobaaaccount.ActivateRotatedAccountKeys(secret); err != nil {
+			return err
+		}
+		if !util.KubeUpdate(secret) {
+			return fmt.Errorf("failed to update secret %q with the rotated keys", secret.Name)
+		}
+		util.SecretResetStringDataFromData(secret)
+	}
+	if err := noobaaaccount.RemovePreviousAccountKeys(sysClient.NBClient, accountName, secret); err != nil {
+		return err
+	}
+	secret.Annotations[lastCredentialRotationAnnotation] = now.Format(time.RFC3339)
+	delete(secret.Annotations, credentialRotationPendingAnnotation)
+	if !util.KubeUpdate(secret) {
+		return fmt.Errorf("failed to remove the previous keys from secret %q", secret.Name)
+	}
+	log.Infof("✅ Rotated the keys of OBC %s/%s", obc.Namespace, obc.Name)
+	if recorder != nil {
+		recorder.Eventf(obc, nil, corev1.EventTypeNormal, "CredentialsRotated", "CredentialsRotated",
+			"The S3 keys in secret %q were rotated, the previous keys are no longer valid", secret.Name)
+	}
+	return nil
+}
+
+// parseCredentialRotationGracePeriod parses the credentialRotationGracePeriod additional config,
+// an empty value returns the default grace period
+func parseCredentialRotationGracePeriod(value string) (time.Duration, error) {
+	if value == "" {
+		return util.DefaultCredentialRotationGracePeriod, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -67,6 +67,10 @@ func CmdCreate() *cobra.Command {
 		"Set quota max objects quantity config to requested bucket")
 	cmd.Flags().String("max-size", "",
 		"Set quota max size config to requested bucket")
+	cmd.Flags().String("credential-rotation-schedule", "",
+		"Set a cron schedule to rotate the OBC S3 credentials, e.g. \"0 0 1 */3 *\"")
+	cmd.Flags().String("credential-rotation-grace-period", "",
+		"Set the time both the current and the rotated credentials are valid before the current ones are removed (default 24h)")
 	// vectors bucket configuration
 	cmd.Flags().String("bucket-type", "",
 		"Set bucket type: '' (default) or 'vector'")
@@ -149,6 +153,8 @@ func RunCreate(cmd *cobra.Command, args []string) {
 	uid, _ := cmd.Flags().GetInt("uid")
 	distinguishedName, _ := cmd.Flags().GetString("distinguished-name")
 	bucketType, _ := cmd.Flags().GetString("bucket-type")
+	rotationSchedule, _ := cmd.Flags().GetString("credential-rotation-schedule")
+	rotationGracePeriod, _ := cmd.Flags().GetString("credential-rotation-grace-period")
 
 	if distinguishedName != "" && (gid > -1 || uid > -1) {
 		log.Fatalf(`❌ NSFS account config cannot include both distinguished name and UID/GID`)
@@ -252,6 +258,12 @@ func RunCreate(cmd *cobra.Command, args []string) {
 	if maxObjects != "" {
 		obc.Spec.AdditionalConfig["maxObjects"] = maxObjects
 	}
+	if rotationSchedule != "" {
+		obc.Spec.AdditionalConfig["credentialRotationSchedule"] = rotationSchedule
+	}
+	if rotationGracePeriod != "" {
+		obc.Spec.AdditionalConfig["credentialRotationGracePeriod"] = rotationGracePeriod
+	}
 
 	err := ValidateOBC(obc, true)
 	if err != nil {
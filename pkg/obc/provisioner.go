@@ -605,6 +605,13 @@ func (r *BucketRequest) CreateAndUpdateBucket(
 		}
 	}
 
+	if r.BucketClass.Spec.LifecyclePolicy != nil {
+		err = bucketclass.ApplyLifecyclePolicy(*r.BucketClass.Spec.LifecyclePolicy, r.BucketName, r.SysClient.NBClient)
+		if err != nil {
+			return fmt.Errorf("Provisioner Failed to set lifecycle policy on bucket %q with error: %v", r.BucketName, err)
+		}
+	}
+
 	log.Infof("✅ Successfully created bucket %q", r.BucketName)
 
 	return r.UpdateBucket()
@@ -48,6 +48,16 @@ func validateAdditionalConfig(objectName string, additionalConfig map[string]str
 		return err
 	}
 
+	if schedule := additionalConfig["credentialRotationSchedule"]; schedule != "" {
+		gracePeriod, err := parseCredentialRotationGracePeriod(additionalConfig["credentialRotationGracePeriod"])
+		if err != nil {
+			return fmt.Errorf("OBC %q specifies invalid credentialRotationGracePeriod: %v", objectName, err)
+		}
+		if err := validations.ValidateCredentialRotation(objectName, schedule, gracePeriod); err != nil {
+			return err
+		}
+	}
+
 	if bucketType := additionalConfig["bucketType"]; bucketType != "" && bucketType != "vector" {
 		return fmt.Errorf("OBC %q specifies unsupported bucketType %q (valid values: vector)",
 			objectName, bucketType)
@@ -262,6 +262,9 @@ func GenerateCSV(opConf *operator.Conf, csvParams *generateCSVParams) *operv1.Cl
 		util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_namespacestore_cr_yaml),
 		util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_bucketclass_cr_yaml),
 		util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaaccount_cr_yaml),
+		util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_bucketreplication_cr_yaml),
+		util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaremote_cr_yaml),
+		util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaperformanceprofile_cr_yaml),
 	})
 	util.Panic(err)
 
@@ -386,18 +389,27 @@ func GenerateCSV(opConf *operator.Conf, csvParams *generateCSVParams) *operv1.Cl
 			`Used in BucketClass to construct namespace policies.`,
 		"BucketClass": `Storage policy spec  tiering, mirroring, spreading, namespace policy. ` +
 			`Combines BackingStores Or NamespaceStores. Referenced by ObjectBucketClaims.`,
+		"BucketReplication": `Replication of a bucket or ObjectBucketClaim to destination buckets. ` +
+			`The operator applies the replication rules and reports the last sync state.`,
+		"NooBaaRemote": `A remote NooBaa system federated into this system. ` +
+			`The operator creates NamespaceStores for the remote buckets and reports the remote reachability.`,
+		"NooBaaPerformanceProfile": `A cluster wide bundle of resource and count settings for the NooBaa components. ` +
+			`Selected by name in the NooBaa performanceProfile in addition to the built-in profiles.`,
 		"ObjectBucketClaim": `Claim a bucket just like claiming a PV. ` +
 			`Automate you app bucket provisioning by creating OBC with your app deployment. ` +
 			`A secret and configmap (name=claim) will be created with access details for the app pods.`,
 		"ObjectBucket": `Used under-the-hood. Created per ObjectBucketClaim and keeps provisioning information.`,
 	}
 	crdDisplayNames := map[string]string{
-		"NooBaa":            "NooBaa",
-		"BackingStore":      "Backing Store",
-		"NamespaceStore":    "Namespace Store",
-		"BucketClass":       "Bucket Class",
-		"ObjectBucketClaim": "Object Bucket Claim",
-		"ObjectBucket":      "Object Bucket",
+		"NooBaa":                   "NooBaa",
+		"BackingStore":             "Backing Store",
+		"NamespaceStore":           "Namespace Store",
+		"BucketClass":              "Bucket Class",
+		"BucketReplication":        "Bucket Replication",
+		"NooBaaRemote":             "NooBaa Remote",
+		"NooBaaPerformanceProfile": "NooBaa Performance Profile",
+		"ObjectBucketClaim":        "Object Bucket Claim",
+		"ObjectBucket":             "Object Bucket",
 	}
 	const (
 		uiTectonic                     = "urn:alm:descriptor:com.tectonic.ui:"
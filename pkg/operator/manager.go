@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/noobaa/noobaa-operator/v5/pkg/admission"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
@@ -46,6 +48,8 @@ func RunOperator(cmd *cobra.Command, args []string) {
 		probeAddr = "0.0.0.0:8081"
 	}
 
+	loadRPCCallPolicyEnv()
+
 	config := util.KubeConfig()
 
 	// Become the leader before proceeding
@@ -143,3 +147,24 @@ func RunOperator(cmd *cobra.Command, args []string) {
 
 	wg.Wait()
 }
+
+// loadRPCCallPolicyEnv overrides the call policy options of the RPC calls to noobaa core
+// from the NOOBAA_RPC_TIMEOUT and NOOBAA_RPC_MAX_RETRIES env of the operator
+func loadRPCCallPolicyEnv() {
+	if v, ok := os.LookupEnv("NOOBAA_RPC_TIMEOUT"); ok {
+		timeout, err := time.ParseDuration(v)
+		if err != nil || timeout <= 0 {
+			log.Warnf("Ignoring invalid NOOBAA_RPC_TIMEOUT %q, using %s", v, options.RPCTimeout)
+		} else {
+			options.RPCTimeout = timeout
+		}
+	}
+	if v, ok := os.LookupEnv("NOOBAA_RPC_MAX_RETRIES"); ok {
+		retries, err := strconv.Atoi(v)
+		if err != nil || retries < 0 {
+			log.Warnf("Ignoring invalid NOOBAA_RPC_MAX_RETRIES %q, using %d", v, options.RPCMaxRetries)
+		} else {
+			options.RPCMaxRetries = retries
+		}
+	}
+}
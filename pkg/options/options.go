@@ -1,6 +1,8 @@
 package options
 
 import (
+	"time"
+
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
 	"github.com/noobaa/noobaa-operator/v5/version"
 
@@ -123,6 +125,14 @@ var DebugLevel = "default_level"
 // OperatorLogLevel controls the operator process log verbosity (warn/info/debug)
 var OperatorLogLevel = "info"
 
+// RPCTimeout limits every attempt of an RPC call from the operator to noobaa core.
+// It can be overridden by the NOOBAA_RPC_TIMEOUT env of the operator.
+var RPCTimeout = 120 * time.Second
+
+// RPCMaxRetries is the number of retries of read RPC calls to noobaa core after transport errors.
+// It can be overridden by the NOOBAA_RPC_MAX_RETRIES env of the operator.
+var RPCMaxRetries = 3
+
 // PVPoolDefaultStorageClass is used for PVC's allocation for the noobaa server data
 // it can be overridden for testing or different PV providers.
 var PVPoolDefaultStorageClass = ""
@@ -332,6 +342,14 @@ func init() {
 		&OperatorLogLevel, "operator-log-level",
 		OperatorLogLevel, "The operator process log verbosity (warn, info, debug)",
 	)
+	FlagSet.DurationVar(
+		&RPCTimeout, "rpc-timeout",
+		RPCTimeout, "The timeout of every attempt of an RPC call to noobaa core",
+	)
+	FlagSet.IntVar(
+		&RPCMaxRetries, "rpc-max-retries",
+		RPCMaxRetries, "The number of retries of read RPC calls to noobaa core after transport errors",
+	)
 	FlagSet.StringVar(
 		&PVPoolDefaultStorageClass, "pv-pool-default-storage-class",
 		PVPoolDefaultStorageClass, "The default storage class name for BackingStores of type pv-pool",
@@ -2,11 +2,18 @@ package sts
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
+	"github.com/noobaa/noobaa-operator/v5/pkg/noobaaaccount"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/system"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/validations"
 
 	"github.com/spf13/cobra"
 )
@@ -17,7 +24,8 @@ func Cmd() *cobra.Command {
 		Use:   "sts",
 		Short: "Manage the NooBaa Security Token Service",
 		Long: "Manage the NooBaa Security Token Service by assigning, updating or removing a NooBaa account's role config.\n" +
-			"The role config object must contain the keys 'role_name' and 'assume_role_policy', with their respective values.",
+			"For accounts that are managed by a NooBaaAccount, the role is set in spec.sts_role and applied by the operator.\n" +
+			"The legacy role config object must contain the keys 'role_name' and 'assume_role_policy', with their respective values.",
 	}
 	cmd.AddCommand(
 		CmdAssignRole(),
@@ -29,20 +37,15 @@ func Cmd() *cobra.Command {
 // CmdAssignRole returns a CLI command
 func CmdAssignRole() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "assign-role <noobaa-account-name> <role-config>",
+		Use:   "assign-role <noobaa-account-name>",
 		Short: "Assign a role config to a NooBaa account - note that this will override the existing role config",
 		Run:   RunAssign,
 	}
-	cmd.Flags().String("email", "", "The email of the account that will be updated")
-	err := cmd.MarkFlagRequired("email")
-	if err != nil {
-		log.Fatalf(`❌ Failed to mark email flag as required - %s`, err)
-	}
-	cmd.Flags().String("role_config", "", "The new value that the account's role_config should be set to")
-	err = cmd.MarkFlagRequired("role_config")
-	if err != nil {
-		log.Fatalf(`❌ Failed to mark role_config flag as required - %s`, err)
-	}
+	cmd.Flags().String("email", "", "The email of the account that will be updated, when the account name is not provided")
+	cmd.Flags().String("role_name", "", "The name of the role, defaults to the account name")
+	cmd.Flags().StringSlice("trusted_principals", nil, "The accounts that are allowed to assume the role")
+	cmd.Flags().StringSlice("allowed_actions", nil, "The STS actions the trusted principals are allowed (default sts:AssumeRole)")
+	cmd.Flags().String("role_config", "", "The new value that the account's role_config should be set to, instead of the role flags")
 	return cmd
 }
 
@@ -53,66 +56,145 @@ func CmdRemoveRole() *cobra.Command {
 		Short: "Remove a NooBaa account's role config",
 		Run:   RunRemove,
 	}
-	cmd.Flags().String("email", "", "The email of the account that will be updated")
-	err := cmd.MarkFlagRequired("email")
-	if err != nil {
-		log.Fatalf(`❌ Failed to mark email flag as required - %s`, err)
-	}
+	cmd.Flags().String("email", "", "The email of the account that will be updated, when the account name is not provided")
 	return cmd
 }
 
 // RunAssign runs a CLI command
 func RunAssign(cmd *cobra.Command, args []string) {
 	log := util.Logger()
-	email, _ := cmd.Flags().GetString("email")
+	name := getAccountName(cmd, args)
 	roleConfig, _ := cmd.Flags().GetString("role_config")
 
-	if !json.Valid([]byte(roleConfig)) {
-		log.Fatalf(`❌ The provided role configuration is not valid JSON`)
+	noobaaAccount := loadNooBaaAccount(name)
+	if noobaaAccount == nil {
+		// accounts that are not managed by a NooBaaAccount keep the imperative role config
+		var roleConfigObject interface{}
+		if roleConfig != "" {
+			if err := json.Unmarshal([]byte(roleConfig), &roleConfigObject); err != nil {
+				log.Fatalf(`❌ The provided role configuration is not valid JSON - %s`, err)
+			}
+		} else {
+			role, err := getSTSRoleFromFlags(cmd)
+			if err != nil {
+				log.Fatalf(`❌ %s`, err)
+			}
+			roleConfigObject = noobaaaccount.CreateRoleConfig(name, role)
+		}
+		updateAccountRoleConfig(nb.UpdateAccountParams{Email: name, RoleConfig: roleConfigObject})
+		log.Printf("✅ Assigned the role config of account %q", name)
+		return
 	}
 
-	sysClient, err := system.ConnectAuto()
+	var role *nbv1.AccountSTSRole
+	var err error
+	if roleConfig != "" {
+		role, err = ParseRoleConfig(roleConfig)
+	} else {
+		role, err = getSTSRoleFromFlags(cmd)
+	}
 	if err != nil {
-		log.Fatalf(`❌ Failed to create RPC client %s`, err)
+		log.Fatalf(`❌ %s`, err)
 	}
-	NBClient := sysClient.NBClient
 
-	var roleConfigObject interface{}
-	err = json.Unmarshal([]byte(roleConfig), &roleConfigObject)
-	if err != nil {
-		log.Fatalf("❌ Failed to parse role config - %s", err)
+	noobaaAccount.Spec.STSRole = role
+	if err := validations.ValidateAccountSTSRole(*noobaaAccount); err != nil {
+		log.Fatalf(`❌ %s`, err)
 	}
-	if err != nil {
-		log.Fatalf(`❌ Failed to read account - %s`, err)
+	if !util.KubeUpdate(noobaaAccount) {
+		log.Fatalf(`❌ Failed to update the STS role of NooBaaAccount %q`, name)
 	}
-	UpdateAccountParams := nb.UpdateAccountParams{
-		Email:      email,
-		RoleConfig: roleConfigObject,
+	log.Printf("✅ Set the STS role of NooBaaAccount %q, the operator will apply it to the account", name)
+}
+
+// RunRemove runs a CLI command
+func RunRemove(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+	name := getAccountName(cmd, args)
+
+	noobaaAccount := loadNooBaaAccount(name)
+	if noobaaAccount != nil && noobaaAccount.Spec.STSRole != nil {
+		noobaaAccount.Spec.STSRole = nil
+		if !util.KubeUpdate(noobaaAccount) {
+			log.Fatalf(`❌ Failed to remove the STS role of NooBaaAccount %q`, name)
+		}
+		log.Printf("✅ Removed the STS role of NooBaaAccount %q, the operator will remove it from the account", name)
+		return
 	}
 
-	err = NBClient.UpdateAccount(UpdateAccountParams)
-	if err != nil {
-		log.Fatalf(`❌ Failed to update account - %s`, err)
+	// the role config was not declared in a NooBaaAccount, so it is removed directly
+	updateAccountRoleConfig(nb.UpdateAccountParams{Email: name, RemoveRoleConfig: true})
+	log.Printf("✅ Removed the role config of account %q", name)
+}
+
+// ParseRoleConfig converts a legacy role config JSON to the STS role of a NooBaaAccount.
+// Only allow statements can be declared, since the STS role is a list of trusted principals.
+func ParseRoleConfig(roleConfig string) (*nbv1.AccountSTSRole, error) {
+	config := &nb.RoleConfig{}
+	if err := json.Unmarshal([]byte(roleConfig), config); err != nil {
+		return nil, fmt.Errorf("The provided role configuration is not valid JSON - %v", err)
+	}
+	role := &nbv1.AccountSTSRole{RoleName: config.RoleName}
+	for _, statement := range config.AssumeRolePolicy.Statement {
+		if !strings.EqualFold(statement.Effect, "allow") {
+			return nil, fmt.Errorf("The role config statement effect %q cannot be declared on a NooBaaAccount, only allow statements are supported", statement.Effect)
+		}
+		for _, principal := range statement.Principal {
+			if !util.Contains(role.TrustedPrincipals, principal) {
+				role.TrustedPrincipals = append(role.TrustedPrincipals, principal)
+			}
+		}
+		for _, action := range statement.Action {
+			if !util.Contains(role.AllowedActions, action) {
+				role.AllowedActions = append(role.AllowedActions, action)
+			}
+		}
 	}
+	return role, nil
 }
 
-// RunRemove runs a CLI command
-func RunRemove(cmd *cobra.Command, args []string) {
-	email, _ := cmd.Flags().GetString("email")
+func getSTSRoleFromFlags(cmd *cobra.Command) (*nbv1.AccountSTSRole, error) {
+	roleName, _ := cmd.Flags().GetString("role_name")
+	trustedPrincipals, _ := cmd.Flags().GetStringSlice("trusted_principals")
+	allowedActions, _ := cmd.Flags().GetStringSlice("allowed_actions")
+	if len(trustedPrincipals) == 0 {
+		return nil, fmt.Errorf("Missing expected flag: --trusted_principals or --role_config")
+	}
+	return &nbv1.AccountSTSRole{
+		RoleName:          roleName,
+		TrustedPrincipals: trustedPrincipals,
+		AllowedActions:    allowedActions,
+	}, nil
+}
 
-	sysClient, err := system.ConnectAuto()
-	if err != nil {
-		log.Fatalf(`❌ Failed to create RPC client %s`, err)
+func getAccountName(cmd *cobra.Command, args []string) string {
+	email, _ := cmd.Flags().GetString("email")
+	if len(args) == 1 && args[0] != "" {
+		return args[0]
 	}
-	NBClient := sysClient.NBClient
+	if email == "" {
+		log.Fatalf(`❌ Missing expected arguments: <noobaa-account-name> %s`, cmd.UsageString())
+	}
+	return email
+}
 
-	UpdateAccountParams := nb.UpdateAccountParams{
-		Email:            email,
-		RemoveRoleConfig: true,
+// loadNooBaaAccount returns the NooBaaAccount of the account, or nil when the account is not managed by one
+func loadNooBaaAccount(name string) *nbv1.NooBaaAccount {
+	noobaaAccount := util.KubeObject(bundle.File_deploy_crds_noobaa_io_v1alpha1_noobaaaccount_cr_yaml).(*nbv1.NooBaaAccount)
+	noobaaAccount.Name = name
+	noobaaAccount.Namespace = options.Namespace
+	if !util.KubeCheckQuiet(noobaaAccount) {
+		return nil
 	}
+	return noobaaAccount
+}
 
-	err = NBClient.UpdateAccount(UpdateAccountParams)
+func updateAccountRoleConfig(params nb.UpdateAccountParams) {
+	sysClient, err := system.ConnectAuto()
 	if err != nil {
-		log.Fatalf(`❌ Failed to remove the requested role config - %s`, err)
+		log.Fatalf(`❌ Failed to create RPC client %s`, err)
+	}
+	if err := sysClient.NBClient.UpdateAccount(params); err != nil {
+		log.Fatalf(`❌ Failed to update the role config of account %q - %s`, params.Email, err)
 	}
 }
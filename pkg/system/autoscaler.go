@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -33,8 +34,30 @@ import (
 
 const (
 	schema = "https://"
+
+	defaultAutoscalerCPUTarget = "80"
+	endpointPodsMatchers       = `namespace="%s",pod=~"noobaa-endpoint-.*"`
+	kedaTriggerTypePrometheus  = "prometheus"
 )
 
+// autoscalerAdapterMetrics maps the endpoint S3 metrics to the custom metrics served by the prometheus adapter rules
+var autoscalerAdapterMetrics = map[nbv1.AutoscalerMetricType]string{
+	nbv1.AutoscalerMetricRequestsPerSecond: "noobaa_endpoint_s3_requests_per_second",
+	nbv1.AutoscalerMetricLatencyP95:        "noobaa_endpoint_s3_latency_p95_seconds",
+	nbv1.AutoscalerMetricInflightOps:       "noobaa_endpoint_s3_inflight_requests",
+}
+
+// autoscalerKedaQueries are the prometheus queries of the keda triggers of the endpoint S3 metrics.
+// The requests and inflight ops are summed over the endpoints and divided by keda per pod (AverageValue),
+// while the latency is a single value for all the endpoints (Value)
+// The NooBaa_Endpoint_s3_* series are listed in doc/prometheus-monitoring.md
+var autoscalerKedaQueries = map[nbv1.AutoscalerMetricType]string{
+	nbv1.AutoscalerMetricRequestsPerSecond: `sum(rate(NooBaa_Endpoint_s3_requests_total{` + endpointPodsMatchers + `}[2m]))`,
+	nbv1.AutoscalerMetricLatencyP95: `histogram_quantile(0.95, sum(rate(NooBaa_Endpoint_s3_request_duration_seconds_bucket{` +
+		endpointPodsMatchers + `}[2m])) by (le))`,
+	nbv1.AutoscalerMetricInflightOps: `sum(NooBaa_Endpoint_s3_inflight_requests{` + endpointPodsMatchers + `})`,
+}
+
 func (r *Reconciler) reconcileAutoscaler() error {
 	log := r.Logger.WithField("func", "reconcileAutoscaler")
 	var err error
@@ -60,8 +83,8 @@ func (r *Reconciler) reconcileAutoscaler() error {
 			return err
 		}
 	case nbv1.AutoscalerTypeHPAV2:
-		if r.AdapterHPA.Spec.Metrics[0].Type == autoscalingv2.ResourceMetricSourceType {
-			r.Logger.Debugf("HPAV2 autoscaler type is %s, skipping HPAV2 resource creation", autoscalingv2.ResourceMetricSourceType)
+		if !usesPrometheusMetrics(getAutoscalerMetrics(&r.NooBaa.Spec.Autoscaler)) {
+			r.Logger.Debugf("HPAV2 autoscaler metrics are of type %s, skipping HPAV2 resource creation", autoscalingv2.ResourceMetricSourceType)
 			if err := r.ReconcileObject(r.AdapterHPA, r.reconcileAdapterHPA); err != nil {
 				return err
 			}
@@ -260,30 +283,40 @@ func (r *Reconciler) autoscaleKeda(prometheus *monitoringv1.Prometheus) error {
 		return errors.New("Keda deployment not ready")
 	}
 	log.Infof("✅  Keda found")
-	if r.KedaScaled.Spec.Triggers[0].AuthenticationRef != nil {
+	triggers, err := getKedaTriggers(getAutoscalerMetrics(&r.NooBaa.Spec.Autoscaler), r.Request.Namespace)
+	if err != nil {
+		return err
+	}
+	if usesPrometheusMetrics(getAutoscalerMetrics(&r.NooBaa.Spec.Autoscaler)) {
 		promethesNamespace := prometheus.Namespace
 		serviceAccountName := prometheus.Spec.ServiceAccountName
 
-		authSecretTargetRef := r.createAuthSecretTargetRef(serviceAccountName, promethesNamespace, log)
+		authSecretTargetRef := r.createAuthSecretTargetRef(triggers, serviceAccountName, promethesNamespace, log)
 		r.KedaTriggerAuthentication.Spec.SecretTargetRef = authSecretTargetRef
 		if !util.KubeCreateSkipExisting(r.KedaTriggerAuthentication) {
 			log.Errorf("❌ Failed to create KedaTriggerAuthetication")
 			return fmt.Errorf("Failed to create KedaTriggerAuthetication")
 		}
 
-		//create ScaledObject
-		r.KedaScaled.Spec.Triggers[0].AuthenticationRef = &kedav1alpha1.AuthenticationRef{
-			Name: r.KedaTriggerAuthentication.Name,
-		}
 		prometheusURL, err := getPrometheusURL(serviceAccountName, promethesNamespace)
 		if err != nil {
 			return err
 		}
-		r.KedaScaled.Spec.Triggers[0].Metadata["serverAddress"] = prometheusURL
-		query := strings.Replace(r.KedaScaled.Spec.Triggers[0].Metadata["query"], "placeholder", r.Request.Namespace, 1)
-		r.KedaScaled.Spec.Triggers[0].Metadata["query"] = query
+		for i := range triggers {
+			if triggers[i].Type != kedaTriggerTypePrometheus {
+				continue
+			}
+			triggers[i].AuthenticationRef = &kedav1alpha1.AuthenticationRef{
+				Name: r.KedaTriggerAuthentication.Name,
+			}
+			triggers[i].Metadata["serverAddress"] = prometheusURL
+		}
 	}
-	if err := r.ReconcileObject(r.KedaScaled, r.reconcileKedaReplicaCount); err != nil {
+	if err := r.ReconcileObject(r.KedaScaled, func() error {
+		r.KedaScaled.Spec.Triggers = triggers
+		r.reconcileKedaScaleDownStabilization()
+		return r.reconcileKedaReplicaCount()
+	}); err != nil {
 		return err
 	}
 	return nil
@@ -296,15 +329,40 @@ func (r *Reconciler) reconcileKedaReplicaCount() error {
 	return nil
 }
 
-func (r *Reconciler) createAuthSecretTargetRef(serviceAccountName, promethesNamespace string, log *logrus.Entry) []kedav1alpha1.AuthSecretTargetRef {
+// reconcileKedaScaleDownStabilization sets the scale down stabilization window of the HPA created by keda
+func (r *Reconciler) reconcileKedaScaleDownStabilization() {
+	window := r.NooBaa.Spec.Autoscaler.ScaleDownStabilizationWindowSeconds
+	if window == nil {
+		return
+	}
+	if r.KedaScaled.Spec.Advanced == nil {
+		r.KedaScaled.Spec.Advanced = &kedav1alpha1.AdvancedConfig{}
+	}
+	advanced := r.KedaScaled.Spec.Advanced
+	if advanced.HorizontalPodAutoscalerConfig == nil {
+		advanced.HorizontalPodAutoscalerConfig = &kedav1alpha1.HorizontalPodAutoscalerConfig{}
+	}
+	hpaConfig := advanced.HorizontalPodAutoscalerConfig
+	if hpaConfig.Behavior == nil {
+		hpaConfig.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{}
+	}
+	if hpaConfig.Behavior.ScaleDown == nil {
+		hpaConfig.Behavior.ScaleDown = &autoscalingv2.HPAScalingRules{}
+	}
+	windowSeconds := *window
+	hpaConfig.Behavior.ScaleDown.StabilizationWindowSeconds = &windowSeconds
+}
+
+func (r *Reconciler) createAuthSecretTargetRef(triggers []kedav1alpha1.ScaleTriggers, serviceAccountName, promethesNamespace string,
+	log *logrus.Entry) []kedav1alpha1.AuthSecretTargetRef {
 	var authSecretTargetRef []kedav1alpha1.AuthSecretTargetRef
 	err := r.checkAndCreatePrometheusSecret(serviceAccountName, promethesNamespace)
 	if err != nil {
 		// changes to work with minikube deployment
 		log.Warnf("❌ Failed to get Prometheus Secret: %s", err)
-		authSecretTargetRef = r.creatBasicSecretTargetRef(log)
+		authSecretTargetRef = r.creatBasicSecretTargetRef(triggers, log)
 	} else {
-		r.KedaScaled.Spec.Triggers[0].Metadata["authModes"] = "bearer"
+		setKedaPrometheusMetadata(triggers, "authModes", "bearer")
 		authSecretTargetRef = []kedav1alpha1.AuthSecretTargetRef{
 			{
 				Parameter: "bearerToken",
@@ -321,7 +379,7 @@ func (r *Reconciler) createAuthSecretTargetRef(serviceAccountName, promethesName
 	return authSecretTargetRef
 }
 
-func (r *Reconciler) creatBasicSecretTargetRef(log *logrus.Entry) []kedav1alpha1.AuthSecretTargetRef {
+func (r *Reconciler) creatBasicSecretTargetRef(triggers []kedav1alpha1.ScaleTriggers, log *logrus.Entry) []kedav1alpha1.AuthSecretTargetRef {
 	kedaSecretObject := util.KubeObject(bundle.File_deploy_internal_hpa_keda_secret_yaml).(*corev1.Secret)
 	kedaSecretObject.Namespace = r.Request.Namespace
 	kedaSecretObject.Data = map[string][]byte{
@@ -331,8 +389,8 @@ func (r *Reconciler) creatBasicSecretTargetRef(log *logrus.Entry) []kedav1alpha1
 		log.Errorf("❌ dummy prometheus secret not created %s", kedaSecretObject)
 	}
 
-	r.KedaScaled.Spec.Triggers[0].Metadata["unsafeSsl"] = "true"
-	r.KedaScaled.Spec.Triggers[0].Metadata["authModes"] = "basic"
+	setKedaPrometheusMetadata(triggers, "unsafeSsl", "true")
+	setKedaPrometheusMetadata(triggers, "authModes", "basic")
 	authSecretTargetRef := []kedav1alpha1.AuthSecretTargetRef{
 		{
 			Parameter: "username",
@@ -470,7 +528,7 @@ func (r *Reconciler) reconcilePrometheusAdapterResources(prometheus *monitoringv
 	adapterConfigMap := util.KubeObject(bundle.File_deploy_internal_hpav2_configmap_adapter_yaml).(*corev1.ConfigMap)
 	adapterConfigMap.Namespace = r.Request.Namespace
 	config := adapterConfigMap.Data["config.yaml"]
-	adapterConfigMap.Data["config.yaml"] = strings.Replace(config, "placeholder", r.Request.Namespace, 1)
+	adapterConfigMap.Data["config.yaml"] = strings.ReplaceAll(config, "placeholder", r.Request.Namespace)
 	if err := r.ReconcileObject(adapterConfigMap, nil); err != nil {
 		return err
 	}
@@ -511,11 +569,11 @@ func (r *Reconciler) reconcileAdapterHPA() error {
 	minReplicas, maxReplicas := r.getEndpointMinMaxCount()
 	r.AdapterHPA.Spec.MinReplicas = &minReplicas
 	r.AdapterHPA.Spec.MaxReplicas = maxReplicas
-	// target value should be nil otherwise Kubernetes HPA reconciler tries to validate the value,
-	// for type AverageValue this validation is not required.
-	if r.AdapterHPA.Spec.Metrics[0].Object != nil {
-		r.AdapterHPA.Spec.Metrics[0].Object.Target.Value = nil
+	metrics, err := getHPAV2Metrics(getAutoscalerMetrics(&r.NooBaa.Spec.Autoscaler))
+	if err != nil {
+		return err
 	}
+	r.AdapterHPA.Spec.Metrics = metrics
 
 	if r.AdapterHPA.Spec.Behavior == nil {
 		// update the behavior of an existing HPA object that has no defined behavior
@@ -523,10 +581,142 @@ func (r *Reconciler) reconcileAdapterHPA() error {
 		desired := util.KubeObject(bundle.File_deploy_internal_hpav2_autoscaling_yaml).(*autoscalingv2.HorizontalPodAutoscaler)
 		r.AdapterHPA.Spec.Behavior = desired.Spec.Behavior
 	}
+	if window := r.NooBaa.Spec.Autoscaler.ScaleDownStabilizationWindowSeconds; window != nil {
+		if r.AdapterHPA.Spec.Behavior.ScaleDown == nil {
+			r.AdapterHPA.Spec.Behavior.ScaleDown = &autoscalingv2.HPAScalingRules{}
+		}
+		windowSeconds := *window
+		r.AdapterHPA.Spec.Behavior.ScaleDown.StabilizationWindowSeconds = &windowSeconds
+	}
 
 	return nil
 }
 
+// getAutoscalerMetrics returns the metrics to scale the endpoints on, defaulting to the CPU utilization
+func getAutoscalerMetrics(autoscaler *nbv1.AutoscalerSpec) []nbv1.AutoscalerMetric {
+	if len(autoscaler.Metrics) == 0 {
+		return []nbv1.AutoscalerMetric{{Type: nbv1.AutoscalerMetricCPU, Target: defaultAutoscalerCPUTarget}}
+	}
+	return autoscaler.Metrics
+}
+
+// usesPrometheusMetrics returns true when any of the metrics is read from prometheus
+func usesPrometheusMetrics(metrics []nbv1.AutoscalerMetric) bool {
+	for _, metric := range metrics {
+		if metric.Type != nbv1.AutoscalerMetricCPU {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAutoscalerMetricTarget parses the target value of an autoscaler metric.
+// A cpu target is a utilization percent, a latencyP95 target is a duration that is returned in seconds,
+// and the other targets are positive quantities
+func ParseAutoscalerMetricTarget(metric nbv1.AutoscalerMetric) (resource.Quantity, error) {
+	switch metric.Type {
+	case nbv1.AutoscalerMetricCPU:
+		percent, err := strconv.Atoi(metric.Target)
+		if err != nil || percent <= 0 {
+			return resource.Quantity{}, fmt.Errorf("autoscaler metric %s target %q must be a positive percent", metric.Type, metric.Target)
+		}
+		return *resource.NewQuantity(int64(percent), resource.DecimalSI), nil
+	case nbv1.AutoscalerMetricLatencyP95:
+		latency, err := time.ParseDuration(metric.Target)
+		if err != nil || latency <= 0 {
+			return resource.Quantity{}, fmt.Errorf("autoscaler metric %s target %q must be a positive duration such as 250ms", metric.Type, metric.Target)
+		}
+		return *resource.NewMilliQuantity(latency.Milliseconds(), resource.DecimalSI), nil
+	case nbv1.AutoscalerMetricRequestsPerSecond, nbv1.AutoscalerMetricInflightOps:
+		target, err := resource.ParseQuantity(metric.Target)
+		if err != nil || target.Sign() <= 0 {
+			return resource.Quantity{}, fmt.Errorf("autoscaler metric %s target %q must be a positive number", metric.Type, metric.Target)
+		}
+		return target, nil
+	default:
+		return resource.Quantity{}, fmt.Errorf("unknown autoscaler metric type %q", metric.Type)
+	}
+}
+
+// getHPAV2Metrics renders the autoscaler metrics to HPA metrics.
+// The endpoint S3 metrics are pod metrics served by the prometheus adapter rules.
+func getHPAV2Metrics(metrics []nbv1.AutoscalerMetric) ([]autoscalingv2.MetricSpec, error) {
+	hpaMetrics := make([]autoscalingv2.MetricSpec, 0, len(metrics))
+	for _, metric := range metrics {
+		target, err := ParseAutoscalerMetricTarget(metric)
+		if err != nil {
+			return nil, err
+		}
+		if metric.Type == nbv1.AutoscalerMetricCPU {
+			utilization := int32(target.Value())
+			hpaMetrics = append(hpaMetrics, autoscalingv2.MetricSpec{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: &utilization,
+					},
+				},
+			})
+			continue
+		}
+		hpaMetrics = append(hpaMetrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: autoscalerAdapterMetrics[metric.Type]},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &target,
+				},
+			},
+		})
+	}
+	return hpaMetrics, nil
+}
+
+// getKedaTriggers renders the autoscaler metrics to keda triggers.
+// The prometheus triggers are returned without the server address and authentication which depend on the prometheus deployment.
+func getKedaTriggers(metrics []nbv1.AutoscalerMetric, namespace string) ([]kedav1alpha1.ScaleTriggers, error) {
+	triggers := make([]kedav1alpha1.ScaleTriggers, 0, len(metrics))
+	for _, metric := range metrics {
+		target, err := ParseAutoscalerMetricTarget(metric)
+		if err != nil {
+			return nil, err
+		}
+		if metric.Type == nbv1.AutoscalerMetricCPU {
+			triggers = append(triggers, kedav1alpha1.ScaleTriggers{
+				Type:       "cpu",
+				MetricType: autoscalingv2.UtilizationMetricType,
+				Metadata:   map[string]string{"value": target.String()},
+			})
+			continue
+		}
+		metricType := autoscalingv2.AverageValueMetricType
+		if metric.Type == nbv1.AutoscalerMetricLatencyP95 {
+			metricType = autoscalingv2.ValueMetricType
+		}
+		triggers = append(triggers, kedav1alpha1.ScaleTriggers{
+			Type:       kedaTriggerTypePrometheus,
+			Name:       autoscalerAdapterMetrics[metric.Type],
+			MetricType: metricType,
+			Metadata: map[string]string{
+				"query":     fmt.Sprintf(autoscalerKedaQueries[metric.Type], namespace),
+				"threshold": strconv.FormatFloat(target.AsApproximateFloat64(), 'f', -1, 64),
+			},
+		})
+	}
+	return triggers, nil
+}
+
+func setKedaPrometheusMetadata(triggers []kedav1alpha1.ScaleTriggers, key string, value string) {
+	for i := range triggers {
+		if triggers[i].Type == kedaTriggerTypePrometheus {
+			triggers[i].Metadata[key] = value
+		}
+	}
+}
+
 func (r *Reconciler) reconcileHPAV2RBAC() error {
 	serviceAccount := util.KubeObject(bundle.File_deploy_service_acount_hpav2_yaml).(*corev1.ServiceAccount)
 	serviceAccount.Namespace = r.Request.Namespace
@@ -0,0 +1,96 @@
+package system
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetAutoscalerMetricsDefault(t *testing.T) {
+	metrics := getAutoscalerMetrics(&nbv1.AutoscalerSpec{})
+	if len(metrics) != 1 || metrics[0].Type != nbv1.AutoscalerMetricCPU || metrics[0].Target != "80" {
+		t.Fatalf("expected the default cpu metric, got %+v", metrics)
+	}
+	if usesPrometheusMetrics(metrics) {
+		t.Fatalf("expected the default cpu metric not to use prometheus")
+	}
+}
+
+func TestGetHPAV2Metrics(t *testing.T) {
+	metrics, err := getHPAV2Metrics([]nbv1.AutoscalerMetric{
+		{Type: nbv1.AutoscalerMetricCPU, Target: "70"},
+		{Type: nbv1.AutoscalerMetricRequestsPerSecond, Target: "500"},
+		{Type: nbv1.AutoscalerMetricLatencyP95, Target: "250ms"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(metrics) != 3 {
+		t.Fatalf("expected 3 metrics, got %d", len(metrics))
+	}
+
+	cpu := metrics[0]
+	if cpu.Type != autoscalingv2.ResourceMetricSourceType || cpu.Resource.Name != corev1.ResourceCPU ||
+		*cpu.Resource.Target.AverageUtilization != 70 {
+		t.Errorf("unexpected cpu metric %+v", cpu)
+	}
+
+	rps := metrics[1]
+	if rps.Type != autoscalingv2.PodsMetricSourceType || rps.Pods.Metric.Name != "noobaa_endpoint_s3_requests_per_second" ||
+		rps.Pods.Target.AverageValue.Value() != 500 {
+		t.Errorf("unexpected requests per second metric %+v", rps)
+	}
+
+	latency := metrics[2]
+	if latency.Pods.Metric.Name != "noobaa_endpoint_s3_latency_p95_seconds" || latency.Pods.Target.AverageValue.String() != "250m" {
+		t.Errorf("unexpected latency metric %+v", latency)
+	}
+
+	if _, err := getHPAV2Metrics([]nbv1.AutoscalerMetric{{Type: nbv1.AutoscalerMetricInflightOps, Target: "many"}}); err == nil {
+		t.Errorf("expected an error for an invalid target")
+	}
+}
+
+func TestGetKedaTriggers(t *testing.T) {
+	triggers, err := getKedaTriggers([]nbv1.AutoscalerMetric{
+		{Type: nbv1.AutoscalerMetricCPU, Target: "70"},
+		{Type: nbv1.AutoscalerMetricLatencyP95, Target: "250ms"},
+		{Type: nbv1.AutoscalerMetricInflightOps, Target: "64"},
+	}, "noobaa-ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(triggers) != 3 {
+		t.Fatalf("expected 3 triggers, got %d", len(triggers))
+	}
+
+	if triggers[0].Type != "cpu" || triggers[0].MetricType != autoscalingv2.UtilizationMetricType || triggers[0].Metadata["value"] != "70" {
+		t.Errorf("unexpected cpu trigger %+v", triggers[0])
+	}
+
+	latency := triggers[1]
+	if latency.Type != kedaTriggerTypePrometheus || latency.MetricType != autoscalingv2.ValueMetricType ||
+		latency.Metadata["threshold"] != "0.25" {
+		t.Errorf("unexpected latency trigger %+v", latency)
+	}
+	if !strings.Contains(latency.Metadata["query"], `namespace="noobaa-ns"`) ||
+		!strings.Contains(latency.Metadata["query"], "histogram_quantile(0.95") {
+		t.Errorf("unexpected latency query %q", latency.Metadata["query"])
+	}
+
+	inflight := triggers[2]
+	if inflight.MetricType != autoscalingv2.AverageValueMetricType || inflight.Metadata["threshold"] != "64" {
+		t.Errorf("unexpected inflight trigger %+v", inflight)
+	}
+
+	setKedaPrometheusMetadata(triggers, "authModes", "bearer")
+	if _, ok := triggers[0].Metadata["authModes"]; ok {
+		t.Errorf("expected the cpu trigger to have no prometheus auth metadata")
+	}
+	if triggers[1].Metadata["authModes"] != "bearer" || triggers[2].Metadata["authModes"] != "bearer" {
+		t.Errorf("expected the prometheus triggers to have the auth metadata")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,11 +13,15 @@ import (
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/cnpg"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
 	secv1 "github.com/openshift/api/security/v1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -33,6 +38,24 @@ const (
 	defaultCalcMemoryKB     = int64(16 * 1024 * 1024) // 16GB in KB — used when DBResources not specified
 	defaultCalcCPU          = int64(4)                // used when DBResources not specified
 	defaultFailoverDelaySec = int32(30)
+
+	objectStoreBackupCompression  = "gzip"
+	objectStoreCAKey              = "ca.crt"
+	maxReportedObjectStoreBackups = 20
+
+	// recoverySourceName is the name of the external cluster pointing at the object store archive being recovered from
+	recoverySourceName = "noobaa-db-backup-source"
+
+	dbDataMountPath                = "/var/lib/postgresql/data"
+	dbAutoGrowCheckInterval        = 5 * time.Minute
+	defaultDBAutoGrowThreshold     = 80
+	defaultDBAutoGrowStep          = "20%"
+	dbAutoGrowReasonBelowThreshold = "BelowThreshold"
+	dbAutoGrowReasonExpanded       = "Expanded"
+	dbAutoGrowReasonExpanding      = "Expanding"
+	dbAutoGrowReasonAtMaxSize      = "AtMaxSize"
+	dbAutoGrowReasonNotExpandable  = "ExpansionNotAllowed"
+	dbAutoGrowReasonCheckFailed    = "UsageCheckFailed"
 )
 
 // ReconcileCNPGCluster reconciles the CNPG cluster
@@ -43,7 +66,8 @@ const (
 //   - Create a new CNPG cluster
 //
 // 2. Reconciling recovery from a snapshot - CNPG cluster was deleted by the user to initiate a recovery from a snapshot
-//   - In this case we need to create a new CNPG cluster and set the bootstrap configuration to recover from the snapshot
+//   - In this case we need to create a new CNPG cluster and set the bootstrap configuration to recover from the snapshot,
+//     or from the backup object store, optionally replaying the archived WAL files up to a recovery target
 //   - All other pods (core, endpoints) should be stopped before starting the recovery
 //
 // 3. Reconciling an existing CNPG cluster with no standalone DB - CNPG cluster exists and DB statefulset does not exist
@@ -79,14 +103,39 @@ func (r *Reconciler) ReconcileCNPGCluster() error {
 		r.NooBaa.Status.DBStatus.DBClusterStatus = nbv1.DBClusterStatusReady
 		r.NooBaa.Status.DBStatus.ActualVolumeSize = r.CNPGCluster.Spec.StorageConfiguration.Size
 
-		if r.NooBaa.Status.DBStatus.RecoveryStatus != nil {
-			r.NooBaa.Status.DBStatus.RecoveryStatus.Status = nbv1.DBRecoveryStatusCompleted
+		if recoveryStatus := r.NooBaa.Status.DBStatus.RecoveryStatus; recoveryStatus != nil &&
+			recoveryStatus.Status != nbv1.DBRecoveryStatusCompleted {
+			r.cnpgLog("database recovery completed")
+			recoveryStatus.Status = nbv1.DBRecoveryStatusCompleted
+			recoveryStatus.CompletionTime = &metav1.Time{Time: time.Now()}
+			recoveryStatus.ClusterPhase = r.CNPGCluster.Status.Phase
+			recoveryStatus.Message = ""
 		}
 
 	} else {
+		// report the recovery progress as reported by the cluster
+		if recoveryStatus := r.NooBaa.Status.DBStatus.RecoveryStatus; recoveryStatus != nil &&
+			recoveryStatus.Status == nbv1.DBRecoveryStatusRunning {
+			recoveryStatus.ClusterPhase = r.CNPGCluster.Status.Phase
+			recoveryStatus.Message = r.CNPGCluster.Status.PhaseReason
+			if isClusterPhaseFailed(r.CNPGCluster.Status.Phase) {
+				r.cnpgLogError("database recovery failed. cluster phase: %q, reason: %q",
+					r.CNPGCluster.Status.Phase, r.CNPGCluster.Status.PhaseReason)
+				recoveryStatus.Status = nbv1.DBRecoveryStatusFailed
+				if recoveryStatus.Message == "" {
+					recoveryStatus.Message = r.CNPGCluster.Status.Phase
+				}
+			}
+		}
 		return fmt.Errorf("cnpg cluster is not ready")
 	}
 
+	// Expand the DB volume when it is nearly full
+	if err := r.reconcileDBAutoGrow(); err != nil {
+		r.cnpgLogError("got error reconciling DB volume auto grow. error: %v", err)
+		return err
+	}
+
 	// Reconcile backup configuration
 	if err := r.reconcileDBBackup(); err != nil {
 		r.cnpgLogError("got error reconciling backup. error: %v", err)
@@ -202,23 +251,24 @@ func (r *Reconciler) reconcileDBCluster() error {
 			return err
 		}
 
+		// the new cluster archives to the object store under the server name set in its spec
+		if r.CNPGCluster.Spec.Backup != nil && r.CNPGCluster.Spec.Backup.BarmanObjectStore != nil {
+			r.setObjectStoreServerName(r.CNPGCluster.Spec.Backup.BarmanObjectStore.ServerName)
+		}
+
 		// update the DB status
 		if r.CNPGCluster.Spec.Bootstrap.Recovery == nil {
 			r.NooBaa.Status.DBStatus.DBClusterStatus = nbv1.DBClusterStatusCreating
 		} else {
 			r.NooBaa.Status.DBStatus.DBClusterStatus = nbv1.DBClusterStatusRecovering
-			snapshotName := ""
-			if r.NooBaa.Spec.DBSpec.DBRecovery != nil {
-				snapshotName = r.NooBaa.Spec.DBSpec.DBRecovery.VolumeSnapshotName
-			} else if r.NooBaa.Status.DBStatus.RecoveryStatus != nil {
-				// fall back to previously recorded snapshot name, if any
-				snapshotName = r.NooBaa.Status.DBStatus.RecoveryStatus.SnapshotName
-			}
-			r.NooBaa.Status.DBStatus.RecoveryStatus = &nbv1.DBRecoveryStatus{
-				Status:       nbv1.DBRecoveryStatusRunning,
-				SnapshotName: snapshotName,
-				RecoveryTime: &metav1.Time{Time: time.Now()},
+			if r.NooBaa.Status.DBStatus.RecoveryStatus == nil {
+				r.NooBaa.Status.DBStatus.RecoveryStatus = &nbv1.DBRecoveryStatus{}
 			}
+			recoveryStatus := r.NooBaa.Status.DBStatus.RecoveryStatus
+			recoveryStatus.Status = nbv1.DBRecoveryStatusRunning
+			recoveryStatus.RecoveryTime = &metav1.Time{Time: time.Now()}
+			recoveryStatus.CompletionTime = nil
+			recoveryStatus.Message = "the database cluster was created and is recovering"
 		}
 	} else {
 		// Handle Cluster CRD changes
@@ -281,10 +331,10 @@ func (r *Reconciler) reconcileClusterSpec(dbSpec *nbv1.NooBaaDBSpec) error {
 	}
 
 	// update number of instances
-	r.CNPGCluster.Spec.Instances = getDBInstances(r.NooBaa)
+	r.CNPGCluster.Spec.Instances = getDBInstances(r.NooBaa, r.PerformanceProfile)
 
 	// update db resources
-	r.CNPGCluster.Spec.Resources = getDBResources(r.NooBaa)
+	r.CNPGCluster.Spec.Resources = getDBResources(r.NooBaa, r.PerformanceProfile)
 
 	// update db volume resources
 	// update the storage configuration
@@ -336,24 +386,40 @@ func (r *Reconciler) reconcileClusterSpec(dbSpec *nbv1.NooBaaDBSpec) error {
 
 	// Configure backup settings if specified
 	if backupSpec := r.NooBaa.Spec.DBSpec.DBBackup; backupSpec != nil {
-		if backupSpec.VolumeSnapshot == nil {
-			r.cnpgLogError("volume snapshot backup configuration is not specified")
-			return fmt.Errorf("volume snapshot backup configuration is not specified")
+		if err := validateDBBackupMethod(backupSpec); err != nil {
+			r.cnpgLogError("%v", err)
+			return err
 		}
-		offlineBackup := false
 		if r.CNPGCluster.Spec.Backup == nil {
-			r.CNPGCluster.Spec.Backup = &cnpgv1.BackupConfiguration{
-				VolumeSnapshot: &cnpgv1.VolumeSnapshotConfiguration{
-					ClassName: backupSpec.VolumeSnapshot.VolumeSnapshotClass,
-					Online:    &offlineBackup,
+			r.CNPGCluster.Spec.Backup = &cnpgv1.BackupConfiguration{}
+		}
+		if backupSpec.ObjectStore != nil {
+			credentialsSecret := &corev1.Secret{
+				TypeMeta: metav1.TypeMeta{Kind: "Secret"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      backupSpec.ObjectStore.CredentialsSecret,
+					Namespace: r.NooBaa.Namespace,
 				},
 			}
+			if !util.KubeCheckQuiet(credentialsSecret) {
+				r.cnpgLogError("object store backup credentials secret %q not found", credentialsSecret.Name)
+				return fmt.Errorf("object store backup credentials secret %q not found", credentialsSecret.Name)
+			}
+			r.CNPGCluster.Spec.Backup.VolumeSnapshot = nil
+			setDesiredObjectStoreConf(r.CNPGCluster.Spec.Backup, backupSpec.ObjectStore, credentialsSecret, r.getObjectStoreServerName())
+			if r.CNPGCluster.UID != "" {
+				// keep the status in sync with the cluster in case the update after the cluster creation was lost
+				r.setObjectStoreServerName(r.CNPGCluster.Spec.Backup.BarmanObjectStore.ServerName)
+			}
 		} else {
+			offlineBackup := false
 			if r.CNPGCluster.Spec.Backup.VolumeSnapshot == nil {
 				r.CNPGCluster.Spec.Backup.VolumeSnapshot = &cnpgv1.VolumeSnapshotConfiguration{}
 			}
 			r.CNPGCluster.Spec.Backup.VolumeSnapshot.ClassName = backupSpec.VolumeSnapshot.VolumeSnapshotClass
 			r.CNPGCluster.Spec.Backup.VolumeSnapshot.Online = &offlineBackup
+			r.CNPGCluster.Spec.Backup.BarmanObjectStore = nil
+			r.CNPGCluster.Spec.Backup.RetentionPolicy = ""
 		}
 	} else {
 		// Remove backup configuration if not specified
@@ -367,6 +433,91 @@ func (r *Reconciler) reconcileClusterSpec(dbSpec *nbv1.NooBaaDBSpec) error {
 
 }
 
+// validateDBBackupMethod verifies that exactly one backup method is configured
+func validateDBBackupMethod(backupSpec *nbv1.DBBackupSpec) error {
+	if backupSpec.VolumeSnapshot == nil && backupSpec.ObjectStore == nil {
+		return fmt.Errorf("db backup configuration requires either volumeSnapshot or objectStore")
+	}
+	if backupSpec.VolumeSnapshot != nil && backupSpec.ObjectStore != nil {
+		return fmt.Errorf("db backup configuration accepts only one of volumeSnapshot or objectStore")
+	}
+	return nil
+}
+
+// setDesiredObjectStoreConf sets the cluster backup configuration to take base backups and
+// continuously archive WAL files to the S3 object store. Existing fields are updated in place
+// to keep the defaults set by cnpg and avoid needless cluster updates.
+// An empty server name archives under the name of the cluster.
+func setDesiredObjectStoreConf(backupConf *cnpgv1.BackupConfiguration, objectStore *nbv1.ObjectStoreBackupSpec,
+	credentialsSecret *corev1.Secret, serverName string) {
+	if backupConf.BarmanObjectStore == nil {
+		backupConf.BarmanObjectStore = &cnpgv1.BarmanObjectStoreConfiguration{}
+	}
+	barmanConf := backupConf.BarmanObjectStore
+	barmanConf.DestinationPath = objectStore.DestinationPath
+	barmanConf.ServerName = serverName
+	barmanConf.EndpointURL = objectStore.Endpoint
+	barmanConf.EndpointCA = nil
+	if objectStore.EndpointCASecret != "" {
+		barmanConf.EndpointCA = getSecretKeySelector(objectStore.EndpointCASecret, objectStoreCAKey)
+	}
+
+	s3Credentials := &cnpgv1.S3Credentials{
+		AccessKeyIDReference:     getSecretKeySelector(credentialsSecret.Name, "AWS_ACCESS_KEY_ID"),
+		SecretAccessKeyReference: getSecretKeySelector(credentialsSecret.Name, "AWS_SECRET_ACCESS_KEY"),
+	}
+	_, hasRegion := credentialsSecret.Data["AWS_REGION"]
+	if _, ok := credentialsSecret.StringData["AWS_REGION"]; ok {
+		hasRegion = true
+	}
+	if hasRegion {
+		s3Credentials.RegionReference = getSecretKeySelector(credentialsSecret.Name, "AWS_REGION")
+	}
+	barmanConf.BarmanCredentials = cnpgv1.BarmanCredentials{AWS: s3Credentials}
+
+	if barmanConf.Wal == nil {
+		barmanConf.Wal = &cnpgv1.WalBackupConfiguration{}
+	}
+	barmanConf.Wal.Compression = objectStoreBackupCompression
+	if barmanConf.Data == nil {
+		barmanConf.Data = &cnpgv1.DataBackupConfiguration{}
+	}
+	barmanConf.Data.Compression = objectStoreBackupCompression
+
+	// cnpg deletes base backups and WAL files from the object store according to the retention policy
+	backupConf.RetentionPolicy = objectStore.RetentionPolicy
+}
+
+// getObjectStoreServerName returns the server name the database is archived under in the object store.
+// The server name of an existing cluster is never replaced, since a recovered cluster archives under a new
+// server name and must not archive into the archive it was recovered from. The status is only a fallback
+// for a cluster that is not created yet or does not archive to the object store yet.
+func (r *Reconciler) getObjectStoreServerName() string {
+	if r.CNPGCluster.UID != "" && r.CNPGCluster.Spec.Backup != nil && r.CNPGCluster.Spec.Backup.BarmanObjectStore != nil {
+		if serverName := r.CNPGCluster.Spec.Backup.BarmanObjectStore.ServerName; serverName != "" {
+			return serverName
+		}
+	}
+	if backupStatus := r.NooBaa.Status.DBStatus.BackupStatus; backupStatus != nil {
+		return backupStatus.ServerName
+	}
+	return ""
+}
+
+func (r *Reconciler) setObjectStoreServerName(serverName string) {
+	if r.NooBaa.Status.DBStatus.BackupStatus == nil {
+		r.NooBaa.Status.DBStatus.BackupStatus = &nbv1.DBBackupStatus{}
+	}
+	r.NooBaa.Status.DBStatus.BackupStatus.ServerName = serverName
+}
+
+func getSecretKeySelector(secretName string, key string) *cnpgv1.SecretKeySelector {
+	return &cnpgv1.SecretKeySelector{
+		LocalObjectReference: cnpgv1.LocalObjectReference{Name: secretName},
+		Key:                  key,
+	}
+}
+
 // cleanupDBBackup removes the scheduled backup configuration if it exists
 func (r *Reconciler) cleanupDBBackup() error {
 	scheduledBackup := cnpg.GetCnpgScheduledBackupObj(r.CNPGCluster.Namespace, r.getBackupResourceName())
@@ -381,13 +532,35 @@ func (r *Reconciler) cleanupDBBackup() error {
 }
 
 func (r *Reconciler) reconcileClusterRecovery() error {
+	dbSpec := r.NooBaa.Spec.DBSpec
+	recoverySpec := dbSpec.DBRecovery
+
+	// the object store provides the base backup when there is no volume snapshot,
+	// and the archived WAL files to replay up to the recovery target
+	useObjectStore := recoverySpec.VolumeSnapshotName == "" || recoverySpec.RecoveryTarget != nil
+	if useObjectStore && (dbSpec.DBBackup == nil || dbSpec.DBBackup.ObjectStore == nil) {
+		r.cnpgLogError("invalid recovery configuration. recovery from the WAL archive requires dbBackup.objectStore")
+		return fmt.Errorf("invalid recovery configuration, recovery from the WAL archive requires dbBackup.objectStore")
+	}
+	source := nbv1.DBRecoverySourceVolumeSnapshot
+	if recoverySpec.VolumeSnapshotName == "" {
+		source = nbv1.DBRecoverySourceObjectStore
+	}
+	sourceServerName := ""
+	if useObjectStore {
+		sourceServerName = r.getRecoverySourceServerName()
+	}
 
-	r.cnpgLog("recovery configuration found, setting up cluster to recover from snapshot")
+	r.cnpgLog("recovery configuration found, setting up cluster to recover from %s", source)
 
 	// set recovery status to pending
 	r.NooBaa.Status.DBStatus.RecoveryStatus = &nbv1.DBRecoveryStatus{
-		Status:       nbv1.DBRecoveryStatusPending,
-		SnapshotName: r.NooBaa.Spec.DBSpec.DBRecovery.VolumeSnapshotName,
+		Status:         nbv1.DBRecoveryStatusPending,
+		SnapshotName:   recoverySpec.VolumeSnapshotName,
+		Source:         source,
+		ServerName:     sourceServerName,
+		RecoveryTarget: recoverySpec.RecoveryTarget.DeepCopy(),
+		Message:        "waiting for noobaa-core and noobaa-endpoint pods to be terminated",
 	}
 
 	// stop core and endpoints pods and wait for them to be terminated
@@ -401,22 +574,23 @@ func (r *Reconciler) reconcileClusterRecovery() error {
 		return fmt.Errorf("waiting for noobaa-core and noobaa-endpoint pods to be terminated")
 	}
 
-	r.cnpgLog("setting up cluster to recover from snapshot %q", r.NooBaa.Spec.DBSpec.DBRecovery.VolumeSnapshotName)
+	r.cnpgLog("setting up cluster to recover from %s, volume snapshot %q, server name %q, recovery target %+v",
+		source, recoverySpec.VolumeSnapshotName, sourceServerName, recoverySpec.RecoveryTarget)
 
-	VolSnapshotAPIGroup := storagesnapshotv1.GroupName
 	//setup once the pods are terminated, set recovery in the bootstrap configuration and continue to create the cluster
 	r.CNPGCluster.Spec.Bootstrap = &cnpgv1.BootstrapConfiguration{
-		Recovery: &cnpgv1.BootstrapRecovery{
-			Database: noobaaDBName,
-			Owner:    noobaaDBUser,
-			VolumeSnapshots: &cnpgv1.DataSource{
-				Storage: corev1.TypedLocalObjectReference{
-					Kind:     "VolumeSnapshot",
-					Name:     r.NooBaa.Spec.DBSpec.DBRecovery.VolumeSnapshotName,
-					APIGroup: &VolSnapshotAPIGroup,
-				},
-			},
-		},
+		Recovery: getDesiredBootstrapRecovery(recoverySpec),
+	}
+
+	if useObjectStore {
+		// the backup configuration of the object store was set by reconcileClusterSpec
+		r.CNPGCluster.Spec.ExternalClusters = []cnpgv1.ExternalCluster{{
+			Name:              recoverySourceName,
+			BarmanObjectStore: getRecoverySourceObjectStoreConf(r.CNPGCluster.Spec.Backup.BarmanObjectStore, sourceServerName),
+		}}
+		// archive the recovered database under a new server name, since archiving to the
+		// archive being recovered from would fail and could overwrite the WAL files of the source
+		r.CNPGCluster.Spec.Backup.BarmanObjectStore.ServerName = r.CNPGCluster.Name + "-" + time.Now().UTC().Format("20060102150405")
 	}
 
 	// delete the existing scheduled backup configuration to avoid a creation of a new snapshot while recovering
@@ -431,6 +605,236 @@ func (r *Reconciler) reconcileClusterRecovery() error {
 
 }
 
+// getRecoverySourceServerName returns the server name of the object store archive to recover from
+func (r *Reconciler) getRecoverySourceServerName() string {
+	if serverName := r.NooBaa.Spec.DBSpec.DBRecovery.ServerName; serverName != "" {
+		return serverName
+	}
+	if serverName := r.getObjectStoreServerName(); serverName != "" {
+		return serverName
+	}
+	return r.CNPGCluster.Name
+}
+
+// getDesiredBootstrapRecovery returns the cluster bootstrap configuration to recover from a volume snapshot
+// or from the object store, replaying the archived WAL files up to the recovery target if one is set
+func getDesiredBootstrapRecovery(recoverySpec *nbv1.DBRecoverySpec) *cnpgv1.BootstrapRecovery {
+	recovery := &cnpgv1.BootstrapRecovery{
+		Database: noobaaDBName,
+		Owner:    noobaaDBUser,
+	}
+	if recoverySpec.VolumeSnapshotName != "" {
+		VolSnapshotAPIGroup := storagesnapshotv1.GroupName
+		recovery.VolumeSnapshots = &cnpgv1.DataSource{
+			Storage: corev1.TypedLocalObjectReference{
+				Kind:     "VolumeSnapshot",
+				Name:     recoverySpec.VolumeSnapshotName,
+				APIGroup: &VolSnapshotAPIGroup,
+			},
+		}
+	}
+	if recoverySpec.VolumeSnapshotName == "" || recoverySpec.RecoveryTarget != nil {
+		recovery.Source = recoverySourceName
+	}
+	if target := recoverySpec.RecoveryTarget; target != nil {
+		recovery.RecoveryTarget = &cnpgv1.RecoveryTarget{
+			BackupID:   target.BackupID,
+			TargetTime: target.TargetTime,
+			TargetLSN:  target.TargetLSN,
+			TargetName: target.TargetName,
+			Exclusive:  target.Exclusive,
+		}
+	}
+	return recovery
+}
+
+// getRecoverySourceObjectStoreConf returns the object store configuration of the archive to recover from
+func getRecoverySourceObjectStoreConf(backupConf *cnpgv1.BarmanObjectStoreConfiguration, serverName string) *cnpgv1.BarmanObjectStoreConfiguration {
+	sourceConf := &cnpgv1.BarmanObjectStoreConfiguration{
+		DestinationPath:   backupConf.DestinationPath,
+		EndpointURL:       backupConf.EndpointURL,
+		BarmanCredentials: *backupConf.BarmanCredentials.DeepCopy(),
+		ServerName:        serverName,
+	}
+	if backupConf.EndpointCA != nil {
+		sourceConf.EndpointCA = backupConf.EndpointCA.DeepCopy()
+	}
+	if backupConf.Wal != nil {
+		sourceConf.Wal = backupConf.Wal.DeepCopy()
+	}
+	return sourceConf
+}
+
+// reconcileDBAutoGrow checks the usage of the DB volume on the primary instance periodically,
+// and raises the requested size of the cluster storage by the growth step when the usage crosses the threshold.
+// Failing to check the usage or to expand is reported in the auto grow condition and does not fail the reconcile.
+func (r *Reconciler) reconcileDBAutoGrow() error {
+	autoGrow := r.NooBaa.Spec.DBSpec.DBAutoGrow
+	dbStatus := r.NooBaa.Status.DBStatus
+	if autoGrow == nil {
+		conditionsv1.RemoveStatusCondition(&r.NooBaa.Status.Conditions, nbv1.ConditionTypeDBVolumeAutoGrow)
+		dbStatus.VolumeUsedPercent = 0
+		dbStatus.VolumeUsageCheckTime = nil
+		return nil
+	}
+	if dbStatus.VolumeUsageCheckTime != nil && time.Since(dbStatus.VolumeUsageCheckTime.Time) < dbAutoGrowCheckInterval {
+		return nil
+	}
+	primaryPod := r.CNPGCluster.Status.CurrentPrimary
+	if primaryPod == "" {
+		return nil
+	}
+
+	usedPercent, err := util.GetVolumeUsedPercent(r.CNPGCluster.Namespace, primaryPod, "postgres", dbDataMountPath)
+	dbStatus.VolumeUsageCheckTime = &metav1.Time{Time: time.Now()}
+	if err != nil {
+		r.setDBAutoGrowCondition(corev1.ConditionFalse, dbAutoGrowReasonCheckFailed,
+			fmt.Sprintf("failed to check the usage of the database volume on pod %s: %v", primaryPod, err))
+		return nil
+	}
+	dbStatus.VolumeUsedPercent = usedPercent
+
+	threshold := autoGrow.ThresholdPercent
+	if threshold == 0 {
+		threshold = defaultDBAutoGrowThreshold
+	}
+	if usedPercent < threshold {
+		r.setDBAutoGrowCondition(corev1.ConditionTrue, dbAutoGrowReasonBelowThreshold,
+			fmt.Sprintf("the database volume is %d%% used, below the threshold of %d%%", usedPercent, threshold))
+		return nil
+	}
+
+	currentSize := r.CNPGCluster.Spec.StorageConfiguration.Size
+	newSize, err := getDBAutoGrowSize(currentSize, autoGrow.GrowthStep, autoGrow.MaxSize)
+	if err != nil {
+		r.setDBAutoGrowCondition(corev1.ConditionFalse, dbAutoGrowReasonNotExpandable, err.Error())
+		return nil
+	}
+	if newSize == "" {
+		msg := fmt.Sprintf("the database volume is %d%% used and already at the maximum size %s", usedPercent, autoGrow.MaxSize)
+		r.Recorder.Eventf(r.NooBaa, nil, corev1.EventTypeWarning, "DBVolumeAtMaxSize", "DBVolumeAtMaxSize", msg)
+		r.setDBAutoGrowCondition(corev1.ConditionFalse, dbAutoGrowReasonAtMaxSize, msg)
+		return nil
+	}
+
+	resizing, err := r.isDBVolumeResizing(primaryPod)
+	if err != nil {
+		r.cnpgLogError("got error checking the resize of the database volume. error: %v", err)
+		return nil
+	}
+	if resizing {
+		r.setDBAutoGrowCondition(corev1.ConditionTrue, dbAutoGrowReasonExpanding,
+			fmt.Sprintf("the database volume is %d%% used, waiting for the expansion to %s to complete", usedPercent, currentSize))
+		return nil
+	}
+
+	if err := r.checkDBStorageClassExpandable(); err != nil {
+		msg := fmt.Sprintf("the database volume is %d%% used but cannot be expanded: %v", usedPercent, err)
+		r.Recorder.Eventf(r.NooBaa, nil, corev1.EventTypeWarning, "DBVolumeExpansionNotAllowed", "DBVolumeExpansionNotAllowed", msg)
+		r.setDBAutoGrowCondition(corev1.ConditionFalse, dbAutoGrowReasonNotExpandable, msg)
+		return nil
+	}
+
+	r.cnpgLog("the database volume is %d%% used, expanding it from %s to %s", usedPercent, currentSize, newSize)
+	r.CNPGCluster.Spec.StorageConfiguration.Size = newSize
+	if err := r.Client.Update(r.Ctx, r.CNPGCluster); err != nil {
+		r.cnpgLogError("got error expanding the database volume. error: %v", err)
+		return err
+	}
+	msg := fmt.Sprintf("the database volume was %d%% used and is expanded from %s to %s", usedPercent, currentSize, newSize)
+	r.Recorder.Eventf(r.NooBaa, nil, corev1.EventTypeNormal, "DBVolumeExpanded", "DBVolumeExpanded", msg)
+	r.setDBAutoGrowCondition(corev1.ConditionTrue, dbAutoGrowReasonExpanded, msg)
+	dbStatus.LastAutoGrowTime = &metav1.Time{Time: time.Now()}
+	dbStatus.ActualVolumeSize = newSize
+	return nil
+}
+
+func (r *Reconciler) setDBAutoGrowCondition(status corev1.ConditionStatus, reason string, message string) {
+	conditionsv1.SetStatusCondition(&r.NooBaa.Status.Conditions, conditionsv1.Condition{
+		LastHeartbeatTime: metav1.NewTime(time.Now()),
+		Type:              nbv1.ConditionTypeDBVolumeAutoGrow,
+		Status:            status,
+		Reason:            reason,
+		Message:           message,
+	})
+	if status != corev1.ConditionTrue {
+		r.cnpgLogError("DB volume auto grow %s: %s", reason, message)
+	}
+}
+
+// isDBVolumeResizing returns true when the requested size of the instance volume is larger than its capacity
+func (r *Reconciler) isDBVolumeResizing(instanceName string) (bool, error) {
+	// cnpg names the volume of an instance after the instance
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Client.Get(r.Ctx, client.ObjectKey{Namespace: r.CNPGCluster.Namespace, Name: instanceName}, pvc); err != nil {
+		return false, err
+	}
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	return requested.Cmp(capacity) > 0, nil
+}
+
+// checkDBStorageClassExpandable returns an error when the storage class of the DB volume does not allow expansion
+func (r *Reconciler) checkDBStorageClassExpandable() error {
+	storageClassName := r.CNPGCluster.Spec.StorageConfiguration.StorageClass
+	if storageClassName == nil || *storageClassName == "" {
+		return fmt.Errorf("the database volume has no storage class")
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := r.Client.Get(r.Ctx, client.ObjectKey{Name: *storageClassName}, storageClass); err != nil {
+		return err
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return fmt.Errorf("storage class %q does not allow volume expansion", *storageClassName)
+	}
+	return nil
+}
+
+// getDBAutoGrowSize returns the size to expand the DB volume to, rounded up to a whole Gi and capped at maxSize.
+// An empty size means the volume is already at the maximum size.
+func getDBAutoGrowSize(currentSize string, growthStep string, maxSize string) (string, error) {
+	current, err := resource.ParseQuantity(currentSize)
+	if err != nil {
+		return "", fmt.Errorf("invalid database volume size %q: %v", currentSize, err)
+	}
+	maxQuantity, err := resource.ParseQuantity(maxSize)
+	if err != nil {
+		return "", fmt.Errorf("invalid auto grow maxSize %q: %v", maxSize, err)
+	}
+	if current.Cmp(maxQuantity) >= 0 {
+		return "", nil
+	}
+	if growthStep == "" {
+		growthStep = defaultDBAutoGrowStep
+	}
+
+	var step int64
+	if percentStr, isPercent := strings.CutSuffix(growthStep, "%"); isPercent {
+		percent, err := strconv.Atoi(percentStr)
+		if err != nil || percent <= 0 {
+			return "", fmt.Errorf("invalid auto grow growthStep %q", growthStep)
+		}
+		step = current.Value() / 100 * int64(percent)
+	} else {
+		stepQuantity, err := resource.ParseQuantity(growthStep)
+		if err != nil || stepQuantity.Sign() <= 0 {
+			return "", fmt.Errorf("invalid auto grow growthStep %q", growthStep)
+		}
+		step = stepQuantity.Value()
+	}
+
+	const gi = int64(1024 * 1024 * 1024)
+	newSize := (current.Value() + step + gi - 1) / gi * gi
+	if newSize <= current.Value() {
+		newSize += gi
+	}
+	newQuantity := resource.NewQuantity(newSize, resource.BinarySI)
+	if newQuantity.Cmp(maxQuantity) > 0 {
+		return maxQuantity.String(), nil
+	}
+	return newQuantity.String(), nil
+}
+
 // reconcileDBBackup reconciles the backup configuration for the CNPG cluster
 func (r *Reconciler) reconcileDBBackup() error {
 	if r.NooBaa.Spec.DBSpec.DBBackup == nil {
@@ -440,6 +844,7 @@ func (r *Reconciler) reconcileDBBackup() error {
 			return err
 		}
 		r.NooBaa.Status.DBStatus.BackupStatus = nil
+		metrics.SetDBBackupTime(r.NooBaa.Namespace, r.NooBaa.Name, nil)
 		return nil
 	}
 
@@ -447,10 +852,9 @@ func (r *Reconciler) reconcileDBBackup() error {
 		r.NooBaa.Status.DBStatus.BackupStatus = &nbv1.DBBackupStatus{}
 	}
 
-	// currently only volume snapshot backup is supported. VolumeSnapshot is required.
-	if r.NooBaa.Spec.DBSpec.DBBackup.VolumeSnapshot == nil {
-		r.cnpgLogError("volume snapshot backup configuration is not specified")
-		return fmt.Errorf("volume snapshot backup configuration is not specified")
+	if err := validateDBBackupMethod(r.NooBaa.Spec.DBSpec.DBBackup); err != nil {
+		r.cnpgLogError("%v", err)
+		return err
 	}
 
 	// Create or update ScheduledBackup
@@ -458,6 +862,7 @@ func (r *Reconciler) reconcileDBBackup() error {
 		r.cnpgLogError("got error reconciling scheduled backup. error: %v", err)
 		return err
 	}
+	metrics.SetDBBackupTime(r.NooBaa.Namespace, r.NooBaa.Name, r.NooBaa.Status.DBStatus.BackupStatus.LastBackupTime)
 
 	// reconcile the backup retention
 	if err := r.reconcileBackupRetention(); err != nil {
@@ -475,6 +880,7 @@ func (r *Reconciler) getBackupResourceName() string {
 func (r *Reconciler) reconcileScheduledBackup() error {
 	backupSpec := r.NooBaa.Spec.DBSpec.DBBackup
 	offlineBackup := false
+	immediateBackup := true
 	// convert the standard cron schedule to the cnpg cron schedule
 	cnpgSchedule, err := convertToSixFieldCron(backupSpec.Schedule)
 	if err != nil {
@@ -487,8 +893,16 @@ func (r *Reconciler) reconcileScheduledBackup() error {
 		// Update spec if needed
 		scheduledBackup.Spec.Schedule = cnpgSchedule
 		scheduledBackup.Spec.Cluster.Name = r.CNPGCluster.Name
-		scheduledBackup.Spec.Method = cnpgv1.BackupMethodVolumeSnapshot
-		scheduledBackup.Spec.Online = &offlineBackup
+		if backupSpec.ObjectStore != nil {
+			scheduledBackup.Spec.Method = cnpgv1.BackupMethodBarmanObjectStore
+			scheduledBackup.Spec.Online = nil
+			// archived WAL files are useless for recovery without a base backup, so take the first one right away
+			scheduledBackup.Spec.Immediate = &immediateBackup
+		} else {
+			scheduledBackup.Spec.Method = cnpgv1.BackupMethodVolumeSnapshot
+			scheduledBackup.Spec.Online = &offlineBackup
+			scheduledBackup.Spec.Immediate = nil
+		}
 		scheduledBackup.Spec.Target = cnpgv1.BackupTargetStandby
 		if scheduledBackup.Status.LastScheduleTime != nil {
 			r.NooBaa.Status.DBStatus.BackupStatus.LastBackupTime = scheduledBackup.Status.LastScheduleTime
@@ -501,6 +915,20 @@ func (r *Reconciler) reconcileScheduledBackup() error {
 }
 
 func (r *Reconciler) reconcileBackupRetention() error {
+	if r.NooBaa.Spec.DBSpec.DBBackup.ObjectStore != nil {
+		return r.reconcileObjectStoreBackupRetention()
+	}
+	return r.reconcileVolumeSnapshotRetention()
+}
+
+func (r *Reconciler) reconcileVolumeSnapshotRetention() error {
+	// clear the object store backup status left from a previous backup configuration
+	backupStatus := r.NooBaa.Status.DBStatus.BackupStatus
+	backupStatus.Backups = nil
+	backupStatus.FirstRecoverabilityPoint = nil
+	backupStatus.ContinuousArchiving = false
+	backupStatus.ContinuousArchivingMessage = ""
+
 	maxSnapshots := r.NooBaa.Spec.DBSpec.DBBackup.VolumeSnapshot.MaxSnapshots
 	if maxSnapshots == 0 {
 		r.cnpgLog("backup retention is not specified, skipping")
@@ -554,6 +982,147 @@ func (r *Reconciler) reconcileBackupRetention() error {
 	return nil
 }
 
+// reconcileObjectStoreBackupRetention deletes the backup resources that are out of the retention policy
+// and reports the object store backups and the WAL archiving state in the backup status.
+// The backup data itself is deleted from the object store by cnpg according to the same retention policy.
+func (r *Reconciler) reconcileObjectStoreBackupRetention() error {
+	objectStore := r.NooBaa.Spec.DBSpec.DBBackup.ObjectStore
+
+	backups, err := r.listObjectStoreBackupsOrderByCreate()
+	if err != nil {
+		r.cnpgLogError("got error listing cluster object store backups. error: %v", err)
+		return err
+	}
+
+	if objectStore.RetentionPolicy != "" {
+		cutoff, err := getRetentionCutoff(objectStore.RetentionPolicy, time.Now())
+		if err != nil {
+			r.cnpgLogError("got error parsing backup retention policy. error: %v", err)
+			return err
+		}
+		retained, expired := splitExpiredBackups(backups, cutoff)
+		for _, backup := range expired {
+			r.cnpgLog("deleting backup %s which is out of the retention policy %s", backup.Name, objectStore.RetentionPolicy)
+			// if encountered an error we only report it and continue with the reconciliation
+			if err := r.Client.Delete(r.Ctx, &backup); err != nil && !errors.IsNotFound(err) {
+				r.cnpgLogError("got error deleting backup %s. error: %v", backup.Name, err)
+			}
+		}
+		backups = retained
+	}
+
+	// update the backup status
+	backupStatus := r.NooBaa.Status.DBStatus.BackupStatus
+	backupStatus.TotalSnapshots = 0
+	backupStatus.AvailableSnapshots = nil
+	backupStatus.Backups = getObjectStoreBackupsStatus(backups)
+	backupStatus.FirstRecoverabilityPoint = nil
+	if firstPoint, ok := r.CNPGCluster.Status.FirstRecoverabilityPointByMethod[cnpgv1.BackupMethodBarmanObjectStore]; ok {
+		backupStatus.FirstRecoverabilityPoint = &firstPoint
+	}
+	backupStatus.ContinuousArchiving = false
+	backupStatus.ContinuousArchivingMessage = ""
+	if cond := meta.FindStatusCondition(r.CNPGCluster.Status.Conditions, string(cnpgv1.ConditionContinuousArchiving)); cond != nil {
+		backupStatus.ContinuousArchiving = cond.Status == metav1.ConditionTrue
+		if !backupStatus.ContinuousArchiving {
+			backupStatus.ContinuousArchivingMessage = cond.Message
+		}
+	}
+
+	return nil
+}
+
+// listObjectStoreBackupsOrderByCreate lists all object store backups of the cluster, ordered by creation timestamp
+func (r *Reconciler) listObjectStoreBackupsOrderByCreate() ([]cnpgv1.Backup, error) {
+	backupList := cnpg.GetCnpgBackupListObj(r.CNPGCluster.Namespace)
+	if err := r.Client.List(r.Ctx, backupList, client.InNamespace(r.CNPGCluster.Namespace)); err != nil {
+		return nil, err
+	}
+
+	// include scheduled and on-demand backups of the cluster
+	filteredItems := []cnpgv1.Backup{}
+	for _, backup := range backupList.Items {
+		if backup.Spec.Cluster.Name == r.CNPGCluster.Name && backup.Spec.Method == cnpgv1.BackupMethodBarmanObjectStore {
+			filteredItems = append(filteredItems, backup)
+		}
+	}
+	slices.SortFunc(filteredItems, func(a, b cnpgv1.Backup) int {
+		return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+	})
+	return filteredItems, nil
+}
+
+// getRetentionCutoff returns the start of the recovery window of a retention policy such as 30d, 4w or 6m
+func getRetentionCutoff(retentionPolicy string, now time.Time) (time.Time, error) {
+	if len(retentionPolicy) < 2 {
+		return time.Time{}, fmt.Errorf("invalid retention policy %q", retentionPolicy)
+	}
+	count, err := strconv.Atoi(retentionPolicy[:len(retentionPolicy)-1])
+	if err != nil || count <= 0 {
+		return time.Time{}, fmt.Errorf("invalid retention policy %q", retentionPolicy)
+	}
+	switch retentionPolicy[len(retentionPolicy)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -count), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*count), nil
+	case 'm':
+		return now.AddDate(0, -count, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid retention policy %q", retentionPolicy)
+	}
+}
+
+// splitExpiredBackups splits backups ordered by creation to the ones to retain and the ones that expired.
+// The newest completed backup that ended before the cutoff is retained, since it is the base
+// for recovering to the points in time at the start of the recovery window.
+func splitExpiredBackups(backups []cnpgv1.Backup, cutoff time.Time) ([]cnpgv1.Backup, []cnpgv1.Backup) {
+	endedBeforeCutoff := func(backup *cnpgv1.Backup) bool {
+		return backup.Status.Phase == cnpgv1.BackupPhaseCompleted &&
+			backup.Status.StoppedAt != nil && backup.Status.StoppedAt.Time.Before(cutoff)
+	}
+	baseIndex := -1
+	for i := range backups {
+		if endedBeforeCutoff(&backups[i]) {
+			baseIndex = i
+		}
+	}
+
+	retained := []cnpgv1.Backup{}
+	expired := []cnpgv1.Backup{}
+	for i := range backups {
+		backup := &backups[i]
+		switch {
+		case i == baseIndex:
+			retained = append(retained, *backup)
+		case endedBeforeCutoff(backup):
+			expired = append(expired, *backup)
+		case backup.Status.Phase == cnpgv1.BackupPhaseFailed && backup.CreationTimestamp.Time.Before(cutoff):
+			expired = append(expired, *backup)
+		default:
+			retained = append(retained, *backup)
+		}
+	}
+	return retained, expired
+}
+
+// getObjectStoreBackupsStatus returns the status of the newest backups, newest first
+func getObjectStoreBackupsStatus(backups []cnpgv1.Backup) []nbv1.DBObjectStoreBackup {
+	backupsStatus := []nbv1.DBObjectStoreBackup{}
+	for i := len(backups) - 1; i >= 0 && len(backupsStatus) < maxReportedObjectStoreBackups; i-- {
+		backup := &backups[i]
+		backupsStatus = append(backupsStatus, nbv1.DBObjectStoreBackup{
+			Name:      backup.Name,
+			BackupID:  backup.Status.BackupID,
+			Phase:     string(backup.Status.Phase),
+			StartedAt: backup.Status.StartedAt,
+			StoppedAt: backup.Status.StoppedAt,
+			Error:     backup.Status.Error,
+		})
+	}
+	return backupsStatus
+}
+
 // listVolumeSnapshotsOrderByCreate lists all volume snapshots of the scheduled backup, ordered by creation timestamp
 func (r *Reconciler) listVolumeSnapshotsOrderByCreate() ([]storagesnapshotv1.VolumeSnapshot, error) {
 	volumeSnapshots := storagesnapshotv1.VolumeSnapshotList{
@@ -615,7 +1184,7 @@ func (r *Reconciler) setPostgresConfig() {
 
 	totalMemoryKB := defaultCalcMemoryKB
 	cpuNum := defaultCalcCPU
-	dbResources := getDBResources(r.NooBaa)
+	dbResources := getDBResources(r.NooBaa, r.PerformanceProfile)
 	if !dbResources.Limits.Memory().IsZero() {
 		totalMemoryKB = dbResources.Limits.Memory().Value() / 1024
 	} else if !dbResources.Requests.Memory().IsZero() {
@@ -870,6 +1439,20 @@ func isClusterReady(cluster *cnpgv1.Cluster) bool {
 	return false
 }
 
+// isClusterPhaseFailed returns true if the cluster phase requires manual intervention
+func isClusterPhaseFailed(phase string) bool {
+	switch phase {
+	case cnpgv1.PhaseUnrecoverable,
+		cnpgv1.PhaseImageCatalogError,
+		cnpgv1.PhaseCannotCreateClusterObjects,
+		cnpgv1.PhaseUnknownPlugin,
+		cnpgv1.PhaseFailurePlugin,
+		cnpgv1.PhaseArchitectureBinaryMissing:
+		return true
+	}
+	return false
+}
+
 func (r *Reconciler) shouldReconcileCNPGCluster() bool {
 	return r.NooBaa.Spec.DBSpec != nil && r.NooBaa.Spec.ExternalPgSecret == nil
 }
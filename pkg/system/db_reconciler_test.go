@@ -1,12 +1,15 @@
 package system
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestFormatBytesKB(t *testing.T) {
@@ -485,3 +488,296 @@ func TestWasClusterSpecChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDBBackupMethod(t *testing.T) {
+	volumeSnapshot := &nbv1.VolumeSnapshotBackupSpec{VolumeSnapshotClass: "csi-snapclass", MaxSnapshots: 3}
+	objectStore := &nbv1.ObjectStoreBackupSpec{DestinationPath: "s3://backups/db", CredentialsSecret: "creds"}
+	tests := []struct {
+		name    string
+		spec    nbv1.DBBackupSpec
+		wantErr bool
+	}{
+		{"volume snapshot", nbv1.DBBackupSpec{VolumeSnapshot: volumeSnapshot}, false},
+		{"object store", nbv1.DBBackupSpec{ObjectStore: objectStore}, false},
+		{"none", nbv1.DBBackupSpec{}, true},
+		{"both", nbv1.DBBackupSpec{VolumeSnapshot: volumeSnapshot, ObjectStore: objectStore}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateDBBackupMethod(&tt.spec); (err != nil) != tt.wantErr {
+				t.Fatalf("validateDBBackupMethod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetDesiredObjectStoreConf(t *testing.T) {
+	objectStore := &nbv1.ObjectStoreBackupSpec{
+		DestinationPath:   "s3://backups/db",
+		Endpoint:          "https://s3.cluster-b.example.com",
+		EndpointCASecret:  "cluster-b-ca",
+		CredentialsSecret: "creds",
+		RetentionPolicy:   "30d",
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds"},
+		Data:       map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("id"), "AWS_SECRET_ACCESS_KEY": []byte("key")},
+	}
+
+	backupConf := &cnpgv1.BackupConfiguration{Target: cnpgv1.BackupTargetStandby}
+	setDesiredObjectStoreConf(backupConf, objectStore, secret, "")
+
+	barmanConf := backupConf.BarmanObjectStore
+	if barmanConf == nil {
+		t.Fatalf("expected barman object store configuration")
+	}
+	if barmanConf.DestinationPath != objectStore.DestinationPath || barmanConf.EndpointURL != objectStore.Endpoint {
+		t.Fatalf("unexpected destination %q endpoint %q", barmanConf.DestinationPath, barmanConf.EndpointURL)
+	}
+	if barmanConf.EndpointCA == nil || barmanConf.EndpointCA.Name != "cluster-b-ca" || barmanConf.EndpointCA.Key != objectStoreCAKey {
+		t.Fatalf("unexpected endpoint CA %+v", barmanConf.EndpointCA)
+	}
+	creds := barmanConf.AWS
+	if creds == nil || creds.AccessKeyIDReference.Name != "creds" || creds.SecretAccessKeyReference.Key != "AWS_SECRET_ACCESS_KEY" {
+		t.Fatalf("unexpected s3 credentials %+v", creds)
+	}
+	if creds.RegionReference != nil {
+		t.Fatalf("expected no region reference when the secret has no AWS_REGION")
+	}
+	if barmanConf.Wal == nil || barmanConf.Wal.Compression != objectStoreBackupCompression ||
+		barmanConf.Data == nil || barmanConf.Data.Compression != objectStoreBackupCompression {
+		t.Fatalf("expected %s compression of WAL and data", objectStoreBackupCompression)
+	}
+	if backupConf.RetentionPolicy != "30d" || backupConf.Target != cnpgv1.BackupTargetStandby {
+		t.Fatalf("unexpected retention %q target %q", backupConf.RetentionPolicy, backupConf.Target)
+	}
+
+	// an updated configuration is applied in place
+	secret.Data["AWS_REGION"] = []byte("us-east-1")
+	objectStore.EndpointCASecret = ""
+	setDesiredObjectStoreConf(backupConf, objectStore, secret, "")
+	if backupConf.BarmanObjectStore.AWS.RegionReference == nil || backupConf.BarmanObjectStore.EndpointCA != nil {
+		t.Fatalf("expected region reference and no endpoint CA, got %+v", backupConf.BarmanObjectStore)
+	}
+}
+
+func TestGetRetentionCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		policy   string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"30d", time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), false},
+		{"2w", time.Date(2026, 3, 17, 12, 0, 0, 0, time.UTC), false},
+		{"1m", time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC), false},
+		{"", time.Time{}, true},
+		{"d", time.Time{}, true},
+		{"0d", time.Time{}, true},
+		{"7y", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			got, err := getRetentionCutoff(tt.policy, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getRetentionCutoff(%q) error = %v, wantErr %v", tt.policy, err, tt.wantErr)
+			}
+			if !got.Equal(tt.expected) {
+				t.Fatalf("getRetentionCutoff(%q) = %v, want %v", tt.policy, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitExpiredBackups(t *testing.T) {
+	cutoff := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	backup := func(name string, day int, phase cnpgv1.BackupPhase) cnpgv1.Backup {
+		created := metav1.NewTime(time.Date(2026, 3, day, 1, 0, 0, 0, time.UTC))
+		b := cnpgv1.Backup{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created}}
+		b.Status.Phase = phase
+		b.Status.StartedAt = &created
+		if phase == cnpgv1.BackupPhaseCompleted || phase == cnpgv1.BackupPhaseFailed {
+			stopped := metav1.NewTime(created.Add(time.Hour))
+			b.Status.StoppedAt = &stopped
+		}
+		return b
+	}
+	backups := []cnpgv1.Backup{
+		backup("b1", 1, cnpgv1.BackupPhaseCompleted),
+		backup("b2", 3, cnpgv1.BackupPhaseFailed),
+		backup("b3", 5, cnpgv1.BackupPhaseCompleted),
+		backup("b4", 8, cnpgv1.BackupPhaseCompleted),
+		backup("b5", 9, cnpgv1.BackupPhaseFailed),
+		backup("b6", 12, cnpgv1.BackupPhaseCompleted),
+		backup("b7", 14, cnpgv1.BackupPhaseRunning),
+	}
+
+	retained, expired := splitExpiredBackups(backups, cutoff)
+
+	names := func(backups []cnpgv1.Backup) []string {
+		result := []string{}
+		for _, b := range backups {
+			result = append(result, b.Name)
+		}
+		return result
+	}
+	if got, want := names(retained), []string{"b4", "b6", "b7"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("retained = %v, want %v", got, want)
+	}
+	if got, want := names(expired), []string{"b1", "b2", "b3", "b5"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expired = %v, want %v", got, want)
+	}
+
+	// nothing expires when all backups are inside the recovery window
+	retained, expired = splitExpiredBackups(backups[5:], cutoff)
+	if len(retained) != 2 || len(expired) != 0 {
+		t.Fatalf("expected all backups to be retained, got retained %v expired %v", names(retained), names(expired))
+	}
+
+	status := getObjectStoreBackupsStatus(backups)
+	if len(status) != len(backups) || status[0].Name != "b7" || status[len(status)-1].Name != "b1" {
+		t.Fatalf("expected backups status ordered newest first, got %+v", status)
+	}
+}
+
+func TestGetDesiredBootstrapRecovery(t *testing.T) {
+	exclusive := true
+	tests := []struct {
+		name         string
+		spec         nbv1.DBRecoverySpec
+		wantSnapshot bool
+		wantSource   bool
+		wantTarget   *cnpgv1.RecoveryTarget
+	}{
+		{"volume snapshot", nbv1.DBRecoverySpec{VolumeSnapshotName: "snap-1"}, true, false, nil},
+		{"object store", nbv1.DBRecoverySpec{}, false, true, nil},
+		{"object store to time", nbv1.DBRecoverySpec{
+			RecoveryTarget: &nbv1.DBRecoveryTargetSpec{TargetTime: "2026-01-13T08:50:00Z", BackupID: "20260113T010002"},
+		}, false, true, &cnpgv1.RecoveryTarget{TargetTime: "2026-01-13T08:50:00Z", BackupID: "20260113T010002"}},
+		{"volume snapshot to restore point", nbv1.DBRecoverySpec{
+			VolumeSnapshotName: "snap-1",
+			RecoveryTarget:     &nbv1.DBRecoveryTargetSpec{TargetName: "before-delete", Exclusive: &exclusive},
+		}, true, true, &cnpgv1.RecoveryTarget{TargetName: "before-delete", Exclusive: &exclusive}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recovery := getDesiredBootstrapRecovery(&tt.spec)
+			if recovery.Database != noobaaDBName || recovery.Owner != noobaaDBUser {
+				t.Fatalf("unexpected database %q owner %q", recovery.Database, recovery.Owner)
+			}
+			if got := recovery.VolumeSnapshots != nil; got != tt.wantSnapshot {
+				t.Fatalf("volume snapshots set = %v, want %v", got, tt.wantSnapshot)
+			}
+			if tt.wantSnapshot && recovery.VolumeSnapshots.Storage.Name != tt.spec.VolumeSnapshotName {
+				t.Fatalf("unexpected volume snapshot %q", recovery.VolumeSnapshots.Storage.Name)
+			}
+			if got := recovery.Source == recoverySourceName; got != tt.wantSource {
+				t.Fatalf("source = %q, want source %v", recovery.Source, tt.wantSource)
+			}
+			if !reflect.DeepEqual(recovery.RecoveryTarget, tt.wantTarget) {
+				t.Fatalf("recovery target = %+v, want %+v", recovery.RecoveryTarget, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestGetRecoverySourceObjectStoreConf(t *testing.T) {
+	backupConf := &cnpgv1.BackupConfiguration{}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds"}}
+	objectStore := &nbv1.ObjectStoreBackupSpec{
+		DestinationPath:   "s3://backups/db",
+		Endpoint:          "https://s3.cluster-b.example.com",
+		EndpointCASecret:  "cluster-b-ca",
+		CredentialsSecret: "creds",
+		RetentionPolicy:   "30d",
+	}
+	setDesiredObjectStoreConf(backupConf, objectStore, secret, "noobaa-db-pg-cluster-20260114010000")
+
+	sourceConf := getRecoverySourceObjectStoreConf(backupConf.BarmanObjectStore, "noobaa-db-pg-cluster")
+	if sourceConf.ServerName != "noobaa-db-pg-cluster" || backupConf.BarmanObjectStore.ServerName != "noobaa-db-pg-cluster-20260114010000" {
+		t.Fatalf("unexpected source server name %q archive server name %q",
+			sourceConf.ServerName, backupConf.BarmanObjectStore.ServerName)
+	}
+	if sourceConf.DestinationPath != objectStore.DestinationPath || sourceConf.EndpointURL != objectStore.Endpoint ||
+		sourceConf.EndpointCA == nil || sourceConf.AWS == nil || sourceConf.AWS.AccessKeyIDReference.Name != "creds" {
+		t.Fatalf("unexpected source configuration %+v", sourceConf)
+	}
+	// the source must not share the credentials of the archive configuration
+	sourceConf.AWS.AccessKeyIDReference.Name = "other"
+	if backupConf.BarmanObjectStore.AWS.AccessKeyIDReference.Name != "creds" {
+		t.Fatalf("expected the source configuration to be a copy")
+	}
+}
+
+func TestGetObjectStoreServerName(t *testing.T) {
+	r := &Reconciler{NooBaa: &nbv1.NooBaa{}, CNPGCluster: &cnpgv1.Cluster{}}
+	r.NooBaa.Status.DBStatus = &nbv1.NooBaaDBStatus{}
+	r.CNPGCluster.Name = "noobaa-db-pg-cluster"
+	if got := r.getObjectStoreServerName(); got != "" {
+		t.Fatalf("getObjectStoreServerName() = %q, want empty", got)
+	}
+
+	r.setObjectStoreServerName("noobaa-db-pg-cluster")
+	if got := r.getObjectStoreServerName(); got != "noobaa-db-pg-cluster" {
+		t.Fatalf("getObjectStoreServerName() = %q, want the server name of the status", got)
+	}
+
+	// a recovered cluster whose status update was lost keeps archiving under its own server name
+	r.CNPGCluster.UID = "uid"
+	r.CNPGCluster.Spec.Backup = &cnpgv1.BackupConfiguration{BarmanObjectStore: &cnpgv1.BarmanObjectStoreConfiguration{
+		ServerName: "noobaa-db-pg-cluster-20260114010000",
+	}}
+	if got := r.getObjectStoreServerName(); got != "noobaa-db-pg-cluster-20260114010000" {
+		t.Fatalf("getObjectStoreServerName() = %q, want the server name of the cluster", got)
+	}
+}
+
+func TestIsClusterPhaseFailed(t *testing.T) {
+	for _, phase := range []string{cnpgv1.PhaseUnrecoverable, cnpgv1.PhaseImageCatalogError, cnpgv1.PhaseCannotCreateClusterObjects} {
+		if !isClusterPhaseFailed(phase) {
+			t.Errorf("isClusterPhaseFailed(%q) = false, want true", phase)
+		}
+	}
+	for _, phase := range []string{"", cnpgv1.PhaseFirstPrimary, cnpgv1.PhaseHealthy, cnpgv1.PhaseWaitingForInstancesToBeActive} {
+		if isClusterPhaseFailed(phase) {
+			t.Errorf("isClusterPhaseFailed(%q) = true, want false", phase)
+		}
+	}
+}
+
+func TestGetDBAutoGrowSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentSize string
+		growthStep  string
+		maxSize     string
+		want        string
+		wantErr     bool
+	}{
+		{name: "default step", currentSize: "50Gi", growthStep: "", maxSize: "200Gi", want: "60Gi"},
+		{name: "percent step rounds up to Gi", currentSize: "50Gi", growthStep: "15%", maxSize: "200Gi", want: "58Gi"},
+		{name: "quantity step", currentSize: "50Gi", growthStep: "10Gi", maxSize: "200Gi", want: "60Gi"},
+		{name: "small step grows by at least 1Gi", currentSize: "2Gi", growthStep: "1%", maxSize: "200Gi", want: "3Gi"},
+		{name: "capped at max size", currentSize: "190Gi", growthStep: "20%", maxSize: "200Gi", want: "200Gi"},
+		{name: "already at max size", currentSize: "200Gi", growthStep: "20%", maxSize: "200Gi", want: ""},
+		{name: "invalid step", currentSize: "50Gi", growthStep: "x%", maxSize: "200Gi", wantErr: true},
+		{name: "invalid max size", currentSize: "50Gi", growthStep: "20%", maxSize: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getDBAutoGrowSize(tt.currentSize, tt.growthStep, tt.maxSize)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got size %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("size = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/cnpg"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CmdDBRestore returns a CLI command
+func CmdDBRestore() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db-restore",
+		Short: "List the database backups, or restore the database from a backup",
+		Long: `List the database backups with --list, or restore the database from a volume snapshot with --snapshot.
+When an object store backup is configured, the database can be restored from the object store
+and to a point in time with --target-time, --target-lsn or --target-name.
+The restore stops noobaa-core and noobaa-endpoint, recreates the database cluster from the backup,
+waits for the system to be ready and clears the recovery configuration from the NooBaa CR.`,
+		Run:  RunDBRestore,
+		Args: cobra.NoArgs,
+	}
+	cmd.Flags().Bool("list", false, "List the available volume snapshots and object store backups")
+	cmd.Flags().String("snapshot", "", "The name of the volume snapshot to restore")
+	cmd.Flags().String("target-time", "", "Recover to this time (RFC 3339) by replaying the WAL files archived in the object store")
+	cmd.Flags().String("target-lsn", "", "Recover to this WAL location (LSN) by replaying the WAL files archived in the object store")
+	cmd.Flags().String("target-name", "", "Recover to this named restore point by replaying the WAL files archived in the object store")
+	cmd.Flags().String("backup-id", "", "The ID of the object store base backup to restore")
+	cmd.Flags().Bool("yes", false, "Restore without asking for confirmation")
+	cmd.Flags().Duration("timeout", 60*time.Minute, "How long to wait for the restore to complete")
+	return cmd
+}
+
+// RunDBRestore runs a CLI command
+func RunDBRestore(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	sys := &nbv1.NooBaa{
+		TypeMeta: metav1.TypeMeta{Kind: "NooBaa"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      options.SystemName,
+			Namespace: options.Namespace,
+		},
+	}
+	if !util.KubeCheck(sys) {
+		log.Fatalf("❌ System %q not found", options.SystemName)
+	}
+	if sys.Spec.DBSpec == nil {
+		log.Fatalf("❌ The system is not configured with a CNPG cluster")
+	}
+
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		printDBBackups(sys)
+		return
+	}
+
+	snapshotName, _ := cmd.Flags().GetString("snapshot")
+	targetTime, _ := cmd.Flags().GetString("target-time")
+	targetLSN, _ := cmd.Flags().GetString("target-lsn")
+	targetName, _ := cmd.Flags().GetString("target-name")
+	backupID, _ := cmd.Flags().GetString("backup-id")
+	recoverySpec := getDBRecoverySpecFromFlags(snapshotName, targetTime, targetLSN, targetName, backupID)
+
+	if snapshotName == "" && (sys.Spec.DBSpec.DBBackup == nil || sys.Spec.DBSpec.DBBackup.ObjectStore == nil) {
+		log.Fatalf("❌ Missing --snapshot, use --list to view the available snapshots")
+	}
+	if snapshotName != "" {
+		snapshot := &storagesnapshotv1.VolumeSnapshot{}
+		err := util.KubeClient().Get(util.Context(), client.ObjectKey{Namespace: sys.Namespace, Name: snapshotName}, snapshot)
+		if err != nil {
+			log.Fatalf("❌ Volume snapshot %q not found: %s", snapshotName, err)
+		}
+		if ready, _ := getVolumeSnapshotReadiness(snapshot); !ready {
+			log.Fatalf("❌ Volume snapshot %q is not ready to use", snapshotName)
+		}
+	}
+	if sys.Spec.DBSpec.DBRecovery != nil {
+		log.Printf("⚠️  Replacing the existing recovery configuration %+v", *sys.Spec.DBSpec.DBRecovery)
+	}
+
+	clusterName := sys.Name + pgClusterSuffix
+	fmt.Printf("\nRestoring the database of system %q from %s.\n", sys.Name, describeDBRecovery(recoverySpec))
+	fmt.Printf("This stops noobaa-core and noobaa-endpoint, deletes the database cluster %q and recreates it from the backup.\n", clusterName)
+	fmt.Printf("Changes made to the database after the restore point are lost.\n\n")
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		if !confirmDBRestore(cmd.InOrStdin(), sys.Name) {
+			log.Printf("Restore cancelled")
+			return
+		}
+	}
+
+	sys.Spec.DBSpec.DBRecovery = recoverySpec
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if err := restoreDB(sys, timeout); err != nil {
+		log.Fatalf("❌ %s", err)
+	}
+}
+
+// RestoreDBUpgradeSnapshot restores the database from the backup taken before an upgrade.
+// The spec of the system is updated together with the recovery configuration,
+// so the images of the upgrade can be rolled back in the same update.
+func RestoreDBUpgradeSnapshot(sys *nbv1.NooBaa, backupName string, timeout time.Duration) error {
+	if sys.Spec.DBSpec == nil || sys.Spec.DBSpec.DBBackup == nil {
+		return fmt.Errorf("the system is not configured with a database backup")
+	}
+	recoverySpec := getDBUpgradeFallbackRecovery(backupName, "", time.Time{})
+	if sys.Spec.DBSpec.DBBackup.ObjectStore != nil {
+		backup := cnpg.GetCnpgBackupObj(sys.Namespace, backupName)
+		if !util.KubeCheck(backup) {
+			return fmt.Errorf("backup %q not found", backupName)
+		}
+		if backup.Status.StoppedAt == nil {
+			return fmt.Errorf("backup %q did not complete", backupName)
+		}
+		recoverySpec = getDBUpgradeFallbackRecovery("", backup.Status.BackupID, backup.Status.StoppedAt.Time)
+	}
+	sys.Spec.DBSpec.DBRecovery = recoverySpec
+	return restoreDB(sys, timeout)
+}
+
+// restoreDB recreates the database cluster from the recovery configuration set in the spec of the system,
+// waits for the recovery to complete and clears the recovery configuration
+func restoreDB(sys *nbv1.NooBaa, timeout time.Duration) error {
+	log := util.Logger()
+	clusterName := sys.Name + pgClusterSuffix
+
+	if !util.KubeUpdate(sys) {
+		return fmt.Errorf("failed to set the recovery configuration on system %q", sys.Name)
+	}
+	log.Printf("✅ Recovery configuration set on system %q", sys.Name)
+
+	// delete the cluster once, without waiting for it to be gone,
+	// since the operator recreates it from the backup as soon as it is deleted
+	restoreStartTime := time.Now()
+	cluster := cnpg.GetCnpgClusterObj(sys.Namespace, clusterName)
+	if err := util.KubeClient().Delete(util.Context(), cluster); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the database cluster %q: %w", clusterName, err)
+	}
+	log.Printf("🗑️  Deleted the database cluster %q, the operator recreates it from the backup", clusterName)
+
+	if err := waitForDBRestore(sys, restoreStartTime, timeout); err != nil {
+		return fmt.Errorf("database restore did not complete: %w. The recovery configuration is kept on the NooBaa CR, "+
+			"monitor status.dbStatus.recoveryStatus and remove spec.dbSpec.dbRecovery once the system is ready", err)
+	}
+
+	// clear the recovery configuration so a future deletion of the cluster does not restore the backup again
+	if !util.KubeCheck(sys) {
+		return fmt.Errorf("system %q not found", sys.Name)
+	}
+	sys.Spec.DBSpec.DBRecovery = nil
+	if !util.KubeUpdate(sys) {
+		return fmt.Errorf("failed to clear the recovery configuration of system %q, remove spec.dbSpec.dbRecovery manually", sys.Name)
+	}
+	log.Printf("✅ Database restored successfully and the recovery configuration was cleared")
+	return nil
+}
+
+// waitForDBRestore streams the recovery progress until the system is ready with a completed recovery
+func waitForDBRestore(sys *nbv1.NooBaa, startTime time.Time, timeout time.Duration) error {
+	log := util.Logger()
+	pollCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	lastProgress := ""
+	interval := time.Duration(3)
+	err := wait.PollUntilContextCancel(pollCtx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		if !util.KubeCheckQuiet(sys) {
+			return false, nil
+		}
+		done, progress, err := getDBRestoreProgress(sys, startTime)
+		if progress != lastProgress {
+			log.Printf("⏳ %s", progress)
+			lastProgress = progress
+		}
+		return done, err
+	})
+	return err
+}
+
+// getDBRestoreProgress returns whether the restore that started at startTime is done and a description of its progress.
+// The status of a previous recovery is ignored until the operator starts the new one.
+func getDBRestoreProgress(sys *nbv1.NooBaa, startTime time.Time) (bool, string, error) {
+	var recoveryStatus *nbv1.DBRecoveryStatus
+	if sys.Status.DBStatus != nil {
+		recoveryStatus = sys.Status.DBStatus.RecoveryStatus
+	}
+	// status times are serialized in seconds
+	startedBefore := func(t *metav1.Time) bool { return t == nil || t.Time.Before(startTime.Truncate(time.Second)) }
+	if recoveryStatus == nil ||
+		(recoveryStatus.Status != nbv1.DBRecoveryStatusPending && startedBefore(recoveryStatus.RecoveryTime)) {
+		return false, fmt.Sprintf("System phase %s, waiting for the recovery to start", sys.Status.Phase), nil
+	}
+	progress := fmt.Sprintf("System phase %s, recovery %s", sys.Status.Phase, recoveryStatus.Status)
+	if recoveryStatus.ClusterPhase != "" {
+		progress += fmt.Sprintf(", database cluster %q", recoveryStatus.ClusterPhase)
+	}
+	if recoveryStatus.Message != "" {
+		progress += ": " + recoveryStatus.Message
+	}
+	switch recoveryStatus.Status {
+	case nbv1.DBRecoveryStatusFailed:
+		return false, progress, fmt.Errorf("recovery failed: %s", recoveryStatus.Message)
+	case nbv1.DBRecoveryStatusCompleted:
+		return sys.Status.Phase == nbv1.SystemPhaseReady, progress, nil
+	default:
+		return false, progress, nil
+	}
+}
+
+// getDBRecoverySpecFromFlags returns the recovery configuration of the restore flags
+func getDBRecoverySpecFromFlags(snapshotName, targetTime, targetLSN, targetName, backupID string) *nbv1.DBRecoverySpec {
+	recoverySpec := &nbv1.DBRecoverySpec{VolumeSnapshotName: snapshotName}
+	if targetTime != "" || targetLSN != "" || targetName != "" || backupID != "" {
+		recoverySpec.RecoveryTarget = &nbv1.DBRecoveryTargetSpec{
+			TargetTime: targetTime,
+			TargetLSN:  targetLSN,
+			TargetName: targetName,
+			BackupID:   backupID,
+		}
+	}
+	return recoverySpec
+}
+
+func describeDBRecovery(recoverySpec *nbv1.DBRecoverySpec) string {
+	source := "the object store backup"
+	if recoverySpec.VolumeSnapshotName != "" {
+		source = fmt.Sprintf("volume snapshot %q", recoverySpec.VolumeSnapshotName)
+	}
+	target := recoverySpec.RecoveryTarget
+	switch {
+	case target == nil:
+		return source
+	case target.TargetTime != "":
+		return fmt.Sprintf("%s to time %s", source, target.TargetTime)
+	case target.TargetLSN != "":
+		return fmt.Sprintf("%s to WAL location %s", source, target.TargetLSN)
+	case target.TargetName != "":
+		return fmt.Sprintf("%s to restore point %q", source, target.TargetName)
+	default:
+		return fmt.Sprintf("%s with backup ID %s", source, target.BackupID)
+	}
+}
+
+// confirmDBRestore asks the user to confirm the restore by typing the system name
+func confirmDBRestore(in io.Reader, systemName string) bool {
+	fmt.Printf("Type the system name %q to confirm: ", systemName)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	return strings.TrimSpace(answer) == systemName
+}
+
+// printDBBackups prints the available volume snapshots and object store backups of the system
+func printDBBackups(sys *nbv1.NooBaa) {
+	var backupStatus *nbv1.DBBackupStatus
+	if sys.Status.DBStatus != nil {
+		backupStatus = sys.Status.DBStatus.BackupStatus
+	}
+	if backupStatus == nil {
+		fmt.Printf("No database backups are reported for system %q\n", sys.Name)
+		return
+	}
+
+	if len(backupStatus.AvailableSnapshots) > 0 {
+		table := (&util.PrintTable{}).AddRow("SNAPSHOT", "READY", "SIZE", "AGE")
+		for _, name := range backupStatus.AvailableSnapshots {
+			snapshot := &storagesnapshotv1.VolumeSnapshot{}
+			err := util.KubeClient().Get(util.Context(), client.ObjectKey{Namespace: sys.Namespace, Name: name}, snapshot)
+			if err != nil {
+				table.AddRow(name, "NotFound", "", "")
+				continue
+			}
+			table.AddRow(getVolumeSnapshotRow(snapshot, time.Now())...)
+		}
+		fmt.Print(table.String())
+	}
+
+	if len(backupStatus.Backups) > 0 {
+		if len(backupStatus.AvailableSnapshots) > 0 {
+			fmt.Println()
+		}
+		table := (&util.PrintTable{}).AddRow("BACKUP", "BACKUP-ID", "PHASE", "COMPLETED", "AGE")
+		for i := range backupStatus.Backups {
+			backup := &backupStatus.Backups[i]
+			completed, age := "", ""
+			if backup.StoppedAt != nil {
+				completed = backup.StoppedAt.UTC().Format(time.RFC3339)
+				age = util.HumanizeDuration(time.Since(backup.StoppedAt.Time).Round(time.Second))
+			}
+			table.AddRow(backup.Name, backup.BackupID, backup.Phase, completed, age)
+		}
+		fmt.Print(table.String())
+		if backupStatus.FirstRecoverabilityPoint != nil {
+			fmt.Printf("\nThe database can be restored to any time since %s\n",
+				backupStatus.FirstRecoverabilityPoint.UTC().Format(time.RFC3339))
+		}
+		if !backupStatus.ContinuousArchiving {
+			fmt.Printf("⚠️  WAL archiving is failing: %s\n", backupStatus.ContinuousArchivingMessage)
+		}
+	}
+
+	if len(backupStatus.AvailableSnapshots) == 0 && len(backupStatus.Backups) == 0 {
+		fmt.Printf("No database backups are available for system %q\n", sys.Name)
+	}
+}
+
+// getVolumeSnapshotRow returns the name, readiness, size and age of a volume snapshot
+func getVolumeSnapshotRow(snapshot *storagesnapshotv1.VolumeSnapshot, now time.Time) []string {
+	_, readiness := getVolumeSnapshotReadiness(snapshot)
+	size := ""
+	if snapshot.Status != nil && snapshot.Status.RestoreSize != nil {
+		size = snapshot.Status.RestoreSize.String()
+	}
+	created := snapshot.CreationTimestamp.Time
+	if snapshot.Status != nil && snapshot.Status.CreationTime != nil {
+		created = snapshot.Status.CreationTime.Time
+	}
+	age := util.HumanizeDuration(now.Sub(created).Round(time.Second))
+	return []string{snapshot.Name, readiness, size, age}
+}
+
+// getVolumeSnapshotReadiness returns whether the snapshot is ready to use, or a description of why not
+func getVolumeSnapshotReadiness(snapshot *storagesnapshotv1.VolumeSnapshot) (bool, string) {
+	if snapshot.Status == nil {
+		return false, "false"
+	}
+	if snapshot.Status.Error != nil && snapshot.Status.Error.Message != nil {
+		return false, "error: " + *snapshot.Status.Error.Message
+	}
+	if snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+		return false, "false"
+	}
+	return true, "true"
+}
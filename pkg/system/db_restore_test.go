@@ -0,0 +1,103 @@
+package system
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDBRestoreProgress(t *testing.T) {
+	startTime := time.Date(2026, 1, 14, 10, 0, 0, 500, time.UTC)
+	before := metav1.NewTime(startTime.Add(-time.Hour))
+	after := metav1.NewTime(startTime.Add(time.Minute))
+	tests := []struct {
+		name     string
+		phase    nbv1.SystemPhase
+		recovery *nbv1.DBRecoveryStatus
+		done     bool
+		wantErr  bool
+		progress string
+	}{
+		{"not started", nbv1.SystemPhaseReady, nil, false, false, "waiting for the recovery to start"},
+		{"previous recovery completed", nbv1.SystemPhaseReady,
+			&nbv1.DBRecoveryStatus{Status: nbv1.DBRecoveryStatusCompleted, RecoveryTime: &before}, false, false, "waiting for the recovery to start"},
+		{"pending", nbv1.SystemPhaseConfiguring,
+			&nbv1.DBRecoveryStatus{Status: nbv1.DBRecoveryStatusPending, Message: "waiting for pods"}, false, false, "recovery Pending: waiting for pods"},
+		{"running", nbv1.SystemPhaseConfiguring,
+			&nbv1.DBRecoveryStatus{Status: nbv1.DBRecoveryStatusRunning, RecoveryTime: &after, ClusterPhase: "Setting up primary"},
+			false, false, `database cluster "Setting up primary"`},
+		{"completed while system is not ready", nbv1.SystemPhaseConfiguring,
+			&nbv1.DBRecoveryStatus{Status: nbv1.DBRecoveryStatusCompleted, RecoveryTime: &after}, false, false, "recovery Completed"},
+		{"completed", nbv1.SystemPhaseReady,
+			&nbv1.DBRecoveryStatus{Status: nbv1.DBRecoveryStatusCompleted, RecoveryTime: &after}, true, false, "System phase Ready"},
+		{"failed", nbv1.SystemPhaseConfiguring,
+			&nbv1.DBRecoveryStatus{Status: nbv1.DBRecoveryStatusFailed, RecoveryTime: &after, Message: "bad backup"}, false, true, "bad backup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &nbv1.NooBaa{}
+			sys.Status.Phase = tt.phase
+			sys.Status.DBStatus = &nbv1.NooBaaDBStatus{RecoveryStatus: tt.recovery}
+			done, progress, err := getDBRestoreProgress(sys, startTime)
+			if done != tt.done || (err != nil) != tt.wantErr {
+				t.Fatalf("getDBRestoreProgress() = %v, %v, want done %v wantErr %v", done, err, tt.done, tt.wantErr)
+			}
+			if !strings.Contains(progress, tt.progress) {
+				t.Fatalf("progress %q does not contain %q", progress, tt.progress)
+			}
+		})
+	}
+}
+
+func TestGetDBRecoverySpecFromFlags(t *testing.T) {
+	spec := getDBRecoverySpecFromFlags("snap-1", "", "", "", "")
+	if spec.VolumeSnapshotName != "snap-1" || spec.RecoveryTarget != nil {
+		t.Fatalf("unexpected recovery spec %+v", spec)
+	}
+	spec = getDBRecoverySpecFromFlags("", "2026-01-13T08:50:00Z", "", "", "20260113T010002")
+	if spec.VolumeSnapshotName != "" || spec.RecoveryTarget == nil ||
+		spec.RecoveryTarget.TargetTime != "2026-01-13T08:50:00Z" || spec.RecoveryTarget.BackupID != "20260113T010002" {
+		t.Fatalf("unexpected recovery spec %+v", spec)
+	}
+	if got := describeDBRecovery(spec); got != "the object store backup to time 2026-01-13T08:50:00Z" {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
+
+func TestConfirmDBRestore(t *testing.T) {
+	if !confirmDBRestore(strings.NewReader("noobaa\n"), "noobaa") {
+		t.Fatalf("expected the system name to confirm the restore")
+	}
+	if confirmDBRestore(strings.NewReader("y\n"), "noobaa") {
+		t.Fatalf("expected a wrong answer to cancel the restore")
+	}
+	if confirmDBRestore(strings.NewReader(""), "noobaa") {
+		t.Fatalf("expected no answer to cancel the restore")
+	}
+}
+
+func TestGetVolumeSnapshotRow(t *testing.T) {
+	now := time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC)
+	ready := true
+	size := resource.MustParse("50Gi")
+	created := metav1.NewTime(now.Add(-26 * time.Hour))
+	snapshot := &storagesnapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "snap-1"},
+		Status:     &storagesnapshotv1.VolumeSnapshotStatus{ReadyToUse: &ready, RestoreSize: &size, CreationTime: &created},
+	}
+	row := getVolumeSnapshotRow(snapshot, now)
+	if strings.Join(row, " ") != "snap-1 true 50Gi 1d2h0m0s" {
+		t.Fatalf("unexpected row %v", row)
+	}
+
+	message := "snapshot failed"
+	snapshot.Status = &storagesnapshotv1.VolumeSnapshotStatus{Error: &storagesnapshotv1.VolumeSnapshotError{Message: &message}}
+	if ready, readiness := getVolumeSnapshotReadiness(snapshot); ready || readiness != "error: snapshot failed" {
+		t.Fatalf("unexpected readiness %v %q", ready, readiness)
+	}
+}
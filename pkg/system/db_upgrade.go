@@ -0,0 +1,634 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/cnpg"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	dbUpgradeCheckContainer = "db-upgrade-check"
+	dbUpgradeSnapshotWait   = 30 * time.Minute
+)
+
+// DBUpgradeCheckReport is the report of the compatibility check job that runs on a clone of the latest DB snapshot
+type DBUpgradeCheckReport struct {
+	Version           int
+	TargetVersion     int
+	DataBytes         int64
+	VolumeKB          int64
+	VolumeAvailKB     int64
+	PreparedXacts     int
+	Extensions        []string
+	MissingExtensions []string
+	RegColumns        []string
+	Errors            []string
+	// Complete is set when the report ends with the end marker line of the job
+	Complete bool
+	// Snapshot is the volume snapshot that the check ran on, taken SnapshotAge before the check
+	Snapshot    string
+	SnapshotAge time.Duration
+}
+
+// CmdDBUpgrade returns a CLI command
+func CmdDBUpgrade() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db-upgrade",
+		Short: "Upgrade the postgres major version of the database",
+		Long: `Upgrade the postgres major version of the database to --target-version with the image of --db-image.
+Before the upgrade a compatibility check runs the checks of pg_upgrade --check that apply to the database,
+on a clone of the latest volume snapshot of the database, no older than --max-snapshot-age, and reports what blocks the upgrade -
+missing extensions in the target image, reg* columns, prepared transactions and the free space on the volume.
+Use --check to only print the report.
+The upgrade takes a snapshot of the database, updates the major version and the image of the database,
+and waits for the database cluster to run the target version and for the system to be ready.
+When the upgrade fails, the database is restored from the snapshot with the previous major version and image.
+The progress is reported in status.postgresUpdatePhase of the NooBaa CR.`,
+		Run:  RunDBUpgrade,
+		Args: cobra.NoArgs,
+	}
+	cmd.Flags().Bool("check", false, "Only run the compatibility check and print the report, without upgrading")
+	cmd.Flags().Int("target-version", options.PostgresMajorVersion, "The postgres major version to upgrade to, --db-image must be an image of this version")
+	cmd.Flags().Bool("skip-check", false, "Upgrade without running the compatibility check")
+	cmd.Flags().Int("max-db-volume-used", DefaultUpgradeMaxDBVolumeUsedPercent, "The maximum used percent of the DB volume for the upgrade to start")
+	cmd.Flags().Bool("yes", false, "Upgrade without asking for confirmation")
+	cmd.Flags().Duration("check-timeout", 30*time.Minute, "How long to wait for the compatibility check to complete")
+	cmd.Flags().Duration("max-snapshot-age", 24*time.Hour, "The maximum age of the volume snapshot that the compatibility check runs on, 0 for any age")
+	cmd.Flags().Duration("timeout", 60*time.Minute, "How long to wait for the upgrade to complete before falling back to the snapshot")
+	return cmd
+}
+
+// RunDBUpgrade runs a CLI command
+func RunDBUpgrade(cmd *cobra.Command, args []string) {
+	log := util.Logger()
+
+	sys := &nbv1.NooBaa{
+		TypeMeta: metav1.TypeMeta{Kind: "NooBaa"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      options.SystemName,
+			Namespace: options.Namespace,
+		},
+	}
+	if !util.KubeCheck(sys) {
+		log.Fatalf("❌ System %q not found", options.SystemName)
+	}
+	if !isCNPGManagedDB(sys) {
+		log.Fatalf("❌ The system is not configured with a CNPG cluster")
+	}
+	clusterName := sys.Name + pgClusterSuffix
+	cluster := cnpg.GetCnpgClusterObj(sys.Namespace, clusterName)
+	if !util.KubeCheck(cluster) {
+		log.Fatalf("❌ Database cluster %q not found", clusterName)
+	}
+
+	checkOnly, _ := cmd.Flags().GetBool("check")
+	skipCheck, _ := cmd.Flags().GetBool("skip-check")
+	target, _ := cmd.Flags().GetInt("target-version")
+	maxDBVolumeUsed, _ := cmd.Flags().GetInt("max-db-volume-used")
+	checkTimeout, _ := cmd.Flags().GetDuration("check-timeout")
+	maxSnapshotAge, _ := cmd.Flags().GetDuration("max-snapshot-age")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	targetImage := options.DBImage
+	if checkOnly && skipCheck {
+		log.Fatalf("❌ --check and --skip-check cannot be used together")
+	}
+
+	currentVersion, currentImage := getCurrentDBVersion(sys, cluster)
+	if target <= currentVersion {
+		log.Fatalf("❌ The database runs postgres major version %d, --target-version must be newer", currentVersion)
+	}
+	if targetImage == currentImage {
+		log.Fatalf("❌ The database already runs image %q, set --db-image to an image of postgres %d", currentImage, target)
+	}
+	switch sys.Status.PostgresUpdatePhase {
+	case nbv1.UpgradePhasePrepare, nbv1.UpgradePhaseUpgrade, nbv1.UpgradePhaseReverting:
+		log.Warnf("⚠️  status.postgresUpdatePhase is %q, another upgrade of the database may be in progress", sys.Status.PostgresUpdatePhase)
+	}
+	log.Printf("Postgres major version %d (%s) -> %d (%s)", currentVersion, currentImage, target, targetImage)
+
+	if skipCheck {
+		log.Warnf("⚠️  Skipping the compatibility check with --skip-check")
+	} else {
+		report, err := runDBUpgradeCheck(sys, cluster, currentImage, targetImage, maxSnapshotAge, checkTimeout)
+		if err != nil {
+			log.Fatalf("❌ The compatibility check did not complete: %s. Use --skip-check to upgrade without it", err)
+		}
+		log.Printf("Compatibility check of volume snapshot %q, taken %s ago", report.Snapshot, report.SnapshotAge)
+		failed := PrintUpgradePreflightChecks(getDBUpgradeCheckResults(report, target, maxDBVolumeUsed))
+		if failed > 0 {
+			log.Fatalf("❌ %d checks failed, the upgrade to postgres %d is blocked", failed, target)
+		}
+		if checkOnly {
+			log.Printf("✅ The database can be upgraded to postgres %d", target)
+			return
+		}
+	}
+
+	if sys.Spec.DBSpec.DBBackup == nil {
+		log.Fatalf("❌ The upgrade falls back to a snapshot of the database on failure, configure spec.dbSpec.dbBackup first")
+	}
+	fmt.Printf("\nUpgrading the database of system %q from postgres %d to %d.\n", sys.Name, currentVersion, target)
+	fmt.Printf("The database is snapshotted first, and restored from the snapshot when the upgrade fails.\n")
+	fmt.Printf("noobaa-core and noobaa-endpoint cannot access the database during the upgrade.\n\n")
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		if !confirmDBRestore(cmd.InOrStdin(), sys.Name) {
+			log.Printf("Upgrade cancelled")
+			return
+		}
+	}
+
+	// preparing - snapshot the database with the current major version
+	updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+		status.PostgresUpdatePhase = nbv1.UpgradePhasePrepare
+		status.BeforeUpgradeDbImage = &currentImage
+	})
+	fallback, err := createDBUpgradeSnapshot(sys, target)
+	if err != nil {
+		updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+			status.PostgresUpdatePhase = nbv1.UpgradePhaseFailed
+		})
+		log.Fatalf("❌ Failed to snapshot the database before the upgrade: %s", err)
+	}
+
+	// upgrading - the operator updates the image catalog and cnpg runs pg_upgrade on the primary
+	upgradeStartTime := time.Now()
+	updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+		status.PostgresUpdatePhase = nbv1.UpgradePhaseUpgrade
+	})
+	if !util.KubeCheck(sys) {
+		log.Fatalf("❌ System %q not found", sys.Name)
+	}
+	previousVersion := sys.Spec.DBSpec.PostgresMajorVersion
+	previousImage := sys.Spec.DBSpec.DBImage
+	sys.Spec.DBSpec.PostgresMajorVersion = &target
+	sys.Spec.DBSpec.DBImage = &targetImage
+	if !util.KubeUpdate(sys) {
+		updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+			status.PostgresUpdatePhase = nbv1.UpgradePhaseFailed
+		})
+		log.Fatalf("❌ Failed to set the postgres major version of system %q", sys.Name)
+	}
+	log.Printf("✅ Postgres major version %d and image %q set on system %q", target, targetImage, sys.Name)
+
+	err = waitForDBMajorUpgrade(sys, cluster, target, upgradeStartTime, timeout)
+	if err == nil {
+		updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+			status.PostgresUpdatePhase = nbv1.UpgradePhaseFinished
+		})
+		log.Printf("✅ The database was upgraded to postgres %d", target)
+		return
+	}
+
+	// reverting - restore the snapshot with the previous major version and image
+	log.Errorf("❌ The upgrade to postgres %d failed: %s", target, err)
+	log.Printf("Falling back to the database snapshot taken before the upgrade, %s", describeDBRecovery(fallback))
+	updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+		status.PostgresUpdatePhase = nbv1.UpgradePhaseReverting
+	})
+	if !util.KubeCheck(sys) {
+		log.Fatalf("❌ System %q not found", sys.Name)
+	}
+	sys.Spec.DBSpec.PostgresMajorVersion = previousVersion
+	sys.Spec.DBSpec.DBImage = previousImage
+	sys.Spec.DBSpec.DBRecovery = fallback
+	if err := restoreDB(sys, timeout); err != nil {
+		updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+			status.PostgresUpdatePhase = nbv1.UpgradePhaseFailed
+		})
+		log.Fatalf("❌ Failed to fall back to the database snapshot: %s", err)
+	}
+	updatePostgresUpgradeStatus(sys, func(status *nbv1.NooBaaStatus) {
+		status.PostgresUpdatePhase = nbv1.UpgradePhaseFailed
+	})
+	log.Fatalf("❌ The upgrade to postgres %d failed, the database was restored to postgres %d from the snapshot", target, currentVersion)
+}
+
+// getCurrentDBVersion returns the postgres major version and the image that last ran on the data directory of the cluster,
+// or the version and image reported by the operator when the cluster does not report them
+func getCurrentDBVersion(sys *nbv1.NooBaa, cluster *cnpgv1.Cluster) (int, string) {
+	version := getDesiredMajorVersion(sys.Spec.DBSpec)
+	image := getDesiredDbImage(sys.Spec.DBSpec)
+	if sys.Status.DBStatus != nil {
+		if sys.Status.DBStatus.CurrentPgMajorVersion != 0 {
+			version = sys.Status.DBStatus.CurrentPgMajorVersion
+		}
+		if sys.Status.DBStatus.DBCurrentImage != "" {
+			image = sys.Status.DBStatus.DBCurrentImage
+		}
+	}
+	if info := cluster.Status.PGDataImageInfo; info != nil && info.MajorVersion != 0 {
+		version = info.MajorVersion
+		if info.Image != "" {
+			image = info.Image
+		}
+	}
+	return version, image
+}
+
+// updatePostgresUpgradeStatus applies update to the latest status of the system
+func updatePostgresUpgradeStatus(sys *nbv1.NooBaa, update func(status *nbv1.NooBaaStatus)) {
+	log := util.Logger()
+	if !util.KubeCheckQuiet(sys) {
+		log.Warnf("⚠️  System %q not found, the postgres upgrade phase was not updated", sys.Name)
+		return
+	}
+	update(&sys.Status)
+	if err := util.KubeClient().Status().Update(util.Context(), sys); err != nil {
+		log.Warnf("⚠️  Failed to update status.postgresUpdatePhase to %q: %s", sys.Status.PostgresUpdatePhase, err)
+		return
+	}
+	log.Printf("Postgres upgrade phase: %s", sys.Status.PostgresUpdatePhase)
+}
+
+// runDBUpgradeCheck clones the latest volume snapshot of the database to a new volume,
+// runs the compatibility check job on it and returns its report. The job and the volume are deleted after.
+// A snapshot older than maxSnapshotAge is rejected, since the database may have changed since it was taken.
+func runDBUpgradeCheck(sys *nbv1.NooBaa, cluster *cnpgv1.Cluster, currentImage string, targetImage string,
+	maxSnapshotAge time.Duration, timeout time.Duration) (*DBUpgradeCheckReport, error) {
+	log := util.Logger()
+
+	snapshots := &storagesnapshotv1.VolumeSnapshotList{}
+	if !util.KubeList(snapshots, client.InNamespace(sys.Namespace), client.MatchingLabels{"cnpg.io/cluster": cluster.Name}) {
+		return nil, fmt.Errorf("failed to list the volume snapshots of the database cluster %q", cluster.Name)
+	}
+	snapshot, created := getLatestReadyVolumeSnapshot(snapshots.Items)
+	if snapshot == nil {
+		return nil, fmt.Errorf("no ready volume snapshot of the database cluster %q, "+
+			"the check runs on a clone of a volume snapshot, use noobaa system db-backup to take one", cluster.Name)
+	}
+	snapshotAge := time.Since(created).Round(time.Second)
+	if maxSnapshotAge > 0 && snapshotAge > maxSnapshotAge {
+		return nil, fmt.Errorf("the latest volume snapshot %q of the database cluster %q was taken %s ago, more than --max-snapshot-age %s, "+
+			"use noobaa system db-backup to take a new one", snapshot.Name, cluster.Name, snapshotAge, maxSnapshotAge)
+	}
+
+	name := sys.Name + "-db-upgrade-check"
+	pvc, err := getDBUpgradeCheckPVC(sys, cluster, snapshot, name)
+	if err != nil {
+		return nil, err
+	}
+	job := util.KubeObject(bundle.File_deploy_job_db_upgrade_check_yml).(*batchv1.Job)
+	job.Name = name
+	job.Namespace = sys.Namespace
+	deadline := int64(timeout.Seconds())
+	job.Spec.ActiveDeadlineSeconds = &deadline
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes[0].PersistentVolumeClaim.ClaimName = pvc.Name
+	podSpec.InitContainers[0].Image = targetImage
+	podSpec.Containers[0].Image = currentImage
+	if sys.Spec.ImagePullSecret != nil {
+		podSpec.ImagePullSecrets = []corev1.LocalObjectReference{*sys.Spec.ImagePullSecret}
+	}
+
+	log.Printf("Checking the upgrade on a clone of volume snapshot %q, taken %s ago", snapshot.Name, snapshotAge)
+	if !util.KubeCreateFailExisting(pvc) {
+		return nil, fmt.Errorf("failed to create the volume %q from volume snapshot %q", pvc.Name, snapshot.Name)
+	}
+	defer util.KubeDelete(pvc)
+	if !util.KubeCreateFailExisting(job) {
+		return nil, fmt.Errorf("failed to create the job %q", job.Name)
+	}
+	propagationPolicy := metav1.DeletePropagationForeground
+	defer util.KubeDelete(job, &client.DeleteOptions{PropagationPolicy: &propagationPolicy})
+
+	pollCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	interval := time.Duration(3)
+	err = wait.PollUntilContextCancel(pollCtx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		if !util.KubeCheckQuiet(job) {
+			return false, nil
+		}
+		if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+			log.Printf("⏳ Waiting for the compatibility check job %q to complete", job.Name)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for the job %q: %w", job.Name, err)
+	}
+
+	output, err := getDBUpgradeCheckOutput(job)
+	if err != nil {
+		return nil, err
+	}
+	report := parseDBUpgradeCheckReport(output)
+	if !report.Complete {
+		log.Printf("The output of the job %q:\n%s", job.Name, output)
+		return nil, fmt.Errorf("the job %q did not complete its report", job.Name)
+	}
+	report.Snapshot = snapshot.Name
+	report.SnapshotAge = snapshotAge
+	return report, nil
+}
+
+// getLatestReadyVolumeSnapshot returns the most recent volume snapshot that is ready to use and its creation time, or nil
+func getLatestReadyVolumeSnapshot(snapshots []storagesnapshotv1.VolumeSnapshot) (*storagesnapshotv1.VolumeSnapshot, time.Time) {
+	var latest *storagesnapshotv1.VolumeSnapshot
+	var latestTime time.Time
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		if ready, _ := getVolumeSnapshotReadiness(snapshot); !ready {
+			continue
+		}
+		created := snapshot.CreationTimestamp.Time
+		if snapshot.Status.CreationTime != nil {
+			created = snapshot.Status.CreationTime.Time
+		}
+		if latest == nil || created.After(latestTime) {
+			latest = snapshot
+			latestTime = created
+		}
+	}
+	return latest, latestTime
+}
+
+// getDBUpgradeCheckPVC returns a volume for the check job, restored from the snapshot with the storage class of the cluster
+func getDBUpgradeCheckPVC(sys *nbv1.NooBaa, cluster *cnpgv1.Cluster, snapshot *storagesnapshotv1.VolumeSnapshot, name string) (*corev1.PersistentVolumeClaim, error) {
+	size := resource.Quantity{}
+	if snapshot.Status != nil && snapshot.Status.RestoreSize != nil {
+		size = *snapshot.Status.RestoreSize
+	}
+	if cluster.Spec.StorageConfiguration.Size != "" {
+		clusterSize, err := resource.ParseQuantity(cluster.Spec.StorageConfiguration.Size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the storage size %q of the database cluster: %w", cluster.Spec.StorageConfiguration.Size, err)
+		}
+		if clusterSize.Cmp(size) > 0 {
+			size = clusterSize
+		}
+	}
+	if size.IsZero() {
+		return nil, fmt.Errorf("the restore size of volume snapshot %q is unknown", snapshot.Name)
+	}
+	apiGroup := storagesnapshotv1.GroupName
+	return &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: sys.Namespace,
+			Labels:    map[string]string{"app": "noobaa"},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: cluster.Spec.StorageConfiguration.StorageClass,
+			DataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     "VolumeSnapshot",
+				Name:     snapshot.Name,
+			},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}, nil
+}
+
+// getDBUpgradeCheckOutput returns the log of the check container, where the job writes its report.
+// The termination message is not used since it is truncated at 4 KiB.
+func getDBUpgradeCheckOutput(job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	if !util.KubeList(pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}) {
+		return "", fmt.Errorf("failed to list the pods of the job %q", job.Name)
+	}
+	for i := range pods.Items {
+		for _, status := range pods.Items[i].Status.ContainerStatuses {
+			if status.Name != dbUpgradeCheckContainer || status.State.Terminated == nil {
+				continue
+			}
+			logs, err := util.GetPodLogs(pods.Items[i])
+			if err != nil {
+				return "", fmt.Errorf("failed to read the logs of the job %q: %w", job.Name, err)
+			}
+			defer func() {
+				for _, stream := range logs {
+					stream.Close()
+				}
+			}()
+			stream, ok := logs[dbUpgradeCheckContainer]
+			if !ok {
+				return "", fmt.Errorf("failed to read the logs of the job %q", job.Name)
+			}
+			output, err := io.ReadAll(stream)
+			if err != nil {
+				return "", fmt.Errorf("failed to read the logs of the job %q: %w", job.Name, err)
+			}
+			return string(output), nil
+		}
+	}
+	return "", nil
+}
+
+// parseDBUpgradeCheckReport parses the key=value lines written by the check job
+func parseDBUpgradeCheckReport(message string) *DBUpgradeCheckReport {
+	report := &DBUpgradeCheckReport{}
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "version":
+			report.Version, _ = strconv.Atoi(value)
+		case "target_version":
+			report.TargetVersion, _ = strconv.Atoi(value)
+		case "data_bytes":
+			report.DataBytes, _ = strconv.ParseInt(value, 10, 64)
+		case "volume_kb":
+			report.VolumeKB, _ = strconv.ParseInt(value, 10, 64)
+		case "volume_avail_kb":
+			report.VolumeAvailKB, _ = strconv.ParseInt(value, 10, 64)
+		case "prepared_xacts":
+			report.PreparedXacts, _ = strconv.Atoi(value)
+		case "extension":
+			report.Extensions = append(report.Extensions, value)
+		case "missing_extension":
+			report.MissingExtensions = append(report.MissingExtensions, value)
+		case "reg_column":
+			report.RegColumns = append(report.RegColumns, value)
+		case "error":
+			report.Errors = append(report.Errors, value)
+		case "end":
+			report.Complete = value == "true"
+		}
+	}
+	return report
+}
+
+// getDBUpgradeCheckResults returns the blockers of the upgrade to the target major version found in the report
+func getDBUpgradeCheckResults(report *DBUpgradeCheckReport, target int, maxUsedPercent int) []UpgradePreflightCheck {
+	checks := []UpgradePreflightCheck{}
+
+	version := UpgradePreflightCheck{Name: "postgres-version"}
+	switch {
+	case report.Version == 0 || report.TargetVersion == 0:
+		version.Message = "the check did not report the postgres versions"
+	case report.TargetVersion != target:
+		version.Message = fmt.Sprintf("the target image runs postgres %d, not %d", report.TargetVersion, target)
+	case report.TargetVersion <= report.Version:
+		version.Message = fmt.Sprintf("postgres %d cannot be upgraded to %d", report.Version, report.TargetVersion)
+	default:
+		version.Passed = true
+		version.Message = fmt.Sprintf("postgres %d can be upgraded to %d", report.Version, report.TargetVersion)
+	}
+	checks = append(checks, version)
+
+	start := UpgradePreflightCheck{Name: "postgres-start"}
+	if len(report.Errors) > 0 {
+		start.Message = strings.Join(report.Errors, "; ")
+		// the other checks need a running postgres
+		return append(checks, start)
+	}
+	start.Passed = true
+	start.Message = fmt.Sprintf("postgres started on the clone with %d MB of data", report.DataBytes/1024/1024)
+	checks = append(checks, start)
+
+	extensions := UpgradePreflightCheck{Name: "extensions"}
+	if len(report.MissingExtensions) > 0 {
+		extensions.Message = fmt.Sprintf("extensions are missing in the target image: %v", report.MissingExtensions)
+	} else {
+		extensions.Passed = true
+		extensions.Message = fmt.Sprintf("%d extensions are available in the target image", len(report.Extensions))
+	}
+	checks = append(checks, extensions)
+
+	regColumns := UpgradePreflightCheck{Name: "reg-columns"}
+	if len(report.RegColumns) > 0 {
+		regColumns.Message = fmt.Sprintf("pg_upgrade cannot upgrade columns of reg* data types: %v", report.RegColumns)
+	} else {
+		regColumns.Passed = true
+		regColumns.Message = "no columns of reg* data types"
+	}
+	checks = append(checks, regColumns)
+
+	preparedXacts := UpgradePreflightCheck{Name: "prepared-transactions"}
+	if report.PreparedXacts > 0 {
+		preparedXacts.Message = fmt.Sprintf("%d prepared transactions must be committed or rolled back", report.PreparedXacts)
+	} else {
+		preparedXacts.Passed = true
+		preparedXacts.Message = "no prepared transactions"
+	}
+	checks = append(checks, preparedXacts)
+
+	if report.VolumeKB <= 0 {
+		checks = append(checks, UpgradePreflightCheck{Name: "db-volume", Message: "the check did not report the usage of the DB volume"})
+	} else {
+		usedPercent := int((report.VolumeKB - report.VolumeAvailKB) * 100 / report.VolumeKB)
+		checks = append(checks, checkUpgradeDBVolumeUsage(usedPercent, maxUsedPercent))
+	}
+	return checks
+}
+
+// createDBUpgradeSnapshot backs up the database before the upgrade
+// and returns the recovery configuration to fall back to when the upgrade fails
+func createDBUpgradeSnapshot(sys *nbv1.NooBaa, target int) (*nbv1.DBRecoverySpec, error) {
+	log := util.Logger()
+	name := fmt.Sprintf("%s-db-pre-pg%d-%s", sys.Name, target, time.Now().Format("20060102150405"))
+	if err := CreateDBBackup(sys, name, dbUpgradeSnapshotWait); err != nil {
+		return nil, err
+	}
+
+	if sys.Spec.DBSpec.DBBackup.ObjectStore != nil {
+		backup := cnpg.GetCnpgBackupObj(sys.Namespace, name)
+		if !util.KubeCheck(backup) {
+			return nil, fmt.Errorf("backup %q not found", name)
+		}
+		return getDBUpgradeFallbackRecovery("", backup.Status.BackupID, time.Now()), nil
+	}
+
+	// the cluster must not be upgraded before the snapshot is ready to restore
+	snapshot := &storagesnapshotv1.VolumeSnapshot{}
+	pollCtx, cancel := context.WithTimeout(context.Background(), dbUpgradeSnapshotWait)
+	defer cancel()
+	interval := time.Duration(3)
+	err := wait.PollUntilContextCancel(pollCtx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		err := util.KubeClient().Get(util.Context(), client.ObjectKey{Namespace: sys.Namespace, Name: name}, snapshot)
+		if err != nil {
+			return false, nil
+		}
+		ready, readiness := getVolumeSnapshotReadiness(snapshot)
+		if strings.HasPrefix(readiness, "error: ") {
+			return false, fmt.Errorf("volume snapshot %q failed: %s", name, readiness)
+		}
+		if !ready {
+			log.Printf("⏳ Waiting for volume snapshot %q to be ready to use", name)
+		}
+		return ready, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("volume snapshot %q is not ready to use: %w", name, err)
+	}
+	return getDBUpgradeFallbackRecovery(name, "", time.Now()), nil
+}
+
+// getDBUpgradeFallbackRecovery returns the recovery configuration that restores the database as it was before the upgrade -
+// the volume snapshot, or the base backup in the object store replayed up to the start of the upgrade
+func getDBUpgradeFallbackRecovery(snapshotName string, backupID string, upgradeStartTime time.Time) *nbv1.DBRecoverySpec {
+	if snapshotName != "" {
+		return &nbv1.DBRecoverySpec{VolumeSnapshotName: snapshotName}
+	}
+	return &nbv1.DBRecoverySpec{
+		RecoveryTarget: &nbv1.DBRecoveryTargetSpec{
+			TargetTime: upgradeStartTime.UTC().Truncate(time.Second).Format(time.RFC3339),
+			BackupID:   backupID,
+		},
+	}
+}
+
+// waitForDBMajorUpgrade waits until the database cluster runs the target major version and the system is ready
+func waitForDBMajorUpgrade(sys *nbv1.NooBaa, cluster *cnpgv1.Cluster, target int, startTime time.Time, timeout time.Duration) error {
+	log := util.Logger()
+	pollCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	lastProgress := ""
+	interval := time.Duration(3)
+	return wait.PollUntilContextCancel(pollCtx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		if !util.KubeCheckQuiet(sys) || !util.KubeCheckQuiet(cluster) {
+			return false, nil
+		}
+		done, progress, err := getDBMajorUpgradeProgress(sys, cluster, target)
+		if progress != lastProgress {
+			log.Printf("⏳ %s (%s)", progress, util.HumanizeDuration(time.Since(startTime).Round(time.Second)))
+			lastProgress = progress
+		}
+		return done, err
+	})
+}
+
+// getDBMajorUpgradeProgress returns whether the cluster runs the target major version with the system ready,
+// and a description of the progress. It fails when the cluster needs manual intervention.
+func getDBMajorUpgradeProgress(sys *nbv1.NooBaa, cluster *cnpgv1.Cluster, target int) (bool, string, error) {
+	dataVersion := 0
+	if cluster.Status.PGDataImageInfo != nil {
+		dataVersion = cluster.Status.PGDataImageInfo.MajorVersion
+	}
+	progress := fmt.Sprintf("System phase %s, database cluster %q, data directory of postgres %d",
+		sys.Status.Phase, cluster.Status.Phase, dataVersion)
+	switch cluster.Status.Phase {
+	case cnpgv1.PhaseUnrecoverable, cnpgv1.PhaseImageCatalogError, cnpgv1.PhaseWaitingForUser:
+		return false, progress, fmt.Errorf("database cluster %q: %s", cluster.Status.Phase, cluster.Status.PhaseReason)
+	}
+	done := dataVersion == target && isClusterReady(cluster) && sys.Status.Phase == nbv1.SystemPhaseReady
+	return done, progress, nil
+}
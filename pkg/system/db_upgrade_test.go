@@ -0,0 +1,170 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseDBUpgradeCheckReport(t *testing.T) {
+	message := `version=16
+target_version=17
+volume_kb=1000
+volume_avail_kb=600
+data_bytes=12345
+prepared_xacts=2
+extension=nbcore:plpgsql
+extension=nbcore:pg_trgm
+missing_extension=nbcore:pg_trgm
+reg_column=nbcore:public.t.c
+error=postgres failed to start
+garbage line
+end=true
+`
+	want := &DBUpgradeCheckReport{
+		Version:           16,
+		TargetVersion:     17,
+		DataBytes:         12345,
+		VolumeKB:          1000,
+		VolumeAvailKB:     600,
+		PreparedXacts:     2,
+		Extensions:        []string{"nbcore:plpgsql", "nbcore:pg_trgm"},
+		MissingExtensions: []string{"nbcore:pg_trgm"},
+		RegColumns:        []string{"nbcore:public.t.c"},
+		Errors:            []string{"postgres failed to start"},
+		Complete:          true,
+	}
+	if got := parseDBUpgradeCheckReport(message); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseDBUpgradeCheckReport() = %+v, want %+v", got, want)
+	}
+	if got := parseDBUpgradeCheckReport(strings.TrimSuffix(message, "end=true\n")); got.Complete {
+		t.Fatalf("parseDBUpgradeCheckReport() of a report without the end marker is complete")
+	}
+}
+
+func TestGetDBUpgradeCheckResults(t *testing.T) {
+	clean := func() *DBUpgradeCheckReport {
+		return &DBUpgradeCheckReport{
+			Version:       16,
+			TargetVersion: 17,
+			VolumeKB:      1000,
+			VolumeAvailKB: 500,
+			Extensions:    []string{"nbcore:plpgsql"},
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(r *DBUpgradeCheckReport)
+		failed []string
+	}{
+		{"compatible", func(r *DBUpgradeCheckReport) {}, nil},
+		{"missing versions", func(r *DBUpgradeCheckReport) { r.TargetVersion = 0 }, []string{"postgres-version"}},
+		{"target image of another version", func(r *DBUpgradeCheckReport) { r.TargetVersion = 18 }, []string{"postgres-version"}},
+		{"postgres failed to start", func(r *DBUpgradeCheckReport) { r.Errors = []string{"bad"} }, []string{"postgres-start"}},
+		{"missing extension", func(r *DBUpgradeCheckReport) { r.MissingExtensions = []string{"nbcore:pg_trgm"} }, []string{"extensions"}},
+		{"reg columns", func(r *DBUpgradeCheckReport) { r.RegColumns = []string{"nbcore:public.t.c"} }, []string{"reg-columns"}},
+		{"prepared transactions", func(r *DBUpgradeCheckReport) { r.PreparedXacts = 1 }, []string{"prepared-transactions"}},
+		{"volume full", func(r *DBUpgradeCheckReport) { r.VolumeAvailKB = 100 }, []string{"db-volume"}},
+		{"volume not reported", func(r *DBUpgradeCheckReport) { r.VolumeKB = 0 }, []string{"db-volume"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := clean()
+			tt.modify(report)
+			failed := []string{}
+			for _, check := range getDBUpgradeCheckResults(report, 17, 80) {
+				if !check.Passed {
+					failed = append(failed, check.Name)
+				}
+			}
+			if len(tt.failed) == 0 && len(failed) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(failed, tt.failed) {
+				t.Fatalf("failed checks %v, want %v", failed, tt.failed)
+			}
+		})
+	}
+}
+
+func TestGetLatestReadyVolumeSnapshot(t *testing.T) {
+	ready, notReady := true, false
+	now := time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC)
+	snapshot := func(name string, age time.Duration, readyToUse *bool) storagesnapshotv1.VolumeSnapshot {
+		created := metav1.NewTime(now.Add(-age))
+		return storagesnapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     &storagesnapshotv1.VolumeSnapshotStatus{ReadyToUse: readyToUse, CreationTime: &created},
+		}
+	}
+	snapshots := []storagesnapshotv1.VolumeSnapshot{
+		snapshot("old", 2*time.Hour, &ready),
+		snapshot("newest-not-ready", time.Minute, &notReady),
+		snapshot("latest", time.Hour, &ready),
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-status"}},
+	}
+	if latest, created := getLatestReadyVolumeSnapshot(snapshots); latest == nil || latest.Name != "latest" || !created.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("getLatestReadyVolumeSnapshot() = %v, %v, want latest", latest, created)
+	}
+	if latest, _ := getLatestReadyVolumeSnapshot(snapshots[1:2]); latest != nil {
+		t.Fatalf("getLatestReadyVolumeSnapshot() = %q, want nil", latest.Name)
+	}
+}
+
+func TestGetDBUpgradeFallbackRecovery(t *testing.T) {
+	startTime := time.Date(2026, 1, 14, 10, 0, 0, 500, time.UTC)
+
+	recovery := getDBUpgradeFallbackRecovery("snap-1", "", startTime)
+	if recovery.VolumeSnapshotName != "snap-1" || recovery.RecoveryTarget != nil {
+		t.Fatalf("unexpected recovery spec %+v", recovery)
+	}
+
+	recovery = getDBUpgradeFallbackRecovery("", "20260114T095500", startTime)
+	if recovery.RecoveryTarget == nil || recovery.RecoveryTarget.BackupID != "20260114T095500" ||
+		recovery.RecoveryTarget.TargetTime != "2026-01-14T10:00:00Z" {
+		t.Fatalf("unexpected recovery spec %+v", recovery)
+	}
+}
+
+func TestGetDBMajorUpgradeProgress(t *testing.T) {
+	readyConditions := []metav1.Condition{{Type: string(cnpgv1.ConditionClusterReady), Status: metav1.ConditionTrue}}
+	tests := []struct {
+		name        string
+		sysPhase    nbv1.SystemPhase
+		phase       string
+		dataVersion int
+		conditions  []metav1.Condition
+		done        bool
+		wantErr     bool
+	}{
+		{"upgrading", nbv1.SystemPhaseConfiguring, cnpgv1.PhaseMajorUpgrade, 16, nil, false, false},
+		{"upgraded while system is not ready", nbv1.SystemPhaseConfiguring, cnpgv1.PhaseHealthy, 17, readyConditions, false, false},
+		{"upgraded", nbv1.SystemPhaseReady, cnpgv1.PhaseHealthy, 17, readyConditions, true, false},
+		{"old version ready", nbv1.SystemPhaseReady, cnpgv1.PhaseHealthy, 16, readyConditions, false, false},
+		{"unrecoverable", nbv1.SystemPhaseConfiguring, cnpgv1.PhaseUnrecoverable, 16, nil, false, true},
+		{"image catalog error", nbv1.SystemPhaseConfiguring, cnpgv1.PhaseImageCatalogError, 16, nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &nbv1.NooBaa{}
+			sys.Status.Phase = tt.sysPhase
+			cluster := &cnpgv1.Cluster{}
+			cluster.Status.Phase = tt.phase
+			cluster.Status.Conditions = tt.conditions
+			cluster.Status.PGDataImageInfo = &cnpgv1.ImageInfo{MajorVersion: tt.dataVersion}
+			done, progress, err := getDBMajorUpgradeProgress(sys, cluster, 17)
+			if done != tt.done || (err != nil) != tt.wantErr {
+				t.Fatalf("getDBMajorUpgradeProgress() = %v, %v, want done %v wantErr %v", done, err, tt.done, tt.wantErr)
+			}
+			if !strings.Contains(progress, tt.phase) {
+				t.Fatalf("progress %q does not contain %q", progress, tt.phase)
+			}
+		})
+	}
+}
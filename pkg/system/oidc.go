@@ -0,0 +1,255 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	oidcConfigKey                 = "config.json"
+	oidcConfigMountPath           = "/etc/noobaa-server/oidc/config"
+	oidcDiscoveryPath             = "/.well-known/openid-configuration"
+	kubernetesJWKSPath            = "/openid/v1/jwks"
+	defaultOIDCKubernetesAudience = "noobaa"
+	defaultOIDCClaim              = "sub"
+
+	// oidcKubernetesKeysRefreshInterval is how often the embedded service account issuer keys are read again,
+	// so keys that the cluster rotated are picked up by the endpoints
+	oidcKubernetesKeysRefreshInterval = 10 * time.Minute
+)
+
+// oidcDiscovery is the subset of the OIDC discovery document that is used by the operator
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	JWKSURI               string `json:"jwks_uri"`
+	IntrospectionEndpoint string `json:"introspection_endpoint,omitempty"`
+}
+
+// oidcConfig is the OIDC configuration that is mounted to the endpoint pods
+type oidcConfig struct {
+	Providers []oidcProviderConfig `json:"providers"`
+}
+
+// oidcProviderConfig is a provider of the OIDC configuration of the endpoint pods.
+// The introspection fields keep the names of the keycloak configuration that is created by the CLI.
+type oidcProviderConfig struct {
+	Name                       string                   `json:"name"`
+	Type                       nbv1.OIDCProviderType    `json:"type"`
+	Issuer                     string                   `json:"issuer"`
+	Audiences                  []string                 `json:"audiences,omitempty"`
+	Validation                 nbv1.OIDCTokenValidation `json:"validation"`
+	JWKSURI                    string                   `json:"jwks_uri,omitempty"`
+	JWKS                       json.RawMessage          `json:"jwks,omitempty"`
+	TokenIntrospectionEndpoint string                   `json:"token_introspection_endpoint,omitempty"`
+	ClientID                   string                   `json:"client_id,omitempty"`
+	ClientSecret               string                   `json:"client_secret,omitempty"`
+	ClaimMappings              []oidcClaimMappingConfig `json:"claim_mappings"`
+}
+
+// oidcClaimMappingConfig is a claim mapping rule of the OIDC configuration of the endpoint pods
+type oidcClaimMappingConfig struct {
+	Claim   string `json:"claim"`
+	Value   string `json:"value"`
+	Account string `json:"account"`
+}
+
+// fetchOIDCDiscovery reads the discovery document of an issuer, replaced in tests
+var fetchOIDCDiscovery = func(issuer string) (*oidcDiscovery, error) {
+	client := &http.Client{
+		Transport: util.GlobalCARefreshingTransport,
+		Timeout:   10 * time.Second,
+	}
+	res, err := client.Get(strings.TrimSuffix(issuer, "/") + oidcDiscoveryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery document of %q returned status %d", issuer, res.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	discovery := &oidcDiscovery{}
+	if err := json.Unmarshal(body, discovery); err != nil {
+		return nil, fmt.Errorf("failed to parse the discovery document of %q: %v", issuer, err)
+	}
+	return discovery, nil
+}
+
+// fetchKubernetesOIDC reads the service account issuer discovery document and public keys from the API server,
+// replaced in tests
+var fetchKubernetesOIDC = func() (*oidcDiscovery, json.RawMessage, error) {
+	clientset, err := kubernetes.NewForConfig(util.KubeConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := clientset.RESTClient().Get().AbsPath(oidcDiscoveryPath).DoRaw(util.Context())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the service account issuer discovery document: %v", err)
+	}
+	discovery := &oidcDiscovery{}
+	if err := json.Unmarshal(body, discovery); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the service account issuer discovery document: %v", err)
+	}
+	jwks, err := clientset.RESTClient().Get().AbsPath(kubernetesJWKSPath).DoRaw(util.Context())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the service account issuer public keys: %v", err)
+	}
+	if !json.Valid(jwks) {
+		return nil, nil, fmt.Errorf("the service account issuer public keys are not valid JSON")
+	}
+	return discovery, jwks, nil
+}
+
+// isOIDCConfigEnabled returns true when the system has declarative OIDC providers
+// and their configuration is enabled for the endpoints
+func isOIDCConfigEnabled(sys *nbv1.NooBaa) bool {
+	oidc := sys.Spec.Security.OIDC
+	return oidc != nil && oidc.Enabled && len(oidc.Providers) > 0
+}
+
+// ReconcileOIDCConfig renders the OIDC providers of the system to the configuration secret of the endpoints
+func (r *Reconciler) ReconcileOIDCConfig() error {
+	if !isOIDCConfigEnabled(r.NooBaa) {
+		if util.KubeCheckQuiet(r.SecretOIDCConfig) {
+			r.Logger.Infof("ReconcileOIDCConfig: deleting the OIDC configuration of removed or disabled providers")
+			util.KubeDeleteNoPolling(r.SecretOIDCConfig)
+		}
+		return nil
+	}
+
+	config, err := r.getOIDCConfig()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the OIDC configuration: %v", err)
+	}
+
+	return r.ReconcileObject(r.SecretOIDCConfig, func() error {
+		r.SecretOIDCConfig.StringData = nil
+		r.SecretOIDCConfig.Data = map[string][]byte{oidcConfigKey: data}
+		return nil
+	})
+}
+
+// getOIDCConfig resolves the OIDC providers of the system to the configuration of the endpoints
+func (r *Reconciler) getOIDCConfig() (*oidcConfig, error) {
+	config := &oidcConfig{}
+	for i := range r.NooBaa.Spec.Security.OIDC.Providers {
+		provider := &r.NooBaa.Spec.Security.OIDC.Providers[i]
+		providerConfig, err := r.getOIDCProviderConfig(provider)
+		if err != nil {
+			return nil, fmt.Errorf("OIDC provider %q: %v", provider.Name, err)
+		}
+		config.Providers = append(config.Providers, *providerConfig)
+	}
+	return config, nil
+}
+
+// getOIDCProviderConfig resolves the issuer, the public keys or introspection endpoint,
+// and the default values of a provider
+func (r *Reconciler) getOIDCProviderConfig(provider *nbv1.OIDCProviderSpec) (*oidcProviderConfig, error) {
+	config := &oidcProviderConfig{
+		Name:                       provider.Name,
+		Type:                       provider.Type,
+		Issuer:                     provider.Issuer,
+		Audiences:                  provider.Audiences,
+		Validation:                 provider.Validation,
+		JWKSURI:                    provider.JWKSURI,
+		TokenIntrospectionEndpoint: provider.IntrospectionEndpoint,
+	}
+	if config.Validation == "" {
+		config.Validation = nbv1.OIDCTokenValidationJWKS
+	}
+	for _, mapping := range provider.ClaimMappings {
+		claim := mapping.Claim
+		if claim == "" {
+			claim = defaultOIDCClaim
+		}
+		config.ClaimMappings = append(config.ClaimMappings, oidcClaimMappingConfig{
+			Claim:   claim,
+			Value:   mapping.Value,
+			Account: mapping.Account,
+		})
+	}
+
+	if provider.Type == nbv1.OIDCProviderKubernetes {
+		// the service account issuer keys are served by the API server, which the endpoints
+		// may not be allowed to read, so the keys are embedded in the configuration
+		discovery, jwks, err := fetchKubernetesOIDC()
+		if err != nil {
+			return nil, err
+		}
+		r.requeueWithin(oidcKubernetesKeysRefreshInterval)
+		if config.Issuer == "" {
+			config.Issuer = discovery.Issuer
+		}
+		if len(config.Audiences) == 0 {
+			config.Audiences = []string{defaultOIDCKubernetesAudience}
+		}
+		config.JWKSURI = ""
+		config.JWKS = jwks
+		return config, nil
+	}
+
+	needJWKSURI := config.Validation == nbv1.OIDCTokenValidationJWKS && config.JWKSURI == ""
+	needIntrospection := config.Validation == nbv1.OIDCTokenValidationIntrospection && config.TokenIntrospectionEndpoint == ""
+	if needJWKSURI || needIntrospection {
+		discovery, err := fetchOIDCDiscovery(config.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(config.Issuer, "/") {
+			return nil, fmt.Errorf("the discovery document issuer %q does not match the issuer %q", discovery.Issuer, config.Issuer)
+		}
+		if needJWKSURI {
+			if discovery.JWKSURI == "" {
+				return nil, fmt.Errorf("the discovery document has no jwks_uri")
+			}
+			config.JWKSURI = discovery.JWKSURI
+		}
+		if needIntrospection {
+			if discovery.IntrospectionEndpoint == "" {
+				return nil, fmt.Errorf("the discovery document has no introspection_endpoint, set introspectionEndpoint")
+			}
+			config.TokenIntrospectionEndpoint = discovery.IntrospectionEndpoint
+		}
+	}
+
+	if config.Validation == nbv1.OIDCTokenValidationIntrospection {
+		if provider.ClientSecret == nil || provider.ClientSecret.Name == "" {
+			return nil, fmt.Errorf("introspection requires a clientSecret")
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      provider.ClientSecret.Name,
+			Namespace: provider.ClientSecret.Namespace,
+		}}
+		if secret.Namespace == "" {
+			secret.Namespace = r.Request.Namespace
+		}
+		if !util.KubeCheckQuiet(secret) {
+			return nil, fmt.Errorf("client secret %q not found", secret.Name)
+		}
+		config.ClientID = string(secret.Data["client_id"])
+		config.ClientSecret = string(secret.Data["client_secret"])
+		if config.ClientID == "" || config.ClientSecret == "" {
+			return nil, fmt.Errorf("client secret %q is missing the client_id or client_secret keys", secret.Name)
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,184 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+func stubOIDCFetchers(t *testing.T) {
+	origDiscovery, origKubernetes := fetchOIDCDiscovery, fetchKubernetesOIDC
+	t.Cleanup(func() {
+		fetchOIDCDiscovery, fetchKubernetesOIDC = origDiscovery, origKubernetes
+	})
+	fetchOIDCDiscovery = func(issuer string) (*oidcDiscovery, error) {
+		switch issuer {
+		case "https://dex.example.com":
+			return &oidcDiscovery{Issuer: issuer, JWKSURI: issuer + "/keys"}, nil
+		case "https://idp.example.com/":
+			return &oidcDiscovery{
+				Issuer:                "https://idp.example.com",
+				JWKSURI:               "https://idp.example.com/jwks",
+				IntrospectionEndpoint: "https://idp.example.com/introspect",
+			}, nil
+		case "https://other.example.com":
+			return &oidcDiscovery{Issuer: "https://impostor.example.com", JWKSURI: "https://impostor.example.com/jwks"}, nil
+		}
+		return nil, fmt.Errorf("unexpected issuer %s", issuer)
+	}
+	fetchKubernetesOIDC = func() (*oidcDiscovery, json.RawMessage, error) {
+		return &oidcDiscovery{Issuer: "https://kubernetes.default.svc"}, json.RawMessage(`{"keys":[{"kid":"k1"}]}`), nil
+	}
+}
+
+func TestGetOIDCProviderConfig(t *testing.T) {
+	stubOIDCFetchers(t)
+	r := &Reconciler{}
+	mappings := []nbv1.OIDCClaimMapping{{Value: "system:serviceaccount:apps:*", Account: "apps"}}
+
+	t.Run("kubernetes provider embeds the issuer keys", func(t *testing.T) {
+		config, err := r.getOIDCProviderConfig(&nbv1.OIDCProviderSpec{
+			Name: "cluster", Type: nbv1.OIDCProviderKubernetes, ClaimMappings: mappings,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Issuer != "https://kubernetes.default.svc" || string(config.JWKS) != `{"keys":[{"kid":"k1"}]}` || config.JWKSURI != "" {
+			t.Errorf("unexpected issuer or keys %+v", config)
+		}
+		if len(config.Audiences) != 1 || config.Audiences[0] != defaultOIDCKubernetesAudience {
+			t.Errorf("expected the default audience, got %v", config.Audiences)
+		}
+		if config.Validation != nbv1.OIDCTokenValidationJWKS || config.ClaimMappings[0].Claim != "sub" {
+			t.Errorf("expected the default validation and claim, got %+v", config)
+		}
+		if r.RequeueAfter != oidcKubernetesKeysRefreshInterval {
+			t.Errorf("expected a requeue to refresh the issuer keys, got %v", r.RequeueAfter)
+		}
+	})
+
+	t.Run("dex provider reads the jwks_uri from discovery", func(t *testing.T) {
+		config, err := r.getOIDCProviderConfig(&nbv1.OIDCProviderSpec{
+			Name: "dex", Type: nbv1.OIDCProviderDex, Issuer: "https://dex.example.com", ClaimMappings: mappings,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.JWKSURI != "https://dex.example.com/keys" || config.JWKS != nil {
+			t.Errorf("unexpected jwks %+v", config)
+		}
+	})
+
+	t.Run("explicit jwks uri skips discovery", func(t *testing.T) {
+		config, err := r.getOIDCProviderConfig(&nbv1.OIDCProviderSpec{
+			Name: "idp", Type: nbv1.OIDCProviderGeneric, Issuer: "https://unreachable.example.com",
+			JWKSURI: "https://unreachable.example.com/keys", ClaimMappings: mappings,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.JWKSURI != "https://unreachable.example.com/keys" {
+			t.Errorf("unexpected jwks uri %q", config.JWKSURI)
+		}
+	})
+
+	t.Run("issuer mismatch is rejected", func(t *testing.T) {
+		_, err := r.getOIDCProviderConfig(&nbv1.OIDCProviderSpec{
+			Name: "other", Type: nbv1.OIDCProviderGeneric, Issuer: "https://other.example.com", ClaimMappings: mappings,
+		})
+		if err == nil || !strings.Contains(err.Error(), "does not match") {
+			t.Fatalf("expected an issuer mismatch error, got %v", err)
+		}
+	})
+}
+
+func TestValidateOIDCProvidersConfig(t *testing.T) {
+	providers, err := validateOIDCProvidersConfig(nbv1.OIDCProviderKubernetes,
+		`{"providers":[{"name":"cluster","claimMappings":[{"value":"system:serviceaccount:apps:*","account":"apps"}]}]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(providers) != 1 || providers[0].Type != nbv1.OIDCProviderKubernetes {
+		t.Fatalf("expected the provider type to default to --type, got %+v", providers)
+	}
+
+	_, err = validateOIDCProvidersConfig(nbv1.OIDCProviderDex,
+		`{"providers":[{"name":"cluster","type":"kubernetes","claimMappings":[{"value":"*","account":"apps"}]}]}`)
+	if err == nil || !strings.Contains(err.Error(), "does not match --type") {
+		t.Fatalf("expected a type mismatch error, got %v", err)
+	}
+
+	_, err = validateOIDCProvidersConfig(nbv1.OIDCProviderDex,
+		`{"providers":[{"name":"dex","issuer":"https://dex.example.com","client_secret":"s","claimMappings":[{"value":"*","account":"apps"}]}]}`)
+	if err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Fatalf("expected an unknown field error, got %v", err)
+	}
+}
+
+func TestMergeOIDCProviders(t *testing.T) {
+	spec := &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+		{Name: "cluster", Type: nbv1.OIDCProviderKubernetes},
+		{Name: "dex", Type: nbv1.OIDCProviderDex, Issuer: "https://old.example.com"},
+	}}
+	mergeOIDCProviders(spec, []nbv1.OIDCProviderSpec{
+		{Name: "dex", Type: nbv1.OIDCProviderDex, Issuer: "https://dex.example.com"},
+		{Name: "idp", Type: nbv1.OIDCProviderGeneric, Issuer: "https://idp.example.com"},
+	})
+	if len(spec.Providers) != 3 || spec.Providers[1].Issuer != "https://dex.example.com" || spec.Providers[2].Name != "idp" {
+		t.Fatalf("unexpected providers after merge %+v", spec.Providers)
+	}
+}
+
+func TestValidateKeycloakOIDCConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		errMsg string
+	}{
+		{"valid", `{"providers":[{"issuer":"https://kc","client_id":"c","client_secret":"s","token_introspection_endpoint":"https://kc/introspect"}]}`, ""},
+		{"invalid json", `{"providers":`, "not valid JSON"},
+		{"no providers", `{"providers":[]}`, "at least one provider"},
+		{"empty provider", `{"providers":[{}]}`, "provider[0] is empty"},
+		{"missing fields", `{"providers":[{"issuer":"https://kc","client_id":"c"}]}`, "provider[0] is missing required fields: client_secret, token_introspection_endpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateKeycloakOIDCConfig(tt.config)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("validateKeycloakOIDCConfig() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("validateKeycloakOIDCConfig() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestIsOIDCConfigEnabled(t *testing.T) {
+	providers := []nbv1.OIDCProviderSpec{{Name: "cluster", Type: nbv1.OIDCProviderKubernetes}}
+	tests := []struct {
+		name string
+		oidc *nbv1.OIDCSpec
+		want bool
+	}{
+		{"no oidc", nil, false},
+		{"disabled", &nbv1.OIDCSpec{Providers: providers}, false},
+		{"enabled without providers", &nbv1.OIDCSpec{Enabled: true}, false},
+		{"enabled", &nbv1.OIDCSpec{Enabled: true, Providers: providers}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &nbv1.NooBaa{}
+			sys.Spec.Security.OIDC = tt.oidc
+			if got := isOIDCConfigEnabled(sys); got != tt.want {
+				t.Fatalf("isOIDCConfigEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"runtime"
 
 	"github.com/sirupsen/logrus"
@@ -9,6 +10,7 @@ import (
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -121,43 +123,102 @@ func init() {
 	}
 }
 
-func lookupProfile(nb *nbv1.NooBaa) performanceProfile {
+// lookupProfile returns the settings of the performance profile of the system.
+// customProfile is the profile loaded by LoadPerformanceProfile when the profile is not built-in.
+func lookupProfile(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) performanceProfile {
 	profileType := nb.Spec.PerformanceProfile
 	if profile, ok := performanceProfiles[profileType]; ok {
 		return profile
 	}
+	if customProfile != nil && customProfile.Name == string(profileType) {
+		return customProfileSettings(customProfile)
+	}
 	if profileType != "" {
 		logrus.Warnf("Unknown performanceProfile %q, falling back to %q", profileType, nbv1.PerformanceProfileDefault)
 	}
 	return performanceProfiles[nbv1.PerformanceProfileDefault]
 }
 
-func getCoreResources(nb *nbv1.NooBaa) corev1.ResourceRequirements {
+// LoadPerformanceProfile reads the user defined NooBaaPerformanceProfile of the system with c,
+// so it is read once and passed to the profile getters. It returns nil when the profile is built-in or unset.
+func LoadPerformanceProfile(ctx context.Context, c client.Client, nb *nbv1.NooBaa) (*nbv1.NooBaaPerformanceProfile, error) {
+	profileType := nb.Spec.PerformanceProfile
+	if _, ok := performanceProfiles[profileType]; ok || profileType == "" {
+		return nil, nil
+	}
+	customProfile := &nbv1.NooBaaPerformanceProfile{}
+	if err := c.Get(ctx, client.ObjectKey{Name: string(profileType)}, customProfile); err != nil {
+		return nil, err
+	}
+	return customProfile, nil
+}
+
+// customProfileSettings converts a user defined profile to profile settings,
+// taking the settings that are not set in the profile from the default profile.
+// The IBM Z CPU adjustment is not applied, since the resources are set explicitly by the user.
+func customProfileSettings(customProfile *nbv1.NooBaaPerformanceProfile) performanceProfile {
+	profile := performanceProfiles[nbv1.PerformanceProfileDefault]
+	spec := &customProfile.Spec
+	if spec.CoreResources != nil {
+		profile.coreResources = *spec.CoreResources
+	}
+	if spec.LogResources != nil {
+		profile.logResources = *spec.LogResources
+	}
+	if spec.DBResources != nil {
+		profile.dbResources = *spec.DBResources
+	}
+	if spec.EndpointResources != nil {
+		profile.endpointResources = *spec.EndpointResources
+	}
+	if spec.PVPoolResources != nil {
+		profile.pvPoolResources = *spec.PVPoolResources
+	}
+	if spec.EndpointMinCount > 0 {
+		profile.endpointMinCount = spec.EndpointMinCount
+	}
+	if spec.EndpointMaxCount > 0 {
+		profile.endpointMaxCount = spec.EndpointMaxCount
+	}
+	if spec.DBInstances > 0 {
+		profile.dbInstances = spec.DBInstances
+	}
+	if spec.PVPoolNumVolumes > 0 {
+		profile.pvPoolNumVolumes = spec.PVPoolNumVolumes
+	}
+	// a profile that only raises the min count should not end up with max < min
+	if profile.endpointMaxCount < profile.endpointMinCount {
+		profile.endpointMaxCount = profile.endpointMinCount
+	}
+	return profile
+}
+
+func getCoreResources(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) corev1.ResourceRequirements {
 	if nb.Spec.CoreResources != nil {
 		return *nb.Spec.CoreResources
 	}
-	return lookupProfile(nb).coreResources
+	return lookupProfile(nb, customProfile).coreResources
 }
 
-func getLogResources(nb *nbv1.NooBaa) corev1.ResourceRequirements {
+func getLogResources(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) corev1.ResourceRequirements {
 	if nb.Spec.LogResources != nil {
 		return *nb.Spec.LogResources
 	}
-	return lookupProfile(nb).logResources
+	return lookupProfile(nb, customProfile).logResources
 }
 
-func getDBResources(nb *nbv1.NooBaa) corev1.ResourceRequirements {
+func getDBResources(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) corev1.ResourceRequirements {
 	if nb.Spec.DBSpec != nil && nb.Spec.DBSpec.DBResources != nil {
 		return *nb.Spec.DBSpec.DBResources
 	}
-	return lookupProfile(nb).dbResources
+	return lookupProfile(nb, customProfile).dbResources
 }
 
-func getEndpointResources(nb *nbv1.NooBaa) corev1.ResourceRequirements {
+func getEndpointResources(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) corev1.ResourceRequirements {
 	if nb.Spec.Endpoints != nil && nb.Spec.Endpoints.Resources != nil {
 		return *nb.Spec.Endpoints.Resources
 	}
-	return lookupProfile(nb).endpointResources
+	return lookupProfile(nb, customProfile).endpointResources
 }
 
 // adjustCpuResourcesForIbmZ multiplies CPU requests by adjustFactor (limits are unchanged).
@@ -175,11 +236,11 @@ func adjustCpu(cpuQty resource.Quantity, adjustFactor float64) resource.Quantity
 	return *resource.NewMilliQuantity(int64(float64(cpuQty.MilliValue())*adjustFactor), resource.DecimalSI)
 }
 
-func getDBInstances(nb *nbv1.NooBaa) int {
+func getDBInstances(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) int {
 	if nb.Spec.DBSpec != nil && nb.Spec.DBSpec.Instances != nil {
 		return *nb.Spec.DBSpec.Instances
 	}
-	return lookupProfile(nb).dbInstances
+	return lookupProfile(nb, customProfile).dbInstances
 }
 
 // getPVPoolNumVolumes determines the NumVolumes for the default pv-pool backingstore.
@@ -187,8 +248,8 @@ func getDBInstances(nb *nbv1.NooBaa) int {
 // - New deployment (existingVolumes <= 0): use profile value
 // - Existing + "default" profile (or unset): keep current (no migration)
 // - Existing + non-default profile: max(current, profile value) — never decrease
-func getPVPoolNumVolumes(nb *nbv1.NooBaa, existingVolumes int) int {
-	profile := lookupProfile(nb)
+func getPVPoolNumVolumes(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile, existingVolumes int) int {
+	profile := lookupProfile(nb, customProfile)
 	if existingVolumes <= 0 {
 		return profile.pvPoolNumVolumes
 	}
@@ -202,8 +263,8 @@ func getPVPoolNumVolumes(nb *nbv1.NooBaa, existingVolumes int) int {
 	return existingVolumes
 }
 
-func getEndpointMinMax(nb *nbv1.NooBaa) (int32, int32) {
-	profile := lookupProfile(nb)
+func getEndpointMinMax(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) (int32, int32) {
+	profile := lookupProfile(nb, customProfile)
 	minCount := profile.endpointMinCount
 	maxCount := profile.endpointMaxCount
 	if nb.Spec.Endpoints != nil {
@@ -218,11 +279,11 @@ func getEndpointMinMax(nb *nbv1.NooBaa) (int32, int32) {
 }
 
 // GetPVPoolResources returns the default CPU/memory resources for PV pool
-// backingstore pods based on the performance profile.
+// backingstore pods based on the performance profile, see LoadPerformanceProfile.
 // In test env, returns minimal resources regardless of the profile.
-func GetPVPoolResources(nb *nbv1.NooBaa) corev1.ResourceRequirements {
+func GetPVPoolResources(nb *nbv1.NooBaa, customProfile *nbv1.NooBaaPerformanceProfile) corev1.ResourceRequirements {
 	if util.IsTestEnv() {
 		return profileResources("50m", "50m", "200Mi", "200Mi")
 	}
-	return lookupProfile(nb).pvPoolResources
+	return lookupProfile(nb, customProfile).pvPoolResources
 }
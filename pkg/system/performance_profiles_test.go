@@ -6,6 +6,7 @@ import (
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestLookupProfile(t *testing.T) {
@@ -48,7 +49,70 @@ func TestLookupProfile(t *testing.T) {
 					PerformanceProfile: tt.profile,
 				},
 			}
-			got := lookupProfile(nb)
+			got := lookupProfile(nb, nil)
+			assertPerformanceProfile(t, got, tt.expected)
+		})
+	}
+}
+
+func TestLookupCustomProfile(t *testing.T) {
+	largeCore := profileResources("4", "8", "16Gi", "16Gi")
+	customProfiles := map[string]*nbv1.NooBaaPerformanceProfile{
+		"large-nodes": {
+			ObjectMeta: metav1.ObjectMeta{Name: "large-nodes"},
+			Spec: nbv1.NooBaaPerformanceProfileSpec{
+				CoreResources:    &largeCore,
+				EndpointMinCount: 3,
+				EndpointMaxCount: 12,
+				DBInstances:      3,
+			},
+		},
+		"min-endpoints-only": {
+			ObjectMeta: metav1.ObjectMeta{Name: "min-endpoints-only"},
+			Spec:       nbv1.NooBaaPerformanceProfileSpec{EndpointMinCount: 5},
+		},
+	}
+
+	defaults := performanceProfiles[nbv1.PerformanceProfileDefault]
+	largeNodes := defaults
+	largeNodes.coreResources = largeCore
+	largeNodes.endpointMinCount = 3
+	largeNodes.endpointMaxCount = 12
+	largeNodes.dbInstances = 3
+	minEndpointsOnly := defaults
+	minEndpointsOnly.endpointMinCount = 5
+	minEndpointsOnly.endpointMaxCount = 5
+
+	tests := []struct {
+		name     string
+		profile  nbv1.PerformanceProfileType
+		expected performanceProfile
+	}{
+		{
+			name:     "custom profile overrides the set fields of the default profile",
+			profile:  "large-nodes",
+			expected: largeNodes,
+		},
+		{
+			name:     "custom profile with only min count raises the max count",
+			profile:  "min-endpoints-only",
+			expected: minEndpointsOnly,
+		},
+		{
+			name:     "missing custom profile falls back to default",
+			profile:  "missing",
+			expected: defaults,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nb := &nbv1.NooBaa{
+				Spec: nbv1.NooBaaSpec{
+					PerformanceProfile: tt.profile,
+				},
+			}
+			got := lookupProfile(nb, customProfiles[string(tt.profile)])
 			assertPerformanceProfile(t, got, tt.expected)
 		})
 	}
@@ -103,7 +167,7 @@ func TestGetCoreResources(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getCoreResources(tt.nb)
+			got := getCoreResources(tt.nb, nil)
 			assertResourceRequirements(t, got, tt.expected)
 		})
 	}
@@ -149,7 +213,7 @@ func TestGetLogResources(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getLogResources(tt.nb)
+			got := getLogResources(tt.nb, nil)
 			assertResourceRequirements(t, got, tt.expected)
 		})
 	}
@@ -216,7 +280,7 @@ func TestGetDBResources(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getDBResources(tt.nb)
+			got := getDBResources(tt.nb, nil)
 			assertResourceRequirements(t, got, tt.expected)
 		})
 	}
@@ -273,7 +337,7 @@ func TestGetEndpointResources(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getEndpointResources(tt.nb)
+			got := getEndpointResources(tt.nb, nil)
 			assertResourceRequirements(t, got, tt.expected)
 		})
 	}
@@ -316,7 +380,7 @@ func TestGetPVPoolResources(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GetPVPoolResources(tt.nb)
+			got := GetPVPoolResources(tt.nb, nil)
 			assertResourceRequirements(t, got, tt.expected)
 		})
 	}
@@ -364,7 +428,7 @@ func TestGetDBInstances(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getDBInstances(tt.nb)
+			got := getDBInstances(tt.nb, nil)
 			if got != tt.expected {
 				t.Errorf("getDBInstances() = %d, want %d", got, tt.expected)
 			}
@@ -424,7 +488,7 @@ func TestGetPVPoolNumVolumes(t *testing.T) {
 					PerformanceProfile: tt.profile,
 				},
 			}
-			got := getPVPoolNumVolumes(nb, tt.existingVolumes)
+			got := getPVPoolNumVolumes(nb, nil, tt.existingVolumes)
 			if got != tt.expected {
 				t.Errorf("getPVPoolNumVolumes() = %d, want %d", got, tt.expected)
 			}
@@ -515,7 +579,7 @@ func TestGetEndpointMinMax(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotMin, gotMax := getEndpointMinMax(tt.nb)
+			gotMin, gotMax := getEndpointMinMax(tt.nb, nil)
 			if gotMin != tt.expectedMin || gotMax != tt.expectedMax {
 				t.Errorf("getEndpointMinMax() = (%d, %d), want (%d, %d)", gotMin, gotMax, tt.expectedMin, tt.expectedMax)
 			}
@@ -541,9 +605,9 @@ func TestGetResourcesPrecedencePerComponent(t *testing.T) {
 		},
 	}
 
-	assertResourceRequirements(t, getCoreResources(nb), explicitCore)
-	assertResourceRequirements(t, getDBResources(nb), explicitDB)
-	assertResourceRequirements(t, getEndpointResources(nb), explicitEndpoint)
+	assertResourceRequirements(t, getCoreResources(nb, nil), explicitCore)
+	assertResourceRequirements(t, getDBResources(nb, nil), explicitDB)
+	assertResourceRequirements(t, getEndpointResources(nb, nil), explicitEndpoint)
 }
 
 func assertPerformanceProfile(t *testing.T, got, want performanceProfile) {
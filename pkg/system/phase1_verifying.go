@@ -9,6 +9,7 @@ import (
 	_ "github.com/lib/pq"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/asaskevich/govalidator"
@@ -155,10 +156,14 @@ func (r *Reconciler) CheckSystemCR() error {
 	// Set ActualImage to be updated in the noobaa status
 	r.NooBaa.Status.ActualImage = specImage
 
+	if err := r.CheckPerformanceProfile(); err != nil {
+		return err
+	}
+
 	// Verify the endpoints spec
 	endpointsSpec := r.NooBaa.Spec.Endpoints
 	if endpointsSpec != nil {
-		minCount, maxCount := getEndpointMinMax(r.NooBaa)
+		minCount, maxCount := getEndpointMinMax(r.NooBaa, r.PerformanceProfile)
 		if minCount <= 0 {
 			return util.NewPersistentError("InvalidEndpointsConfiguration",
 				"Invalid endpoint min count (must be greater than 0)")
@@ -190,6 +195,29 @@ func (r *Reconciler) CheckSystemCR() error {
 			fmt.Sprintf("Autoscaler %s missing prometheusNamespace property ", r.NooBaa.Spec.Autoscaler.AutoscalerType))
 	}
 
+	for _, metric := range r.NooBaa.Spec.Autoscaler.Metrics {
+		if _, err := ParseAutoscalerMetricTarget(metric); err != nil {
+			return util.NewPersistentError("InvalidEndpointsAutoscalerConfiguration", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// CheckPerformanceProfile checks that a performance profile which is not built-in
+// refers to an existing NooBaaPerformanceProfile, so that the system is not silently
+// reconciled with the default profile resources. The profile is kept for the rest of the reconcile.
+func (r *Reconciler) CheckPerformanceProfile() error {
+	profileType := r.NooBaa.Spec.PerformanceProfile
+	customProfile, err := LoadPerformanceProfile(r.Ctx, r.Client, r.NooBaa)
+	if errors.IsNotFound(err) {
+		return util.NewPersistentError("PerformanceProfileNotFound",
+			fmt.Sprintf("NooBaaPerformanceProfile %q not found", profileType))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get NooBaaPerformanceProfile %q: %v", profileType, err)
+	}
+	r.PerformanceProfile = customProfile
 	return nil
 }
 
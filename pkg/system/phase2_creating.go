@@ -14,6 +14,7 @@ import (
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
 	"github.com/noobaa/noobaa-operator/v5/pkg/leaderelect"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util/kms"
@@ -743,7 +744,7 @@ func (r *Reconciler) SetDesiredCoreApp() error {
 			util.ReflectEnvVariable(&c.Env, "HTTPS_PROXY")
 			util.ReflectEnvVariable(&c.Env, "NO_PROXY")
 
-			c.Resources = getCoreResources(r.NooBaa)
+			c.Resources = getCoreResources(r.NooBaa, r.PerformanceProfile)
 
 			if r.shouldReconcileCNPGCluster() {
 				dbSecretVolumeMounts := []corev1.VolumeMount{{
@@ -829,7 +830,7 @@ func (r *Reconciler) SetDesiredCoreApp() error {
 				c.Image = r.NooBaa.Status.ActualImage
 			}
 
-			c.Resources = getLogResources(r.NooBaa)
+			c.Resources = getLogResources(r.NooBaa, r.PerformanceProfile)
 			// we want to check that the cm exists and also that it has data in it
 			if util.KubeCheckQuiet(r.CaBundleConf) && len(r.CaBundleConf.Data) > 0 {
 				configMapVolumeMounts := []corev1.VolumeMount{{
@@ -1302,6 +1303,7 @@ func (r *Reconciler) reportKMSConditionStatus() {
 	cond := conditionsv1.FindStatusCondition(*conditions, nbv1.ConditionTypeKMSStatus)
 	if cond == nil {
 		util.Logger().Printf("❌ Missing KMS status: %q\n", nbv1.ConditionTypeKMSStatus)
+		metrics.DeleteKMSStatus(r.NooBaa.Namespace, r.NooBaa.Name)
 		return
 	}
 
@@ -1313,6 +1315,7 @@ func (r *Reconciler) reportKMSConditionStatus() {
 		kmsStatusBadge = "❌"
 	}
 	util.Logger().Printf("%v KMS status: %q\n", kmsStatusBadge, st)
+	metrics.SetKMSStatus(r.NooBaa.Namespace, r.NooBaa.Name, string(st))
 }
 
 func (r *Reconciler) setKMSConditionType(t string) {
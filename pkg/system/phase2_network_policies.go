@@ -1,23 +1,95 @@
 package system
 
-// ReconcileNetworkPolicies reconciles network policies for NooBaa operands.
-// Operator and CNPG controller policies are Phase 2 scope (ODF 5.1).
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util/kms"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// lookupIP resolves the hostnames of the egress allow-list, replaced in tests
+var lookupIP = net.LookupIP
+
+// egressHostsResolveInterval is how often the system is reconciled to resolve the egress hostnames again,
+// since the addresses of cloud endpoints rotate and the network policies only allow the resolved addresses
+const egressHostsResolveInterval = 2 * time.Minute
+
+// kmsAddressKeys are the KMS connection details that hold the address of the KMS server
+var kmsAddressKeys = []string{
+	kms.VaultAddr,
+	kms.AWSKMSEndpoint,
+	kms.AzureVaultURL,
+	kms.IbmBaseURLKey,
+	kms.IbmTokenURLKey,
+	kms.KMIPEndpoint,
+}
+
+// networkPolicyEgress holds the egress rules of the network policies when egress is denied by default.
+// The rules are computed once per reconcile since they require listing the stores and resolving hostnames.
+type networkPolicyEgress struct {
+	// internal rules allow DNS, the kubernetes API server and the pods of the namespace
+	internal []networkingv1.NetworkPolicyEgressRule
+	// external rules also allow the stores, KMS, external postgres and the allow-list
+	external []networkingv1.NetworkPolicyEgressRule
+	// db rules also allow the DB backup object store and the allow-list
+	db []networkingv1.NetworkPolicyEgressRule
+}
+
+// ReconcileNetworkPolicies reconciles network policies for the NooBaa operands,
+// the operator and the CNPG controller.
 func (r *Reconciler) ReconcileNetworkPolicies() error {
 
-	if err := r.ReconcileObject(r.NetworkPolicyCore, r.SetDesiredNetworkPolicyCore); err != nil {
+	egress, err := r.getNetworkPolicyEgress()
+	if err != nil {
 		return err
 	}
 
-	if err := r.ReconcileObject(r.NetworkPolicyEndpoint, r.SetDesiredNetworkPolicyEndpoint); err != nil {
+	if err := r.ReconcileObject(r.NetworkPolicyCore, func() error {
+		return r.SetDesiredNetworkPolicyCore(egress)
+	}); err != nil {
 		return err
 	}
 
-	if err := r.ReconcileObject(r.NetworkPolicyPVPool, nil); err != nil {
+	if err := r.ReconcileObject(r.NetworkPolicyEndpoint, func() error {
+		return r.SetDesiredNetworkPolicyEndpoint(egress)
+	}); err != nil {
+		return err
+	}
+
+	if err := r.ReconcileObject(r.NetworkPolicyPVPool, func() error {
+		return r.SetDesiredNetworkPolicyPVPool(egress)
+	}); err != nil {
+		return err
+	}
+
+	if err := r.ReconcileObject(r.NetworkPolicyOperator, func() error {
+		return r.SetDesiredNetworkPolicyOperator(egress)
+	}); err != nil {
 		return err
 	}
 
 	if r.shouldReconcileCNPGCluster() {
-		if err := r.ReconcileObject(r.NetworkPolicyDb, r.SetDesiredNetworkPolicyDb); err != nil {
+		if err := r.ReconcileObject(r.NetworkPolicyDb, func() error {
+			return r.SetDesiredNetworkPolicyDb(egress)
+		}); err != nil {
+			return err
+		}
+		if err := r.ReconcileObject(r.NetworkPolicyCNPG, func() error {
+			return r.SetDesiredNetworkPolicyCNPG(egress)
+		}); err != nil {
 			return err
 		}
 	}
@@ -30,21 +102,390 @@ func (r *Reconciler) ReconcileNetworkPolicies() error {
 // The cross-namespace rule allows TCP 8443 (mgmt-https) from any namespace because:
 // - Prometheus/monitoring scrapes metrics on this port (namespace varies by platform)
 // - Ingress controllers route external RouteMgmt traffic to this port
-func (r *Reconciler) SetDesiredNetworkPolicyCore() error {
+func (r *Reconciler) SetDesiredNetworkPolicyCore(egress *networkPolicyEgress) error {
 	r.NetworkPolicyCore.Spec.PodSelector.MatchLabels["noobaa-core"] = r.Request.Name
+	r.setDesiredNetworkPolicyRules(r.NetworkPolicyCore, bundle.File_deploy_internal_networkpolicy_core_yaml,
+		nbv1.NetworkPolicyComponentCore, egress.getExternal())
 	return nil
 }
 
 // SetDesiredNetworkPolicyEndpoint updates the endpoint network policy pod selector
 // to match the actual NooBaa system name
-func (r *Reconciler) SetDesiredNetworkPolicyEndpoint() error {
+func (r *Reconciler) SetDesiredNetworkPolicyEndpoint(egress *networkPolicyEgress) error {
 	r.NetworkPolicyEndpoint.Spec.PodSelector.MatchLabels["noobaa-s3"] = r.Request.Name
+	r.setDesiredNetworkPolicyRules(r.NetworkPolicyEndpoint, bundle.File_deploy_internal_networkpolicy_endpoint_yaml,
+		nbv1.NetworkPolicyComponentEndpoint, egress.getExternal())
+	return nil
+}
+
+// SetDesiredNetworkPolicyPVPool updates the pv-pool network policy,
+// the pv-pool pods only connect to the pods of the namespace
+func (r *Reconciler) SetDesiredNetworkPolicyPVPool(egress *networkPolicyEgress) error {
+	r.setDesiredNetworkPolicyRules(r.NetworkPolicyPVPool, bundle.File_deploy_internal_networkpolicy_pvpool_yaml,
+		"", egress.getInternal())
 	return nil
 }
 
 // SetDesiredNetworkPolicyDb updates the DB network policy pod selector
 // to match the actual CNPG cluster name
-func (r *Reconciler) SetDesiredNetworkPolicyDb() error {
+func (r *Reconciler) SetDesiredNetworkPolicyDb(egress *networkPolicyEgress) error {
 	r.NetworkPolicyDb.Spec.PodSelector.MatchLabels["cnpg.io/cluster"] = r.CNPGCluster.Name
+	r.setDesiredNetworkPolicyRules(r.NetworkPolicyDb, bundle.File_deploy_internal_networkpolicy_db_yaml,
+		nbv1.NetworkPolicyComponentDB, egress.getDB())
+	return nil
+}
+
+// SetDesiredNetworkPolicyOperator updates the operator network policy.
+// The operator checks the connectivity to the stores, KMS and external postgres,
+// so it gets the same egress rules as the core and endpoint pods
+func (r *Reconciler) SetDesiredNetworkPolicyOperator(egress *networkPolicyEgress) error {
+	r.setDesiredNetworkPolicyRules(r.NetworkPolicyOperator, bundle.File_deploy_internal_networkpolicy_operator_yaml,
+		"", egress.getExternal())
 	return nil
 }
+
+// SetDesiredNetworkPolicyCNPG updates the CNPG controller network policy,
+// the controller only connects to the API server and the DB pods
+func (r *Reconciler) SetDesiredNetworkPolicyCNPG(egress *networkPolicyEgress) error {
+	r.setDesiredNetworkPolicyRules(r.NetworkPolicyCNPG, bundle.File_deploy_internal_networkpolicy_cnpg_controller_yaml,
+		"", egress.getInternal())
+	return nil
+}
+
+// setDesiredNetworkPolicyRules resets the rules of the network policy to the bundled rules,
+// adds the additional ingress rules of the component, and denies the egress that is not allowed by the egress rules.
+// A nil list of egress rules keeps the egress unrestricted.
+func (r *Reconciler) setDesiredNetworkPolicyRules(
+	np *networkingv1.NetworkPolicy,
+	bundled string,
+	component nbv1.NetworkPolicyComponent,
+	egress []networkingv1.NetworkPolicyEgressRule,
+) {
+	desired := util.KubeObject(bundled).(*networkingv1.NetworkPolicy)
+	np.Spec.PolicyTypes = desired.Spec.PolicyTypes
+	np.Spec.Ingress = desired.Spec.Ingress
+	np.Spec.Egress = nil
+
+	if spec := r.NooBaa.Spec.NetworkPolicy; spec != nil && component != "" {
+		np.Spec.Ingress = append(np.Spec.Ingress, getAdditionalIngressRules(spec.AdditionalIngress, component)...)
+	}
+
+	if egress != nil {
+		np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		np.Spec.Egress = egress
+	}
+}
+
+// getAdditionalIngressRules returns the ingress rules of the NooBaa CR that apply to the component
+func getAdditionalIngressRules(rules []nbv1.NetworkPolicyIngressRule, component nbv1.NetworkPolicyComponent) []networkingv1.NetworkPolicyIngressRule {
+	var ingress []networkingv1.NetworkPolicyIngressRule
+	for _, rule := range rules {
+		if rule.Component != component {
+			continue
+		}
+		var from []networkingv1.NetworkPolicyPeer
+		for _, namespace := range rule.Namespaces {
+			from = append(from, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: namespace},
+				},
+			})
+		}
+		if len(from) == 0 {
+			from = []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}
+		}
+		ports := make([]networkingv1.NetworkPolicyPort, len(rule.Ports))
+		for i := range rule.Ports {
+			rule.Ports[i].DeepCopyInto(&ports[i])
+		}
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{From: from, Ports: ports})
+	}
+	return ingress
+}
+
+// getNetworkPolicyEgress returns the egress rules of the network policies,
+// or nil when the egress of the NooBaa pods is not denied by default
+func (r *Reconciler) getNetworkPolicyEgress() (*networkPolicyEgress, error) {
+	spec := r.NooBaa.Spec.NetworkPolicy
+	if spec == nil || spec.Egress == nil || !spec.Egress.DenyByDefault {
+		return nil, nil
+	}
+
+	apiServerPeers, apiServerPorts, err := getAPIServerEgressTargets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the kubernetes API server addresses: %v", err)
+	}
+	internal := getInternalEgressRules(apiServerPeers, apiServerPorts)
+
+	allowedPeers := make([]networkingv1.NetworkPolicyPeer, 0, len(spec.Egress.AllowedCIDRs))
+	for _, cidr := range spec.Egress.AllowedCIDRs {
+		allowedPeers = append(allowedPeers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+
+	externalHosts, err := r.getStoresEgressHosts()
+	if err != nil {
+		return nil, err
+	}
+	externalHosts = append(externalHosts, r.getKMSEgressHosts()...)
+	if r.ExternalPgSecret != nil {
+		externalHosts = append(externalHosts, egressHost(r.ExternalPgSecret.StringData["db_url"]))
+	}
+	externalHosts = append(externalHosts, spec.Egress.AllowedHosts...)
+
+	dbHosts := append([]string{}, spec.Egress.AllowedHosts...)
+	if r.NooBaa.Spec.DBSpec != nil && r.NooBaa.Spec.DBSpec.DBBackup != nil && r.NooBaa.Spec.DBSpec.DBBackup.ObjectStore != nil {
+		endpoint := r.NooBaa.Spec.DBSpec.DBBackup.ObjectStore.Endpoint
+		if endpoint == "" {
+			endpoint = "s3.amazonaws.com"
+		}
+		dbHosts = append(dbHosts, egressHost(endpoint))
+	}
+
+	return &networkPolicyEgress{
+		internal: internal,
+		external: appendEgressPeersRule(internal, append(allowedPeers, r.resolveEgressHosts(externalHosts)...)),
+		db:       appendEgressPeersRule(internal, append(allowedPeers, r.resolveEgressHosts(dbHosts)...)),
+	}, nil
+}
+
+func (egress *networkPolicyEgress) getInternal() []networkingv1.NetworkPolicyEgressRule {
+	if egress == nil {
+		return nil
+	}
+	return egress.internal
+}
+
+func (egress *networkPolicyEgress) getExternal() []networkingv1.NetworkPolicyEgressRule {
+	if egress == nil {
+		return nil
+	}
+	return egress.external
+}
+
+func (egress *networkPolicyEgress) getDB() []networkingv1.NetworkPolicyEgressRule {
+	if egress == nil {
+		return nil
+	}
+	return egress.db
+}
+
+// getInternalEgressRules returns the egress rules that every NooBaa pod requires:
+// DNS in any namespace (5353 is the DNS pods port on OpenShift), the kubernetes API server and the pods of the namespace
+func getInternalEgressRules(apiServerPeers []networkingv1.NetworkPolicyPeer, apiServerPorts []networkingv1.NetworkPolicyPort) []networkingv1.NetworkPolicyEgressRule {
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	var dnsPorts []networkingv1.NetworkPolicyPort
+	for _, port := range []int32{53, 5353} {
+		dnsPort := intstr.FromInt32(port)
+		dnsPorts = append(dnsPorts,
+			networkingv1.NetworkPolicyPort{Protocol: &udp, Port: &dnsPort},
+			networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &dnsPort},
+		)
+	}
+	return []networkingv1.NetworkPolicyEgressRule{{
+		To:    []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}},
+		Ports: dnsPorts,
+	}, {
+		To:    apiServerPeers,
+		Ports: apiServerPorts,
+	}, {
+		To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+	}}
+}
+
+// appendEgressPeersRule returns the rules with an additional rule that allows all the ports of the peers.
+// An egress rule without peers allows all destinations, so no rule is added when there are no peers.
+func appendEgressPeersRule(rules []networkingv1.NetworkPolicyEgressRule, peers []networkingv1.NetworkPolicyPeer) []networkingv1.NetworkPolicyEgressRule {
+	result := append([]networkingv1.NetworkPolicyEgressRule{}, rules...)
+	if len(peers) > 0 {
+		result = append(result, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+	return result
+}
+
+// getAPIServerEgressTargets returns the addresses and ports of the kubernetes API server.
+// The network policies apply to the traffic after the service address is translated to the API server address,
+// so the addresses are read from the endpoint slices of the default/kubernetes service.
+func getAPIServerEgressTargets() ([]networkingv1.NetworkPolicyPeer, []networkingv1.NetworkPolicyPort, error) {
+	sliceList := &discoveryv1.EndpointSliceList{}
+	if err := util.KubeClient().List(util.Context(), sliceList,
+		client.InNamespace(metav1.NamespaceDefault),
+		client.MatchingLabels{discoveryv1.LabelServiceName: "kubernetes"},
+	); err != nil {
+		return nil, nil, err
+	}
+	ips := map[string]bool{}
+	ports := map[int32]bool{}
+	for _, slice := range sliceList.Items {
+		for _, endpoint := range slice.Endpoints {
+			for _, address := range endpoint.Addresses {
+				ips[address] = true
+			}
+		}
+		for _, port := range slice.Ports {
+			if port.Port != nil {
+				ports[*port.Port] = true
+			}
+		}
+	}
+	if len(ips) == 0 || len(ports) == 0 {
+		return nil, nil, fmt.Errorf("no endpoints found for the kubernetes service")
+	}
+	tcp := corev1.ProtocolTCP
+	var policyPorts []networkingv1.NetworkPolicyPort
+	for _, port := range sortedKeys(ports) {
+		policyPort := intstr.FromInt32(port)
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &policyPort})
+	}
+	return ipBlockPeers(ips), policyPorts, nil
+}
+
+// getStoresEgressHosts returns the hostnames of the cloud targets of the backingstores and namespacestores
+func (r *Reconciler) getStoresEgressHosts() ([]string, error) {
+	bsList := &nbv1.BackingStoreList{}
+	if err := r.Client.List(r.Ctx, bsList, client.InNamespace(r.Request.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list backingstores: %v", err)
+	}
+	nsList := &nbv1.NamespaceStoreList{}
+	if err := r.Client.List(r.Ctx, nsList, client.InNamespace(r.Request.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list namespacestores: %v", err)
+	}
+
+	var hosts []string
+	for i := range bsList.Items {
+		spec := &bsList.Items[i].Spec
+		hosts = append(hosts, r.getStoreEgressHosts(spec.AWSS3, spec.S3Compatible, spec.IBMCos, spec.AzureBlob, spec.GoogleCloudStorage)...)
+	}
+	for i := range nsList.Items {
+		spec := &nsList.Items[i].Spec
+		hosts = append(hosts, r.getStoreEgressHosts(spec.AWSS3, spec.S3Compatible, spec.IBMCos, spec.AzureBlob, spec.GoogleCloudStorage)...)
+	}
+	return hosts, nil
+}
+
+// getStoreEgressHosts returns the hostnames that a store of any cloud type connects to
+func (r *Reconciler) getStoreEgressHosts(
+	awsS3 *nbv1.AWSS3Spec,
+	s3Compatible *nbv1.S3CompatibleSpec,
+	ibmCos *nbv1.IBMCosSpec,
+	azureBlob *nbv1.AzureBlobSpec,
+	googleCloudStorage *nbv1.GoogleCloudStorageSpec,
+) []string {
+	switch {
+	case awsS3 != nil:
+		if awsS3.Region == "" {
+			hosts := []string{"s3.amazonaws.com"}
+			if awsS3.AWSSTSRoleARN != nil {
+				hosts = append(hosts, "sts.amazonaws.com")
+			}
+			return hosts
+		}
+		hosts := []string{fmt.Sprintf("s3.%s.amazonaws.com", awsS3.Region)}
+		if awsS3.AWSSTSRoleARN != nil {
+			hosts = append(hosts, "sts.amazonaws.com", fmt.Sprintf("sts.%s.amazonaws.com", awsS3.Region))
+		}
+		return hosts
+	case s3Compatible != nil:
+		return []string{egressHost(s3Compatible.Endpoint)}
+	case ibmCos != nil:
+		return []string{egressHost(ibmCos.Endpoint)}
+	case azureBlob != nil:
+		hosts := []string{}
+		if azureBlob.ClientId != nil {
+			hosts = append(hosts, "login.microsoftonline.com", "management.azure.com")
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      azureBlob.Secret.Name,
+			Namespace: azureBlob.Secret.Namespace,
+		}}
+		if secret.Namespace == "" {
+			secret.Namespace = r.Request.Namespace
+		}
+		if secret.Name != "" && util.KubeCheckQuiet(secret) && secret.StringData["AccountName"] != "" {
+			hosts = append(hosts, secret.StringData["AccountName"]+".blob.core.windows.net")
+		}
+		return hosts
+	case googleCloudStorage != nil:
+		return []string{"storage.googleapis.com", "oauth2.googleapis.com", "sts.googleapis.com"}
+	}
+	return nil
+}
+
+// getKMSEgressHosts returns the hostnames of the KMS server
+func (r *Reconciler) getKMSEgressHosts() []string {
+	var hosts []string
+	details := r.NooBaa.Spec.Security.KeyManagementService.ConnectionDetails
+	for _, key := range kmsAddressKeys {
+		if address := details[key]; address != "" {
+			hosts = append(hosts, egressHost(address))
+		}
+	}
+	return hosts
+}
+
+// resolveEgressHosts resolves the hostnames to IP block peers.
+// A hostname that cannot be resolved is skipped and will be retried on the next reconcile.
+// When any hostname is resolved the system is requeued to refresh the addresses every egressHostsResolveInterval.
+func (r *Reconciler) resolveEgressHosts(hosts []string) []networkingv1.NetworkPolicyPeer {
+	ips := map[string]bool{}
+	for _, host := range hosts {
+		if host == "" {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			ips[ip.String()] = true
+			continue
+		}
+		r.requeueWithin(egressHostsResolveInterval)
+		resolved, err := lookupIP(host)
+		if err != nil {
+			r.Logger.Warnf("failed to resolve the egress host %q, it is not allowed in the network policies: %v", host, err)
+			continue
+		}
+		for _, ip := range resolved {
+			ips[ip.String()] = true
+		}
+	}
+	return ipBlockPeers(ips)
+}
+
+// egressHost returns the hostname of an address of the form scheme://host:port/path, host:port or host
+func egressHost(address string) string {
+	if strings.Contains(address, "://") {
+		u, err := url.Parse(address)
+		if err != nil {
+			return ""
+		}
+		return u.Hostname()
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+// ipBlockPeers returns a sorted list of single address IP block peers
+func ipBlockPeers(ips map[string]bool) []networkingv1.NetworkPolicyPeer {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, address := range sortedKeys(ips) {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+		cidr := ip.String() + "/128"
+		if ip.To4() != nil {
+			cidr = ip.String() + "/32"
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	return peers
+}
+
+func sortedKeys[K string | int32](m map[K]bool) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
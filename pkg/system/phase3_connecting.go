@@ -174,6 +174,8 @@ func (r *Reconciler) InitNBClient() error {
 			Address: addr,
 		})
 	}
+	// bind the calls of the reconcile to its context
+	r.NBClient = r.NBClient.WithContext(r.Ctx)
 
 	// Check that the server is indeed serving the API already
 	// we use the read_auth call here because it's an API that always answers
@@ -79,6 +79,12 @@ func (r *Reconciler) ReconcilePhaseConfiguring() error {
 		return err
 	}
 	util.KubeCreateOptional(util.KubeObject(bundle.File_deploy_scc_endpoint_yaml).(*secv1.SecurityContextConstraints))
+	// a provider that cannot be resolved should not block the endpoints, the previous configuration is kept
+	if err := r.ReconcileOIDCConfig(); err != nil {
+		r.Logger.Warnf("ReconcileOIDCConfig: %v (will retry next cycle)", err)
+		r.Recorder.Eventf(r.NooBaa, nil, corev1.EventTypeWarning, "OIDCConfigFailed", "OIDCConfigFailed",
+			"Failed to configure the OIDC providers: %v", err)
+	}
 	if err := r.ReconcileObject(r.DeploymentEndpoint, r.SetDesiredDeploymentEndpoint); err != nil {
 		return err
 	}
@@ -110,6 +116,9 @@ func (r *Reconciler) ReconcilePhaseConfiguring() error {
 	if err := r.ReconcileServiceMonitors(); err != nil {
 		return err
 	}
+	if err := r.ReconcileOperatorServiceMonitor(); err != nil {
+		return err
+	}
 	if err := r.ReconcileReadSystem(); err != nil {
 		return err
 	}
@@ -357,7 +366,7 @@ func (r *Reconciler) SetDesiredDeploymentEndpoint() error {
 		switch c.Name {
 		case "endpoint":
 			c.Image = r.NooBaa.Status.ActualImage
-			c.Resources = getEndpointResources(r.NooBaa)
+			c.Resources = getEndpointResources(r.NooBaa, r.PerformanceProfile)
 			mgmtBaseAddr := ""
 			s3BaseAddr := ""
 			syslogBaseAddr := ""
@@ -766,6 +775,28 @@ func (r *Reconciler) setDesiredEndpointMounts(podSpec *corev1.PodSpec, container
 		util.MergeVolumeMountList(&container.VolumeMounts, &oidcConfigVolumeMounts)
 	}
 
+	// Mount the configuration of the declarative OIDC providers
+	if isOIDCConfigEnabled(r.NooBaa) {
+		optionalTrue := true
+		oidcProvidersVolumes := []corev1.Volume{{
+			Name: r.SecretOIDCConfig.Name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: r.SecretOIDCConfig.Name,
+					Optional:   &optionalTrue,
+				},
+			},
+		}}
+		util.MergeVolumeList(&podSpec.Volumes, &oidcProvidersVolumes)
+
+		oidcProvidersVolumeMounts := []corev1.VolumeMount{{
+			Name:      r.SecretOIDCConfig.Name,
+			MountPath: oidcConfigMountPath,
+			ReadOnly:  true,
+		}}
+		util.MergeVolumeMountList(&container.VolumeMounts, &oidcProvidersVolumeMounts)
+	}
+
 	return nil
 }
 
@@ -1037,7 +1068,7 @@ func (r *Reconciler) preparePVPoolBackingStore() error {
 	}
 	r.DefaultBackingStore.Spec.Type = nbv1.StoreTypePVPool
 	r.DefaultBackingStore.Spec.PVPool = &nbv1.PVPoolSpec{}
-	r.DefaultBackingStore.Spec.PVPool.NumVolumes = getPVPoolNumVolumes(r.NooBaa, existingVolumes)
+	r.DefaultBackingStore.Spec.PVPool.NumVolumes = getPVPoolNumVolumes(r.NooBaa, r.PerformanceProfile, existingVolumes)
 	r.DefaultBackingStore.Spec.PVPool.VolumeResources = &corev1.VolumeResourceRequirements{
 		Requests: corev1.ResourceList{
 			corev1.ResourceStorage: *resource.NewQuantity(defaultPVSize, resource.BinarySI),
@@ -1763,6 +1794,18 @@ func (r *Reconciler) ReconcileServiceMonitors() error {
 	return nil
 }
 
+// ReconcileOperatorServiceMonitor publishes the operator metrics (CR phases, reconciles, KMS status
+// and DB backup age) with a service in front of the operator pod and a service monitor scraping it.
+// Unlike the core service monitors it is not skipped when joining another NooBaa,
+// since the operator of this cluster has its own metrics.
+func (r *Reconciler) ReconcileOperatorServiceMonitor() error {
+	if err := r.ReconcileObject(r.ServiceOperatorMetrics, nil); err != nil {
+		return err
+	}
+	r.ApplyMonitoringLabels(r.ServiceMonitorOperator)
+	return r.ReconcileObjectOptional(r.ServiceMonitorOperator, nil)
+}
+
 // setDesiredServiceMonitorMgmt set authorization and TLS config for management ServiceMonitor
 func (r *Reconciler) setDesiredServiceMonitorMgmt() error {
 	r.setServiceMonitorEndpointsToHTTPS(r.ServiceMonitorMgmt.Spec.Endpoints, "mgmt-https")
@@ -12,6 +12,7 @@ import (
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/bundle"
 	"github.com/noobaa/noobaa-operator/v5/pkg/cnpg"
+	"github.com/noobaa/noobaa-operator/v5/pkg/metrics"
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
@@ -71,6 +72,9 @@ type Reconciler struct {
 	OAuthEndpoints           *util.OAuth2Endpoints
 	PostgresConnectionString string
 	ApplyCAsToPods           string // the path that will be applied to the core and endpoint pods in NODE_EXTRA_CA_CERTS
+	// PerformanceProfile is the NooBaaPerformanceProfile of the system loaded by CheckPerformanceProfile,
+	// nil when the performance profile is built-in
+	PerformanceProfile *nbv1.NooBaaPerformanceProfile
 
 	NooBaa                    *nbv1.NooBaa
 	ServiceAccount            *corev1.ServiceAccount
@@ -88,6 +92,7 @@ type Reconciler struct {
 	ServiceDbPg               *corev1.Service
 	ServiceSyslog             *corev1.Service
 	ServiceVectors            *corev1.Service
+	ServiceOperatorMetrics    *corev1.Service
 	SecretServer              *corev1.Secret
 	SecretDB                  *corev1.Secret
 	SecretOp                  *corev1.Secret
@@ -116,6 +121,7 @@ type Reconciler struct {
 	PrometheusRule            *monitoringv1.PrometheusRule
 	ServiceMonitorMgmt        *monitoringv1.ServiceMonitor
 	ServiceMonitorS3          *monitoringv1.ServiceMonitor
+	ServiceMonitorOperator    *monitoringv1.ServiceMonitor
 	SystemInfo                *nb.SystemInfo
 	CephObjectStoreUser       *cephv1.CephObjectStoreUser
 	CephObjectStore           *cephv1.CephObjectStore
@@ -136,17 +142,23 @@ type Reconciler struct {
 	BucketNotificationsPVC    *corev1.PersistentVolumeClaim
 	SecretMetricsAuth         *corev1.Secret
 	SecretOIDCKeyCloakConfig  *corev1.Secret
+	SecretOIDCConfig          *corev1.Secret
 	webIdentityTokenPath      string
 
 	// CNPG resources
 	CNPGImageCatalog *cnpgv1.ImageCatalog
 	CNPGCluster      *cnpgv1.Cluster
 
-	// Network Policies (Phase 1 - operands only)
+	// Network Policies of the operands, the operator and the CNPG controller
 	NetworkPolicyCore     *networkingv1.NetworkPolicy
 	NetworkPolicyDb       *networkingv1.NetworkPolicy
 	NetworkPolicyEndpoint *networkingv1.NetworkPolicy
 	NetworkPolicyPVPool   *networkingv1.NetworkPolicy
+	NetworkPolicyOperator *networkingv1.NetworkPolicy
+	NetworkPolicyCNPG     *networkingv1.NetworkPolicy
+	// RequeueAfter is the shortest period after which a ready system is reconciled again
+	// to refresh external state, like resolved egress hostnames and OIDC keys. Zero means no periodic requeue
+	RequeueAfter time.Duration
 }
 
 // NewReconciler initializes a reconciler to be used for loading or reconciling a noobaa system
@@ -181,6 +193,7 @@ func NewReconciler(
 		ServiceIam:                util.KubeObject(bundle.File_deploy_internal_service_iam_yaml).(*corev1.Service),
 		ServiceSyslog:             util.KubeObject(bundle.File_deploy_internal_service_syslog_yaml).(*corev1.Service),
 		ServiceVectors:            util.KubeObject(bundle.File_deploy_internal_service_vectors_yaml).(*corev1.Service),
+		ServiceOperatorMetrics:    util.KubeObject(bundle.File_deploy_internal_service_operator_metrics_yaml).(*corev1.Service),
 		SecretServer:              util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml).(*corev1.Secret),
 		SecretDB:                  util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml).(*corev1.Secret),
 		SecretOp:                  util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml).(*corev1.Secret),
@@ -203,6 +216,7 @@ func NewReconciler(
 		PrometheusRule:            util.KubeObject(bundle.File_deploy_internal_prometheus_rules_yaml).(*monitoringv1.PrometheusRule),
 		ServiceMonitorMgmt:        util.KubeObject(bundle.File_deploy_internal_servicemonitor_mgmt_yaml).(*monitoringv1.ServiceMonitor),
 		ServiceMonitorS3:          util.KubeObject(bundle.File_deploy_internal_servicemonitor_s3_yaml).(*monitoringv1.ServiceMonitor),
+		ServiceMonitorOperator:    util.KubeObject(bundle.File_deploy_internal_servicemonitor_operator_yaml).(*monitoringv1.ServiceMonitor),
 		CephObjectStoreUser:       util.KubeObject(bundle.File_deploy_internal_ceph_objectstore_user_yaml).(*cephv1.CephObjectStoreUser),
 		RouteMgmt:                 util.KubeObject(bundle.File_deploy_internal_route_mgmt_yaml).(*routev1.Route),
 		RouteS3:                   util.KubeObject(bundle.File_deploy_internal_route_s3_yaml).(*routev1.Route),
@@ -222,9 +236,12 @@ func NewReconciler(
 		NetworkPolicyDb:       util.KubeObject(bundle.File_deploy_internal_networkpolicy_db_yaml).(*networkingv1.NetworkPolicy),
 		NetworkPolicyEndpoint: util.KubeObject(bundle.File_deploy_internal_networkpolicy_endpoint_yaml).(*networkingv1.NetworkPolicy),
 		NetworkPolicyPVPool:   util.KubeObject(bundle.File_deploy_internal_networkpolicy_pvpool_yaml).(*networkingv1.NetworkPolicy),
+		NetworkPolicyOperator: util.KubeObject(bundle.File_deploy_internal_networkpolicy_operator_yaml).(*networkingv1.NetworkPolicy),
+		NetworkPolicyCNPG:     util.KubeObject(bundle.File_deploy_internal_networkpolicy_cnpg_controller_yaml).(*networkingv1.NetworkPolicy),
 
 		SecretMetricsAuth:        util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml).(*corev1.Secret),
 		SecretOIDCKeyCloakConfig: util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml).(*corev1.Secret),
+		SecretOIDCConfig:         util.KubeObject(bundle.File_deploy_internal_secret_empty_yaml).(*corev1.Secret),
 	}
 
 	// Set Namespace
@@ -243,6 +260,7 @@ func NewReconciler(
 	r.ServiceDbPg.Namespace = r.Request.Namespace
 	r.ServiceSyslog.Namespace = r.Request.Namespace
 	r.ServiceVectors.Namespace = r.Request.Namespace
+	r.ServiceOperatorMetrics.Namespace = r.Request.Namespace
 	r.SecretServer.Namespace = r.Request.Namespace
 	r.SecretDB.Namespace = r.Request.Namespace
 	r.SecretOp.Namespace = r.Request.Namespace
@@ -265,6 +283,7 @@ func NewReconciler(
 	r.PrometheusRule.Namespace = r.Request.Namespace
 	r.ServiceMonitorMgmt.Namespace = r.Request.Namespace
 	r.ServiceMonitorS3.Namespace = r.Request.Namespace
+	r.ServiceMonitorOperator.Namespace = r.Request.Namespace
 	r.CephObjectStoreUser.Namespace = r.Request.Namespace
 	r.RouteMgmt.Namespace = r.Request.Namespace
 	r.RouteS3.Namespace = r.Request.Namespace
@@ -280,12 +299,15 @@ func NewReconciler(
 	r.BucketNotificationsPVC.Namespace = r.Request.Namespace
 	r.SecretMetricsAuth.Namespace = r.Request.Namespace
 	r.SecretOIDCKeyCloakConfig.Namespace = r.Request.Namespace
+	r.SecretOIDCConfig.Namespace = r.Request.Namespace
 
 	// Network Policy namespaces
 	r.NetworkPolicyCore.Namespace = r.Request.Namespace
 	r.NetworkPolicyDb.Namespace = r.Request.Namespace
 	r.NetworkPolicyEndpoint.Namespace = r.Request.Namespace
 	r.NetworkPolicyPVPool.Namespace = r.Request.Namespace
+	r.NetworkPolicyOperator.Namespace = r.Request.Namespace
+	r.NetworkPolicyCNPG.Namespace = r.Request.Namespace
 
 	// Set Names
 	r.NooBaa.Name = r.Request.Name
@@ -300,6 +322,7 @@ func NewReconciler(
 	r.ServiceSts.Name = "sts"
 	r.ServiceIam.Name = "iam"
 	r.ServiceVectors.Name = "vectors"
+	r.ServiceOperatorMetrics.Name = "noobaa-operator-metrics"
 	r.ServiceDb.Name = r.Request.Name + "-db"
 	r.ServiceDbPg.Name = r.Request.Name + "-db-pg"
 	r.SecretServer.Name = r.Request.Name + "-server"
@@ -325,6 +348,7 @@ func NewReconciler(
 	r.PrometheusRule.Name = r.Request.Name + "-prometheus-rules"
 	r.ServiceMonitorMgmt.Name = r.ServiceMgmt.Name + "-service-monitor"
 	r.ServiceMonitorS3.Name = r.ServiceS3.Name + "-service-monitor"
+	r.ServiceMonitorOperator.Name = "noobaa-operator-service-monitor"
 	r.RouteMgmt.Name = r.ServiceMgmt.Name
 	r.RouteS3.Name = r.ServiceS3.Name
 	r.RouteSts.Name = r.ServiceSts.Name
@@ -338,12 +362,15 @@ func NewReconciler(
 	r.BucketNotificationsPVC.Name = r.Request.Name + "-bucket-notifications-pvc"
 	r.SecretMetricsAuth.Name = r.Request.Name + "-metrics-auth-secret"
 	r.SecretOIDCKeyCloakConfig.Name = r.Request.Name + "-oidc-keycloak-config"
+	r.SecretOIDCConfig.Name = r.Request.Name + "-oidc-config"
 
 	// Network Policy names
 	r.NetworkPolicyCore.Name = r.Request.Name + "-core"
 	r.NetworkPolicyDb.Name = r.Request.Name + "-db-pg-cluster"
 	r.NetworkPolicyEndpoint.Name = r.Request.Name + "-endpoint"
 	r.NetworkPolicyPVPool.Name = r.Request.Name + "-pvpool"
+	r.NetworkPolicyOperator.Name = r.Request.Name + "-operator"
+	r.NetworkPolicyCNPG.Name = r.Request.Name + "-cnpg-controller"
 
 	// Set the target service for routes.
 	r.RouteMgmt.Spec.To.Name = r.ServiceMgmt.Name
@@ -424,6 +451,8 @@ func (r *Reconciler) CheckAll() {
 	util.KubeCheckOptional(r.PrometheusRule)
 	util.KubeCheckOptional(r.ServiceMonitorMgmt)
 	util.KubeCheckOptional(r.ServiceMonitorS3)
+	util.KubeCheck(r.ServiceOperatorMetrics)
+	util.KubeCheckOptional(r.ServiceMonitorOperator)
 	util.KubeCheckOptional(r.RouteMgmt)
 	util.KubeCheckOptional(r.RouteS3)
 	util.KubeCheckOptional(r.RouteSts)
@@ -442,6 +471,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 	log.Infof("Start NooBaa system Reconcile ...")
 
 	if !CheckSystem(r.NooBaa) {
+		metrics.DeleteSystem(r.NooBaa.Namespace, r.NooBaa.Name)
 		if r.NooBaa.DeletionTimestamp != nil {
 			log.Infof("NooBaa not found or already deleted.")
 			if err = r.deleteRootSecret(); err != nil {
@@ -502,6 +532,12 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 		return res, nil
 	}
 
+	// verify the mgmt service certificate of core, which is signed by the same service-serving CA
+	err = nb.GlobalRPC.EnableTLSVerification(util.ServiceServingCertCAFile)
+	if err != nil && !os.IsNotExist(err) {
+		log.Errorf("❌ NooBaa %q failed to enable TLS verification of RPC calls: %v", r.NooBaa.Name, err)
+	}
+
 	if r.NooBaa.Spec.ExternalPgSecret != nil {
 		r.ExternalPgSecret = &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -531,6 +567,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 	err = r.ReconcilePhases()
 
 	if err != nil {
+		metrics.ReconcileError(metrics.ControllerNooBaa, err)
 		if perr, isPERR := err.(*util.PersistentError); isPERR {
 			r.SetPhase(nbv1.SystemPhaseRejected, perr.Reason, perr.Message)
 			log.Errorf("❌ Persistent Error: %s", err)
@@ -559,6 +596,7 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 			)
 			log.Infof("✅ Done")
 		}
+		res.RequeueAfter = r.RequeueAfter
 
 	}
 
@@ -572,6 +610,13 @@ func (r *Reconciler) Reconcile() (reconcile.Result, error) {
 	return res, nil
 }
 
+// requeueWithin makes sure a ready system is reconciled again within the period
+func (r *Reconciler) requeueWithin(period time.Duration) {
+	if r.RequeueAfter == 0 || period < r.RequeueAfter {
+		r.RequeueAfter = period
+	}
+}
+
 func (r *Reconciler) deleteRootSecret() error {
 	// External KMS Spec
 	connectionDetails := r.NooBaa.Spec.Security.KeyManagementService.ConnectionDetails
@@ -696,6 +741,7 @@ func (r *Reconciler) SetPhase(phase nbv1.SystemPhase, reason string, message str
 	}
 
 	r.NooBaa.Status.Phase = phase
+	metrics.SetPhase("NooBaa", r.NooBaa.Namespace, r.NooBaa.Name, string(phase))
 }
 
 // SetReadme runs the template and sets the readme
@@ -832,5 +878,5 @@ func (r *Reconciler) GetAffinity() *corev1.Affinity {
 }
 
 func (r *Reconciler) getEndpointMinMaxCount() (int32, int32) {
-	return getEndpointMinMax(r.NooBaa)
+	return getEndpointMinMax(r.NooBaa, r.PerformanceProfile)
 }
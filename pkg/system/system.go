@@ -51,6 +51,8 @@ func Cmd() *cobra.Command {
 		CmdDelete(),
 		CmdStatus(),
 		CmdDBBackup(),
+		CmdDBRestore(),
+		CmdDBUpgrade(),
 		CmdSetDebugLevel(),
 		CmdList(),
 		CmdReconcile(),
@@ -132,7 +134,7 @@ func CmdSetDebugLevel() *cobra.Command {
 func CmdDBBackup() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "db-backup",
-		Short: "Create a volume snapshot backup of the database",
+		Short: "Create an on-demand backup of the database (volume snapshot or object store)",
 		Run:   RunDBBackup,
 		Args:  cobra.NoArgs,
 	}
@@ -184,10 +186,12 @@ func CmdOidc() *cobra.Command {
 		Use:   "oidc",
 		Short: "Manage OIDC configuration",
 		Long: "Configure OIDC providers for NooBaa STS.\n" +
-			"The configuration is stored in a Kubernetes secret and mounted into endpoint pods.",
+			"Keycloak configuration is stored in a Kubernetes secret and mounted into endpoint pods.\n" +
+			"The oidc, kubernetes and dex providers are added to spec.security.oidc.providers of the NooBaa CR,\n" +
+			"replacing a provider with the same name.",
 		Run: RunOidc,
 	}
-	cmd.Flags().String("type", "", "OIDC provider type (keycloak)")
+	cmd.Flags().String("type", "", "OIDC provider type (keycloak, oidc, kubernetes, dex)")
 	cmd.Flags().String(
 		"configure",
 		"",
@@ -195,11 +199,19 @@ func CmdOidc() *cobra.Command {
 			"For Keycloak, the expected structure is:\n"+
 			`{"providers":[{"issuer":"<kc-server>:<kc-port>/realms/<realm-name>",`+
 			`"client_id":"<client-id>","client_secret":"<client-secret>",`+
-			`"token_introspection_endpoint":"http://<kc-server>:<kc-port>/realms/<realm-name>/protocol/openid-connect/token/introspect"}]}`,
+			`"token_introspection_endpoint":"http://<kc-server>:<kc-port>/realms/<realm-name>/protocol/openid-connect/token/introspect"}]}`+"\n"+
+			"For oidc, kubernetes and dex, the providers have the fields of spec.security.oidc.providers, e.g.:\n"+
+			`{"providers":[{"name":"cluster","audiences":["noobaa"],`+
+			`"claimMappings":[{"value":"system:serviceaccount:<namespace>:<service-account>","account":"<account-name>"}]}]}`,
 	)
 	return cmd
 }
 
+// OIDCProvidersConfig is the CLI configuration of the declarative OIDC providers
+type OIDCProvidersConfig struct {
+	Providers []nbv1.OIDCProviderSpec `json:"providers"`
+}
+
 // LoadSystemDefaults loads a noobaa system CR from bundled yamls
 // and apply's changes from CLI flags to the defaults.
 func LoadSystemDefaults() *nbv1.NooBaa {
@@ -700,6 +712,14 @@ func RunOidc(cmd *cobra.Command, args []string) {
 		if err := configureKeycloakOIDC(configJSON); err != nil {
 			log.Fatalf(`❌ %v`, err)
 		}
+	case string(nbv1.OIDCProviderGeneric), string(nbv1.OIDCProviderKubernetes), string(nbv1.OIDCProviderDex):
+		configJSON, err := readOIDCConfigInput(configure)
+		if err != nil {
+			log.Fatalf(`❌ %v`, err)
+		}
+		if err := configureOIDCProviders(nbv1.OIDCProviderType(providerType), configJSON); err != nil {
+			log.Fatalf(`❌ %v`, err)
+		}
 	default:
 		log.Fatalf(`❌ Unsupported OIDC provider type %q`, providerType)
 	}
@@ -748,7 +768,7 @@ func validateKeycloakOIDCConfig(configJSON string) (*KeycloakOIDCConfig, error)
 		if len(missing) == 0 {
 			continue
 		}
-		if len(missing) == 5 {
+		if len(missing) == 4 {
 			return nil, fmt.Errorf("provider[%d] is empty", i)
 		}
 		return nil, fmt.Errorf("provider[%d] is missing required fields: %s", i, strings.Join(missing, ", "))
@@ -789,6 +809,80 @@ func configureKeycloakOIDC(configJSON string) error {
 	return nil
 }
 
+// validateOIDCProvidersConfig parses the CLI configuration of the declarative OIDC providers of a type
+func validateOIDCProvidersConfig(providerType nbv1.OIDCProviderType, configJSON string) ([]nbv1.OIDCProviderSpec, error) {
+	if !json.Valid([]byte(configJSON)) {
+		return nil, fmt.Errorf("the provided configuration is not valid JSON")
+	}
+
+	var config OIDCProvidersConfig
+	decoder := json.NewDecoder(strings.NewReader(configJSON))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	if len(config.Providers) == 0 {
+		return nil, fmt.Errorf("configuration must contain at least one provider")
+	}
+
+	for i := range config.Providers {
+		provider := &config.Providers[i]
+		if provider.Type == "" {
+			provider.Type = providerType
+		}
+		if provider.Type != providerType {
+			return nil, fmt.Errorf("provider[%d] type %q does not match --type %s", i, provider.Type, providerType)
+		}
+	}
+	return config.Providers, nil
+}
+
+// mergeOIDCProviders adds the providers to the OIDC spec, replacing the providers with the same name
+func mergeOIDCProviders(spec *nbv1.OIDCSpec, providers []nbv1.OIDCProviderSpec) {
+	for _, provider := range providers {
+		replaced := false
+		for i := range spec.Providers {
+			if spec.Providers[i].Name == provider.Name {
+				spec.Providers[i] = provider
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			spec.Providers = append(spec.Providers, provider)
+		}
+	}
+}
+
+func configureOIDCProviders(providerType nbv1.OIDCProviderType, configJSON string) error {
+	log := util.Logger()
+
+	providers, err := validateOIDCProvidersConfig(providerType, configJSON)
+	if err != nil {
+		return err
+	}
+
+	sys := LoadSystemDefaults()
+	if !util.KubeCheck(sys) {
+		return fmt.Errorf("NooBaa system %q not found in namespace %q", sys.Name, sys.Namespace)
+	}
+
+	if sys.Spec.Security.OIDC == nil {
+		sys.Spec.Security.OIDC = &nbv1.OIDCSpec{}
+	}
+	mergeOIDCProviders(sys.Spec.Security.OIDC, providers)
+	if !util.KubeUpdate(sys) {
+		return fmt.Errorf("failed to update the OIDC providers of NooBaa system %q", sys.Name)
+	}
+
+	log.Printf("✅ %s OIDC providers saved to NooBaa system %q spec.security.oidc", providerType, sys.Name)
+	if !sys.Spec.Security.OIDC.Enabled {
+		log.Printf("The providers are not mounted to the endpoints until spec.security.oidc.enabled is set")
+	}
+	return nil
+}
+
 // CheckNooBaaImages runs a CLI command
 func CheckNooBaaImages(cmd *cobra.Command, sys *nbv1.NooBaa, args []string) string {
 	log := util.Logger()
@@ -1042,22 +1136,47 @@ func RunDBBackup(cmd *cobra.Command, args []string) {
 		log.Fatalf("❌ System %q not found", options.SystemName)
 	}
 
+	backupName, _ := cmd.Flags().GetString("name")
+	if backupName == "" {
+		backupName = sys.Name + pgClusterSuffix + "-backup-" + time.Now().Format("20060102150405")
+	}
+
+	// base backups of large databases can take a long time, so stop waiting after a while and let the user monitor
+	err := CreateDBBackup(sys, backupName, 5*time.Minute)
+	if err != nil && wait.Interrupted(err) && sys.Spec.DBSpec.DBBackup.ObjectStore != nil {
+		log.Printf("⏳ Backup %s is still running, use the command above to monitor it", backupName)
+		return
+	}
+	if err != nil {
+		log.Fatalf("❌ %s", err)
+	}
+}
+
+// CreateDBBackup creates an on-demand backup of the database with the backup method of the system,
+// and waits up to the timeout for the volume snapshot to be created or for the base backup to complete.
+// When the timeout expires the returned error matches wait.Interrupted().
+func CreateDBBackup(sys *nbv1.NooBaa, backupName string, timeout time.Duration) error {
+	log := util.Logger()
+
 	if sys.Spec.DBSpec == nil {
-		log.Fatalf("❌ The system is not configured with a CNPG cluster")
+		return fmt.Errorf("the system is not configured with a CNPG cluster")
 	}
 
-	if sys.Spec.DBSpec.DBBackup == nil || sys.Spec.DBSpec.DBBackup.VolumeSnapshot == nil {
-		log.Fatalf("❌ The system is not configured with a volume snapshot backup")
+	if sys.Spec.DBSpec.DBBackup == nil {
+		return fmt.Errorf("the system is not configured with a database backup")
 	}
 
-	volumeSnapshotClass := sys.Spec.DBSpec.DBBackup.VolumeSnapshot.VolumeSnapshotClass
-	if volumeSnapshotClass == "" {
-		log.Fatalf("❌ The system is not configured with a volume snapshot class")
+	if sys.Spec.DBSpec.DBBackup.ObjectStore != nil {
+		return createObjectStoreDBBackup(sys, backupName, timeout)
 	}
 
-	backupName, _ := cmd.Flags().GetString("name")
-	if backupName == "" {
-		backupName = sys.Name + pgClusterSuffix + "-backup-" + time.Now().Format("20060102150405")
+	if sys.Spec.DBSpec.DBBackup.VolumeSnapshot == nil {
+		return fmt.Errorf("the system is not configured with a volume snapshot backup")
+	}
+
+	volumeSnapshotClass := sys.Spec.DBSpec.DBBackup.VolumeSnapshot.VolumeSnapshotClass
+	if volumeSnapshotClass == "" {
+		return fmt.Errorf("the system is not configured with a volume snapshot class")
 	}
 
 	offlineBackup := false
@@ -1068,7 +1187,7 @@ func RunDBBackup(cmd *cobra.Command, args []string) {
 	backup.Spec.Target = cnpgv1.BackupTargetStandby
 
 	if !util.KubeCreateFailExisting(backup) {
-		log.Fatalf("❌ Backup %s failed to create", backupName)
+		return fmt.Errorf("backup %s failed to create", backupName)
 	}
 
 	log.Printf("✅ Backup object %s created successfully. Waiting for the volume snapshot to be created...\n", backupName)
@@ -1076,8 +1195,7 @@ func RunDBBackup(cmd *cobra.Command, args []string) {
 	log.Printf("kubectl -n %s get backups.postgresql.cnpg.noobaa.io %s", sys.Namespace, backupName)
 
 	// Create a context with timeout for polling
-	pollTimeout := 5 * time.Minute
-	pollCtx, cancel := context.WithTimeout(context.Background(), pollTimeout)
+	pollCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// wait for the volume snapshot to be created
@@ -1097,12 +1215,57 @@ func RunDBBackup(cmd *cobra.Command, args []string) {
 		return true, nil
 	})
 	if err != nil {
-		log.Fatalf("❌ Failed to wait for volume snapshot %s: %s", backupName, err)
+		return fmt.Errorf("failed to wait for volume snapshot %s: %w", backupName, err)
 	}
 
 	log.Printf("✅ Volume snapshot %s created successfully\n", backupName)
 	log.Printf("You can view the volume snapshot with the following command:\n")
 	log.Printf("kubectl -n %s get volumesnapshots.snapshot.storage.k8s.io %s", sys.Namespace, backupName)
+	return nil
+}
+
+// createObjectStoreDBBackup creates a base backup of the database in the object store and waits for it to complete
+func createObjectStoreDBBackup(sys *nbv1.NooBaa, backupName string, timeout time.Duration) error {
+	log := util.Logger()
+
+	backup := cnpg.GetCnpgBackupObj(sys.Namespace, backupName)
+	backup.Spec.Cluster.Name = sys.Name + pgClusterSuffix
+	backup.Spec.Method = cnpgv1.BackupMethodBarmanObjectStore
+	backup.Spec.Target = cnpgv1.BackupTargetStandby
+
+	if !util.KubeCreateFailExisting(backup) {
+		return fmt.Errorf("backup %s failed to create", backupName)
+	}
+
+	log.Printf("✅ Backup object %s created successfully. Waiting for the base backup to be uploaded to %s ...\n",
+		backupName, sys.Spec.DBSpec.DBBackup.ObjectStore.DestinationPath)
+	log.Printf("You can monitor the backup status with the following command:\n")
+	log.Printf("kubectl -n %s get backups.postgresql.cnpg.noobaa.io %s", sys.Namespace, backupName)
+
+	pollCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	interval := time.Duration(3)
+	err := wait.PollUntilContextCancel(pollCtx, interval*time.Second, true, func(ctx context.Context) (bool, error) {
+		if !util.KubeCheckQuiet(backup) {
+			return false, nil
+		}
+		switch backup.Status.Phase {
+		case cnpgv1.BackupPhaseCompleted:
+			return true, nil
+		case cnpgv1.BackupPhaseFailed:
+			return false, fmt.Errorf("backup failed: %s", backup.Status.Error)
+		default:
+			log.Printf("⏳ Backup %s phase is %q", backupName, backup.Status.Phase)
+			return false, nil
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for backup %s: %w", backupName, err)
+	}
+
+	log.Printf("✅ Backup %s completed successfully with backup ID %s\n", backupName, backup.Status.BackupID)
+	return nil
 }
 
 // RunReconcile runs a CLI command
@@ -1131,6 +1294,12 @@ func RunReconcile(cmd *cobra.Command, args []string) {
 
 // WaitReady waits until the system phase changes to ready by the operator
 func WaitReady() bool {
+	return WaitReadyContext(ctx)
+}
+
+// WaitReadyContext waits until the system phase changes to ready by the operator,
+// and returns false when the context is done before that
+func WaitReadyContext(ctx context.Context) bool {
 	log := util.Logger()
 	klient := util.KubeClient()
 
@@ -0,0 +1,210 @@
+package system
+
+import (
+	"fmt"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/cnpg"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultUpgradeBackupMaxAge is the default maximum age of the last DB backup for an upgrade to start
+	DefaultUpgradeBackupMaxAge = 24 * time.Hour
+
+	// DefaultUpgradeMaxDBVolumeUsedPercent is the default maximum usage of the DB volume for an upgrade to start,
+	// core migrations and the postgres upgrade need free space on the volume
+	DefaultUpgradeMaxDBVolumeUsedPercent = 80
+)
+
+// UpgradePreflightCheck is the result of a check that runs before an upgrade
+type UpgradePreflightCheck struct {
+	Name    string
+	Passed  bool
+	Warning bool
+	Message string
+}
+
+// UpgradePreflightOptions are the thresholds of the upgrade pre-flight checks
+type UpgradePreflightOptions struct {
+	TargetPgMajorVersion   int
+	AllowPgMajorUpgrade    bool
+	BackupMaxAge           time.Duration
+	MaxDBVolumeUsedPercent int
+}
+
+// RunUpgradePreflightChecks checks that the system can be upgraded safely -
+// a fresh DB backup, every backing store and namespace store Ready,
+// a compatible postgres major version and enough free space on the DB volume
+func RunUpgradePreflightChecks(sys *nbv1.NooBaa, opts UpgradePreflightOptions) []UpgradePreflightCheck {
+	checks := []UpgradePreflightCheck{
+		checkUpgradeDBBackup(sys, opts.BackupMaxAge, time.Now()),
+	}
+
+	backingStores := &nbv1.BackingStoreList{}
+	namespaceStores := &nbv1.NamespaceStoreList{}
+	if !util.KubeList(backingStores, client.InNamespace(sys.Namespace)) ||
+		!util.KubeList(namespaceStores, client.InNamespace(sys.Namespace)) {
+		checks = append(checks, UpgradePreflightCheck{Name: "stores", Message: "failed to list the backing stores and namespace stores"})
+	} else {
+		checks = append(checks, checkUpgradeStoresReady(backingStores.Items, namespaceStores.Items))
+	}
+
+	checks = append(checks, checkUpgradePostgresMajorVersion(sys, opts.TargetPgMajorVersion, opts.AllowPgMajorUpgrade))
+
+	checks = append(checks, checkUpgradeDBVolume(sys, opts.MaxDBVolumeUsedPercent))
+	return checks
+}
+
+// PrintUpgradePreflightChecks prints the results of the checks as a table and returns the number of failed checks
+func PrintUpgradePreflightChecks(checks []UpgradePreflightCheck) int {
+	failed := 0
+	table := (&util.PrintTable{}).AddRow("CHECK", "RESULT", "MESSAGE")
+	for _, check := range checks {
+		result := "✅ passed"
+		if !check.Passed {
+			result = "❌ failed"
+			failed++
+		} else if check.Warning {
+			result = "⚠️  warning"
+		}
+		table.AddRow(check.Name, result, check.Message)
+	}
+	fmt.Print(table.String())
+	return failed
+}
+
+// isCNPGManagedDB returns true when the DB of the system is a postgres cluster managed by the operator
+func isCNPGManagedDB(sys *nbv1.NooBaa) bool {
+	return sys.Spec.DBSpec != nil && sys.Spec.ExternalPgSecret == nil
+}
+
+// checkUpgradeDBBackup checks that the last DB backup is not older than maxAge
+func checkUpgradeDBBackup(sys *nbv1.NooBaa, maxAge time.Duration, now time.Time) UpgradePreflightCheck {
+	check := UpgradePreflightCheck{Name: "db-backup"}
+	if !isCNPGManagedDB(sys) {
+		check.Passed = true
+		check.Warning = true
+		check.Message = "the DB is not managed by the operator, make sure it is backed up before the upgrade"
+		return check
+	}
+	if sys.Spec.DBSpec.DBBackup == nil {
+		check.Passed = true
+		check.Warning = true
+		check.Message = "DB backup is not configured (spec.dbSpec.dbBackup), the upgrade cannot take a DB snapshot"
+		return check
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultUpgradeBackupMaxAge
+	}
+	var backupStatus *nbv1.DBBackupStatus
+	if sys.Status.DBStatus != nil {
+		backupStatus = sys.Status.DBStatus.BackupStatus
+	}
+	if backupStatus == nil || backupStatus.LastBackupTime == nil {
+		check.Message = "no DB backup completed yet"
+		return check
+	}
+	age := now.Sub(backupStatus.LastBackupTime.Time)
+	if age > maxAge {
+		check.Message = fmt.Sprintf("the last DB backup is %s old, older than %s", age.Round(time.Minute), maxAge)
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("the last DB backup is %s old", age.Round(time.Minute))
+	return check
+}
+
+// checkUpgradeStoresReady checks that every backing store and namespace store is Ready
+func checkUpgradeStoresReady(backingStores []nbv1.BackingStore, namespaceStores []nbv1.NamespaceStore) UpgradePreflightCheck {
+	check := UpgradePreflightCheck{Name: "stores"}
+	notReady := []string{}
+	for i := range backingStores {
+		bs := &backingStores[i]
+		if bs.Status.Phase != nbv1.BackingStorePhaseReady {
+			notReady = append(notReady, fmt.Sprintf("backingstore %s is %q", bs.Name, bs.Status.Phase))
+		}
+	}
+	for i := range namespaceStores {
+		ns := &namespaceStores[i]
+		if ns.Status.Phase != nbv1.NamespaceStorePhaseReady {
+			notReady = append(notReady, fmt.Sprintf("namespacestore %s is %q", ns.Name, ns.Status.Phase))
+		}
+	}
+	if len(notReady) > 0 {
+		check.Message = fmt.Sprintf("stores are not ready: %v", notReady)
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("%d backing stores and %d namespace stores are ready", len(backingStores), len(namespaceStores))
+	return check
+}
+
+// checkUpgradePostgresMajorVersion checks that the target postgres major version can be reached from the current one.
+// Postgres cannot be downgraded to an older major version. An upgrade to a newer major version skips the
+// compatibility check and the snapshot fallback of noobaa system db-upgrade, so it fails unless it is explicitly allowed.
+func checkUpgradePostgresMajorVersion(sys *nbv1.NooBaa, target int, allowMajorUpgrade bool) UpgradePreflightCheck {
+	check := UpgradePreflightCheck{Name: "postgres-version"}
+	if !isCNPGManagedDB(sys) {
+		check.Passed = true
+		check.Message = "the DB is not managed by the operator"
+		return check
+	}
+	current := getDesiredMajorVersion(sys.Spec.DBSpec)
+	if sys.Status.DBStatus != nil && sys.Status.DBStatus.CurrentPgMajorVersion != 0 {
+		current = sys.Status.DBStatus.CurrentPgMajorVersion
+	}
+	switch {
+	case target < current:
+		check.Message = fmt.Sprintf("postgres cannot be downgraded from major version %d to %d", current, target)
+	case target > current && allowMajorUpgrade:
+		check.Passed = true
+		check.Warning = true
+		check.Message = fmt.Sprintf("postgres will be upgraded from major version %d to %d without a compatibility check", current, target)
+	case target > current:
+		check.Message = fmt.Sprintf("postgres would be upgraded from major version %d to %d, "+
+			"run noobaa system db-upgrade --target-version %d first, or use --allow-postgres-major-upgrade", current, target, target)
+	default:
+		check.Passed = true
+		check.Message = fmt.Sprintf("postgres major version %d is unchanged", current)
+	}
+	return check
+}
+
+// checkUpgradeDBVolume checks that the DB volume of the primary instance has enough free space
+func checkUpgradeDBVolume(sys *nbv1.NooBaa, maxUsedPercent int) UpgradePreflightCheck {
+	check := UpgradePreflightCheck{Name: "db-volume"}
+	if !isCNPGManagedDB(sys) {
+		check.Passed = true
+		check.Message = "the DB is not managed by the operator"
+		return check
+	}
+	cluster := cnpg.GetCnpgClusterObj(sys.Namespace, sys.Name+pgClusterSuffix)
+	if !util.KubeCheckQuiet(cluster) || cluster.Status.CurrentPrimary == "" {
+		check.Message = "the primary instance of the DB cluster was not found"
+		return check
+	}
+	usedPercent, err := util.GetVolumeUsedPercent(cluster.Namespace, cluster.Status.CurrentPrimary, "postgres", dbDataMountPath)
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to check the usage of the DB volume: %v", err)
+		return check
+	}
+	return checkUpgradeDBVolumeUsage(usedPercent, maxUsedPercent)
+}
+
+// checkUpgradeDBVolumeUsage checks the usage of the DB volume against the maximum used percent
+func checkUpgradeDBVolumeUsage(usedPercent int, maxUsedPercent int) UpgradePreflightCheck {
+	check := UpgradePreflightCheck{Name: "db-volume"}
+	if maxUsedPercent <= 0 {
+		maxUsedPercent = DefaultUpgradeMaxDBVolumeUsedPercent
+	}
+	if usedPercent > maxUsedPercent {
+		check.Message = fmt.Sprintf("the DB volume is %d%% used, more than %d%%", usedPercent, maxUsedPercent)
+		return check
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("the DB volume is %d%% used", usedPercent)
+	return check
+}
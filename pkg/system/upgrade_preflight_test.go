@@ -0,0 +1,95 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckUpgradeDBBackup(t *testing.T) {
+	now := time.Now()
+	backupAt := func(age time.Duration) *nbv1.NooBaaDBStatus {
+		return &nbv1.NooBaaDBStatus{BackupStatus: &nbv1.DBBackupStatus{LastBackupTime: &metav1.Time{Time: now.Add(-age)}}}
+	}
+	withBackup := &nbv1.NooBaaDBSpec{DBBackup: &nbv1.DBBackupSpec{}}
+	tests := []struct {
+		name        string
+		dbSpec      *nbv1.NooBaaDBSpec
+		dbStatus    *nbv1.NooBaaDBStatus
+		wantPassed  bool
+		wantWarning bool
+	}{
+		{"standalone db", nil, nil, true, true},
+		{"backup not configured", &nbv1.NooBaaDBSpec{}, nil, true, true},
+		{"no backup yet", withBackup, nil, false, false},
+		{"fresh backup", withBackup, backupAt(time.Hour), true, false},
+		{"stale backup", withBackup, backupAt(48 * time.Hour), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &nbv1.NooBaa{}
+			sys.Spec.DBSpec = tt.dbSpec
+			sys.Status.DBStatus = tt.dbStatus
+			check := checkUpgradeDBBackup(sys, DefaultUpgradeBackupMaxAge, now)
+			if check.Passed != tt.wantPassed || check.Warning != tt.wantWarning {
+				t.Errorf("checkUpgradeDBBackup() = %+v, want passed=%v warning=%v", check, tt.wantPassed, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestCheckUpgradeStoresReady(t *testing.T) {
+	ready := nbv1.BackingStore{}
+	ready.Status.Phase = nbv1.BackingStorePhaseReady
+	rejected := nbv1.BackingStore{}
+	rejected.Name = "bs-rejected"
+	rejected.Status.Phase = nbv1.BackingStorePhaseRejected
+	nsReady := nbv1.NamespaceStore{}
+	nsReady.Status.Phase = nbv1.NamespaceStorePhaseReady
+
+	if check := checkUpgradeStoresReady([]nbv1.BackingStore{ready}, []nbv1.NamespaceStore{nsReady}); !check.Passed {
+		t.Errorf("expected ready stores to pass, got %+v", check)
+	}
+	if check := checkUpgradeStoresReady([]nbv1.BackingStore{ready, rejected}, nil); check.Passed {
+		t.Errorf("expected a rejected store to fail, got %+v", check)
+	}
+}
+
+func TestCheckUpgradePostgresMajorVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     int
+		target      int
+		allow       bool
+		wantPassed  bool
+		wantWarning bool
+	}{
+		{"same version", 16, 16, false, true, false},
+		{"major upgrade", 15, 16, false, false, false},
+		{"allowed major upgrade", 15, 16, true, true, true},
+		{"downgrade", 16, 15, false, false, false},
+		{"allowed downgrade", 16, 15, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &nbv1.NooBaa{}
+			sys.Spec.DBSpec = &nbv1.NooBaaDBSpec{}
+			sys.Status.DBStatus = &nbv1.NooBaaDBStatus{CurrentPgMajorVersion: tt.current}
+			check := checkUpgradePostgresMajorVersion(sys, tt.target, tt.allow)
+			if check.Passed != tt.wantPassed || check.Warning != tt.wantWarning {
+				t.Errorf("checkUpgradePostgresMajorVersion() = %+v, want passed=%v warning=%v", check, tt.wantPassed, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestCheckUpgradeDBVolumeUsage(t *testing.T) {
+	if check := checkUpgradeDBVolumeUsage(60, 80); !check.Passed {
+		t.Errorf("expected 60%% used to pass, got %+v", check)
+	}
+	if check := checkUpgradeDBVolumeUsage(90, 0); check.Passed {
+		t.Errorf("expected 90%% used to fail the default threshold, got %+v", check)
+	}
+}
@@ -0,0 +1,120 @@
+package kms
+
+import (
+	"fmt"
+
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AWS KMS config options
+const (
+	AWSKMSKeyID                = "AWS_KMS_KEY_ID"
+	AWSKMSRegion               = "AWS_REGION"
+	AWSKMSEndpoint             = "AWS_KMS_ENDPOINT"
+	AWSKMSRoleARN              = "AWS_ROLE_ARN"
+	AWSKMSWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	AWSKMSAccessKeyID          = "AWS_ACCESS_KEY_ID"
+	AWSKMSSecretAccessKey      = "AWS_SECRET_ACCESS_KEY"
+	AWSKMSNamespace            = "namespace"
+	AWSKMSSystemUID            = "uid"
+)
+
+// AWSKMS is an AWS KMS driver, the root master keys are wrapped
+// by a customer managed key (CMK) and only the cipher text is kept in the cluster
+type AWSKMS struct {
+	UID  string // NooBaa system UID
+	name string // NooBaa system name
+	ns   string // NooBaa system namespace
+}
+
+// NewAWSKMS is AWS KMS driver constructor
+func NewAWSKMS(
+	name string,
+	namespace string,
+	uid string,
+) Driver {
+	return &AWSKMS{uid, name, namespace}
+}
+
+//
+// AWS KMS specific driver for root master key
+//
+
+// Config returns this driver secret config
+func (a *AWSKMS) Config(config map[string]string, tokenSecretName, namespace string) (map[string]interface{}, error) {
+	// create a type correct copy of the configuration
+	c := make(map[string]interface{})
+	for k, v := range config {
+		c[k] = v
+	}
+
+	if keyID, exists := config[AWSKMSKeyID]; !exists || keyID == "" {
+		return nil, fmt.Errorf("❌ Could not find in config AWS KMS key ID %v", AWSKMSKeyID)
+	}
+	if region, exists := config[AWSKMSRegion]; !exists || region == "" {
+		return nil, fmt.Errorf("❌ Could not find in config AWS region %v", AWSKMSRegion)
+	}
+
+	// STS web identity does not need long lived credentials,
+	// otherwise fetch the access keys from the token secret if one was given
+	if _, sts := config[AWSKMSRoleARN]; !sts && tokenSecretName != "" {
+		secret := &corev1.Secret{}
+		secret.Namespace = namespace
+		secret.Name = tokenSecretName
+		if !util.KubeCheck(secret) {
+			return nil, fmt.Errorf(`❌ Could not find secret %q in namespace %q`, secret.Name, secret.Namespace)
+		}
+		for _, key := range []string{AWSKMSAccessKeyID, AWSKMSSecretAccessKey} {
+			val, keyOk := secret.StringData[key]
+			if !keyOk {
+				return nil, fmt.Errorf(`❌ Could not find key %v in secret %q in namespace %q`, key, secret.Name, secret.Namespace)
+			}
+			c[key] = val
+		}
+	}
+
+	c[AWSKMSNamespace] = namespace
+	c[AWSKMSSystemUID] = a.UID
+
+	return c, nil
+}
+
+// Name returns root key map key
+func (a *AWSKMS) Name() string {
+	return "rootkeyb64-" + a.UID
+}
+
+// Path returns the secret id
+func (a *AWSKMS) Path() string {
+	return a.Name()
+}
+
+// GetContext returns context used for secret get operation
+func (*AWSKMS) GetContext() map[string]string {
+	return nil
+}
+
+// SetContext returns context used for secret set operation
+func (*AWSKMS) SetContext() map[string]string {
+	return nil
+}
+
+// DeleteContext returns context used for secret delete operation
+func (*AWSKMS) DeleteContext() map[string]string {
+	return nil
+}
+
+// Version returns the current driver KMS version
+// either single string or map, i.e. rotating key
+func (a *AWSKMS) Version(kms *KMS) Version {
+	return &VersionRotatingSecret{VersionBase{kms, nil}, a.name, a.ns}
+}
+
+// Register AWS KMS driver with KMS layer
+func init() {
+	if err := RegisterDriver(AWSKMSSecretStorageName, NewAWSKMS); err != nil {
+		panic(err.Error())
+	}
+}
@@ -0,0 +1,323 @@
+package kms
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/libopenstorage/secrets"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AWSKMSSecretStorageName is KMS backend name
+	AWSKMSSecretStorageName = "aws-kms"
+
+	// AWSKMSKeyIDAnnotation on the wrapped keys secret holds the key ID that wrapped them
+	AWSKMSKeyIDAnnotation = "noobaa.io/aws-kms-key-id"
+
+	// awsKMSSecretSuffix is appended to the secret id to name the wrapped keys secret
+	awsKMSSecretSuffix = "-aws-kms"
+
+	// awsKMSContextSystem and awsKMSContextSecret are the encryption context keys,
+	// binding the wrapped keys to the NooBaa system and secret they were created for
+	awsKMSContextSystem = "noobaa-system-uid"
+	awsKMSContextSecret = "noobaa-secret-id"
+)
+
+// AWSKMSSecretStorage is an AWS KMS backend Key Management Systems (KMS)
+// which implements libopenstorage Secrets interface.
+// Secret values are encrypted by the customer managed key (envelope encryption)
+// and the resulting cipher text is kept in a k8s secret in the system namespace
+type AWSKMSSecretStorage struct {
+	client    kmsiface.KMSAPI
+	keyID     string
+	namespace string
+	uid       string
+}
+
+func getAWSKMSParam(secretConfig map[string]interface{}, name string) string {
+	if value, exists := secretConfig[name]; exists {
+		if s, ok := value.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// NewAWSKMSSecretStorage is a constructor, returns a new instance of AWSKMSSecretStorage
+func NewAWSKMSSecretStorage(
+	secretConfig map[string]interface{}, // config
+) (secrets.Secrets, error) {
+	keyID := getAWSKMSParam(secretConfig, AWSKMSKeyID)
+	if keyID == "" {
+		return nil, fmt.Errorf("%v is not set", AWSKMSKeyID)
+	}
+	region := getAWSKMSParam(secretConfig, AWSKMSRegion)
+	if region == "" {
+		return nil, fmt.Errorf("%v is not set", AWSKMSRegion)
+	}
+	namespace := getAWSKMSParam(secretConfig, AWSKMSNamespace)
+	if namespace == "" {
+		return nil, fmt.Errorf("%v is not set", AWSKMSNamespace)
+	}
+	uid := getAWSKMSParam(secretConfig, AWSKMSSystemUID)
+
+	config := &aws.Config{
+		Region:              aws.String(region),
+		STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
+	}
+	// a custom endpoint allows using VPC endpoints or a local KMS emulator
+	if endpoint := getAWSKMSParam(secretConfig, AWSKMSEndpoint); endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create AWS session %v", err)
+	}
+
+	creds, err := awsKMSCredentials(sess, secretConfig, uid)
+	if err != nil {
+		return nil, err
+	}
+	client := kms.New(sess, &aws.Config{Credentials: creds})
+
+	r := &AWSKMSSecretStorage{
+		client:    client,
+		keyID:     keyID,
+		namespace: namespace,
+		uid:       uid,
+	}
+	return r, nil
+}
+
+// awsKMSCredentials returns the credentials used to access AWS KMS:
+// STS web identity when a role ARN is configured, static access keys when found in the config,
+// otherwise nil so the default credentials chain of the session is used
+func awsKMSCredentials(sess *session.Session, secretConfig map[string]interface{}, uid string) (*credentials.Credentials, error) {
+	if roleARN := getAWSKMSParam(secretConfig, AWSKMSRoleARN); roleARN != "" {
+		tokenFile := getAWSKMSParam(secretConfig, AWSKMSWebIdentityTokenFile)
+		if tokenFile == "" {
+			tokenFile = util.WebIdentityTokenPath
+		}
+		if _, err := os.Stat(tokenFile); err != nil {
+			return nil, fmt.Errorf("could not find web identity token file %v for role %v: %v", tokenFile, roleARN, err)
+		}
+		return stscreds.NewWebIdentityCredentials(sess, roleARN, "noobaa-kms-"+uid, tokenFile), nil
+	}
+
+	accessKeyID := getAWSKMSParam(secretConfig, AWSKMSAccessKeyID)
+	secretAccessKey := getAWSKMSParam(secretConfig, AWSKMSSecretAccessKey)
+	if accessKeyID != "" && secretAccessKey != "" {
+		return credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""), nil
+	}
+
+	return nil, nil
+}
+
+// String representation of this implementation
+func (*AWSKMSSecretStorage) String() string {
+	return AWSKMSSecretStorageName
+}
+
+// wrappedSecret returns the k8s secret holding the wrapped values of secretID
+func (a *AWSKMSSecretStorage) wrappedSecret(secretID string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretID + awsKMSSecretSuffix,
+			Namespace: a.namespace,
+		},
+	}
+}
+
+// encryptionContext returns the AWS KMS encryption context of secretID
+func (a *AWSKMSSecretStorage) encryptionContext(secretID string) map[string]*string {
+	return map[string]*string{
+		awsKMSContextSystem: aws.String(a.uid),
+		awsKMSContextSecret: aws.String(secretID),
+	}
+}
+
+// wrap encrypts a single value with the customer managed key
+func (a *AWSKMSSecretStorage) wrap(secretID string, value string) (string, error) {
+	out, err := a.client.Encrypt(&kms.EncryptInput{
+		KeyId:             aws.String(a.keyID),
+		Plaintext:         []byte(value),
+		EncryptionContext: a.encryptionContext(secretID),
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(out.CiphertextBlob), nil
+}
+
+// unwrap decrypts a single value, the key that wrapped it is part of the cipher text blob
+func (a *AWSKMSSecretStorage) unwrap(secretID string, wrapped string) (string, error) {
+	blob, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return "", err
+	}
+	out, err := a.client.Decrypt(&kms.DecryptInput{
+		CiphertextBlob:    blob,
+		EncryptionContext: a.encryptionContext(secretID),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(out.Plaintext), nil
+}
+
+// GetSecret returns the secret data associated with the
+// supplied secretId.
+func (a *AWSKMSSecretStorage) GetSecret(
+	secretID string,
+	keyContext map[string]string,
+) (map[string]interface{}, secrets.Version, error) {
+	log := util.Logger()
+
+	secret := a.wrappedSecret(secretID)
+	if !util.KubeCheckQuiet(secret) {
+		return nil, secrets.NoVersion, secrets.ErrInvalidSecretId
+	}
+
+	r := map[string]interface{}{}
+	for k, v := range secret.Data {
+		// the active key pointer is not a secret
+		if k == ActiveRootKey {
+			r[k] = string(v)
+			continue
+		}
+		value, err := a.unwrap(secretID, string(v))
+		if err != nil {
+			log.Errorf("AWSKMSSecretStorage.GetSecret() failed to decrypt %v in secret %v: %v", k, secret.Name, err)
+			return nil, secrets.NoVersion, err
+		}
+		r[k] = value
+	}
+	return r, secrets.NoVersion, nil
+}
+
+// PutSecret will associate an secretId to its secret data
+// provided in the arguments and store it into the secret backend
+func (a *AWSKMSSecretStorage) PutSecret(
+	secretID string,
+	plainText map[string]interface{},
+	keyContext map[string]string,
+) (secrets.Version, error) {
+	log := util.Logger()
+
+	secret := a.wrappedSecret(secretID)
+	exists := util.KubeCheckQuiet(secret)
+
+	// root keys are never modified once created, so values that were already
+	// wrapped by the current key are kept instead of calling KMS for each of them
+	wrapped := map[string]string{}
+	if exists && secret.Annotations[AWSKMSKeyIDAnnotation] == a.keyID {
+		for k, v := range secret.Data {
+			wrapped[k] = string(v)
+		}
+	}
+
+	data := map[string][]byte{}
+	for k, v := range plainText {
+		value, ok := v.(string)
+		if !ok {
+			return secrets.NoVersion, fmt.Errorf("invalid secret value format for %v", k)
+		}
+		if k == ActiveRootKey {
+			data[k] = []byte(value)
+			continue
+		}
+		if w, found := wrapped[k]; found {
+			data[k] = []byte(w)
+			continue
+		}
+		w, err := a.wrap(secretID, value)
+		if err != nil {
+			log.Errorf("AWSKMSSecretStorage.PutSecret() failed to encrypt %v with key %v: %v", k, a.keyID, err)
+			return secrets.NoVersion, err
+		}
+		data[k] = []byte(w)
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[AWSKMSKeyIDAnnotation] = a.keyID
+	secret.Data = data
+	secret.StringData = nil
+	if exists {
+		if !util.KubeUpdate(secret) {
+			return secrets.NoVersion, fmt.Errorf("Failed to update KMS secret %v in ns %v", secret.Name, secret.Namespace)
+		}
+	} else if !util.KubeCreateFailExisting(secret) {
+		return secrets.NoVersion, fmt.Errorf("Failed to create KMS secret %v in ns %v", secret.Name, secret.Namespace)
+	}
+
+	log.Infof("AWSKMSSecretStorage.PutSecret() stored %v values wrapped by key %v in secret %v", len(data), a.keyID, secret.Name)
+	return secrets.NoVersion, nil
+}
+
+// DeleteSecret deletes the secret data associated with the
+// supplied secretId. The customer managed key itself is never deleted.
+func (a *AWSKMSSecretStorage) DeleteSecret(
+	secretID string,
+	keyContext map[string]string,
+) error {
+	secret := a.wrappedSecret(secretID)
+	if !util.KubeDelete(secret) {
+		return fmt.Errorf("Failed to delete KMS secret %v in ns %v", secret.Name, secret.Namespace)
+	}
+	return nil
+}
+
+// ListSecrets is no supported
+func (*AWSKMSSecretStorage) ListSecrets() ([]string, error) {
+	return nil, secrets.ErrNotSupported
+}
+
+// Encrypt is no supported
+func (*AWSKMSSecretStorage) Encrypt(
+	secretID string,
+	plaintTextData string,
+	keyContext map[string]string,
+) (string, error) {
+	return "", secrets.ErrNotSupported
+}
+
+// Decrypt is no supported
+func (*AWSKMSSecretStorage) Decrypt(
+	secretID string,
+	encryptedData string,
+	keyContext map[string]string,
+) (string, error) {
+	return "", secrets.ErrNotSupported
+}
+
+// Rencrypt is no supported
+func (*AWSKMSSecretStorage) Rencrypt(
+	originalSecretID string,
+	newSecretID string,
+	originalKeyContext map[string]string,
+	newKeyContext map[string]string,
+	encryptedData string,
+) (string, error) {
+	return "", secrets.ErrNotSupported
+}
+
+// Register AWSKMSSecretStorage backend with libopenstorage secrets layer
+func init() {
+	if err := secrets.Register(AWSKMSSecretStorageName, NewAWSKMSSecretStorage); err != nil {
+		panic(err.Error())
+	}
+}
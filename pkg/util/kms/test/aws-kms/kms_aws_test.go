@@ -0,0 +1,171 @@
+package kmsawstest
+
+import (
+	"os"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/options"
+	"github.com/noobaa/noobaa-operator/v5/pkg/system"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util/kms"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func getMiniNooBaa() *nbv1.NooBaa {
+	options.MiniEnv = true
+	options.Namespace = corev1.NamespaceDefault
+	nb := system.LoadSystemDefaults()
+	return nb
+}
+
+func simpleKmsSpec(token, endpoint, keyID string) nbv1.KeyManagementServiceSpec {
+	k := nbv1.KeyManagementServiceSpec{}
+	k.TokenSecretName = token
+	k.ConnectionDetails = map[string]string{
+		kms.Provider:       kms.AWSKMSSecretStorageName,
+		kms.AWSKMSEndpoint: endpoint,
+		kms.AWSKMSKeyID:    keyID,
+		kms.AWSKMSRegion:   "us-east-1",
+	}
+
+	return k
+}
+
+// wrappedSecret returns the secret holding the root keys wrapped by the AWS KMS key
+func wrappedSecret(noobaa *nbv1.NooBaa) *corev1.Secret {
+	secret := &corev1.Secret{}
+	secret.Name = noobaa.Name + "-root-master-key-backend-aws-kms"
+	secret.Namespace = noobaa.Namespace
+	return secret
+}
+
+// verifyWrappedSecret checks that every root key exposed to the noobaa pods
+// is kept only in its wrapped form, and returns the number of root keys
+func verifyWrappedSecret(noobaa *nbv1.NooBaa, keyID string) int {
+	secret := wrappedSecret(noobaa)
+	Expect(util.KubeCheck(secret)).To(BeTrue())
+	Expect(secret.Annotations[kms.AWSKMSKeyIDAnnotation]).To(Equal(keyID))
+
+	volumeSecret := &corev1.Secret{}
+	volumeSecret.Name = noobaa.Name + "-root-master-key-volume"
+	volumeSecret.Namespace = noobaa.Namespace
+	Expect(util.KubeCheck(volumeSecret)).To(BeTrue())
+
+	Expect(secret.StringData[kms.ActiveRootKey]).To(Equal(volumeSecret.StringData[kms.ActiveRootKey]))
+	for k, v := range volumeSecret.StringData {
+		if k == kms.ActiveRootKey {
+			continue
+		}
+		wrapped, exists := secret.StringData[k]
+		Expect(exists).To(BeTrue())
+		Expect(wrapped).NotTo(Equal(v))
+	}
+	return len(secret.StringData) - 1
+}
+
+func restartOperator() {
+	podList := &corev1.PodList{}
+	podSelector, _ := labels.Parse("noobaa-operator=deployment")
+	listOptions := client.ListOptions{Namespace: options.Namespace, LabelSelector: podSelector}
+
+	Expect(util.KubeList(podList, &listOptions)).To(BeTrue())
+	Expect(len(podList.Items)).To(BeEquivalentTo(1))
+	Expect(util.KubeDelete(&podList.Items[0])).To(BeTrue())
+}
+
+var _ = Describe("KMS - AWS KMS", func() {
+
+	endpoint, endpointFound := os.LookupEnv(kms.AWSKMSEndpoint)
+	keyID, keyIDFound := os.LookupEnv(kms.AWSKMSKeyID)
+	tokenSecretName, tokenSecretNameFound := os.LookupEnv("TOKEN_SECRET_NAME")
+
+	Context("Verify AWS KMS NooBaa", func() {
+		noobaa := getMiniNooBaa()
+		noobaa.Spec.Security.KeyManagementService = simpleKmsSpec(tokenSecretName, endpoint, keyID)
+		Specify("Verify ENV", func() {
+			Expect(endpointFound).To(BeTrue())
+			logger.Printf("💬 Found %v=%v", kms.AWSKMSEndpoint, endpoint)
+			Expect(keyIDFound).To(BeTrue())
+			logger.Printf("💬 Found %v=%v", kms.AWSKMSKeyID, keyID)
+			Expect(tokenSecretNameFound).To(BeTrue())
+			logger.Printf("💬 Found TOKEN_SECRET_NAME=%v", tokenSecretName)
+			logger.Printf("💬 KMS Spec %v", noobaa.Spec.Security.KeyManagementService)
+		})
+		Specify("Create AWS KMS Noobaa", func() {
+			Expect(util.KubeCreateFailExisting(noobaa)).To(BeTrue())
+		})
+		Specify("Verify KMS condition Type", func() {
+			Expect(util.NooBaaCondition(noobaa, nbv1.ConditionTypeKMSType, kms.AWSKMSSecretStorageName)).To(BeTrue())
+		})
+		Specify("Verify KMS condition status Init", func() {
+			Expect(util.NooBaaCondStatus(noobaa, nbv1.ConditionKMSInit)).To(BeTrue())
+		})
+		Specify("Verify wrapped root key", func() {
+			Expect(verifyWrappedSecret(noobaa, keyID)).To(BeEquivalentTo(1))
+		})
+		Specify("Restart NooBaa operator", func() {
+			restartOperator()
+		})
+		Specify("Verify KMS condition status Sync", func() {
+			Expect(util.NooBaaCondStatus(noobaa, nbv1.ConditionKMSSync)).To(BeTrue())
+		})
+		Specify("Delete NooBaa", func() {
+			Expect(util.KubeDelete(noobaa)).To(BeTrue())
+		})
+		Specify("Verify wrapped root key deletion", func() {
+			Expect(util.KubeCheckQuiet(wrappedSecret(noobaa))).To(BeFalse())
+		})
+	})
+
+	Context("Invalid AWS KMS Configuration", func() {
+		Specify("Invalid Token Secret name", func() {
+			noobaa := getMiniNooBaa()
+			noobaa.Spec.Security.KeyManagementService = simpleKmsSpec("invalid", endpoint, keyID)
+			Expect(util.KubeCreateFailExisting(noobaa)).To(BeTrue())
+			Expect(util.NooBaaCondStatus(noobaa, nbv1.ConditionKMSInvalid)).To(BeTrue())
+			Expect(util.KubeDelete(noobaa)).To(BeTrue())
+		})
+		Specify("Missing AWS KMS key ID", func() {
+			noobaa := getMiniNooBaa()
+			noobaa.Spec.Security.KeyManagementService = simpleKmsSpec(tokenSecretName, endpoint, keyID)
+			delete(noobaa.Spec.Security.KeyManagementService.ConnectionDetails, kms.AWSKMSKeyID)
+			Expect(util.KubeCreateFailExisting(noobaa)).To(BeTrue())
+			Expect(util.NooBaaCondStatus(noobaa, nbv1.ConditionKMSInvalid)).To(BeTrue())
+			Expect(util.KubeDelete(noobaa)).To(BeTrue())
+		})
+		Specify("Unknown AWS KMS key ID", func() {
+			noobaa := getMiniNooBaa()
+			noobaa.Spec.Security.KeyManagementService = simpleKmsSpec(tokenSecretName, endpoint, "alias/missing")
+			Expect(util.KubeCreateFailExisting(noobaa)).To(BeTrue())
+			Expect(util.NooBaaCondStatus(noobaa, nbv1.ConditionKMSErrorWrite)).To(BeTrue())
+			Expect(util.KubeDelete(noobaa)).To(BeTrue())
+		})
+	})
+
+	Context("Verify Rotate", func() {
+		noobaa := getMiniNooBaa()
+		noobaa.Spec.Security.KeyManagementService = simpleKmsSpec(tokenSecretName, endpoint, keyID)
+		noobaa.Spec.Security.KeyManagementService.EnableKeyRotation = true
+		noobaa.Spec.Security.KeyManagementService.Schedule = "* * * * *" // every min
+
+		Specify("Create key rotate schedule system", func() {
+			Expect(util.KubeCreateFailExisting(noobaa)).To(BeTrue())
+		})
+		Specify("Verify KMS condition Type", func() {
+			Expect(util.NooBaaCondition(noobaa, nbv1.ConditionTypeKMSType, kms.AWSKMSSecretStorageName)).To(BeTrue())
+		})
+		Specify("Verify KMS condition status Key Rotate", func() {
+			Expect(util.NooBaaCondStatus(noobaa, nbv1.ConditionKMSKeyRotate)).To(BeTrue())
+		})
+		Specify("Verify rotated root keys are wrapped", func() {
+			Expect(verifyWrappedSecret(noobaa, keyID)).To(BeNumerically(">", 1))
+		})
+		Specify("Delete NooBaa", func() {
+			Expect(util.KubeDelete(noobaa)).To(BeTrue())
+		})
+	})
+})
@@ -45,6 +45,7 @@ import (
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	operv1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/robfig/cron/v3"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -764,6 +765,12 @@ func GetPodStatusLine(pod *corev1.Pod) string {
 
 // GetPodLogs info
 func GetPodLogs(pod corev1.Pod) (map[string]io.ReadCloser, error) {
+	return GetPodLogsWithOptions(pod, corev1.PodLogOptions{})
+}
+
+// GetPodLogsWithOptions info, logOpts are applied to the logs of every container,
+// e.g. SinceTime to limit the logs to a time window
+func GetPodLogsWithOptions(pod corev1.Pod, logOpts corev1.PodLogOptions) (map[string]io.ReadCloser, error) {
 	allContainers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
 	config := KubeConfig()
 	clientset, err := kubernetes.NewForConfig(config)
@@ -793,11 +800,14 @@ func GetPodLogs(pod corev1.Pod) (map[string]io.ReadCloser, error) {
 		}
 
 		// retrieve logs from a current instantiation of pod containers
-		podLogOpts := corev1.PodLogOptions{Container: container.Name}
+		podLogOpts := logOpts
+		podLogOpts.Container = container.Name
 		getPodLogOpts(&pod, &podLogOpts, nil)
 
 		// retrieve logs from a previous instantiation of pod containers
-		prevPodLogOpts := corev1.PodLogOptions{Container: container.Name, Previous: true}
+		prevPodLogOpts := logOpts
+		prevPodLogOpts.Container = container.Name
+		prevPodLogOpts.Previous = true
 		previousSuffix := "previous"
 		getPodLogOpts(&pod, &prevPodLogOpts, &previousSuffix)
 	}
@@ -1067,6 +1077,76 @@ func RandomHex(numBytes int) string {
 	return hex.EncodeToString(randomBytes)
 }
 
+// RandomS3Credentials creates a random access key and secret key
+// that match AccessKeyRegexp and SecretKeyRegexp
+func RandomS3Credentials() (string, string) {
+	// 32 chars so that every random byte maps to a char without bias
+	const accessKeyChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	randomBytes := make([]byte, 20)
+	_, err := rand.Read(randomBytes)
+	Panic(err)
+	for i, b := range randomBytes {
+		randomBytes[i] = accessKeyChars[int(b)%len(accessKeyChars)]
+	}
+	// 30 random bytes are encoded to exactly 40 base64 chars without padding
+	return string(randomBytes), RandomBase64(30)
+}
+
+// DefaultCredentialRotationGracePeriod is the grace period of a credentials rotation when it is not specified
+const DefaultCredentialRotationGracePeriod = 24 * time.Hour
+
+// CredentialRotationStep is the step of a scheduled credentials rotation
+type CredentialRotationStep string
+
+// These are the steps of a scheduled credentials rotation:
+const (
+	// CredentialRotationWait means nothing is due until the next step time
+	CredentialRotationWait CredentialRotationStep = "Wait"
+
+	// CredentialRotationStage means new credentials should be published next to the current ones
+	CredentialRotationStage CredentialRotationStep = "Stage"
+
+	// CredentialRotationActivate means the grace period ended and the new credentials should replace the current ones
+	CredentialRotationActivate CredentialRotationStep = "Activate"
+)
+
+// CredentialRotationGracePeriod returns the grace period of a credentials rotation, or the default when it is not set
+func CredentialRotationGracePeriod(gracePeriod *metav1.Duration) time.Duration {
+	if gracePeriod == nil {
+		return DefaultCredentialRotationGracePeriod
+	}
+	return gracePeriod.Duration
+}
+
+// NextCredentialRotationStep returns the step of a scheduled credentials rotation that is due at now,
+// and the time of the next step. The schedule counts from the since time, which is the last rotation,
+// or the creation time when the credentials were never rotated. A non zero pendingSince means new
+// credentials were staged and the rotation is in its grace period.
+func NextCredentialRotationStep(
+	schedule string,
+	gracePeriod time.Duration,
+	since time.Time,
+	pendingSince time.Time,
+	now time.Time,
+) (CredentialRotationStep, time.Time, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !pendingSince.IsZero() {
+		activateTime := pendingSince.Add(gracePeriod)
+		if now.Before(activateTime) {
+			return CredentialRotationWait, activateTime, nil
+		}
+		return CredentialRotationActivate, activateTime, nil
+	}
+	stageTime := sched.Next(since)
+	if now.Before(stageTime) {
+		return CredentialRotationWait, stageTime, nil
+	}
+	return CredentialRotationStage, stageTime, nil
+}
+
 // SetAvailableCondition updates the status conditions to available state
 func SetAvailableCondition(conditions *[]conditionsv1.Condition, reason string, message string) {
 	currentTime := metav1.NewTime(time.Now())
@@ -2571,6 +2651,14 @@ func SetNamespaceStoreSecretRef(ns *nbv1.NamespaceStore, ref *corev1.SecretRefer
 	}
 }
 
+// GetNooBaaRemoteNamespaceStoreName returns the name of the namespace store created for a bucket of a noobaa remote
+func GetNooBaaRemoteNamespaceStoreName(remote *nbv1.NooBaaRemote, bucket *nbv1.NooBaaRemoteBucket) string {
+	if bucket.NamespaceStore != "" {
+		return bucket.NamespaceStore
+	}
+	return remote.Name + "-" + bucket.Name
+}
+
 // GetNamespaceStoreTargetBucket returns the target bucket of the namespace store if it is relevant to the type
 func GetNamespaceStoreTargetBucket(ns *nbv1.NamespaceStore) (string, error) {
 	switch ns.Spec.Type {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -935,3 +936,87 @@ func TestIsTolerationSuperset(t *testing.T) {
 		})
 	}
 }
+
+func TestRandomS3Credentials(t *testing.T) {
+	accessKey, secretKey := RandomS3Credentials()
+	if !AccessKeyRegexp.MatchString(accessKey) {
+		t.Fatalf("access key %q does not match %s", accessKey, AccessKeyRegexp)
+	}
+	if !SecretKeyRegexp.MatchString(secretKey) {
+		t.Fatalf("secret key %q does not match %s", secretKey, SecretKeyRegexp)
+	}
+	otherAccessKey, _ := RandomS3Credentials()
+	if otherAccessKey == accessKey {
+		t.Fatalf("expected different access keys, got %q twice", accessKey)
+	}
+}
+
+func TestNextCredentialRotationStep(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	firstOfFeb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		since        time.Time
+		pendingSince time.Time
+		now          time.Time
+		expectedStep CredentialRotationStep
+		expectedTime time.Time
+	}{
+		{
+			name:         "wait for the schedule counted from creation",
+			since:        created,
+			now:          created.Add(time.Hour),
+			expectedStep: CredentialRotationWait,
+			expectedTime: firstOfFeb,
+		},
+		{
+			name:         "stage when the schedule is due",
+			since:        created,
+			now:          firstOfFeb.Add(time.Minute),
+			expectedStep: CredentialRotationStage,
+			expectedTime: firstOfFeb,
+		},
+		{
+			name:         "wait during the grace period",
+			since:        created,
+			pendingSince: firstOfFeb,
+			now:          firstOfFeb.Add(time.Hour),
+			expectedStep: CredentialRotationWait,
+			expectedTime: firstOfFeb.Add(24 * time.Hour),
+		},
+		{
+			name:         "activate when the grace period ends",
+			since:        created,
+			pendingSince: firstOfFeb,
+			now:          firstOfFeb.Add(25 * time.Hour),
+			expectedStep: CredentialRotationActivate,
+			expectedTime: firstOfFeb.Add(24 * time.Hour),
+		},
+		{
+			name:         "count the schedule from the last rotation",
+			since:        firstOfFeb.Add(24 * time.Hour),
+			now:          firstOfFeb.Add(48 * time.Hour),
+			expectedStep: CredentialRotationWait,
+			expectedTime: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			step, nextTime, err := NextCredentialRotationStep("0 0 1 * *", DefaultCredentialRotationGracePeriod, tc.since, tc.pendingSince, tc.now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if step != tc.expectedStep {
+				t.Fatalf("expected step %q, got %q", tc.expectedStep, step)
+			}
+			if !nextTime.Equal(tc.expectedTime) {
+				t.Fatalf("expected next time %v, got %v", tc.expectedTime, nextTime)
+			}
+		})
+	}
+
+	if _, _, err := NextCredentialRotationStep("not a schedule", time.Hour, created, time.Time{}, created); err == nil {
+		t.Fatalf("expected an error for an invalid schedule")
+	}
+}
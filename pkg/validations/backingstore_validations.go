@@ -48,6 +48,9 @@ func ValidateBackingStore(bs nbv1.BackingStore) error {
 		if err := ValidatePvpoolResources(bs); err != nil {
 			return err
 		}
+		if err := ValidatePvpoolDecommission(bs); err != nil {
+			return err
+		}
 	case nbv1.StoreTypeS3Compatible:
 		return ValidateSigVersion(bs.Spec.S3Compatible.SignatureVersion)
 	case nbv1.StoreTypeIBMCos:
@@ -300,11 +303,33 @@ func ValidatePvpoolResources(bs nbv1.BackingStore) error {
 	return nil
 }
 
-// ValidatePvpoolScaleDown validates an operation of scaling down node in pvpool backingstore
+// ValidatePvpoolDecommission validates the list of pods to decommission in pvpool backingstore
+func ValidatePvpoolDecommission(bs nbv1.BackingStore) error {
+	pods := map[string]bool{}
+	for _, podName := range bs.Spec.PVPool.Decommission {
+		if !strings.HasPrefix(podName, bs.Name+"-") {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Invalid pod %q to decommission, expected a pod of BackingStore %q", podName, bs.Name),
+			}
+		}
+		if pods[podName] {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Pod %q is listed more than once to decommission", podName),
+			}
+		}
+		pods[podName] = true
+	}
+	return nil
+}
+
+// ValidatePvpoolScaleDown validates an operation of scaling down node in pvpool backingstore.
+// Scaling down is allowed when the removed nodes are listed to decommission.
 func ValidatePvpoolScaleDown(bs nbv1.BackingStore, oldBs nbv1.BackingStore) error {
-	if oldBs.Spec.PVPool.NumVolumes > bs.Spec.PVPool.NumVolumes {
+	removed := oldBs.Spec.PVPool.NumVolumes - bs.Spec.PVPool.NumVolumes
+	if removed > len(bs.Spec.PVPool.Decommission) {
 		return util.ValidationError{
-			Msg: "Scaling down the number of nodes is not currently supported",
+			Msg: fmt.Sprintf("Scaling down the number of nodes from %d to %d requires decommissioning %d pods in spec.pvPool.decommission",
+				oldBs.Spec.PVPool.NumVolumes, bs.Spec.PVPool.NumVolumes, removed),
 		}
 	}
 	return nil
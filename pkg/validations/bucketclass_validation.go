@@ -29,6 +29,9 @@ func ValidateBucketClass(bc *nbv1.BucketClass) error {
 	if err := ValidateArchivePolicy(bc); err != nil {
 		return err
 	}
+	if err := ValidateLifecyclePolicy(bc); err != nil {
+		return err
+	}
 
 	return ValidateQuotaConfig(bc.Name, bc.Spec.Quota)
 }
@@ -85,6 +88,52 @@ func ValidateArchivePolicyNS(bc *nbv1.BucketClass) error {
 	return nil
 }
 
+// ValidateLifecyclePolicy validates the lifecyclePolicy field of a BucketClass.
+// It checks that the policy is not used with a vector bucket class, that every rule
+// has a unique ID and that every rule defines at least one action.
+func ValidateLifecyclePolicy(bc *nbv1.BucketClass) error {
+	if bc.Spec.LifecyclePolicy == nil {
+		return nil
+	}
+	if bc.Spec.VectorPolicy != nil {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("BucketClass %q lifecyclePolicy cannot be used together with vectorPolicy", bc.Name),
+		}
+	}
+	ids := map[string]bool{}
+	for i := range bc.Spec.LifecyclePolicy.Rules {
+		rule := &bc.Spec.LifecyclePolicy.Rules[i]
+		if rule.ID == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketClass %q lifecyclePolicy rule #%d must have an id", bc.Name, i),
+			}
+		}
+		if ids[rule.ID] {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketClass %q lifecyclePolicy rule id %q is not unique", bc.Name, rule.ID),
+			}
+		}
+		ids[rule.ID] = true
+		if rule.ExpirationDays < 0 || rule.NoncurrentVersionExpirationDays < 0 ||
+			rule.NewerNoncurrentVersions < 0 || rule.AbortIncompleteMultipartUploadDays < 0 {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketClass %q lifecyclePolicy rule %q must not have negative values", bc.Name, rule.ID),
+			}
+		}
+		if rule.NewerNoncurrentVersions > 0 && rule.NoncurrentVersionExpirationDays == 0 {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketClass %q lifecyclePolicy rule %q sets newerNoncurrentVersions without noncurrentVersionExpirationDays", bc.Name, rule.ID),
+			}
+		}
+		if rule.ExpirationDays == 0 && rule.NoncurrentVersionExpirationDays == 0 && rule.AbortIncompleteMultipartUploadDays == 0 {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketClass %q lifecyclePolicy rule %q must define at least one of expirationDays, noncurrentVersionExpirationDays or abortIncompleteMultipartUploadDays", bc.Name, rule.ID),
+			}
+		}
+	}
+	return nil
+}
+
 // ValidateImmutLabelChange validates that immutable labels are not changed
 func ValidateImmutLabelChange(bc *nbv1.BucketClass, oldBC *nbv1.BucketClass, immuts map[string]struct{}) error {
 	if bc == nil || oldBC == nil {
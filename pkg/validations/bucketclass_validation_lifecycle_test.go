@@ -0,0 +1,97 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+// TestValidateLifecyclePolicy verifies the lifecyclePolicy rules validation of a BucketClass.
+func TestValidateLifecyclePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *nbv1.LifecyclePolicy
+		vector  bool
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow when lifecyclePolicy is not set",
+			policy:  nil,
+			wantErr: false,
+		},
+		{
+			name:    "allow empty rules to remove the lifecycle configuration",
+			policy:  &nbv1.LifecyclePolicy{},
+			wantErr: false,
+		},
+		{
+			name: "allow expiration, noncurrent version expiration and abort multipart rules",
+			policy: &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{
+				{ID: "expire-logs", Prefix: "logs/", ExpirationDays: 30},
+				{ID: "noncurrent", NoncurrentVersionExpirationDays: 7, NewerNoncurrentVersions: 2},
+				{ID: "multipart", AbortIncompleteMultipartUploadDays: 1},
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "deny a rule without id",
+			policy:  &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{{ExpirationDays: 30}}},
+			wantErr: true,
+			errMsg:  "must have an id",
+		},
+		{
+			name: "deny duplicate rule ids",
+			policy: &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{
+				{ID: "rule", ExpirationDays: 30},
+				{ID: "rule", AbortIncompleteMultipartUploadDays: 1},
+			}},
+			wantErr: true,
+			errMsg:  "is not unique",
+		},
+		{
+			name:    "deny a rule without any action",
+			policy:  &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{{ID: "rule", Prefix: "tmp/"}}},
+			wantErr: true,
+			errMsg:  "must define at least one of",
+		},
+		{
+			name:    "deny negative values",
+			policy:  &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{{ID: "rule", ExpirationDays: -1}}},
+			wantErr: true,
+			errMsg:  "must not have negative values",
+		},
+		{
+			name:    "deny newerNoncurrentVersions without noncurrentVersionExpirationDays",
+			policy:  &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{{ID: "rule", ExpirationDays: 1, NewerNoncurrentVersions: 3}}},
+			wantErr: true,
+			errMsg:  "without noncurrentVersionExpirationDays",
+		},
+		{
+			name:    "deny lifecyclePolicy on a vector bucket class",
+			policy:  &nbv1.LifecyclePolicy{Rules: []nbv1.LifecycleRule{{ID: "rule", ExpirationDays: 1}}},
+			vector:  true,
+			wantErr: true,
+			errMsg:  "cannot be used together with vectorPolicy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := &nbv1.BucketClass{Spec: nbv1.BucketClassSpec{LifecyclePolicy: tt.policy}}
+			bc.Name = "test-bucket-class"
+			if tt.vector {
+				bc.Spec.VectorPolicy = &nbv1.VectorPolicy{Resource: "nsfs", VectorDBType: nbv1.VectorDBTypeLance}
+			}
+			err := ValidateLifecyclePolicy(bc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLifecyclePolicy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("ValidateLifecyclePolicy() error = %v, want message containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
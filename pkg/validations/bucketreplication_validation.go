@@ -0,0 +1,65 @@
+package validations
+
+import (
+	"fmt"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+)
+
+// ValidateBucketReplication validates the spec of a BucketReplication.
+// It checks that exactly one source is set and that the rules have unique IDs and destinations.
+func ValidateBucketReplication(br *nbv1.BucketReplication) error {
+	if br == nil {
+		return nil
+	}
+
+	source := br.Spec.Source
+	if (source.BucketName == "") == (source.ObjectBucketClaim == "") {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("BucketReplication %q must set exactly one of source.bucketName or source.objectBucketClaim", br.Name),
+		}
+	}
+
+	if len(br.Spec.Rules) == 0 {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("BucketReplication %q must have at least one rule", br.Name),
+		}
+	}
+
+	ids := map[string]bool{}
+	for i := range br.Spec.Rules {
+		rule := &br.Spec.Rules[i]
+		if rule.RuleID == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketReplication %q rule #%d must have a ruleId", br.Name, i),
+			}
+		}
+		if ids[rule.RuleID] {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketReplication %q rule id %q is not unique", br.Name, rule.RuleID),
+			}
+		}
+		ids[rule.RuleID] = true
+		if rule.DestinationBucket == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketReplication %q rule %q must have a destinationBucket", br.Name, rule.RuleID),
+			}
+		}
+		if source.BucketName != "" && rule.DestinationBucket == source.BucketName {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("BucketReplication %q rule %q cannot replicate bucket %q to itself", br.Name, rule.RuleID, source.BucketName),
+			}
+		}
+	}
+
+	// azure log replication reads the logs from the storage account and does not need a logs bucket
+	logSource := br.Spec.LogReplicationSource
+	if logSource != nil && logSource.EndpointType != "AZURE" && logSource.LogsBucket == "" {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("BucketReplication %q logReplicationSource must have a logsBucket", br.Name),
+		}
+	}
+
+	return nil
+}
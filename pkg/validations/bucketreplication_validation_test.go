@@ -0,0 +1,117 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+// TestValidateBucketReplication verifies the spec validation of a BucketReplication.
+func TestValidateBucketReplication(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    nbv1.BucketReplicationSpec
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "allow a bucket source with rules",
+			spec: nbv1.BucketReplicationSpec{
+				Source: nbv1.BucketReplicationSource{BucketName: "src"},
+				Rules: []nbv1.BucketReplicationRule{
+					{RuleID: "r1", DestinationBucket: "dst1", Prefix: "a/"},
+					{RuleID: "r2", DestinationBucket: "dst2", SyncDeletions: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allow an obc source with azure log replication",
+			spec: nbv1.BucketReplicationSpec{
+				Source:               nbv1.BucketReplicationSource{ObjectBucketClaim: "my-obc"},
+				Rules:                []nbv1.BucketReplicationRule{{RuleID: "r1", DestinationBucket: "dst"}},
+				LogReplicationSource: &nbv1.LogReplicationSource{EndpointType: "AZURE"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "deny missing source",
+			spec: nbv1.BucketReplicationSpec{
+				Rules: []nbv1.BucketReplicationRule{{RuleID: "r1", DestinationBucket: "dst"}},
+			},
+			wantErr: true,
+			errMsg:  "exactly one of",
+		},
+		{
+			name: "deny both bucket and obc source",
+			spec: nbv1.BucketReplicationSpec{
+				Source: nbv1.BucketReplicationSource{BucketName: "src", ObjectBucketClaim: "my-obc"},
+				Rules:  []nbv1.BucketReplicationRule{{RuleID: "r1", DestinationBucket: "dst"}},
+			},
+			wantErr: true,
+			errMsg:  "exactly one of",
+		},
+		{
+			name:    "deny no rules",
+			spec:    nbv1.BucketReplicationSpec{Source: nbv1.BucketReplicationSource{BucketName: "src"}},
+			wantErr: true,
+			errMsg:  "at least one rule",
+		},
+		{
+			name: "deny duplicate rule ids",
+			spec: nbv1.BucketReplicationSpec{
+				Source: nbv1.BucketReplicationSource{BucketName: "src"},
+				Rules: []nbv1.BucketReplicationRule{
+					{RuleID: "r1", DestinationBucket: "dst1"},
+					{RuleID: "r1", DestinationBucket: "dst2"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "is not unique",
+		},
+		{
+			name: "deny a rule without destination",
+			spec: nbv1.BucketReplicationSpec{
+				Source: nbv1.BucketReplicationSource{BucketName: "src"},
+				Rules:  []nbv1.BucketReplicationRule{{RuleID: "r1"}},
+			},
+			wantErr: true,
+			errMsg:  "must have a destinationBucket",
+		},
+		{
+			name: "deny replicating a bucket to itself",
+			spec: nbv1.BucketReplicationSpec{
+				Source: nbv1.BucketReplicationSource{BucketName: "src"},
+				Rules:  []nbv1.BucketReplicationRule{{RuleID: "r1", DestinationBucket: "src"}},
+			},
+			wantErr: true,
+			errMsg:  "to itself",
+		},
+		{
+			name: "deny aws log replication without logs bucket",
+			spec: nbv1.BucketReplicationSpec{
+				Source:               nbv1.BucketReplicationSource{BucketName: "src"},
+				Rules:                []nbv1.BucketReplicationRule{{RuleID: "r1", DestinationBucket: "dst"}},
+				LogReplicationSource: &nbv1.LogReplicationSource{EndpointType: "AWS"},
+			},
+			wantErr: true,
+			errMsg:  "must have a logsBucket",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := &nbv1.BucketReplication{Spec: tt.spec}
+			br.Name = "test-bucket-replication"
+			err := ValidateBucketReplication(br)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBucketReplication() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("ValidateBucketReplication() error = %v, want message containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package validations
+
+import (
+	"fmt"
+	"time"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"github.com/robfig/cron/v3"
+)
+
+// ValidateCredentialRotation validates the cron schedule and grace period of a credentials rotation.
+// The grace period must end before the next rotation is due.
+func ValidateCredentialRotation(objectName string, schedule string, gracePeriod time.Duration) error {
+	if schedule == "" {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("%q credential rotation must have a schedule", objectName),
+		}
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("%q credential rotation schedule %q is invalid: %v", objectName, schedule, err),
+		}
+	}
+	if gracePeriod < 0 {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("%q credential rotation grace period %s must not be negative", objectName, gracePeriod),
+		}
+	}
+	next := sched.Next(time.Now())
+	if interval := sched.Next(next).Sub(next); gracePeriod >= interval {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("%q credential rotation grace period %s must be shorter than the schedule interval %s",
+				objectName, gracePeriod, interval),
+		}
+	}
+	return nil
+}
+
+// ValidateAccountCredentialRotation validates the credential rotation of a NooBaaAccount
+func ValidateAccountCredentialRotation(na nbv1.NooBaaAccount) error {
+	rotation := na.Spec.CredentialRotation
+	if rotation == nil {
+		return nil
+	}
+	return ValidateCredentialRotation(na.Name, rotation.Schedule, util.CredentialRotationGracePeriod(rotation.GracePeriod))
+}
@@ -0,0 +1,70 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestValidateCredentialRotation verifies the schedule and grace period validation of a credentials rotation.
+func TestValidateCredentialRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		schedule    string
+		gracePeriod time.Duration
+		wantErr     bool
+		errMsg      string
+	}{
+		{
+			name:        "allow a quarterly schedule with a day of grace",
+			schedule:    "0 0 1 */3 *",
+			gracePeriod: 24 * time.Hour,
+			wantErr:     false,
+		},
+		{
+			name:        "allow a schedule descriptor without grace period",
+			schedule:    "@weekly",
+			gracePeriod: 0,
+			wantErr:     false,
+		},
+		{
+			name:     "deny an empty schedule",
+			schedule: "",
+			wantErr:  true,
+			errMsg:   "must have a schedule",
+		},
+		{
+			name:     "deny an invalid schedule",
+			schedule: "every 90 days",
+			wantErr:  true,
+			errMsg:   "is invalid",
+		},
+		{
+			name:        "deny a negative grace period",
+			schedule:    "@monthly",
+			gracePeriod: -time.Hour,
+			wantErr:     true,
+			errMsg:      "must not be negative",
+		},
+		{
+			name:        "deny a grace period longer than the schedule interval",
+			schedule:    "@hourly",
+			gracePeriod: 24 * time.Hour,
+			wantErr:     true,
+			errMsg:      "must be shorter than the schedule interval",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCredentialRotation("test-account", tt.schedule, tt.gracePeriod)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCredentialRotation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("ValidateCredentialRotation() error = %v, want message containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
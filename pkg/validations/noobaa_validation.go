@@ -3,9 +3,16 @@ package validations
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/system"
 	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // ValidateNoobaaDeletion check the existence of AllowNoobaaDeletion in Noobaa CR
@@ -26,6 +33,21 @@ func ValidateNoobaaUpdate(nb nbv1.NooBaa) error {
 	if err := validateNoobaaLoadbalancerSourceSubnets(nb); err != nil {
 		return err
 	}
+	if err := validateDBBackup(nb); err != nil {
+		return err
+	}
+	if err := ValidateDBRecoverySpec(nb.Spec.DBSpec); err != nil {
+		return util.ValidationError{Msg: err.Error()}
+	}
+	if err := ValidateNetworkPolicySpec(nb.Spec.NetworkPolicy); err != nil {
+		return util.ValidationError{Msg: err.Error()}
+	}
+	if err := ValidateOIDCSpec(nb.Spec.Security.OIDC); err != nil {
+		return util.ValidationError{Msg: err.Error()}
+	}
+	if err := validateDBAutoGrow(nb); err != nil {
+		return err
+	}
 	return validateTLSSecurity(nb)
 }
 
@@ -37,9 +59,141 @@ func ValidateNoobaaCreation(nb nbv1.NooBaa) error {
 	if err := validateNoobaaLoadbalancerSourceSubnets(nb); err != nil {
 		return err
 	}
+	if err := validateDBBackup(nb); err != nil {
+		return err
+	}
+	if err := ValidateDBRecoverySpec(nb.Spec.DBSpec); err != nil {
+		return util.ValidationError{Msg: err.Error()}
+	}
+	if err := ValidateNetworkPolicySpec(nb.Spec.NetworkPolicy); err != nil {
+		return util.ValidationError{Msg: err.Error()}
+	}
+	if err := ValidateOIDCSpec(nb.Spec.Security.OIDC); err != nil {
+		return util.ValidationError{Msg: err.Error()}
+	}
+	if err := validateDBAutoGrow(nb); err != nil {
+		return err
+	}
 	return validateTLSSecurity(nb)
 }
 
+// validateDBBackup validates that the database backup configuration has exactly one backup method
+// and that an object store backup has a valid destination and endpoint
+func validateDBBackup(nb nbv1.NooBaa) error {
+	if nb.Spec.DBSpec == nil || nb.Spec.DBSpec.DBBackup == nil {
+		return nil
+	}
+	backupSpec := nb.Spec.DBSpec.DBBackup
+	if (backupSpec.VolumeSnapshot == nil) == (backupSpec.ObjectStore == nil) {
+		return util.ValidationError{
+			Msg: "DB backup must specify exactly one of volumeSnapshot or objectStore",
+		}
+	}
+	objectStore := backupSpec.ObjectStore
+	if objectStore == nil {
+		return nil
+	}
+	if !strings.HasPrefix(objectStore.DestinationPath, "s3://") || len(objectStore.DestinationPath) <= len("s3://") {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("Invalid DB backup object store destinationPath %q, expected s3://bucket/path", objectStore.DestinationPath),
+		}
+	}
+	if objectStore.CredentialsSecret == "" {
+		return util.ValidationError{
+			Msg: "DB backup object store must specify credentialsSecret",
+		}
+	}
+	if objectStore.Endpoint != "" {
+		u, err := url.Parse(objectStore.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Invalid DB backup object store endpoint %q, expected http(s)://host:port", objectStore.Endpoint),
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateDBRecoverySpec validates that the database recovery configuration has a source to recover from,
+// and that a recovery target has exactly one target and the WAL archive of the backup object store to replay
+func ValidateDBRecoverySpec(dbSpec *nbv1.NooBaaDBSpec) error {
+	if dbSpec == nil || dbSpec.DBRecovery == nil {
+		return nil
+	}
+	recoverySpec := dbSpec.DBRecovery
+	hasObjectStore := dbSpec.DBBackup != nil && dbSpec.DBBackup.ObjectStore != nil
+	if recoverySpec.VolumeSnapshotName == "" && !hasObjectStore {
+		return fmt.Errorf("DB recovery requires a volumeSnapshotName or a dbBackup.objectStore to recover from")
+	}
+	target := recoverySpec.RecoveryTarget
+	if target == nil {
+		return nil
+	}
+	if !hasObjectStore {
+		return fmt.Errorf("DB recovery to a recoveryTarget requires the WAL archive of dbBackup.objectStore")
+	}
+	numTargets := 0
+	for _, t := range []string{target.TargetTime, target.TargetLSN, target.TargetName} {
+		if t != "" {
+			numTargets++
+		}
+	}
+	if numTargets != 1 {
+		return fmt.Errorf("DB recovery target requires exactly one of targetTime, targetLSN or targetName")
+	}
+	if target.TargetTime != "" {
+		if _, err := time.Parse(time.RFC3339, target.TargetTime); err != nil {
+			return fmt.Errorf("DB recovery targetTime %q is not in RFC 3339 format: %v", target.TargetTime, err)
+		}
+	}
+	if target.BackupID != "" && recoverySpec.VolumeSnapshotName != "" {
+		return fmt.Errorf("DB recovery target backupID cannot be used when recovering from a volume snapshot")
+	}
+	return nil
+}
+
+// validateDBAutoGrow validates that the database volume auto grow policy has a valid threshold,
+// growth step and a maximum size that is not below the initial volume size
+func validateDBAutoGrow(nb nbv1.NooBaa) error {
+	if nb.Spec.DBSpec == nil || nb.Spec.DBSpec.DBAutoGrow == nil {
+		return nil
+	}
+	autoGrow := nb.Spec.DBSpec.DBAutoGrow
+	if autoGrow.ThresholdPercent != 0 && (autoGrow.ThresholdPercent < 50 || autoGrow.ThresholdPercent > 95) {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("Invalid DB auto grow thresholdPercent %d, expected a value between 50 and 95", autoGrow.ThresholdPercent),
+		}
+	}
+	if autoGrow.GrowthStep != "" {
+		if percent, isPercent := strings.CutSuffix(autoGrow.GrowthStep, "%"); isPercent {
+			if value, err := strconv.Atoi(percent); err != nil || value <= 0 {
+				return util.ValidationError{
+					Msg: fmt.Sprintf("Invalid DB auto grow growthStep %q, expected a positive percentage", autoGrow.GrowthStep),
+				}
+			}
+		} else if step, err := resource.ParseQuantity(autoGrow.GrowthStep); err != nil || step.Sign() <= 0 {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Invalid DB auto grow growthStep %q, expected a percentage or a positive quantity", autoGrow.GrowthStep),
+			}
+		}
+	}
+	maxSize, err := resource.ParseQuantity(autoGrow.MaxSize)
+	if err != nil {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("Invalid DB auto grow maxSize %q: %v", autoGrow.MaxSize, err),
+		}
+	}
+	if nb.Spec.DBSpec.DBMinVolumeSize != "" {
+		minSize, err := resource.ParseQuantity(nb.Spec.DBSpec.DBMinVolumeSize)
+		if err == nil && maxSize.Cmp(minSize) < 0 {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("DB auto grow maxSize %s is smaller than dbMinVolumeSize %s", autoGrow.MaxSize, nb.Spec.DBSpec.DBMinVolumeSize),
+			}
+		}
+	}
+	return nil
+}
+
 func validateTLSSecurity(nb nbv1.NooBaa) error {
 	if nb.Spec.Security.APIServerSecurity == nil {
 		return nil
@@ -97,5 +251,110 @@ func validateNoobaaAutoscalerConfig(nb nbv1.NooBaa) error {
 			Msg: fmt.Sprintf("Autoscaler %s missing prometheusNamespace property", nb.Spec.Autoscaler.AutoscalerType),
 		}
 	}
+	if err := ValidateAutoscalerMetrics(nb.Spec.Autoscaler.Metrics); err != nil {
+		return util.ValidationError{
+			Msg: err.Error(),
+		}
+	}
+	return nil
+}
+
+// ValidateAutoscalerMetrics validates that every autoscaler metric has a valid target and appears once
+func ValidateAutoscalerMetrics(metrics []nbv1.AutoscalerMetric) error {
+	seen := map[nbv1.AutoscalerMetricType]bool{}
+	for _, metric := range metrics {
+		if seen[metric.Type] {
+			return fmt.Errorf("autoscaler metric %s is specified more than once", metric.Type)
+		}
+		seen[metric.Type] = true
+		if _, err := system.ParseAutoscalerMetricTarget(metric); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateNetworkPolicySpec validates the namespaces and ports of the additional ingress rules,
+// and the CIDRs and hostnames of the egress allow-list
+func ValidateNetworkPolicySpec(spec *nbv1.NetworkPolicySpec) error {
+	if spec == nil {
+		return nil
+	}
+	for _, rule := range spec.AdditionalIngress {
+		if len(rule.Ports) == 0 {
+			return fmt.Errorf("network policy ingress rule of %s must have at least one port", rule.Component)
+		}
+		for _, namespace := range rule.Namespaces {
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				return fmt.Errorf("network policy ingress namespace %q is invalid: %s", namespace, strings.Join(errs, ", "))
+			}
+		}
+	}
+	if spec.Egress == nil {
+		return nil
+	}
+	for _, cidr := range spec.Egress.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("network policy egress allowed CIDR %q is invalid: %v", cidr, err)
+		}
+	}
+	for _, host := range spec.Egress.AllowedHosts {
+		if net.ParseIP(host) != nil {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+			return fmt.Errorf("network policy egress allowed host %q is invalid, expected a hostname without a scheme or port: %s",
+				host, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateOIDCSpec validates the OIDC providers of the STS service
+func ValidateOIDCSpec(spec *nbv1.OIDCSpec) error {
+	if spec == nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, provider := range spec.Providers {
+		if provider.Name == "" {
+			return fmt.Errorf("OIDC provider name is required")
+		}
+		if names[provider.Name] {
+			return fmt.Errorf("OIDC provider %q is specified more than once", provider.Name)
+		}
+		names[provider.Name] = true
+
+		if provider.Issuer == "" && provider.Type != nbv1.OIDCProviderKubernetes {
+			return fmt.Errorf("OIDC provider %q of type %s requires an issuer", provider.Name, provider.Type)
+		}
+		for _, address := range []string{provider.Issuer, provider.JWKSURI, provider.IntrospectionEndpoint} {
+			if address == "" {
+				continue
+			}
+			u, err := url.Parse(address)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("OIDC provider %q address %q must be an http(s) URL", provider.Name, address)
+			}
+		}
+
+		if provider.Validation == nbv1.OIDCTokenValidationIntrospection {
+			if provider.Type != nbv1.OIDCProviderKeycloak && provider.Type != nbv1.OIDCProviderGeneric {
+				return fmt.Errorf("OIDC provider %q of type %s does not support introspection, use jwks validation", provider.Name, provider.Type)
+			}
+			if provider.ClientSecret == nil || provider.ClientSecret.Name == "" {
+				return fmt.Errorf("OIDC provider %q requires a clientSecret for introspection", provider.Name)
+			}
+		}
+
+		if len(provider.ClaimMappings) == 0 {
+			return fmt.Errorf("OIDC provider %q requires at least one claim mapping", provider.Name)
+		}
+		for _, mapping := range provider.ClaimMappings {
+			if mapping.Value == "" || mapping.Account == "" {
+				return fmt.Errorf("OIDC provider %q claim mapping requires a value and an account", provider.Name)
+			}
+		}
+	}
 	return nil
 }
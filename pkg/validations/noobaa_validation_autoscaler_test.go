@@ -0,0 +1,83 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+// TestValidateNoobaaAutoscalerConfig verifies the validation of the endpoints autoscaler metrics.
+func TestValidateNoobaaAutoscalerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics []nbv1.AutoscalerMetric
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow default metrics",
+			wantErr: false,
+		},
+		{
+			name: "allow all metric types",
+			metrics: []nbv1.AutoscalerMetric{
+				{Type: nbv1.AutoscalerMetricCPU, Target: "70"},
+				{Type: nbv1.AutoscalerMetricRequestsPerSecond, Target: "500"},
+				{Type: nbv1.AutoscalerMetricLatencyP95, Target: "250ms"},
+				{Type: nbv1.AutoscalerMetricInflightOps, Target: "64"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "deny cpu target that is not a percent",
+			metrics: []nbv1.AutoscalerMetric{{Type: nbv1.AutoscalerMetricCPU, Target: "80%"}},
+			wantErr: true,
+			errMsg:  "positive percent",
+		},
+		{
+			name:    "deny latency target without a unit",
+			metrics: []nbv1.AutoscalerMetric{{Type: nbv1.AutoscalerMetricLatencyP95, Target: "250"}},
+			wantErr: true,
+			errMsg:  "positive duration",
+		},
+		{
+			name:    "deny zero requests per second target",
+			metrics: []nbv1.AutoscalerMetric{{Type: nbv1.AutoscalerMetricRequestsPerSecond, Target: "0"}},
+			wantErr: true,
+			errMsg:  "positive number",
+		},
+		{
+			name: "deny duplicate metric types",
+			metrics: []nbv1.AutoscalerMetric{
+				{Type: nbv1.AutoscalerMetricInflightOps, Target: "64"},
+				{Type: nbv1.AutoscalerMetricInflightOps, Target: "32"},
+			},
+			wantErr: true,
+			errMsg:  "more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nb := nbv1.NooBaa{Spec: nbv1.NooBaaSpec{Autoscaler: nbv1.AutoscalerSpec{
+				AutoscalerType:      nbv1.AutoscalerTypeKeda,
+				PrometheusNamespace: "openshift-monitoring",
+				Metrics:             tt.metrics,
+			}}}
+			err := validateNoobaaAutoscalerConfig(nb)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+				}
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
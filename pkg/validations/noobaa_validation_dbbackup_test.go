@@ -0,0 +1,225 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+// TestValidateDBBackup verifies the validation of the database backup configuration.
+func TestValidateDBBackup(t *testing.T) {
+	objectStoreBackup := func() *nbv1.DBBackupSpec {
+		return &nbv1.DBBackupSpec{
+			Schedule: "0 1 * * *",
+			ObjectStore: &nbv1.ObjectStoreBackupSpec{
+				DestinationPath:   "s3://noobaa-db-backups/cluster-a",
+				Endpoint:          "https://s3.cluster-b.example.com",
+				CredentialsSecret: "db-backup-creds",
+				RetentionPolicy:   "30d",
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		mutate  func(spec *nbv1.DBBackupSpec)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow object store backup",
+			mutate:  func(spec *nbv1.DBBackupSpec) {},
+			wantErr: false,
+		},
+		{
+			name:    "allow object store backup on aws without endpoint",
+			mutate:  func(spec *nbv1.DBBackupSpec) { spec.ObjectStore.Endpoint = "" },
+			wantErr: false,
+		},
+		{
+			name: "allow volume snapshot backup",
+			mutate: func(spec *nbv1.DBBackupSpec) {
+				spec.ObjectStore = nil
+				spec.VolumeSnapshot = &nbv1.VolumeSnapshotBackupSpec{VolumeSnapshotClass: "csi-snapclass", MaxSnapshots: 3}
+			},
+			wantErr: false,
+		},
+		{
+			name:    "deny backup without a method",
+			mutate:  func(spec *nbv1.DBBackupSpec) { spec.ObjectStore = nil },
+			wantErr: true,
+			errMsg:  "exactly one",
+		},
+		{
+			name: "deny backup with both methods",
+			mutate: func(spec *nbv1.DBBackupSpec) {
+				spec.VolumeSnapshot = &nbv1.VolumeSnapshotBackupSpec{VolumeSnapshotClass: "csi-snapclass", MaxSnapshots: 3}
+			},
+			wantErr: true,
+			errMsg:  "exactly one",
+		},
+		{
+			name:    "deny destination path without s3 scheme",
+			mutate:  func(spec *nbv1.DBBackupSpec) { spec.ObjectStore.DestinationPath = "noobaa-db-backups/cluster-a" },
+			wantErr: true,
+			errMsg:  "destinationPath",
+		},
+		{
+			name:    "deny missing credentials secret",
+			mutate:  func(spec *nbv1.DBBackupSpec) { spec.ObjectStore.CredentialsSecret = "" },
+			wantErr: true,
+			errMsg:  "credentialsSecret",
+		},
+		{
+			name:    "deny endpoint without scheme",
+			mutate:  func(spec *nbv1.DBBackupSpec) { spec.ObjectStore.Endpoint = "s3.cluster-b.example.com" },
+			wantErr: true,
+			errMsg:  "endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backupSpec := objectStoreBackup()
+			tt.mutate(backupSpec)
+			nb := nbv1.NooBaa{Spec: nbv1.NooBaaSpec{DBSpec: &nbv1.NooBaaDBSpec{DBBackup: backupSpec}}}
+			err := validateDBBackup(nb)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+				}
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestValidateDBRecoverySpec verifies the validation of the database recovery source and target
+func TestValidateDBRecoverySpec(t *testing.T) {
+	objectStoreBackup := &nbv1.DBBackupSpec{
+		Schedule:    "0 1 * * *",
+		ObjectStore: &nbv1.ObjectStoreBackupSpec{DestinationPath: "s3://backups/db", CredentialsSecret: "creds"},
+	}
+	snapshotBackup := &nbv1.DBBackupSpec{
+		Schedule:       "0 1 * * *",
+		VolumeSnapshot: &nbv1.VolumeSnapshotBackupSpec{VolumeSnapshotClass: "csi-snapclass", MaxSnapshots: 3},
+	}
+	tests := []struct {
+		name     string
+		backup   *nbv1.DBBackupSpec
+		recovery *nbv1.DBRecoverySpec
+		errMsg   string
+	}{
+		{"no recovery", snapshotBackup, nil, ""},
+		{"volume snapshot", snapshotBackup, &nbv1.DBRecoverySpec{VolumeSnapshotName: "snap-1"}, ""},
+		{"object store", objectStoreBackup, &nbv1.DBRecoverySpec{}, ""},
+		{"object store to time", objectStoreBackup, &nbv1.DBRecoverySpec{
+			RecoveryTarget: &nbv1.DBRecoveryTargetSpec{TargetTime: "2026-01-13T08:50:00Z", BackupID: "20260113T010002"},
+		}, ""},
+		{"volume snapshot to lsn", objectStoreBackup, &nbv1.DBRecoverySpec{
+			VolumeSnapshotName: "snap-1",
+			RecoveryTarget:     &nbv1.DBRecoveryTargetSpec{TargetLSN: "0/3000060"},
+		}, ""},
+		{"no source", snapshotBackup, &nbv1.DBRecoverySpec{}, "to recover from"},
+		{"target without wal archive", snapshotBackup, &nbv1.DBRecoverySpec{
+			VolumeSnapshotName: "snap-1",
+			RecoveryTarget:     &nbv1.DBRecoveryTargetSpec{TargetName: "before-delete"},
+		}, "WAL archive"},
+		{"no target", objectStoreBackup, &nbv1.DBRecoverySpec{RecoveryTarget: &nbv1.DBRecoveryTargetSpec{}}, "exactly one"},
+		{"two targets", objectStoreBackup, &nbv1.DBRecoverySpec{
+			RecoveryTarget: &nbv1.DBRecoveryTargetSpec{TargetName: "before-delete", TargetLSN: "0/3000060"},
+		}, "exactly one"},
+		{"bad target time", objectStoreBackup, &nbv1.DBRecoverySpec{
+			RecoveryTarget: &nbv1.DBRecoveryTargetSpec{TargetTime: "2026-01-13 08:50"},
+		}, "RFC 3339"},
+		{"backup id with volume snapshot", objectStoreBackup, &nbv1.DBRecoverySpec{
+			VolumeSnapshotName: "snap-1",
+			RecoveryTarget:     &nbv1.DBRecoveryTargetSpec{TargetName: "before-delete", BackupID: "20260113T010002"},
+		}, "backupID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDBRecoverySpec(&nbv1.NooBaaDBSpec{DBBackup: tt.backup, DBRecovery: tt.recovery})
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+// TestValidateDBAutoGrow verifies the validation of the database volume auto grow policy.
+func TestValidateDBAutoGrow(t *testing.T) {
+	tests := []struct {
+		name     string
+		autoGrow nbv1.DBAutoGrowSpec
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "allow defaults with max size",
+			autoGrow: nbv1.DBAutoGrowSpec{MaxSize: "200Gi"},
+			wantErr:  false,
+		},
+		{
+			name:     "allow quantity growth step",
+			autoGrow: nbv1.DBAutoGrowSpec{ThresholdPercent: 90, GrowthStep: "10Gi", MaxSize: "200Gi"},
+			wantErr:  false,
+		},
+		{
+			name:     "deny threshold out of range",
+			autoGrow: nbv1.DBAutoGrowSpec{ThresholdPercent: 99, MaxSize: "200Gi"},
+			wantErr:  true,
+			errMsg:   "thresholdPercent",
+		},
+		{
+			name:     "deny zero percent growth step",
+			autoGrow: nbv1.DBAutoGrowSpec{GrowthStep: "0%", MaxSize: "200Gi"},
+			wantErr:  true,
+			errMsg:   "growthStep",
+		},
+		{
+			name:     "deny invalid max size",
+			autoGrow: nbv1.DBAutoGrowSpec{MaxSize: "lots"},
+			wantErr:  true,
+			errMsg:   "maxSize",
+		},
+		{
+			name:     "deny max size below the initial volume size",
+			autoGrow: nbv1.DBAutoGrowSpec{MaxSize: "20Gi"},
+			wantErr:  true,
+			errMsg:   "dbMinVolumeSize",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			autoGrow := tt.autoGrow
+			nb := nbv1.NooBaa{Spec: nbv1.NooBaaSpec{DBSpec: &nbv1.NooBaaDBSpec{DBMinVolumeSize: "50Gi", DBAutoGrow: &autoGrow}}}
+			err := validateDBAutoGrow(nb)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+				}
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
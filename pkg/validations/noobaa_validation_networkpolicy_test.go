@@ -0,0 +1,92 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TestValidateNetworkPolicy verifies the validation of the network policy customization.
+func TestValidateNetworkPolicy(t *testing.T) {
+	port := intstr.FromInt32(9100)
+	tests := []struct {
+		name    string
+		spec    *nbv1.NetworkPolicySpec
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow unset network policy",
+			wantErr: false,
+		},
+		{
+			name: "allow ingress rule and egress allow-list",
+			spec: &nbv1.NetworkPolicySpec{
+				AdditionalIngress: []nbv1.NetworkPolicyIngressRule{{
+					Component:  nbv1.NetworkPolicyComponentEndpoint,
+					Namespaces: []string{"monitoring"},
+					Ports:      []networkingv1.NetworkPolicyPort{{Port: &port}},
+				}},
+				Egress: &nbv1.NetworkPolicyEgressSpec{
+					DenyByDefault: true,
+					AllowedCIDRs:  []string{"10.0.0.0/8", "2001:db8::/32"},
+					AllowedHosts:  []string{"s3.example.com", "192.0.2.10"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "deny ingress rule without ports",
+			spec: &nbv1.NetworkPolicySpec{
+				AdditionalIngress: []nbv1.NetworkPolicyIngressRule{{Component: nbv1.NetworkPolicyComponentCore}},
+			},
+			wantErr: true,
+			errMsg:  "at least one port",
+		},
+		{
+			name: "deny invalid namespace name",
+			spec: &nbv1.NetworkPolicySpec{
+				AdditionalIngress: []nbv1.NetworkPolicyIngressRule{{
+					Component:  nbv1.NetworkPolicyComponentCore,
+					Namespaces: []string{"Monitoring_NS"},
+					Ports:      []networkingv1.NetworkPolicyPort{{Port: &port}},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "namespace",
+		},
+		{
+			name:    "deny invalid CIDR",
+			spec:    &nbv1.NetworkPolicySpec{Egress: &nbv1.NetworkPolicyEgressSpec{AllowedCIDRs: []string{"10.0.0.0/33"}}},
+			wantErr: true,
+			errMsg:  "allowed CIDR",
+		},
+		{
+			name:    "deny host with a scheme",
+			spec:    &nbv1.NetworkPolicySpec{Egress: &nbv1.NetworkPolicyEgressSpec{AllowedHosts: []string{"https://s3.example.com"}}},
+			wantErr: true,
+			errMsg:  "allowed host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNetworkPolicySpec(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+				}
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestValidateOIDC verifies the validation of the declarative OIDC providers.
+func TestValidateOIDC(t *testing.T) {
+	mappings := []nbv1.OIDCClaimMapping{{Value: "system:serviceaccount:apps:*", Account: "apps"}}
+	tests := []struct {
+		name    string
+		spec    *nbv1.OIDCSpec
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow unset OIDC",
+			wantErr: false,
+		},
+		{
+			name: "allow kubernetes, dex and introspection providers",
+			spec: &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+				{Name: "cluster", Type: nbv1.OIDCProviderKubernetes, ClaimMappings: mappings},
+				{Name: "dex", Type: nbv1.OIDCProviderDex, Issuer: "https://dex.example.com", ClaimMappings: mappings},
+				{
+					Name: "idp", Type: nbv1.OIDCProviderGeneric, Issuer: "https://idp.example.com",
+					Validation:    nbv1.OIDCTokenValidationIntrospection,
+					ClientSecret:  &corev1.SecretReference{Name: "idp-client"},
+					ClaimMappings: mappings,
+				},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "deny duplicate provider names",
+			spec: &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+				{Name: "cluster", Type: nbv1.OIDCProviderKubernetes, ClaimMappings: mappings},
+				{Name: "cluster", Type: nbv1.OIDCProviderKubernetes, ClaimMappings: mappings},
+			}},
+			wantErr: true,
+			errMsg:  "more than once",
+		},
+		{
+			name: "deny missing issuer",
+			spec: &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+				{Name: "dex", Type: nbv1.OIDCProviderDex, ClaimMappings: mappings},
+			}},
+			wantErr: true,
+			errMsg:  "requires an issuer",
+		},
+		{
+			name: "deny issuer that is not a URL",
+			spec: &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+				{Name: "dex", Type: nbv1.OIDCProviderDex, Issuer: "dex.example.com", ClaimMappings: mappings},
+			}},
+			wantErr: true,
+			errMsg:  "http(s) URL",
+		},
+		{
+			name: "deny introspection of a dex provider",
+			spec: &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+				{
+					Name: "dex", Type: nbv1.OIDCProviderDex, Issuer: "https://dex.example.com",
+					Validation:    nbv1.OIDCTokenValidationIntrospection,
+					ClientSecret:  &corev1.SecretReference{Name: "dex-client"},
+					ClaimMappings: mappings,
+				},
+			}},
+			wantErr: true,
+			errMsg:  "does not support introspection",
+		},
+		{
+			name: "deny introspection without a client secret",
+			spec: &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+				{
+					Name: "idp", Type: nbv1.OIDCProviderKeycloak, Issuer: "https://idp.example.com",
+					Validation: nbv1.OIDCTokenValidationIntrospection, ClaimMappings: mappings,
+				},
+			}},
+			wantErr: true,
+			errMsg:  "requires a clientSecret",
+		},
+		{
+			name: "deny claim mapping without an account",
+			spec: &nbv1.OIDCSpec{Providers: []nbv1.OIDCProviderSpec{
+				{Name: "cluster", Type: nbv1.OIDCProviderKubernetes, ClaimMappings: []nbv1.OIDCClaimMapping{{Value: "*"}}},
+			}},
+			wantErr: true,
+			errMsg:  "value and an account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOIDCSpec(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+				}
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
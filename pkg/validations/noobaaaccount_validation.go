@@ -2,6 +2,9 @@ package validations
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"strings"
 
 	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
 	"github.com/noobaa/noobaa-operator/v5/pkg/options"
@@ -9,6 +12,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var stsRoleNameRegex = regexp.MustCompile(`^[\w+=,.@-]{1,64}$`)
+
 // ValidateAccountDefaultResource is the public entry point for validating that a NooBaaAccount's
 // DefaultResource is not an archive NamespaceStore.
 func ValidateAccountDefaultResource(na nbv1.NooBaaAccount) error {
@@ -67,6 +72,94 @@ func ValidateNSFSConfig(na nbv1.NooBaaAccount) error {
 	return nil
 }
 
+// ValidateAccountPermissions validates the buckets and source CIDRs of the account permissions
+func ValidateAccountPermissions(na nbv1.NooBaaAccount) error {
+	perms := na.Spec.Permissions
+	if perms == nil {
+		return nil
+	}
+
+	buckets := map[string]bool{}
+	for i := range perms.Buckets {
+		p := &perms.Buckets[i]
+		if p.BucketName == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Account %q permission #%d must have a bucket_name", na.Name, i),
+			}
+		}
+		if buckets[p.BucketName] {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Account %q has more than one permission for bucket %q", na.Name, p.BucketName),
+			}
+		}
+		buckets[p.BucketName] = true
+		switch p.Access {
+		case "", nbv1.BucketAccessReadOnly, nbv1.BucketAccessReadWrite:
+		default:
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Account %q permission for bucket %q has invalid access %q, expected %q or %q",
+					na.Name, p.BucketName, p.Access, nbv1.BucketAccessReadOnly, nbv1.BucketAccessReadWrite),
+			}
+		}
+	}
+
+	for _, cidr := range perms.AllowedSourceCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Account %q allowed source CIDR %q is invalid: %v", na.Name, cidr, err),
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateAccountSTSRole validates the role name, trusted principals, actions and session duration of the account STS role
+func ValidateAccountSTSRole(na nbv1.NooBaaAccount) error {
+	role := na.Spec.STSRole
+	if role == nil {
+		return nil
+	}
+
+	if role.RoleName != "" && !stsRoleNameRegex.MatchString(role.RoleName) {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("Account %q STS role name %q is invalid, expected up to 64 alphanumeric or '+=,.@_-' characters",
+				na.Name, role.RoleName),
+		}
+	}
+
+	if len(role.TrustedPrincipals) == 0 {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("Account %q STS role must have at least one trusted principal", na.Name),
+		}
+	}
+	principals := map[string]bool{}
+	for _, principal := range role.TrustedPrincipals {
+		if principal == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Account %q STS role has an empty trusted principal", na.Name),
+			}
+		}
+		if principals[principal] {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Account %q STS role trusts principal %q more than once", na.Name, principal),
+			}
+		}
+		principals[principal] = true
+	}
+
+	for _, action := range role.AllowedActions {
+		if !strings.HasPrefix(action, "sts:") || len(action) == len("sts:") {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("Account %q STS role action %q is invalid, expected an sts: action such as sts:AssumeRole",
+					na.Name, action),
+			}
+		}
+	}
+
+	return nil
+}
+
 // checkResourceBackingStore checks if a resourceName exists and if BackingStore
 // returns true if the resource exists and is a BackingStore, and also returns the BackingStore object if it exists
 func checkResourceBackingStore(resourceName string) (bool, *nbv1.BackingStore) {
@@ -0,0 +1,81 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+// TestValidateAccountPermissions verifies the permissions validation of a NooBaaAccount.
+func TestValidateAccountPermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		perms   *nbv1.AccountPermissions
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow when permissions are not set",
+			perms:   nil,
+			wantErr: false,
+		},
+		{
+			name: "allow read-only, read-write and default access with valid CIDRs",
+			perms: &nbv1.AccountPermissions{
+				Buckets: []nbv1.AccountBucketPermission{
+					{BucketName: "b1"},
+					{BucketName: "b2", Access: nbv1.BucketAccessReadOnly},
+					{BucketName: "b3", Access: nbv1.BucketAccessReadWrite},
+				},
+				AllowedSourceCIDRs: []string{"10.0.0.0/8", "fd00::/8"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "deny a permission without bucket name",
+			perms:   &nbv1.AccountPermissions{Buckets: []nbv1.AccountBucketPermission{{Access: nbv1.BucketAccessReadWrite}}},
+			wantErr: true,
+			errMsg:  "must have a bucket_name",
+		},
+		{
+			name: "deny duplicate buckets",
+			perms: &nbv1.AccountPermissions{Buckets: []nbv1.AccountBucketPermission{
+				{BucketName: "b1"},
+				{BucketName: "b1", Access: nbv1.BucketAccessReadWrite},
+			}},
+			wantErr: true,
+			errMsg:  "more than one permission",
+		},
+		{
+			name:    "deny invalid access",
+			perms:   &nbv1.AccountPermissions{Buckets: []nbv1.AccountBucketPermission{{BucketName: "b1", Access: "admin"}}},
+			wantErr: true,
+			errMsg:  "invalid access",
+		},
+		{
+			name: "deny invalid CIDR",
+			perms: &nbv1.AccountPermissions{
+				Buckets:            []nbv1.AccountBucketPermission{{BucketName: "b1"}},
+				AllowedSourceCIDRs: []string{"10.0.0.1"},
+			},
+			wantErr: true,
+			errMsg:  "is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			na := nbv1.NooBaaAccount{Spec: nbv1.NooBaaAccountSpec{Permissions: tt.perms}}
+			na.Name = "test-account"
+			err := ValidateAccountPermissions(na)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAccountPermissions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("ValidateAccountPermissions() error = %v, want message containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
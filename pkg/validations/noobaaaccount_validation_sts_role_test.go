@@ -0,0 +1,72 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+)
+
+// TestValidateAccountSTSRole verifies the STS role validation of a NooBaaAccount.
+func TestValidateAccountSTSRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    *nbv1.AccountSTSRole
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow when the STS role is not set",
+			role:    nil,
+			wantErr: false,
+		},
+		{
+			name: "allow a role with name and actions",
+			role: &nbv1.AccountSTSRole{
+				RoleName:          "analytics-reader",
+				TrustedPrincipals: []string{"account1", "account2@example.com"},
+				AllowedActions:    []string{"sts:AssumeRole", "sts:AssumeRoleWithWebIdentity"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "deny a role without trusted principals",
+			role:    &nbv1.AccountSTSRole{},
+			wantErr: true,
+			errMsg:  "at least one trusted principal",
+		},
+		{
+			name:    "deny duplicate trusted principals",
+			role:    &nbv1.AccountSTSRole{TrustedPrincipals: []string{"account1", "account1"}},
+			wantErr: true,
+			errMsg:  "more than once",
+		},
+		{
+			name:    "deny an invalid role name",
+			role:    &nbv1.AccountSTSRole{RoleName: "my role", TrustedPrincipals: []string{"account1"}},
+			wantErr: true,
+			errMsg:  "role name",
+		},
+		{
+			name:    "deny a non STS action",
+			role:    &nbv1.AccountSTSRole{TrustedPrincipals: []string{"account1"}, AllowedActions: []string{"s3:GetObject"}},
+			wantErr: true,
+			errMsg:  "is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			na := nbv1.NooBaaAccount{Spec: nbv1.NooBaaAccountSpec{STSRole: tt.role}}
+			na.Name = "test-account"
+			err := ValidateAccountSTSRole(na)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAccountSTSRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errMsg != "" && (err == nil || !strings.Contains(err.Error(), tt.errMsg)) {
+				t.Errorf("ValidateAccountSTSRole() error = %v, want message containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package validations
+
+import (
+	"fmt"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var builtinPerformanceProfiles = []nbv1.PerformanceProfileType{
+	nbv1.PerformanceProfileDefault,
+	nbv1.PerformanceProfileMixedWorkload,
+	nbv1.PerformanceProfileSmallObjects,
+	nbv1.PerformanceProfileDevEnv,
+	nbv1.PerformanceProfileMiniEnv,
+}
+
+// ValidatePerformanceProfile validates a user defined NooBaaPerformanceProfile.
+// It checks that the name does not hide a built-in profile, that the counts are consistent
+// and that no resource request exceeds its limit.
+func ValidatePerformanceProfile(profile *nbv1.NooBaaPerformanceProfile) error {
+	if profile == nil {
+		return nil
+	}
+
+	for _, builtin := range builtinPerformanceProfiles {
+		if profile.Name == string(builtin) {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("NooBaaPerformanceProfile name %q is reserved for a built-in profile", profile.Name),
+			}
+		}
+	}
+
+	spec := &profile.Spec
+	if spec.EndpointMinCount < 0 || spec.EndpointMaxCount < 0 || spec.DBInstances < 0 || spec.PVPoolNumVolumes < 0 {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("NooBaaPerformanceProfile %q counts must not be negative", profile.Name),
+		}
+	}
+	if spec.EndpointMinCount > 0 && spec.EndpointMaxCount > 0 && spec.EndpointMinCount > spec.EndpointMaxCount {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("NooBaaPerformanceProfile %q endpointMinCount %d is higher than endpointMaxCount %d",
+				profile.Name, spec.EndpointMinCount, spec.EndpointMaxCount),
+		}
+	}
+
+	resources := []struct {
+		field string
+		rr    *corev1.ResourceRequirements
+	}{
+		{"coreResources", spec.CoreResources},
+		{"logResources", spec.LogResources},
+		{"dbResources", spec.DBResources},
+		{"endpointResources", spec.EndpointResources},
+		{"pvPoolResources", spec.PVPoolResources},
+	}
+	for _, r := range resources {
+		if r.rr == nil {
+			continue
+		}
+		for name, request := range r.rr.Requests {
+			limit, ok := r.rr.Limits[name]
+			if ok && request.Cmp(limit) > 0 {
+				return util.ValidationError{
+					Msg: fmt.Sprintf("NooBaaPerformanceProfile %q %s %s request %s is higher than the limit %s",
+						profile.Name, r.field, name, request.String(), limit.String()),
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestValidatePerformanceProfile verifies the validation of user defined performance profiles.
+func TestValidatePerformanceProfile(t *testing.T) {
+	resources := func(cpuReq, cpuLim string) *corev1.ResourceRequirements {
+		return &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuReq)},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuLim)},
+		}
+	}
+	tests := []struct {
+		name    string
+		profile nbv1.NooBaaPerformanceProfile
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "allow a full profile",
+			profile: nbv1.NooBaaPerformanceProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "large-nodes"},
+				Spec: nbv1.NooBaaPerformanceProfileSpec{
+					CoreResources:    resources("2", "4"),
+					DBResources:      resources("8", "8"),
+					EndpointMinCount: 3,
+					EndpointMaxCount: 10,
+					DBInstances:      3,
+					PVPoolNumVolumes: 4,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "allow an empty profile",
+			profile: nbv1.NooBaaPerformanceProfile{ObjectMeta: metav1.ObjectMeta{Name: "as-default"}},
+			wantErr: false,
+		},
+		{
+			name:    "deny a built-in profile name",
+			profile: nbv1.NooBaaPerformanceProfile{ObjectMeta: metav1.ObjectMeta{Name: "small-objects"}},
+			wantErr: true,
+			errMsg:  "reserved",
+		},
+		{
+			name: "deny endpoint min count higher than max count",
+			profile: nbv1.NooBaaPerformanceProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "large-nodes"},
+				Spec:       nbv1.NooBaaPerformanceProfileSpec{EndpointMinCount: 5, EndpointMaxCount: 2},
+			},
+			wantErr: true,
+			errMsg:  "endpointMinCount",
+		},
+		{
+			name: "deny request higher than limit",
+			profile: nbv1.NooBaaPerformanceProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "large-nodes"},
+				Spec:       nbv1.NooBaaPerformanceProfileSpec{EndpointResources: resources("4", "2")},
+			},
+			wantErr: true,
+			errMsg:  "endpointResources",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePerformanceProfile(&tt.profile)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+				}
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
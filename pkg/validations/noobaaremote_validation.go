@@ -0,0 +1,75 @@
+package validations
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	"github.com/noobaa/noobaa-operator/v5/pkg/util"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateNooBaaRemote validates the spec of a NooBaaRemote.
+// It checks the remote endpoints and credentials and that every bucket maps to a unique namespace store.
+func ValidateNooBaaRemote(remote *nbv1.NooBaaRemote) error {
+	if remote == nil {
+		return nil
+	}
+
+	endpoints := []struct{ field, value string }{
+		{"mgmtEndpoint", remote.Spec.MgmtEndpoint},
+		{"s3Endpoint", remote.Spec.S3Endpoint},
+	}
+	for _, e := range endpoints {
+		u, err := url.Parse(e.value)
+		if e.value == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("NooBaaRemote %q %s %q must be an http(s)://host:port url", remote.Name, e.field, e.value),
+			}
+		}
+	}
+
+	if remote.Spec.Secret.Name == "" {
+		return util.ValidationError{
+			Msg: fmt.Sprintf("NooBaaRemote %q must have a secret with the credentials of the remote system", remote.Name),
+		}
+	}
+
+	if err := ValidateSignatureVersion(remote.Spec.SignatureVersion, remote.Spec.S3Endpoint, remote.Name); err != nil {
+		return err
+	}
+
+	buckets := map[string]bool{}
+	namespaceStores := map[string]bool{}
+	for i := range remote.Spec.Buckets {
+		bucket := &remote.Spec.Buckets[i]
+		if bucket.Name == "" {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("NooBaaRemote %q bucket #%d must have a name", remote.Name, i),
+			}
+		}
+		if buckets[bucket.Name] {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("NooBaaRemote %q bucket %q is listed more than once", remote.Name, bucket.Name),
+			}
+		}
+		buckets[bucket.Name] = true
+
+		nsStoreName := util.GetNooBaaRemoteNamespaceStoreName(remote, bucket)
+		if errs := validation.IsDNS1123Subdomain(nsStoreName); len(errs) > 0 {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("NooBaaRemote %q bucket %q namespace store name %q is invalid: %s",
+					remote.Name, bucket.Name, nsStoreName, strings.Join(errs, ", ")),
+			}
+		}
+		if namespaceStores[nsStoreName] {
+			return util.ValidationError{
+				Msg: fmt.Sprintf("NooBaaRemote %q namespace store name %q is used by more than one bucket", remote.Name, nsStoreName),
+			}
+		}
+		namespaceStores[nsStoreName] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	nbv1 "github.com/noobaa/noobaa-operator/v5/pkg/apis/noobaa/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestValidateNooBaaRemote verifies the spec validation of a NooBaaRemote.
+func TestValidateNooBaaRemote(t *testing.T) {
+	validSpec := func() nbv1.NooBaaRemoteSpec {
+		return nbv1.NooBaaRemoteSpec{
+			MgmtEndpoint: "https://noobaa-mgmt.cluster-b.example.com:443",
+			S3Endpoint:   "https://s3.cluster-b.example.com",
+			Secret:       corev1.SecretReference{Name: "cluster-b-creds"},
+			Buckets: []nbv1.NooBaaRemoteBucket{
+				{Name: "shared"},
+				{Name: "archive", NamespaceStore: "archive-b", AccessMode: nbv1.AccessModeReadOnly},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		mutate  func(spec *nbv1.NooBaaRemoteSpec)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "allow a remote with buckets",
+			mutate:  func(spec *nbv1.NooBaaRemoteSpec) {},
+			wantErr: false,
+		},
+		{
+			name:    "allow a remote without buckets",
+			mutate:  func(spec *nbv1.NooBaaRemoteSpec) { spec.Buckets = nil },
+			wantErr: false,
+		},
+		{
+			name:    "deny missing mgmt endpoint",
+			mutate:  func(spec *nbv1.NooBaaRemoteSpec) { spec.MgmtEndpoint = "" },
+			wantErr: true,
+			errMsg:  "mgmtEndpoint",
+		},
+		{
+			name:    "deny s3 endpoint without scheme",
+			mutate:  func(spec *nbv1.NooBaaRemoteSpec) { spec.S3Endpoint = "s3.cluster-b.example.com" },
+			wantErr: true,
+			errMsg:  "s3Endpoint",
+		},
+		{
+			name:    "deny missing secret",
+			mutate:  func(spec *nbv1.NooBaaRemoteSpec) { spec.Secret = corev1.SecretReference{} },
+			wantErr: true,
+			errMsg:  "must have a secret",
+		},
+		{
+			name: "deny signature v4 on an http endpoint",
+			mutate: func(spec *nbv1.NooBaaRemoteSpec) {
+				spec.S3Endpoint = "http://s3.cluster-b.example.com"
+				spec.SignatureVersion = nbv1.S3SignatureVersionV4
+			},
+			wantErr: true,
+			errMsg:  "signature version",
+		},
+		{
+			name: "deny duplicate bucket",
+			mutate: func(spec *nbv1.NooBaaRemoteSpec) {
+				spec.Buckets = append(spec.Buckets, nbv1.NooBaaRemoteBucket{Name: "shared", NamespaceStore: "other"})
+			},
+			wantErr: true,
+			errMsg:  "more than once",
+		},
+		{
+			name: "deny namespace store name collision with a default name",
+			mutate: func(spec *nbv1.NooBaaRemoteSpec) {
+				spec.Buckets = append(spec.Buckets, nbv1.NooBaaRemoteBucket{Name: "other", NamespaceStore: "cluster-b-shared"})
+			},
+			wantErr: true,
+			errMsg:  "used by more than one bucket",
+		},
+		{
+			name: "deny invalid namespace store name",
+			mutate: func(spec *nbv1.NooBaaRemoteSpec) {
+				spec.Buckets = []nbv1.NooBaaRemoteBucket{{Name: "Upper_Case"}}
+			},
+			wantErr: true,
+			errMsg:  "is invalid",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			remote := &nbv1.NooBaaRemote{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-b"},
+				Spec:       validSpec(),
+			}
+			tc.mutate(&remote.Spec)
+			err := ValidateNooBaaRemote(remote)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if tc.errMsg != "" && !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected error containing %q, got %q", tc.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
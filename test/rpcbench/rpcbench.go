@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/noobaa/noobaa-operator/v5/pkg/nb"
 	"github.com/sirupsen/logrus"
+	"nhooyr.io/websocket"
 )
 
 var client = flag.Bool("client", false, "client option")
@@ -17,9 +22,22 @@ var proto = flag.String("proto", "wss", "protocol (ws/wss/http/https)")
 var wsize = flag.Int64("wsize", 1024*1024, "wsize option")
 var rsize = flag.Int64("rsize", 1024*1024, "rsize option")
 var concur = flag.Int("concur", 5, "concur option")
+var compress = flag.Bool("compress", false, "accept the websocket compression offered by the client (server only)")
+var duration = flag.Duration("duration", 0, "stop after duration, 0 runs until killed")
+
+// BenchParams are the params and the reply of rpcbench.io()
+type BenchParams struct {
+	Rsize int64 `json:"rsize"`
+	Wsize int64 `json:"wsize"`
+}
 
 // noobaa-core/     $ node src/rpc/rpc_benchmark.js --server
 // noobaa-operator/ $ go run test/rpcbench/rpcbench.go --client -v
+//
+// or without noobaa-core:
+//
+// noobaa-operator/ $ go run test/rpcbench/rpcbench.go --server --compress
+// noobaa-operator/ $ go run test/rpcbench/rpcbench.go --client --proto ws
 func main() {
 
 	flag.Parse()
@@ -32,17 +50,32 @@ func main() {
 		return
 	}
 
+	if *server && *client {
+		go ServerMain()
+		time.Sleep(100 * time.Millisecond)
+		ClientMain()
+		return
+	}
+
 	// client side
 	if *client {
 		ClientMain()
 	}
 
+	// server side
 	if *server {
-		fmt.Println("Server is not yet implemented. Sorry...")
-		return
+		ServerMain()
 	}
 }
 
+// stats are the counters of the client reported every second
+type stats struct {
+	ops     int64
+	errors  int64
+	bytes   int64
+	latency int64
+}
+
 // ClientMain handles client option
 func ClientMain() {
 
@@ -50,10 +83,13 @@ func ClientMain() {
 	fmt.Println(addr)
 
 	c := nb.NewClient(&nb.SimpleRouter{Address: addr})
-	type BenchParams struct {
-		Rsize int64 `json:"rsize"`
-		Wsize int64 `json:"wsize"`
+	wbuf := make([]byte, *wsize)
+	// a compressible payload, like the json and text of most bulk replies
+	for i := range wbuf {
+		wbuf[i] = byte('a' + i%16)
 	}
+
+	s := &stats{}
 	for i := 0; i < *concur; i++ {
 		go func() {
 			for {
@@ -64,20 +100,129 @@ func ClientMain() {
 						Rsize: *rsize,
 						Wsize: *wsize,
 					},
+					Buffers: []nb.RPCBuffer{{Name: "wbuf", Length: int32(len(wbuf)), Buffer: wbuf}},
 				}
 				res := &struct {
 					nb.RPCMessage `json:",inline"`
 					Reply         BenchParams `json:"reply"`
 				}{}
+				start := time.Now()
 				err := c.Call(req, res)
 				if err != nil {
+					atomic.AddInt64(&s.errors, 1)
 					logrus.Errorf("RPCBenchmark error: %v", err)
+					continue
 				}
+				size := *wsize
+				for _, b := range res.Buffers {
+					size += int64(len(b.Buffer))
+				}
+				atomic.AddInt64(&s.ops, 1)
+				atomic.AddInt64(&s.bytes, size)
+				atomic.AddInt64(&s.latency, int64(time.Since(start)))
+			}
+		}()
+	}
 
-				//fmt.logf("Made it %#v\n", res.Reply)
+	report(s)
+}
+
+// report prints the client stats every second until the duration ends
+func report(s *stats) {
+	var end <-chan time.Time
+	if *duration > 0 {
+		end = time.After(*duration)
+	}
+	start := time.Now()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var totalOps, totalBytes int64
+	for {
+		select {
+		case <-ticker.C:
+			ops := atomic.SwapInt64(&s.ops, 0)
+			bytes := atomic.SwapInt64(&s.bytes, 0)
+			latency := atomic.SwapInt64(&s.latency, 0)
+			errors := atomic.SwapInt64(&s.errors, 0)
+			totalOps += ops
+			totalBytes += bytes
+			avg := time.Duration(0)
+			if ops > 0 {
+				avg = time.Duration(latency / ops)
+			}
+			fmt.Printf("RPCBenchmark: %d ops/sec %.1f MB/sec avg latency %v errors %d\n",
+				ops, float64(bytes)/1024/1024, avg, errors)
+		case <-end:
+			took := time.Since(start).Seconds()
+			fmt.Printf("RPCBenchmark: total %.1f ops/sec %.1f MB/sec\n",
+				float64(totalOps)/took, float64(totalBytes)/1024/1024/took)
+			return
+		}
+	}
+}
+
+// ServerMain handles server option.
+// It serves rpcbench.io() over ws with the binary framing of nb.EncodeRPCFrame,
+// and replies a buffer of the requested rsize.
+func ServerMain() {
+	addr := fmt.Sprintf("%s:%s", *hostname, *port)
+	fmt.Printf("RPCBenchmark: server listening on ws://%s/rpc/\n", addr)
+	mode := websocket.CompressionDisabled
+	if *compress {
+		mode = websocket.CompressionNoContextTakeover
+	}
+	http.HandleFunc("/rpc/", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{CompressionMode: mode})
+		if err != nil {
+			logrus.Errorf("RPCBenchmark: accept error: %v", err)
+			return
+		}
+		ws.SetReadLimit(nb.RPCMaxMessageSize)
+		serveConn(r.Context(), ws)
+	})
+	logrus.Fatal(http.ListenAndServe(addr, nil))
+}
+
+// serveConn reads the requests of a connection and replies each one from its own goroutine,
+// so the replies of concurrent requests are multiplexed on the connection like in noobaa core
+func serveConn(ctx context.Context, ws *websocket.Conn) {
+	defer ws.Close(websocket.StatusNormalClosure, "")
+	for {
+		_, frame, err := ws.Read(ctx)
+		if err != nil {
+			return
+		}
+		req, err := nb.DecodeRPCFrame(frame)
+		if err != nil {
+			logrus.Errorf("RPCBenchmark: decode error: %v", err)
+			return
+		}
+		go func() {
+			params := &struct {
+				nb.RPCMessage `json:",inline"`
+				Params        BenchParams `json:"params"`
+			}{}
+			if err := req.DecodeReply(params); err != nil {
+				logrus.Errorf("RPCBenchmark: decode params error: %v", err)
+				return
+			}
+			reply := &nb.RPCMessageReply{
+				RPCMessage: nb.RPCMessage{Op: "res", RequestID: req.RequestID},
+				Reply:      params.Params,
+			}
+			if req.API == "rpcbench" && req.Method == "io" {
+				rbuf := make([]byte, params.Params.Rsize)
+				reply.Buffers = []nb.RPCBuffer{{Name: "rbuf", Length: int32(len(rbuf)), Buffer: rbuf}}
+			} else {
+				reply.Error = &nb.RPCError{RPCCode: "NO_SUCH_RPC_SERVICE", Message: req.API + "." + req.Method}
+			}
+			frame, err := nb.EncodeRPCFrame(reply, reply.Buffers)
+			if err == nil {
+				err = ws.Write(ctx, websocket.MessageBinary, frame)
+			}
+			if err != nil {
+				logrus.Errorf("RPCBenchmark: reply error: %v", err)
 			}
 		}()
 	}
-	neverStop := make(chan int)
-	<-neverStop
 }